@@ -0,0 +1,87 @@
+package salary
+
+import (
+	"fmt"
+	"time"
+)
+
+// ReasonCode 一次性调整的原因代码
+type ReasonCode string
+
+const (
+	ReasonBackPay    ReasonCode = "BACKPAY"    // 补发工资
+	ReasonBonus      ReasonCode = "BONUS"      // 奖金
+	ReasonAllowance  ReasonCode = "ALLOWANCE"  // 临时补贴
+	ReasonPenalty    ReasonCode = "PENALTY"    // 违纪扣款
+	ReasonCorrection ReasonCode = "CORRECTION" // 差错更正
+	ReasonTraining   ReasonCode = "TRAINING"   // 服务期未满返还培训费用
+	ReasonOther      ReasonCode = "OTHER"      // 其他
+)
+
+// reasonNames 原因代码对应的工资条显示名称
+var reasonNames = map[ReasonCode]string{
+	ReasonBackPay:    "补发工资",
+	ReasonBonus:      "奖金",
+	ReasonAllowance:  "临时补贴",
+	ReasonPenalty:    "违纪扣款",
+	ReasonCorrection: "差错更正",
+	ReasonTraining:   "培训费用返还",
+	ReasonOther:      "其他调整",
+}
+
+// String 返回原因代码的中文名称
+func (c ReasonCode) String() string {
+	if name, ok := reasonNames[c]; ok {
+		return name
+	}
+	return string(c)
+}
+
+// Adjustment 一次性调整，作为独立的工资条明细计入当期，替代直接修改基本工资
+type Adjustment struct {
+	ID             string      // 调整单号
+	Kind           ElementKind // 收入项或扣款项
+	Amount         Money       // 金额（分）
+	Taxable        bool        // 收入项：是否计税；扣款项：是否税前扣除
+	ReasonCode     ReasonCode  // 原因代码
+	Reason         string      // 原因说明
+	Approver       string      // 审批人
+	SecondApprover string      // 第二审批人，金额超过四眼原则阈值时必填
+}
+
+// line 将调整转换为工资条明细，明细名称为原因代码名称
+func (a Adjustment) line() PayLine {
+	return PayLine{
+		Code:    "ADJ-" + string(a.ReasonCode),
+		Name:    a.ReasonCode.String(),
+		Kind:    a.Kind,
+		Amount:  a.Amount,
+		Taxable: a.Taxable,
+	}
+}
+
+// adjustmentLines 生成一次性调整的工资条明细
+func adjustmentLines(adjustments []Adjustment) []PayLine {
+	lines := make([]PayLine, 0, len(adjustments))
+	for _, a := range adjustments {
+		lines = append(lines, a.line())
+	}
+	return lines
+}
+
+// recordAdjustments 将本期计入的一次性调整写入审计日志
+func recordAdjustments(log *AuditLog, period time.Time, input EmployeeInput) {
+	for _, a := range input.Adjustments {
+		kind := "收入"
+		if a.Kind == KindDeduction {
+			kind = "扣款"
+		}
+		log.Record(AuditEntry{
+			Actor:      a.Approver,
+			Action:     "adjustment.applied",
+			EmployeeID: input.Employee.ID,
+			Period:     monthStart(period),
+			Detail:     fmt.Sprintf("调整单%s：%s%s %s，%s", a.ID, a.ReasonCode, kind, FormatMoneyCenToYuan(a.Amount), a.Reason),
+		})
+	}
+}
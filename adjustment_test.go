@@ -0,0 +1,55 @@
+package salary
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAdjustmentLinesAndAudit(t *testing.T) {
+	audit := NewAuditLog()
+	run := PayrollRun{
+		Period: day("2024-03-01"),
+		Audit:  audit,
+		Inputs: []EmployeeInput{{
+			Employee: Employee{ID: "E1"}, Config: testConfig(),
+			Attendance: AttendanceRecord{WorkHours: hours("174")},
+			Adjustments: []Adjustment{
+				{ID: "A1", Kind: KindEarning, Amount: toMoney(cenToDec(150000)), Taxable: true, ReasonCode: ReasonBackPay, Reason: "2月漏发加班", Approver: "hr1"},
+				{ID: "A2", Kind: KindDeduction, Amount: toMoney(cenToDec(20000)), ReasonCode: ReasonPenalty, Reason: "迟到", Approver: "hr2"},
+			},
+		}},
+	}
+	employee := run.Calculate().Employees[0]
+	if len(employee.Lines) != 2 || employee.Lines[0].Code != "ADJ-BACKPAY" || employee.Lines[0].Name != "补发工资" || employee.Lines[1].Name != "违纪扣款" {
+		t.Fatalf("lines = %+v", employee.Lines)
+	}
+
+	entries := audit.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("audit = %+v", entries)
+	}
+	if e := entries[0]; e.Action != "adjustment.applied" || e.Actor != "hr1" || e.EmployeeID != "E1" || !e.Period.Equal(day("2024-03-01")) || e.Time.IsZero() {
+		t.Errorf("entry = %+v", e)
+	}
+	if !strings.Contains(entries[1].Detail, "调整单A2") || !strings.Contains(entries[1].Detail, "扣款") || !strings.Contains(entries[1].Detail, "迟到") {
+		t.Errorf("detail = %q", entries[1].Detail)
+	}
+
+	// 未在岗员工不计入调整，也不写审计
+	audit = NewAuditLog()
+	run.Audit = audit
+	run.Inputs[0].Status = PeriodInactive
+	run.Calculate()
+	if len(audit.Entries()) != 0 {
+		t.Errorf("inactive audit = %+v", audit.Entries())
+	}
+}
+
+func TestReasonCodeString(t *testing.T) {
+	if got := ReasonBonus.String(); got != "奖金" {
+		t.Errorf("ReasonBonus = %q", got)
+	}
+	if got := ReasonCode("CUSTOM").String(); got != "CUSTOM" {
+		t.Errorf("unknown code = %q", got)
+	}
+}
@@ -0,0 +1,248 @@
+package salary
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PayGapDimension 薪酬差距分析的分组维度
+type PayGapDimension int
+
+const (
+	PayGapByCompany    PayGapDimension = iota // 全公司
+	PayGapByDepartment                        // 按部门
+	PayGapByGrade                             // 按职级
+)
+
+// PayGapRow 薪酬差距分析的一行结果（金额为员工月均税前工资，单位为分）
+type PayGapRow struct {
+	Group        string          // 分组名称（部门或职级，全公司为空）
+	MaleCount    int             // 男性人数
+	FemaleCount  int             // 女性人数
+	MaleMean     Money           // 男性平均值
+	FemaleMean   Money           // 女性平均值
+	MaleMedian   Money           // 男性中位数
+	FemaleMedian Money           // 女性中位数
+	MeanGap      decimal.Decimal // 平均值差距比例 = (男性 - 女性) / 男性
+	MedianGap    decimal.Decimal // 中位数差距比例
+}
+
+// averageGrossByEmployee 按员工汇总历史发薪批次，计算每人的月均税前工资
+// 返回值: 员工档案（取最近一期）和月均税前工资，按工号排序
+func averageGrossByEmployee(history []PayrollResult) ([]Employee, map[string]decimal.Decimal) {
+	totals := make(map[string]decimal.Decimal)
+	months := make(map[string]int64)
+	latest := make(map[string]EmployeeResult)
+
+	for _, run := range history {
+		for _, r := range run.Employees {
+			id := r.Employee.ID
+			totals[id] = totals[id].Add(moneyToDec(r.GrossSalary))
+			months[id]++
+			if prev, ok := latest[id]; !ok || !r.Period.Before(prev.Period) {
+				latest[id] = r
+			}
+		}
+	}
+
+	employees := make([]Employee, 0, len(latest))
+	averages := make(map[string]decimal.Decimal, len(latest))
+	for id, r := range latest {
+		employees = append(employees, r.Employee)
+		averages[id] = totals[id].Div(decimal.NewFromInt(months[id]))
+	}
+	sort.Slice(employees, func(i, j int) bool { return employees[i].ID < employees[j].ID })
+	return employees, averages
+}
+
+// meanAndMedian 计算一组金额的平均值和中位数
+func meanAndMedian(values []decimal.Decimal) (mean, median decimal.Decimal) {
+	if len(values) == 0 {
+		return decimal.Zero, decimal.Zero
+	}
+	sorted := append([]decimal.Decimal(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].LessThan(sorted[j]) })
+
+	mean = decimal.Sum(sorted[0], sorted[1:]...).Div(decimal.NewFromInt(int64(len(sorted))))
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		median = sorted[mid-1].Add(sorted[mid]).Div(decimal.NewFromInt(2))
+	} else {
+		median = sorted[mid]
+	}
+	return mean, median
+}
+
+// gapRatio 计算差距比例 = (男性 - 女性) / 男性，保留4位小数
+func gapRatio(male, female decimal.Decimal) decimal.Decimal {
+	if male.IsZero() {
+		return decimal.Zero
+	}
+	return male.Sub(female).Div(male).Round(4)
+}
+
+// AnalyzePayGap 基于发薪历史按部门、职级或全公司统计男女薪酬的平均值、中位数及差距比例
+// history: 历史发薪批次
+// dimension: 分组维度
+// 返回值: 按分组名称排序的分析结果
+func AnalyzePayGap(history []PayrollResult, dimension PayGapDimension) []PayGapRow {
+	employees, averages := averageGrossByEmployee(history)
+
+	// 按维度和性别分组
+	type bucket struct{ male, female []decimal.Decimal }
+	buckets := make(map[string]*bucket)
+	for _, e := range employees {
+		var group string
+		switch dimension {
+		case PayGapByDepartment:
+			group = e.Department
+		case PayGapByGrade:
+			group = e.Grade
+		}
+		b, ok := buckets[group]
+		if !ok {
+			b = &bucket{}
+			buckets[group] = b
+		}
+		switch e.Gender {
+		case GenderMale:
+			b.male = append(b.male, averages[e.ID])
+		case GenderFemale:
+			b.female = append(b.female, averages[e.ID])
+		}
+	}
+
+	rows := make([]PayGapRow, 0, len(buckets))
+	for group, b := range buckets {
+		maleMean, maleMedian := meanAndMedian(b.male)
+		femaleMean, femaleMedian := meanAndMedian(b.female)
+		rows = append(rows, PayGapRow{
+			Group:        group,
+			MaleCount:    len(b.male),
+			FemaleCount:  len(b.female),
+			MaleMean:     toMoney(maleMean.Round(0)),
+			FemaleMean:   toMoney(femaleMean.Round(0)),
+			MaleMedian:   toMoney(maleMedian.Round(0)),
+			FemaleMedian: toMoney(femaleMedian.Round(0)),
+			MeanGap:      gapRatio(maleMean, femaleMean),
+			MedianGap:    gapRatio(maleMedian, femaleMedian),
+		})
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Group < rows[j].Group })
+	return rows
+}
+
+// WritePayGapCSV 将薪酬差距分析结果导出为CSV，供内部公平性审查使用
+func WritePayGapCSV(w io.Writer, rows []PayGapRow) error {
+	cw := csv.NewWriter(w)
+	header := []string{"分组", "男性人数", "女性人数", "男性平均", "女性平均", "男性中位数", "女性中位数", "平均值差距", "中位数差距"}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := []string{
+			row.Group,
+			strconv.Itoa(row.MaleCount),
+			strconv.Itoa(row.FemaleCount),
+			FormatMoneyCenToYuan(row.MaleMean),
+			FormatMoneyCenToYuan(row.FemaleMean),
+			FormatMoneyCenToYuan(row.MaleMedian),
+			FormatMoneyCenToYuan(row.FemaleMedian),
+			row.MeanGap.String(),
+			row.MedianGap.String(),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// HeadcountMetrics 某一薪资期的人数与流动指标
+type HeadcountMetrics struct {
+	Period              time.Time       // 薪资期
+	Headcount           int             // 当期发薪人数
+	Joiners             int             // 入职人数
+	Leavers             int             // 离职人数
+	TurnoverRate        decimal.Decimal // 离职率 = 离职人数 / 平均人数
+	AverageTenureMonths decimal.Decimal // 平均司龄（月），仅统计有入职日期的员工
+}
+
+// sameMonth 判断两个日期是否在同一月份
+func sameMonth(a, b time.Time) bool {
+	return a.Year() == b.Year() && a.Month() == b.Month()
+}
+
+// monthsBetween 计算两个日期之间相差的整月数
+func monthsBetween(from, to time.Time) int {
+	months := (to.Year()-from.Year())*12 + int(to.Month()-from.Month())
+	if to.Day() < from.Day() {
+		months--
+	}
+	return months
+}
+
+// HeadcountTurnover 根据发薪历史逐月统计人数、入离职人数、离职率和平均司龄
+// history: 历史发薪批次（按薪资期排序后统计）
+// 入职：入职日期在当月；无入职日期的员工以首次出现在发薪批次中的月份为准（最早一期除外）
+// 离职：离职日期在当月；无离职日期的员工在上一期发薪而当期未发薪即视为离职
+func HeadcountTurnover(history []PayrollResult) []HeadcountMetrics {
+	runs := append([]PayrollResult(nil), history...)
+	sort.Slice(runs, func(i, j int) bool { return runs[i].Period.Before(runs[j].Period) })
+
+	metrics := make([]HeadcountMetrics, 0, len(runs))
+	var previous map[string]Employee
+	for i, run := range runs {
+		current := make(map[string]Employee, len(run.Employees))
+		for _, r := range run.Employees {
+			current[r.Employee.ID] = r.Employee
+		}
+
+		m := HeadcountMetrics{Period: run.Period, Headcount: len(current)}
+		tenureTotal, tenureCount := 0, 0
+		periodEnd := run.Period.AddDate(0, 1, -1)
+		for id, e := range current {
+			if e.HireDate.IsZero() {
+				if i > 0 && previous[id].ID == "" {
+					m.Joiners++
+				}
+			} else {
+				if sameMonth(e.HireDate, run.Period) {
+					m.Joiners++
+				}
+				tenureTotal += monthsBetween(e.HireDate, periodEnd)
+				tenureCount++
+			}
+			if !e.TerminationDate.IsZero() && sameMonth(e.TerminationDate, run.Period) {
+				m.Leavers++
+			}
+		}
+		for id, e := range previous {
+			if _, ok := current[id]; !ok && e.TerminationDate.IsZero() {
+				m.Leavers++
+			}
+		}
+
+		// 平均人数取期初（上一期）与期末人数的平均值
+		average := decimal.NewFromInt(int64(m.Headcount))
+		if i > 0 {
+			average = average.Add(decimal.NewFromInt(int64(len(previous)))).Div(decimal.NewFromInt(2))
+		}
+		if !average.IsZero() {
+			m.TurnoverRate = decimal.NewFromInt(int64(m.Leavers)).Div(average).Round(4)
+		}
+		if tenureCount > 0 {
+			m.AverageTenureMonths = decimal.NewFromInt(int64(tenureTotal)).Div(decimal.NewFromInt(int64(tenureCount))).Round(1)
+		}
+
+		metrics = append(metrics, m)
+		previous = current
+	}
+	return metrics
+}
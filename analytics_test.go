@@ -0,0 +1,104 @@
+package salary
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// grossResult 构造只含税前工资的员工结果
+func grossResult(e Employee, period string, cents int64) EmployeeResult {
+	return EmployeeResult{Employee: e, Period: day(period), GrossSalary: toMoney(cenToDec(cents))}
+}
+
+func TestAnalyzePayGap(t *testing.T) {
+	m1 := Employee{ID: "M1", Department: "研发", Grade: "P6", Gender: GenderMale}
+	m2 := Employee{ID: "M2", Department: "研发", Grade: "P5", Gender: GenderMale}
+	f1 := Employee{ID: "F1", Department: "研发", Grade: "P6", Gender: GenderFemale}
+	f2 := Employee{ID: "F2", Department: "销售", Grade: "P5", Gender: GenderFemale}
+	history := []PayrollResult{
+		{Period: day("2024-01-01"), Employees: []EmployeeResult{
+			grossResult(m1, "2024-01-01", 1000000), grossResult(m2, "2024-01-01", 800000),
+			grossResult(f1, "2024-01-01", 900000), grossResult(f2, "2024-01-01", 600000),
+		}},
+		{Period: day("2024-02-01"), Employees: []EmployeeResult{
+			grossResult(m1, "2024-02-01", 1000000), grossResult(m2, "2024-02-01", 800000),
+			grossResult(f1, "2024-02-01", 700000),
+		}},
+	}
+
+	// 全公司：男性月均 10,000、8,000，女性月均 8,000、6,000
+	company := AnalyzePayGap(history, PayGapByCompany)
+	if len(company) != 1 || company[0].MaleCount != 2 || company[0].FemaleCount != 2 {
+		t.Fatalf("company = %+v", company)
+	}
+	assertMoney(t, "male mean", company[0].MaleMean, "900000")
+	assertMoney(t, "female median", company[0].FemaleMedian, "700000")
+	if got := company[0].MeanGap.String(); got != "0.2222" {
+		t.Errorf("MeanGap = %s, want 0.2222", got)
+	}
+
+	byDept := AnalyzePayGap(history, PayGapByDepartment)
+	if len(byDept) != 2 || byDept[0].Group != "研发" || byDept[1].Group != "销售" {
+		t.Fatalf("groups = %+v", byDept)
+	}
+	// 销售部没有男性员工，差距比例为0
+	if byDept[1].MaleCount != 0 || !byDept[1].MeanGap.IsZero() {
+		t.Errorf("sales row = %+v", byDept[1])
+	}
+
+	var buf bytes.Buffer
+	if err := WritePayGapCSV(&buf, byDept); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(buf.String()), "\n"); len(lines) != 3 || !strings.HasPrefix(lines[1], "研发,2,1,") {
+		t.Errorf("csv = %q", buf.String())
+	}
+}
+
+func TestHeadcountTurnover(t *testing.T) {
+	a := Employee{ID: "A", HireDate: day("2022-01-15")}
+	b := Employee{ID: "B", HireDate: day("2024-02-10")}
+	c := Employee{ID: "C"} // 无入职日期，以首次发薪月份为准
+	d := Employee{ID: "D", HireDate: day("2023-06-01"), TerminationDate: day("2024-02-20")}
+	history := []PayrollResult{
+		// 乱序传入，按薪资期排序后统计
+		{Period: day("2024-03-01"), Employees: []EmployeeResult{{Employee: a}, {Employee: b}, {Employee: c}}},
+		{Period: day("2024-01-01"), Employees: []EmployeeResult{{Employee: a}, {Employee: d}, {Employee: Employee{ID: "E"}}}},
+		{Period: day("2024-02-01"), Employees: []EmployeeResult{{Employee: a}, {Employee: b}, {Employee: d}}},
+	}
+	metrics := HeadcountTurnover(history)
+	if len(metrics) != 3 || !metrics[0].Period.Equal(day("2024-01-01")) {
+		t.Fatalf("metrics = %+v", metrics)
+	}
+
+	jan, feb, mar := metrics[0], metrics[1], metrics[2]
+	// 最早一期没有入职日期的员工不计入职
+	if jan.Headcount != 3 || jan.Joiners != 0 || jan.Leavers != 0 {
+		t.Errorf("January = %+v", jan)
+	}
+	// 2月：B 入职；D 离职日期在当月；E 未发薪且无离职日期，视为离职
+	if feb.Joiners != 1 || feb.Leavers != 2 {
+		t.Errorf("February joiners/leavers = %d/%d, want 1/2", feb.Joiners, feb.Leavers)
+	}
+	if got := feb.TurnoverRate.String(); got != "0.6667" {
+		t.Errorf("February turnover = %s, want 0.6667", got)
+	}
+	// 3月：C 首次发薪计入职；D 已在2月登记离职，不重复计算
+	if mar.Joiners != 1 || mar.Leavers != 0 {
+		t.Errorf("March joiners/leavers = %d/%d, want 1/0", mar.Joiners, mar.Leavers)
+	}
+	// 司龄只统计有入职日期的 A(26个月) 和 B(1个月)
+	if got := mar.AverageTenureMonths.String(); got != "13.5" {
+		t.Errorf("March tenure = %s, want 13.5", got)
+	}
+}
+
+func TestMonthsBetween(t *testing.T) {
+	if got := monthsBetween(day("2024-01-31"), day("2024-02-29")); got != 0 {
+		t.Errorf("Jan 31 to Feb 29 = %d, want 0", got)
+	}
+	if got := monthsBetween(day("2022-01-15"), day("2024-03-31")); got != 26 {
+		t.Errorf("tenure = %d, want 26", got)
+	}
+}
@@ -0,0 +1,105 @@
+package salary
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// BonusTaxMethod 全年一次性奖金的计税方式
+type BonusTaxMethod int
+
+const (
+	BonusTaxSeparate BonusTaxMethod = iota // 单独计税：奖金除以12个月的商数确定月度税率和速算扣除数
+	BonusTaxMerged                         // 并入当年综合所得，按年度税率表计税
+)
+
+// String 返回计税方式的中文名称
+func (m BonusTaxMethod) String() string {
+	if m == BonusTaxMerged {
+		return "并入综合所得计税"
+	}
+	return "单独计税"
+}
+
+// BonusTaxPolicy 全年一次性奖金的计税口径
+type BonusTaxPolicy struct {
+	Method BonusTaxMethod // 计税方式
+	// 并入计税时，不含奖金的全年综合所得应纳税所得额（收入减除6万元、专项扣除和专项附加扣除后，分）
+	// 为负数表示尚有未用完的减除额度，奖金先抵减该部分；单独计税时不使用
+	AnnualTaxableIncome Money
+	// 奖金发放期适用的个税配置，可由 ConfigStore.TaxConfigFor 按发放期取得；为空时使用当前生效的税率表
+	Tax *TaxConfig
+}
+
+// taxConfig 返回计税使用的个税配置
+func (p BonusTaxPolicy) taxConfig() TaxConfig {
+	if p.Tax == nil {
+		return DefaultTaxConfig()
+	}
+	return *p.Tax
+}
+
+// CalculateAnnualBonusTax 计算全年一次性奖金应纳的个人所得税
+// 单独计税：应纳税额 = 奖金 × 月度税率 - 月度速算扣除数，税率按奖金除以12后的商数查按月换算的税率表；
+// 并入计税：应纳税额 = 年度税额(综合所得 + 奖金) - 年度税额(综合所得)，即奖金带来的增量税额
+// bonus: 奖金金额（分）
+// policy: 计税口径
+// 返回值: 奖金应纳税额（分）
+func CalculateAnnualBonusTax(bonus Money, policy BonusTaxPolicy) Money {
+	amount := moneyToDec(bonus)
+	if !amount.IsPositive() {
+		return toMoney(decimal.Zero)
+	}
+	if policy.Method == BonusTaxMerged {
+		brackets := policy.taxConfig().AnnualBrackets()
+		without := calculateIncomeTaxWith(policy.AnnualTaxableIncome, toMoney(decimal.Zero), brackets)
+		with := calculateIncomeTaxWith(toMoney(moneyToDec(policy.AnnualTaxableIncome).Add(amount)), toMoney(decimal.Zero), brackets)
+		return toMoney(moneyToDec(with).Sub(moneyToDec(without)))
+	}
+
+	brackets := policy.taxConfig().brackets()
+	monthly := amount.Div(decimal.NewFromInt(12))
+	bracket := brackets[0]
+	for i := len(brackets) - 1; i >= 0; i-- {
+		if monthly.GreaterThan(moneyToDec(brackets[i].Threshold)) {
+			bracket = brackets[i]
+			break
+		}
+	}
+	tax := amount.Mul(bracket.Rate).Sub(moneyToDec(bracket.Deduction))
+	return toMoney(decimal.Max(tax, decimal.Zero).Round(2))
+}
+
+// BonusTaxComparison 两种计税方式的比较结果
+type BonusTaxComparison struct {
+	Separate    Money          // 单独计税的税额（分）
+	Merged      Money          // 并入综合所得计税的税额（分）
+	Recommended BonusTaxMethod // 税额较低的方式，相同时推荐并入计税，保留本年度单独计税的机会
+	Saving      Money          // 推荐方式比另一种方式少缴的税额（分）
+}
+
+// String 返回比较结果的说明
+func (c BonusTaxComparison) String() string {
+	return fmt.Sprintf("单独计税%s，并入综合所得%s，建议%s，可少缴%s",
+		FormatMoneyCenToYuan(c.Separate), FormatMoneyCenToYuan(c.Merged), c.Recommended, FormatMoneyCenToYuan(c.Saving))
+}
+
+// CompareAnnualBonusTax 比较奖金单独计税和并入综合所得计税的税额，推荐税额较低的方式
+// 年中可用累计预扣数据的 TaxableIncome 作为综合所得，或按全年预计收入估算
+// bonus: 奖金金额（分）
+// annualTaxableIncome: 不含奖金的全年综合所得应纳税所得额（分）
+// tax: 奖金发放期适用的个税配置，为空时使用当前生效的税率表
+func CompareAnnualBonusTax(bonus, annualTaxableIncome Money, tax *TaxConfig) BonusTaxComparison {
+	c := BonusTaxComparison{
+		Separate: CalculateAnnualBonusTax(bonus, BonusTaxPolicy{Method: BonusTaxSeparate, Tax: tax}),
+		Merged:   CalculateAnnualBonusTax(bonus, BonusTaxPolicy{Method: BonusTaxMerged, AnnualTaxableIncome: annualTaxableIncome, Tax: tax}),
+	}
+	separate, merged := moneyToDec(c.Separate), moneyToDec(c.Merged)
+	if separate.LessThan(merged) {
+		c.Recommended, c.Saving = BonusTaxSeparate, toMoney(merged.Sub(separate))
+	} else {
+		c.Recommended, c.Saving = BonusTaxMerged, toMoney(separate.Sub(merged))
+	}
+	return c
+}
@@ -0,0 +1,42 @@
+package salary
+
+import "testing"
+
+func TestCalculateAnnualBonusTax(t *testing.T) {
+	yuan := func(v int64) Money { return toMoney(cenToDec(v * 100)) }
+	cases := []struct {
+		name   string
+		bonus  Money
+		policy BonusTaxPolicy
+		want   string
+	}{
+		// 36000 / 12 = 3000，适用3%：1080元
+		{"separate 3%", yuan(36000), BonusTaxPolicy{}, "108000"},
+		// 36001 / 12 > 3000，适用10%、速算扣除数210：3390.10元（临界点税负跳升）
+		{"separate 10%", yuan(36001), BonusTaxPolicy{}, "339010"},
+		// 综合所得20000元，加奖金后56000元：(56000 × 10% - 2520) - 20000 × 3% = 2480元
+		{"merged", yuan(36000), BonusTaxPolicy{Method: BonusTaxMerged, AnnualTaxableIncome: yuan(20000)}, "248000"},
+		// 尚有30000元减除额度未用完，奖金先抵减：6000 × 3% = 180元
+		{"merged negative income", yuan(36000), BonusTaxPolicy{Method: BonusTaxMerged, AnnualTaxableIncome: yuan(-30000)}, "18000"},
+	}
+	for _, tc := range cases {
+		assertMoney(t, tc.name, CalculateAnnualBonusTax(tc.bonus, tc.policy), tc.want)
+	}
+}
+
+func TestCompareAnnualBonusTax(t *testing.T) {
+	yuan := func(v int64) Money { return toMoney(cenToDec(v * 100)) }
+	low := CompareAnnualBonusTax(yuan(36000), yuan(-30000), nil)
+	if low.Recommended != BonusTaxMerged {
+		t.Errorf("low income: recommended %s, want merged", low.Recommended)
+	}
+	assertMoney(t, "low income saving", low.Saving, "90000")
+
+	high := CompareAnnualBonusTax(yuan(36000), yuan(300000), nil)
+	if high.Recommended != BonusTaxSeparate {
+		t.Errorf("high income: recommended %s, want separate", high.Recommended)
+	}
+
+	state := TaxWithholdingState{Year: 2024, StartMonth: 1, LastMonth: 6, Income: yuan(60000), SocialInsurance: yuan(6000)}
+	assertMoney(t, "YTD taxable", state.TaxableIncome(DefaultTaxConfig()), "2400000")
+}
@@ -0,0 +1,175 @@
+package salary
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// DayType 日期类型，决定当天工时计入正常工时还是哪一类加班
+type DayType int
+
+const (
+	Workday DayType = iota // 工作日
+	RestDay                // 休息日（周末）
+	Holiday                // 法定节假日
+)
+
+// RoundingMode 工时取整方式
+type RoundingMode int
+
+const (
+	RoundNearest RoundingMode = iota // 四舍五入到最近的取整单位
+	RoundDown                        // 向下取整
+	RoundUp                          // 向上取整
+)
+
+// OvertimeMode 加班认定方式
+type OvertimeMode int
+
+const (
+	OvertimeDaily  OvertimeMode = iota // 按日认定：工作日加班、休息日和节假日出勤分别计入对应加班类别（默认）
+	OvertimeWeekly                     // 按周认定（美国FLSA）：每周实际工时超过阈值的部分计为加班，不区分日期类型
+)
+
+// DefaultWeeklyOvertimeMinutes FLSA 每周工时阈值40小时
+const DefaultWeeklyOvertimeMinutes = 40 * 60
+
+// DailyAttendance 单日考勤汇总（分钟），通常由打卡数据整理而来
+type DailyAttendance struct {
+	Date              time.Time // 考勤日期
+	DayType           DayType   // 日期类型
+	WorkMinutes       int       // 实际正常出勤分钟数
+	OvertimeMinutes   int       // 加班分钟数
+	LateMinutes       int       // 迟到分钟数
+	EarlyLeaveMinutes int       // 早退分钟数
+	ClockIn           time.Time // 上班打卡时间，用于计算班次津贴，零值表示未记录
+	ClockOut          time.Time // 下班打卡时间
+}
+
+// AttendancePolicy 考勤汇总规则：工时取整与迟到早退宽限
+type AttendancePolicy struct {
+	WorkIncrement          int          // 正常工时取整单位（分钟，如15、30），0表示不取整
+	WorkRounding           RoundingMode // 正常工时取整方式
+	OvertimeIncrement      int          // 加班工时取整单位（分钟），0表示不取整
+	OvertimeRounding       RoundingMode // 加班工时取整方式
+	LateGraceMinutes       int          // 迟到宽限分钟数，宽限内视为准时
+	EarlyLeaveGraceMinutes int          // 早退宽限分钟数，宽限内视为准时
+	PaidHolidays           []time.Time  // 本期法定节假日，未打卡也按带薪计入正常工时
+	PaidHolidayMinutes     int          // 每个法定节假日计入的带薪工时（分钟），如480
+	StandardDailyMinutes   int          // 按出勤时段划分工时时工作日的标准工时（分钟），超出部分计为加班，0表示480
+
+	OvertimeMode          OvertimeMode // 加班认定方式
+	WeeklyOvertimeMinutes int          // 按周认定时每周工时阈值（分钟），0表示40小时
+	WeekStart             time.Weekday // 按周认定时每周的起始日，零值为周日
+
+	ShiftPremiums []ShiftPremiumRule // 班次津贴规则
+}
+
+// weekKey 返回日期所在周的起始日，用于按周汇总工时
+func (p AttendancePolicy) weekKey(date time.Time) string {
+	offset := (int(date.Weekday()) - int(p.WeekStart) + 7) % 7
+	return date.AddDate(0, 0, -offset).Format("2006-01-02")
+}
+
+// roundMinutes 按取整单位和方式对分钟数取整
+func roundMinutes(minutes, increment int, mode RoundingMode) int {
+	if increment <= 0 || minutes <= 0 {
+		return minutes
+	}
+	remainder := minutes % increment
+	if remainder == 0 {
+		return minutes
+	}
+	switch mode {
+	case RoundDown:
+		return minutes - remainder
+	case RoundUp:
+		return minutes - remainder + increment
+	default:
+		// 余数达到取整单位的一半即进位
+		if remainder*2 >= increment {
+			return minutes - remainder + increment
+		}
+		return minutes - remainder
+	}
+}
+
+// minutesToHours 分钟数转换为小时
+func minutesToHours(minutes int) Hours {
+	return Hours(decimal.NewFromInt(int64(minutes)).Div(decimal.NewFromInt(60)))
+}
+
+// AggregateDailyAttendance 将每日考勤按规则取整后汇总为月度考勤记录
+// days: 每日考勤
+// policy: 考勤汇总规则
+// 返回值: 月度考勤记录
+func AggregateDailyAttendance(days []DailyAttendance, policy AttendancePolicy) AttendanceRecord {
+	var work, weekdayOT, weekendOT, holidayOT int
+	weekly := make(map[string]int)
+
+	// 法定节假日带薪工时：考勤中标记为节假日的日期与规则中配置的节假日合并去重
+	paidHolidays := make(map[string]bool)
+	for _, date := range policy.PaidHolidays {
+		paidHolidays[date.Format("2006-01-02")] = true
+	}
+
+	for _, day := range days {
+		if day.DayType == Holiday {
+			paidHolidays[day.Date.Format("2006-01-02")] = true
+		}
+
+		worked := day.WorkMinutes
+
+		// 宽限期内的迟到、早退视为准时，补回相应工时
+		if day.LateMinutes > 0 && day.LateMinutes <= policy.LateGraceMinutes {
+			worked += day.LateMinutes
+		}
+		if day.EarlyLeaveMinutes > 0 && day.EarlyLeaveMinutes <= policy.EarlyLeaveGraceMinutes {
+			worked += day.EarlyLeaveMinutes
+		}
+
+		// 按周认定时先汇总每周实际工时，循环结束后再划分正常工时和加班
+		if policy.OvertimeMode == OvertimeWeekly {
+			weekly[policy.weekKey(day.Date)] += roundMinutes(worked, policy.WorkIncrement, policy.WorkRounding) +
+				roundMinutes(day.OvertimeMinutes, policy.OvertimeIncrement, policy.OvertimeRounding)
+			continue
+		}
+
+		switch day.DayType {
+		case Workday:
+			work += roundMinutes(worked, policy.WorkIncrement, policy.WorkRounding)
+			weekdayOT += roundMinutes(day.OvertimeMinutes, policy.OvertimeIncrement, policy.OvertimeRounding)
+		case RestDay:
+			// 休息日出勤全部按周末加班计算
+			weekendOT += roundMinutes(worked+day.OvertimeMinutes, policy.OvertimeIncrement, policy.OvertimeRounding)
+		case Holiday:
+			// 法定节假日出勤全部按节假日加班计算
+			holidayOT += roundMinutes(worked+day.OvertimeMinutes, policy.OvertimeIncrement, policy.OvertimeRounding)
+		}
+	}
+
+	// 每周超过阈值的工时计为加班，按工作日加班费率计算，其余计为正常工时
+	// 跨月的周只统计传入的日期，需要完整周时应传入整周考勤
+	threshold := policy.WeeklyOvertimeMinutes
+	if threshold <= 0 {
+		threshold = DefaultWeeklyOvertimeMinutes
+	}
+	for _, minutes := range weekly {
+		over := max(minutes-threshold, 0)
+		weekdayOT += over
+		work += minutes - over
+	}
+
+	// 节假日即使未出勤也计入正常工时，避免节假日较多的月份工时不足
+	work += len(paidHolidays) * policy.PaidHolidayMinutes
+
+	return AttendanceRecord{
+		WorkHours:       minutesToHours(work),
+		OvertimeWeekday: minutesToHours(weekdayOT),
+		OvertimeWeekend: minutesToHours(weekendOT),
+		OvertimeHoliday: minutesToHours(holidayOT),
+		AbsenceHours:    Hours(decimal.Zero),
+		Shifts:          aggregateShiftHours(days, policy.ShiftPremiums),
+	}
+}
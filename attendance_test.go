@@ -0,0 +1,119 @@
+package salary
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRoundMinutes(t *testing.T) {
+	cases := []struct {
+		minutes, increment int
+		mode               RoundingMode
+		want               int
+	}{
+		{487, 15, RoundNearest, 480},
+		{488, 15, RoundNearest, 495}, // 余数8分钟超过取整单位的一半
+		{487, 15, RoundDown, 480},
+		{481, 15, RoundUp, 495},
+		{480, 15, RoundUp, 480},
+		{487, 0, RoundNearest, 487}, // 不取整
+		{0, 30, RoundUp, 0},
+	}
+	for _, tc := range cases {
+		if got := roundMinutes(tc.minutes, tc.increment, tc.mode); got != tc.want {
+			t.Errorf("roundMinutes(%d, %d, %v) = %d, want %d", tc.minutes, tc.increment, tc.mode, got, tc.want)
+		}
+	}
+}
+
+func TestAggregateDailyAttendanceRoundingAndGrace(t *testing.T) {
+	policy := AttendancePolicy{
+		WorkIncrement: 30, WorkRounding: RoundDown,
+		OvertimeIncrement: 30, OvertimeRounding: RoundNearest,
+		LateGraceMinutes: 5, EarlyLeaveGraceMinutes: 5,
+	}
+	days := []DailyAttendance{
+		// 迟到3分钟在宽限内补回：477 + 3 = 480
+		{Date: day("2024-03-04"), DayType: Workday, WorkMinutes: 477, LateMinutes: 3, OvertimeMinutes: 50},
+		// 迟到10分钟超出宽限不补回：470 向下取整为 450
+		{Date: day("2024-03-05"), DayType: Workday, WorkMinutes: 470, LateMinutes: 10},
+		// 早退2分钟在宽限内：478 + 2 = 480
+		{Date: day("2024-03-06"), DayType: Workday, WorkMinutes: 478, EarlyLeaveMinutes: 2},
+		{Date: day("2024-03-09"), DayType: RestDay, WorkMinutes: 200, OvertimeMinutes: 30},
+	}
+	got := AggregateDailyAttendance(days, policy)
+	check := func(name string, got Hours, want string) {
+		t.Helper()
+		if !hoursToDec(got).Equal(hoursToDec(hours(want))) {
+			t.Errorf("%s = %s, want %s", name, hoursToDec(got), want)
+		}
+	}
+	check("WorkHours", got.WorkHours, "23.5")
+	check("OvertimeWeekday", got.OvertimeWeekday, "1") // 50分钟四舍五入为60分钟
+	check("OvertimeWeekend", got.OvertimeWeekend, "4") // 休息日出勤230分钟按30分钟四舍五入为240分钟
+	check("OvertimeHoliday", got.OvertimeHoliday, "0")
+}
+
+func TestAggregateDailyAttendancePaidHolidays(t *testing.T) {
+	policy := AttendancePolicy{
+		PaidHolidays:       []time.Time{day("2024-04-04"), day("2024-04-05")},
+		PaidHolidayMinutes: 480,
+	}
+	days := []DailyAttendance{
+		{Date: day("2024-04-03"), DayType: Workday, WorkMinutes: 480},
+		// 配置的节假日当天出勤：计入节假日加班，同时照常计入带薪工时，不重复计算
+		{Date: day("2024-04-04"), DayType: Holiday, WorkMinutes: 240},
+		// 考勤中标记、但未在规则中配置的节假日同样计入带薪工时
+		{Date: day("2024-04-06"), DayType: Holiday},
+	}
+	got := AggregateDailyAttendance(days, policy)
+	// 正常出勤8小时 + 3个节假日 × 8小时
+	if !hoursToDec(got.WorkHours).Equal(hoursToDec(hours("32"))) {
+		t.Errorf("WorkHours = %s, want 32", hoursToDec(got.WorkHours))
+	}
+	if !hoursToDec(got.OvertimeHoliday).Equal(hoursToDec(hours("4"))) {
+		t.Errorf("OvertimeHoliday = %s, want 4", hoursToDec(got.OvertimeHoliday))
+	}
+
+	// 未设置带薪工时时不计入
+	policy.PaidHolidayMinutes = 0
+	if got := AggregateDailyAttendance(days, policy); !hoursToDec(got.WorkHours).Equal(hoursToDec(hours("8"))) {
+		t.Errorf("WorkHours without paid minutes = %s, want 8", hoursToDec(got.WorkHours))
+	}
+}
+
+func TestAggregateDailyAttendanceWeeklyOvertime(t *testing.T) {
+	var days []DailyAttendance
+	// 3月4日（周一）至8日每天9小时，9日（周六）5小时，10日（周日）4小时，11日（周一）8小时
+	for d := 4; d <= 8; d++ {
+		days = append(days, DailyAttendance{Date: day(fmt.Sprintf("2024-03-%02d", d)), DayType: Workday, WorkMinutes: 540})
+	}
+	days = append(days,
+		DailyAttendance{Date: day("2024-03-09"), DayType: RestDay, WorkMinutes: 300},
+		DailyAttendance{Date: day("2024-03-10"), DayType: RestDay, WorkMinutes: 240},
+		DailyAttendance{Date: day("2024-03-11"), DayType: Workday, WorkMinutes: 480},
+	)
+
+	check := func(name string, got Hours, want string) {
+		t.Helper()
+		if !hoursToDec(got).Equal(hoursToDec(hours(want))) {
+			t.Errorf("%s = %s, want %s", name, hoursToDec(got), want)
+		}
+	}
+
+	// 每周从周一开始：第一周54小时，超出40小时的14小时计为加班，不区分休息日
+	got := AggregateDailyAttendance(days, AttendancePolicy{OvertimeMode: OvertimeWeekly, WeekStart: time.Monday})
+	check("WorkHours", got.WorkHours, "48")
+	check("OvertimeWeekday", got.OvertimeWeekday, "14")
+	check("OvertimeWeekend", got.OvertimeWeekend, "0")
+
+	// 默认每周从周日开始：周日的4小时归入下一周
+	got = AggregateDailyAttendance(days, AttendancePolicy{OvertimeMode: OvertimeWeekly})
+	check("WorkHours (Sunday start)", got.WorkHours, "52")
+	check("OvertimeWeekday (Sunday start)", got.OvertimeWeekday, "10")
+
+	// 自定义阈值
+	got = AggregateDailyAttendance(days, AttendancePolicy{OvertimeMode: OvertimeWeekly, WeekStart: time.Monday, WeeklyOvertimeMinutes: 44 * 60})
+	check("OvertimeWeekday (44h)", got.OvertimeWeekday, "10")
+}
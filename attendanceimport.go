@@ -0,0 +1,122 @@
+package salary
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidHours 工时格式错误
+var ErrInvalidHours = errors.New("工时格式错误")
+
+// AttendanceLineError 考勤导入中单行的校验错误
+type AttendanceLineError struct {
+	Line       int    // 行号（含表头，从1开始）
+	EmployeeID string // 工号
+	Column     string // 出错的列
+	Err        error  // 错误原因
+}
+
+// Error 实现 error 接口
+func (e AttendanceLineError) Error() string {
+	if e.Column == "" {
+		return fmt.Sprintf("第%d行（%s）: %v", e.Line, e.EmployeeID, e.Err)
+	}
+	return fmt.Sprintf("第%d行（%s）%s: %v", e.Line, e.EmployeeID, e.Column, e.Err)
+}
+
+// Unwrap 返回原始错误
+func (e AttendanceLineError) Unwrap() error {
+	return e.Err
+}
+
+// AttendanceImportErrors 考勤导入的全部行级错误
+type AttendanceImportErrors []AttendanceLineError
+
+// Error 实现 error 接口，列出全部出错行
+func (e AttendanceImportErrors) Error() string {
+	lines := make([]string, len(e))
+	for i, le := range e {
+		lines[i] = le.Error()
+	}
+	return fmt.Sprintf("考勤导入有%d行错误: %s", len(e), strings.Join(lines, "；"))
+}
+
+// attendanceColumns 考勤CSV工时列的名称，顺序与文件列顺序一致
+var attendanceColumns = []string{"正常工时", "工作日加班", "休息日加班", "节假日加班", "缺勤"}
+
+// ImportAttendanceCSV 导入考勤CSV，首行为表头
+// 列：工号, 正常工时, 工作日加班, 休息日加班, 节假日加班, 缺勤（小时，留空按0处理）
+// 格式错误、负数工时和重复工号等行级错误不中断导入，汇总为 AttendanceImportErrors 返回，校验通过的行照常返回
+// 返回值: (按工号索引的考勤记录, 行级错误为 AttendanceImportErrors，文件无法读取时为读取错误)
+func ImportAttendanceCSV(r io.Reader) (map[string]AttendanceRecord, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	records := make(map[string]AttendanceRecord)
+	var errs AttendanceImportErrors
+	for i, row := range rows {
+		if i == 0 {
+			continue
+		}
+		line := i + 1
+		id := ""
+		if len(row) > 0 {
+			id = strings.TrimSpace(row[0])
+		}
+		if id == "" {
+			errs = append(errs, AttendanceLineError{Line: line, Err: errors.New("缺少工号")})
+			continue
+		}
+		if _, ok := records[id]; ok {
+			errs = append(errs, AttendanceLineError{Line: line, EmployeeID: id, Err: errors.New("工号重复")})
+			continue
+		}
+
+		values := make([]Hours, len(attendanceColumns))
+		valid := true
+		for c, column := range attendanceColumns {
+			cell := ""
+			if c+1 < len(row) {
+				cell = row[c+1]
+			}
+			h, err := parseHours(cell)
+			if err != nil {
+				errs = append(errs, AttendanceLineError{Line: line, EmployeeID: id, Column: column, Err: err})
+				valid = false
+				continue
+			}
+			values[c] = h
+		}
+		if !valid {
+			continue
+		}
+		records[id] = AttendanceRecord{
+			WorkHours:       values[0],
+			OvertimeWeekday: values[1],
+			OvertimeWeekend: values[2],
+			OvertimeHoliday: values[3],
+			AbsenceHours:    values[4],
+		}
+	}
+	if len(errs) > 0 {
+		return records, errs
+	}
+	return records, nil
+}
+
+// parseHours 解析工时单元格，空单元格为0，其他写法同 ParseHours
+func parseHours(s string) (Hours, error) {
+	if strings.TrimSpace(s) == "" {
+		return Hours(decimal.Zero), nil
+	}
+	return ParseHours(s)
+}
@@ -0,0 +1,40 @@
+package salary
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestImportAttendanceCSV(t *testing.T) {
+	data := `工号,正常工时,工作日加班,休息日加班,节假日加班,缺勤
+E1,174,4.5,8,,
+E2,-8,0,0,0,0
+E3,17x,0,0,0,0
+E1,160,0,0,0,14
+E4,１６０,0,0,0,14
+`
+	records, err := ImportAttendanceCSV(strings.NewReader(data))
+	var lineErrs AttendanceImportErrors
+	if !errors.As(err, &lineErrs) {
+		t.Fatalf("error = %v, want AttendanceImportErrors", err)
+	}
+	if len(lineErrs) != 3 {
+		t.Fatalf("line errors = %v, want 3", lineErrs)
+	}
+	if lineErrs[0].Line != 3 || lineErrs[0].Column != "正常工时" || !errors.Is(lineErrs[0].Err, ErrInvalidHours) {
+		t.Errorf("first error = %+v", lineErrs[0])
+	}
+	if len(records) != 2 {
+		t.Fatalf("records = %v, want E1 and E4", records)
+	}
+	e1 := records["E1"]
+	if !hoursToDec(e1.OvertimeWeekday).Equal(decimal.RequireFromString("4.5")) || !hoursToDec(e1.OvertimeHoliday).IsZero() {
+		t.Errorf("E1 = %+v", e1)
+	}
+	if !hoursToDec(records["E4"].WorkHours).Equal(decimal.NewFromInt(160)) {
+		t.Errorf("E4 work hours = %s", hoursToDec(records["E4"].WorkHours))
+	}
+}
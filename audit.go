@@ -0,0 +1,44 @@
+package salary
+
+import (
+	"sync"
+	"time"
+)
+
+// AuditEntry 审计日志中的一条记录
+type AuditEntry struct {
+	Time       time.Time // 发生时间
+	Actor      string    // 操作人或审批人
+	Action     string    // 操作类型，如 adjustment.applied
+	EmployeeID string    // 相关员工工号
+	Period     time.Time // 相关薪资期
+	Detail     string    // 操作说明
+}
+
+// AuditLog 只追加的审计日志，记录影响工资结果的人工操作
+type AuditLog struct {
+	mu      sync.Mutex
+	entries []AuditEntry
+}
+
+// NewAuditLog 创建空的审计日志
+func NewAuditLog() *AuditLog {
+	return &AuditLog{}
+}
+
+// Record 追加一条审计记录，未设置时间时使用当前时间
+func (l *AuditLog) Record(entry AuditEntry) {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, entry)
+}
+
+// Entries 返回全部审计记录的副本，按追加顺序排列
+func (l *AuditLog) Entries() []AuditEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]AuditEntry(nil), l.entries...)
+}
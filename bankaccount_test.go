@@ -0,0 +1,31 @@
+package salary
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateBankAccount(t *testing.T) {
+	bank, err := ValidateBankAccount("6222 0212 3456 7890 128")
+	if err != nil {
+		t.Fatalf("ValidateBankAccount error: %v", err)
+	}
+	if bank.Code != "ICBC" {
+		t.Errorf("bank = %q, want ICBC", bank.Code)
+	}
+
+	for _, account := range []string{"", "6222021234567890123", "62220212345", "6222-abcd-1234-5678"} {
+		if _, err := ValidateBankAccount(account); !errors.Is(err, ErrInvalidBankAccount) {
+			t.Errorf("ValidateBankAccount(%q) error = %v, want ErrInvalidBankAccount", account, err)
+		}
+	}
+}
+
+func TestMaskBankAccount(t *testing.T) {
+	if got, want := MaskBankAccount("6222 0212 3456 7890 128"), "6222***********0128"; got != want {
+		t.Errorf("MaskBankAccount = %q, want %q", got, want)
+	}
+	if got := MaskBankAccount("1234"); got != "****" {
+		t.Errorf("MaskBankAccount short = %q, want ****", got)
+	}
+}
@@ -0,0 +1,112 @@
+package salary
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// BaseAdjustment 单个员工的年度缴费基数调整结果
+type BaseAdjustment struct {
+	EmployeeID      string       // 工号
+	Wage            Money        // 核定工资（分），取员工档案中的基本工资
+	NewBase         Money        // 新缴费基数（分）
+	Clamp           BaseClamp    // 封顶保底情况
+	OldContribution Money        // 调整前每月个人缴费（社保+公积金，取生效月前最近一期实缴）
+	NewContribution Money        // 调整后每月个人缴费
+	Delta           Money        // 每月个人缴费变化 = 调整后 - 调整前
+	BackCharges     []Adjustment // 生效月起已按旧基数发薪月份的补扣或退还调整
+}
+
+// contribution 按城市政策计算指定基数下的个人社保和公积金合计
+func (p RegionPolicy) contribution(base Money) decimal.Decimal {
+	socialInsurance, housingFund := CalculateSocialInsurance(p.Apply(PayrollConfig{}), base)
+	return moneyToDec(socialInsurance).Add(moneyToDec(housingFund))
+}
+
+// ApplyBaseAdjustment 城市公布新缴费基数后，重新核定该城市全部员工的缴费基数
+// 按新的上下限对员工基本工资封顶保底，计算每月个人缴费变化；
+// backCharge 为真时，对生效月起已按旧基数发薪的月份生成补扣（少缴）或退还（多缴）调整，供计入下一期发薪批次
+// policy: 新的城市政策，包含缴费基数上下限
+// effectiveMonth: 新基数生效月份
+// 返回值: 各员工调整结果，按工号排序
+func (s *MemoryStore) ApplyBaseAdjustment(policy RegionPolicy, effectiveMonth time.Time, backCharge bool) ([]BaseAdjustment, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	effective := monthStart(effectiveMonth)
+
+	// 员工在生效月前最近一期的实缴金额，以及生效月起已发薪月份的实缴金额
+	before := make(map[string]EmployeeResult)
+	paid := make(map[string][]EmployeeResult)
+	for _, run := range s.Runs() {
+		for _, r := range run.Employees {
+			if r.Status != PeriodActive {
+				continue
+			}
+			if r.Period.Before(effective) {
+				before[r.Employee.ID] = r
+			} else {
+				paid[r.Employee.ID] = append(paid[r.Employee.ID], r)
+			}
+		}
+	}
+
+	var adjustments []BaseAdjustment
+	for _, record := range s.Employees() {
+		if record.Employee.City != policy.City {
+			continue
+		}
+		id := record.Employee.ID
+		base, clamp := policy.Base.Clamp(record.BaseSalary)
+		newContribution := policy.contribution(base)
+
+		oldContribution := newContribution
+		if r, ok := before[id]; ok {
+			oldContribution = moneyToDec(r.SocialInsurance).Add(moneyToDec(r.HousingFund))
+		}
+
+		adjustment := BaseAdjustment{
+			EmployeeID:      id,
+			Wage:            record.BaseSalary,
+			NewBase:         base,
+			Clamp:           clamp,
+			OldContribution: toMoney(oldContribution),
+			NewContribution: toMoney(newContribution),
+			Delta:           toMoney(newContribution.Sub(oldContribution)),
+		}
+		if backCharge {
+			adjustment.BackCharges = baseBackCharges(id, paid[id], newContribution)
+		}
+		adjustments = append(adjustments, adjustment)
+	}
+	sort.Slice(adjustments, func(i, j int) bool { return adjustments[i].EmployeeID < adjustments[j].EmployeeID })
+	return adjustments, nil
+}
+
+// baseBackCharges 为已按旧基数发薪的月份生成补扣或退还调整
+// 补扣的个人缴费可在税前扣除，退还的个人缴费需计税
+func baseBackCharges(employeeID string, paid []EmployeeResult, contribution decimal.Decimal) []Adjustment {
+	var charges []Adjustment
+	for _, r := range paid {
+		diff := contribution.Sub(moneyToDec(r.SocialInsurance).Add(moneyToDec(r.HousingFund)))
+		if diff.IsZero() {
+			continue
+		}
+		kind, reason := KindDeduction, "补扣"
+		if diff.IsNegative() {
+			kind, reason = KindEarning, "退还"
+		}
+		charges = append(charges, Adjustment{
+			ID:         fmt.Sprintf("BASE-%s-%s", employeeID, r.Period.Format("200601")),
+			Kind:       kind,
+			Amount:     toMoney(diff.Abs()),
+			Taxable:    true,
+			ReasonCode: ReasonCorrection,
+			Reason:     fmt.Sprintf("%s缴费基数调整%s个人社保公积金差额", r.Period.Format("2006-01"), reason),
+		})
+	}
+	return charges
+}
@@ -0,0 +1,68 @@
+package salary
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestApplyBaseAdjustment(t *testing.T) {
+	store := NewMemoryStore()
+	store.PutEmployee(EmployeeRecord{Employee: Employee{ID: "E1", City: "shanghai"}, BaseSalary: toMoney(cenToDec(500000))})
+	store.PutEmployee(EmployeeRecord{Employee: Employee{ID: "E2", City: "shanghai"}, BaseSalary: toMoney(cenToDec(5000000))})
+	store.PutEmployee(EmployeeRecord{Employee: Employee{ID: "E3", City: "beijing"}, BaseSalary: toMoney(cenToDec(500000))})
+
+	paid := func(id, period string, contribution int64) EmployeeResult {
+		return EmployeeResult{Employee: Employee{ID: id}, Period: day(period), Status: PeriodActive, SocialInsurance: toMoney(cenToDec(contribution))}
+	}
+	store.SaveRun(&PayrollResult{Period: day("2024-06-01"), Employees: []EmployeeResult{paid("E1", "2024-06-01", 87500)}})
+	store.SaveRun(&PayrollResult{Period: day("2024-07-01"), Employees: []EmployeeResult{
+		paid("E1", "2024-07-01", 87500),
+		paid("E2", "2024-07-01", 700000),
+	}})
+
+	policy := RegionPolicy{
+		City:        "shanghai",
+		PensionRate: decimal.RequireFromString("0.08"), MedicalRate: decimal.RequireFromString("0.02"),
+		UnemploymentRate: decimal.RequireFromString("0.005"), HousingFundRate: decimal.RequireFromString("0.07"),
+		Base: BaseLimits{Floor: toMoney(cenToDec(731000)), Ceiling: toMoney(cenToDec(3655000))},
+	}
+	adjustments, err := store.ApplyBaseAdjustment(policy, day("2024-07-01"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(adjustments) != 2 || adjustments[0].EmployeeID != "E1" || adjustments[1].EmployeeID != "E2" {
+		t.Fatalf("adjustments = %+v", adjustments)
+	}
+
+	// E1 按下限保底：7310 × 17.5% = 1279.25，较6月实缴多 404.25，7月补扣
+	e1 := adjustments[0]
+	if e1.Clamp != ClampFloor {
+		t.Errorf("E1 clamp = %q", e1.Clamp)
+	}
+	assertMoney(t, "E1 new base", e1.NewBase, "731000")
+	assertMoney(t, "E1 new contribution", e1.NewContribution, "127925")
+	assertMoney(t, "E1 delta", e1.Delta, "40425")
+	if len(e1.BackCharges) != 1 || e1.BackCharges[0].ID != "BASE-E1-202407" || e1.BackCharges[0].Kind != KindDeduction {
+		t.Fatalf("E1 back charges = %+v", e1.BackCharges)
+	}
+	assertMoney(t, "E1 back charge", e1.BackCharges[0].Amount, "40425")
+
+	// E2 按上限封顶：36550 × 17.5% = 6396.25，7月多缴 603.75 退还
+	e2 := adjustments[1]
+	if e2.Clamp != ClampCeiling || len(e2.BackCharges) != 1 || e2.BackCharges[0].Kind != KindEarning {
+		t.Fatalf("E2 = %+v", e2)
+	}
+	assertMoney(t, "E2 delta without prior month", e2.Delta, "0")
+	assertMoney(t, "E2 refund", e2.BackCharges[0].Amount, "60375")
+
+	adjustments, err = store.ApplyBaseAdjustment(policy, day("2024-07-01"), false)
+	if err != nil || len(adjustments[0].BackCharges) != 0 {
+		t.Errorf("without back charge: %+v, err = %v", adjustments, err)
+	}
+
+	policy.Base.Floor = toMoney(cenToDec(5000000))
+	if _, err := store.ApplyBaseAdjustment(policy, day("2024-07-01"), true); err == nil {
+		t.Error("floor above ceiling should fail")
+	}
+}
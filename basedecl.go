@@ -0,0 +1,170 @@
+package salary
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/shopspring/decimal"
+)
+
+// BaseLimits 社保缴费基数上下限（分），零值表示不限
+type BaseLimits struct {
+	Floor   Money `json:"floor"`   // 缴费基数下限
+	Ceiling Money `json:"ceiling"` // 缴费基数上限
+}
+
+// BaseClamp 缴费基数的封顶保底情况
+type BaseClamp string
+
+const (
+	ClampNone    BaseClamp = ""        // 未调整
+	ClampFloor   BaseClamp = "floor"   // 按下限保底
+	ClampCeiling BaseClamp = "ceiling" // 按上限封顶
+)
+
+// Clamp 将工资限制在上下限之间
+// 返回值: (缴费基数, 封顶保底情况)
+func (l BaseLimits) Clamp(wage Money) (Money, BaseClamp) {
+	w := moneyToDec(wage)
+	floor, ceiling := moneyToDec(l.Floor), moneyToDec(l.Ceiling)
+	switch {
+	case floor.IsPositive() && w.LessThan(floor):
+		return l.Floor, ClampFloor
+	case ceiling.IsPositive() && w.GreaterThan(ceiling):
+		return l.Ceiling, ClampCeiling
+	default:
+		return wage, ClampNone
+	}
+}
+
+// BaseDeclarationRow 社保年度缴费基数申报表中的一行
+type BaseDeclarationRow struct {
+	Employee     Employee  // 员工档案（取当年最近一期）
+	Year         int       // 工资所属年度
+	Months       int       // 当年在岗发薪月数
+	TotalWage    Money     // 当年工资总额（分）
+	AverageWage  Money     // 月平均工资（分）
+	DeclaredBase Money     // 申报缴费基数（分）
+	Clamp        BaseClamp // 封顶保底情况
+}
+
+// BuildBaseDeclaration 根据已保存的发薪批次计算员工上年度月平均工资，生成社保年度缴费基数申报数据
+// 多数城市每年7月按上年度月平均工资重新核定缴费基数，月平均工资 = 当年税前工资合计 / 在岗发薪月数，
+// 未在岗月份不计入；当年没有在岗月份的员工不申报
+// history: 发薪批次结果
+// year: 工资所属年度（通常为申报年度的上一年）
+// limits: 当地缴费基数上下限
+// 返回值: 申报数据，按工号排序
+func BuildBaseDeclaration(history []PayrollResult, year int, limits BaseLimits) []BaseDeclarationRow {
+	totals := make(map[string]decimal.Decimal)
+	months := make(map[string]int)
+	latest := make(map[string]EmployeeResult)
+
+	for _, run := range history {
+		for _, r := range run.Employees {
+			if r.Period.Year() != year || r.Status != PeriodActive {
+				continue
+			}
+			id := r.Employee.ID
+			totals[id] = totals[id].Add(moneyToDec(r.GrossSalary))
+			months[id]++
+			if prev, ok := latest[id]; !ok || !r.Period.Before(prev.Period) {
+				latest[id] = r
+			}
+		}
+	}
+
+	rows := make([]BaseDeclarationRow, 0, len(latest))
+	for id, r := range latest {
+		average := toMoney(totals[id].Div(decimal.NewFromInt(int64(months[id]))).Round(2))
+		base, clamp := limits.Clamp(average)
+		rows = append(rows, BaseDeclarationRow{
+			Employee:     r.Employee,
+			Year:         year,
+			Months:       months[id],
+			TotalWage:    toMoney(totals[id]),
+			AverageWage:  average,
+			DeclaredBase: base,
+			Clamp:        clamp,
+		})
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Employee.ID < rows[j].Employee.ID })
+	return rows
+}
+
+// clampNames 封顶保底情况的申报表显示名称
+var clampNames = map[BaseClamp]string{
+	ClampNone:    "",
+	ClampFloor:   "按下限",
+	ClampCeiling: "按上限",
+}
+
+// WriteBaseDeclarationCSV 导出社保年度缴费基数申报文件
+func WriteBaseDeclarationCSV(w io.Writer, rows []BaseDeclarationRow) error {
+	cw := csv.NewWriter(w)
+	header := []string{"工号", "姓名", "部门", "参保城市", "工资年度", "发薪月数", "工资总额", "月平均工资", "申报缴费基数", "备注"}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := []string{
+			row.Employee.ID,
+			row.Employee.Name,
+			row.Employee.Department,
+			row.Employee.City,
+			strconv.Itoa(row.Year),
+			strconv.Itoa(row.Months),
+			moneyToDec(row.TotalWage).Div(decimal.NewFromInt(100)).StringFixed(2),
+			moneyToDec(row.AverageWage).Div(decimal.NewFromInt(100)).StringFixed(2),
+			moneyToDec(row.DeclaredBase).Div(decimal.NewFromInt(100)).StringFixed(2),
+			clampNames[row.Clamp],
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// handleBaseDeclaration 导出社保年度缴费基数申报文件：GET /reports/base-declaration?year=2024&floor_yuan=&ceiling_yuan=
+// floor_yuan、ceiling_yuan 为缴费基数上下限（元，如 7,310.00），可省略；
+// 原以分为单位的 floor、ceiling 参数不再支持，传入时返回错误，避免按元误读
+func (s *Server) handleBaseDeclaration(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	year, err := strconv.Atoi(query.Get("year"))
+	if err != nil {
+		writeError(w, fmt.Errorf("年度格式错误: %w", err))
+		return
+	}
+	for _, old := range []string{"floor", "ceiling"} {
+		if query.Has(old) {
+			writeError(w, fmt.Errorf("参数 %s（分）已停用，请改用 %s_yuan（元）", old, old))
+			return
+		}
+	}
+	var limits BaseLimits
+	for _, p := range []struct {
+		name string
+		dst  *Money
+	}{{"floor_yuan", &limits.Floor}, {"ceiling_yuan", &limits.Ceiling}} {
+		value := query.Get(p.name)
+		if value == "" {
+			continue
+		}
+		amount, err := ParseMoney(value)
+		if err != nil {
+			writeError(w, fmt.Errorf("%s: %w", p.name, err))
+			return
+		}
+		*p.dst = amount
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="base-declaration-%d.csv"`, year))
+	WriteBaseDeclarationCSV(w, BuildBaseDeclaration(s.store.Runs(), year, limits))
+}
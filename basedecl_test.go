@@ -0,0 +1,70 @@
+package salary
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBaseLimitsClamp(t *testing.T) {
+	limits := BaseLimits{Floor: toMoney(cenToDec(731000)), Ceiling: toMoney(cenToDec(3655000))}
+	cases := []struct {
+		wage  int64
+		base  string
+		clamp BaseClamp
+	}{
+		{500000, "731000", ClampFloor},
+		{1000000, "1000000", ClampNone},
+		{5000000, "3655000", ClampCeiling},
+	}
+	for _, c := range cases {
+		base, clamp := limits.Clamp(toMoney(cenToDec(c.wage)))
+		assertMoney(t, "base", base, c.base)
+		if clamp != c.clamp {
+			t.Errorf("wage %d: clamp = %q, want %q", c.wage, clamp, c.clamp)
+		}
+	}
+	if _, clamp := (BaseLimits{}).Clamp(toMoney(cenToDec(100))); clamp != ClampNone {
+		t.Errorf("unlimited clamp = %q", clamp)
+	}
+}
+
+func TestBuildBaseDeclaration(t *testing.T) {
+	result := func(id, period string, gross int64, status PeriodStatus) EmployeeResult {
+		return EmployeeResult{Employee: Employee{ID: id, Name: id}, Period: day(period), Status: status, GrossSalary: toMoney(cenToDec(gross))}
+	}
+	history := []PayrollResult{
+		{Period: day("2023-11-01"), Employees: []EmployeeResult{
+			result("E2", "2023-11-01", 400000, PeriodActive),
+			result("E1", "2023-11-01", 1000000, PeriodActive),
+		}},
+		{Period: day("2023-12-01"), Employees: []EmployeeResult{
+			result("E2", "2023-12-01", 500000, PeriodActive),
+			result("E1", "2023-12-01", 0, PeriodInactive),
+			result("E3", "2023-12-01", 0, PeriodInactive),
+		}},
+		{Period: day("2024-01-01"), Employees: []EmployeeResult{result("E1", "2024-01-01", 2000000, PeriodActive)}},
+	}
+	limits := BaseLimits{Floor: toMoney(cenToDec(731000)), Ceiling: toMoney(cenToDec(3655000))}
+	rows := BuildBaseDeclaration(history, 2023, limits)
+
+	// 未在岗月份和其他年度不计入，当年没有在岗月份的员工不申报
+	if len(rows) != 2 || rows[0].Employee.ID != "E1" || rows[1].Employee.ID != "E2" {
+		t.Fatalf("rows = %+v", rows)
+	}
+	if rows[0].Months != 1 || rows[0].Clamp != ClampNone {
+		t.Errorf("E1 = %+v", rows[0])
+	}
+	assertMoney(t, "E1 average", rows[0].AverageWage, "1000000")
+	assertMoney(t, "E2 total", rows[1].TotalWage, "900000")
+	assertMoney(t, "E2 average", rows[1].AverageWage, "450000")
+	assertMoney(t, "E2 declared", rows[1].DeclaredBase, "731000")
+
+	var b strings.Builder
+	if err := WriteBaseDeclarationCSV(&b, rows); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 3 || lines[2] != "E2,E2,,,2023,2,9000.00,4500.00,7310.00,按下限" {
+		t.Errorf("csv = %q", lines)
+	}
+}
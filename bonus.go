@@ -0,0 +1,80 @@
+package salary
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// BonusDeferral 分期发放的奖金，如年终奖按季度分期发放
+// 每期发放额作为计税收入项并入当月工资薪金计税
+type BonusDeferral struct {
+	Code           string    // 项目代码
+	Name           string    // 项目名称，如 2024年度年终奖
+	Total          Money     // 奖金总额（分）
+	Start          time.Time // 首期发放月份
+	Installments   int       // 分期数
+	IntervalMonths int       // 相邻两期间隔月数，如按季度发放为3；0视为1
+}
+
+// interval 相邻两期的间隔月数
+func (b BonusDeferral) interval() int {
+	if b.IntervalMonths <= 0 {
+		return 1
+	}
+	return b.IntervalMonths
+}
+
+// InstallmentAmount 计算第n期（从1开始）的发放额，尾差计入最后一期
+func (b BonusDeferral) InstallmentAmount(n int) Money {
+	if b.Installments <= 0 || n < 1 || n > b.Installments {
+		return toMoney(decimal.Zero)
+	}
+	total := moneyToDec(b.Total)
+	each := total.Div(decimal.NewFromInt(int64(b.Installments))).Truncate(2)
+	if n < b.Installments {
+		return toMoney(each)
+	}
+	return toMoney(total.Sub(each.Mul(decimal.NewFromInt(int64(b.Installments - 1)))))
+}
+
+// InstallmentPeriod 第n期（从1开始）的发放月份
+func (b BonusDeferral) InstallmentPeriod(n int) time.Time {
+	return monthStart(b.Start).AddDate(0, (n-1)*b.interval(), 0)
+}
+
+// Elements 生成各期发放的计税收入项，可直接加入员工的周期性工资项目
+func (b BonusDeferral) Elements() []PayElement {
+	elements := make([]PayElement, 0, b.Installments)
+	for n := 1; n <= b.Installments; n++ {
+		period := b.InstallmentPeriod(n)
+		elements = append(elements, PayElement{
+			Code:        b.Code,
+			Name:        fmt.Sprintf("%s（第%d/%d期）", b.Name, n, b.Installments),
+			Kind:        KindEarning,
+			Amount:      b.InstallmentAmount(n),
+			Taxable:     true,
+			Start:       period,
+			Occurrences: 1,
+		})
+	}
+	return elements
+}
+
+// Paid 截至某薪资期（含）已发放的金额
+func (b BonusDeferral) Paid(asOf time.Time) Money {
+	paid := decimal.Zero
+	for n := 1; n <= b.Installments; n++ {
+		if b.InstallmentPeriod(n).After(monthStart(asOf)) {
+			break
+		}
+		paid = paid.Add(moneyToDec(b.InstallmentAmount(n)))
+	}
+	return toMoney(paid)
+}
+
+// Remaining 截至某薪资期（含）尚未发放的余额
+func (b BonusDeferral) Remaining(asOf time.Time) Money {
+	return toMoney(moneyToDec(b.Total).Sub(moneyToDec(b.Paid(asOf))))
+}
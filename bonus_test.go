@@ -0,0 +1,43 @@
+package salary
+
+import "testing"
+
+func TestBonusDeferral(t *testing.T) {
+	bonus := BonusDeferral{
+		Code: "BONUS2023", Name: "2023年度年终奖", Total: toMoney(cenToDec(1000000)),
+		Start: day("2024-03-15"), Installments: 3, IntervalMonths: 3,
+	}
+	// 尾差计入最后一期
+	assertMoney(t, "installment 1", bonus.InstallmentAmount(1), "333333.33")
+	assertMoney(t, "installment 3", bonus.InstallmentAmount(3), "333333.34")
+	assertMoney(t, "out of range", bonus.InstallmentAmount(4), "0")
+	if !bonus.InstallmentPeriod(3).Equal(day("2024-09-01")) {
+		t.Errorf("third period = %v", bonus.InstallmentPeriod(3))
+	}
+
+	assertMoney(t, "paid before start", bonus.Paid(day("2024-02-01")), "0")
+	assertMoney(t, "paid in May", bonus.Paid(day("2024-05-01")), "333333.33")
+	assertMoney(t, "remaining in June", bonus.Remaining(day("2024-06-01")), "333333.34")
+	assertMoney(t, "remaining at end", bonus.Remaining(day("2024-12-01")), "0")
+
+	elements := bonus.Elements()
+	if len(elements) != 3 || elements[1].Name != "2023年度年终奖（第2/3期）" || !elements[1].Taxable {
+		t.Fatalf("elements = %+v", elements)
+	}
+	active := 0
+	for _, e := range elements {
+		if e.ActiveIn(day("2024-06-01")) {
+			active++
+		}
+	}
+	if active != 1 {
+		t.Errorf("active in June = %d, want 1", active)
+	}
+}
+
+func TestBonusDeferralMonthlyDefault(t *testing.T) {
+	bonus := BonusDeferral{Total: toMoney(cenToDec(60000)), Start: day("2024-01-01"), Installments: 2}
+	if !bonus.InstallmentPeriod(2).Equal(day("2024-02-01")) {
+		t.Errorf("second period = %v", bonus.InstallmentPeriod(2))
+	}
+}
@@ -0,0 +1,118 @@
+package salary
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Calendar 节假日日历，按年份维护法定节假日及调休安排
+// 调休休息日为放假期间除法定节假日以外的休息日（如春节假期的后几天），出勤按休息日加班计算；
+// 调休上班日为需要上班的周末
+type Calendar struct {
+	mu       sync.RWMutex
+	holidays map[string]time.Time
+	restDays map[string]bool
+	workdays map[string]bool
+	years    map[int]bool
+}
+
+// NewCalendar 创建空的节假日日历
+func NewCalendar() *Calendar {
+	return &Calendar{
+		holidays: make(map[string]time.Time),
+		restDays: make(map[string]bool),
+		workdays: make(map[string]bool),
+		years:    make(map[int]bool),
+	}
+}
+
+// dateKey 日期的字典键，忽略时分秒和时区
+func dateKey(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+// AddHoliday 登记法定节假日
+func (c *Calendar) AddHoliday(dates ...time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, d := range dates {
+		day := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, d.Location())
+		c.holidays[dateKey(day)] = day
+		c.years[day.Year()] = true
+	}
+}
+
+// AddRestDay 登记调休休息日
+func (c *Calendar) AddRestDay(dates ...time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, d := range dates {
+		c.restDays[dateKey(d)] = true
+	}
+}
+
+// AddWorkday 登记调休上班日
+func (c *Calendar) AddWorkday(dates ...time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, d := range dates {
+		c.workdays[dateKey(d)] = true
+	}
+}
+
+// DayType 判断日期类型：法定节假日优先，其次为调休上班日和调休休息日，其余按周一至周五为工作日
+// 日历为空时只区分工作日和周末
+func (c *Calendar) DayType(date time.Time) DayType {
+	weekend := date.Weekday() == time.Saturday || date.Weekday() == time.Sunday
+	if c == nil {
+		if weekend {
+			return RestDay
+		}
+		return Workday
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	key := dateKey(date)
+	_, holiday := c.holidays[key]
+	switch {
+	case holiday:
+		return Holiday
+	case c.workdays[key]:
+		return Workday
+	case c.restDays[key] || weekend:
+		return RestDay
+	default:
+		return Workday
+	}
+}
+
+// IsHoliday 判断日期是否为法定节假日
+func (c *Calendar) IsHoliday(date time.Time) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, ok := c.holidays[dateKey(date)]
+	return ok
+}
+
+// HasYear 判断日历是否已载入该年度的节假日数据
+func (c *Calendar) HasYear(year int) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.years[year]
+}
+
+// HolidaysIn 返回薪资期内的全部法定节假日，按日期排序，可直接用于 AttendancePolicy.PaidHolidays
+func (c *Calendar) HolidaysIn(period time.Time) []time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	start := monthStart(period)
+	var days []time.Time
+	for _, d := range c.holidays {
+		if sameMonth(d, start) {
+			days = append(days, d)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	return days
+}
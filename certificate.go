@@ -0,0 +1,133 @@
+package salary
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrNoIncomeRecords 员工在证明期间内没有存档的发薪结果
+var ErrNoIncomeRecords = errors.New("证明期间内没有发薪记录")
+
+// IncomeCertificate 收入证明，按存档的发薪结果汇总员工最近若干个月的收入，用于员工办理贷款、签证等
+type IncomeCertificate struct {
+	Employee     Employee  `json:"employee"`      // 员工
+	Company      string    `json:"company"`       // 开具单位
+	From         time.Time `json:"from"`          // 证明期间起始薪资期
+	To           time.Time `json:"to"`            // 证明期间截止薪资期
+	Months       int       `json:"months"`        // 证明期间内有发薪记录的月数
+	TotalGross   Money     `json:"total_gross"`   // 期间税前收入合计（分）
+	AverageGross Money     `json:"average_gross"` // 月均税前收入（分）
+	AverageNet   Money     `json:"average_net"`   // 月均实发收入（分）
+	IssuedAt     time.Time `json:"issued_at"`     // 开具日期
+}
+
+// GenerateIncomeCertificate 根据存档的发薪结果生成员工收入证明，不重新计算
+// 证明期间为截至 asOf 所在月份的最近 months 个月，月均收入按期间内实际有发薪记录的月数计算
+// employeeID: 工号
+// months: 证明的月数
+// company: 开具单位名称
+// asOf: 开具日期
+func (s *MemoryStore) GenerateIncomeCertificate(employeeID string, months int, company string, asOf time.Time) (IncomeCertificate, error) {
+	if months <= 0 {
+		return IncomeCertificate{}, fmt.Errorf("证明月数必须大于0: %d", months)
+	}
+	to := monthStart(asOf)
+	from := to.AddDate(0, 1-months, 0)
+	cert := IncomeCertificate{Company: company, From: from, To: to, IssuedAt: dateOnly(asOf)}
+
+	// 同一薪资期有多个批次时取最后保存的批次
+	byPeriod := make(map[string]EmployeeResult)
+	for _, r := range s.EmployeeResults(employeeID) {
+		if r.Period.Before(from) || r.Period.After(to) || r.Status != PeriodActive {
+			continue
+		}
+		byPeriod[r.Period.Format("200601")] = r
+		cert.Employee = r.Employee
+	}
+	if len(byPeriod) == 0 {
+		return cert, fmt.Errorf("%w: 员工 %s，%s至%s", ErrNoIncomeRecords, employeeID, from.Format("2006-01"), to.Format("2006-01"))
+	}
+
+	gross, net := decimal.Zero, decimal.Zero
+	for _, r := range byPeriod {
+		gross = gross.Add(moneyToDec(r.GrossSalary))
+		net = net.Add(moneyToDec(r.NetSalary))
+	}
+	n := decimal.NewFromInt(int64(len(byPeriod)))
+	cert.Months = len(byPeriod)
+	cert.TotalGross = toMoney(gross)
+	cert.AverageGross = toMoney(gross.Div(n).Round(2))
+	cert.AverageNet = toMoney(net.Div(n).Round(2))
+	return cert, nil
+}
+
+// Paragraphs 返回证明正文，按段落排列
+func (c IncomeCertificate) Paragraphs() []string {
+	hire := ""
+	if !c.Employee.HireDate.IsZero() {
+		hire = fmt.Sprintf("，自%s起在我单位工作", c.Employee.HireDate.Format("2006年1月2日"))
+	}
+	dept := ""
+	if c.Employee.Department != "" {
+		dept = "，现任职于" + c.Employee.Department
+	}
+	return []string{
+		fmt.Sprintf("兹证明%s（工号：%s）系我单位员工%s%s。", c.Employee.Name, c.Employee.ID, hire, dept),
+		fmt.Sprintf("该员工%s至%s期间共发放工资%d个月，税前收入合计人民币%s元，月均税前收入人民币%s元，月均实发收入人民币%s元。",
+			c.From.Format("2006年1月"), c.To.Format("2006年1月"), c.Months,
+			FormatMoneyCenToYuan(c.TotalGross), FormatMoneyCenToYuan(c.AverageGross), FormatMoneyCenToYuan(c.AverageNet)),
+		"特此证明。",
+	}
+}
+
+// WritePDF 输出PDF格式的收入证明，落款处留出单位盖章位置
+func (c IncomeCertificate) WritePDF(w io.Writer) error {
+	const left, right = 72.0, pdfPageWidth - 72
+	var page pdfPage
+	y := pdfPageHeight - 100
+	page.Texts = append(page.Texts, pdfText{X: pdfPageWidth/2 - pdfTextWidth("收入证明", 20)/2, Y: y, Size: 20, Text: "收入证明"})
+	y -= 50
+	// 正文按每行字数折行，首行缩进两个字
+	width := float64(right - left)
+	perLine := int(width / 12)
+	for _, para := range c.Paragraphs() {
+		runes := []rune("　　" + para)
+		for len(runes) > 0 {
+			n := min(perLine, len(runes))
+			page.Texts = append(page.Texts, pdfText{X: left, Y: y, Size: 12, Text: string(runes[:n])})
+			runes = runes[n:]
+			y -= 24
+		}
+	}
+	y -= 60
+	page.Texts = append(page.Texts,
+		pdfText{X: right, Y: y, Size: 12, Text: c.Company + "（盖章）", Right: true},
+		pdfText{X: right, Y: y - 24, Size: 12, Text: c.IssuedAt.Format("2006年1月2日"), Right: true})
+	return writePDF(w, []pdfPage{page})
+}
+
+// handleIncomeCertificate 开具收入证明PDF：GET /employees/{id}/income-certificate?months=6&company=...
+func (s *Server) handleIncomeCertificate(w http.ResponseWriter, r *http.Request) {
+	months := 6
+	if v := r.URL.Query().Get("months"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			writeError(w, fmt.Errorf("证明月数格式错误: %w", err))
+			return
+		}
+		months = n
+	}
+	cert, err := s.store.GenerateIncomeCertificate(r.PathValue("id"), months, r.URL.Query().Get("company"), time.Now())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/pdf")
+	cert.WritePDF(w)
+}
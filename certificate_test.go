@@ -0,0 +1,39 @@
+package salary
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerateIncomeCertificate(t *testing.T) {
+	through := time.Date(2024, 6, 1, 0, 0, 0, 0, time.Local)
+	store := NewSandboxStore(SyntheticOptions{Headcount: 2, Seed: 11}, through, 4)
+	id := store.Employees()[0].Employee.ID
+
+	cert, err := store.GenerateIncomeCertificate(id, 3, "示例公司", time.Date(2024, 6, 20, 0, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cert.Months != 3 || !cert.From.Equal(through.AddDate(0, -2, 0)) {
+		t.Errorf("months = %d, from = %s", cert.Months, cert.From.Format("2006-01"))
+	}
+	var gross Money
+	for _, r := range store.EmployeeResults(id)[1:] {
+		gross = toMoney(moneyToDec(gross).Add(moneyToDec(r.GrossSalary)))
+	}
+	assertMoney(t, "TotalGross", cert.TotalGross, moneyToDec(gross).String())
+	if !strings.Contains(cert.Paragraphs()[1], FormatMoneyCenToYuan(cert.AverageGross)) {
+		t.Errorf("certificate text lacks average income: %s", cert.Paragraphs()[1])
+	}
+	var buf bytes.Buffer
+	if err := cert.WritePDF(&buf); err != nil || !bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")) {
+		t.Errorf("WritePDF: %v", err)
+	}
+
+	if _, err := store.GenerateIncomeCertificate(id, 3, "示例公司", time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local)); !errors.Is(err, ErrNoIncomeRecords) {
+		t.Errorf("error = %v, want ErrNoIncomeRecords", err)
+	}
+}
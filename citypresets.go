@@ -0,0 +1,162 @@
+package salary
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrCityPresetMissing 城市未收录费率预设，或查询日期早于最早的预设
+var ErrCityPresetMissing = errors.New("城市未收录社保公积金费率预设")
+
+// CityPreset 城市社保公积金费率预设，包含个人费率、单位费率和缴费基数，按生效日期选用
+type CityPreset struct {
+	Policy          RegionPolicy  `json:"policy"`            // 个人费率、社保缴费基数上下限和生效日期
+	Employer        EmployerRates `json:"employer"`          // 单位费率，工伤保险按最低档行业费率
+	HousingFundBase BaseLimits    `json:"housing_fund_base"` // 公积金缴存基数上下限
+}
+
+// cityPresets 已注册的城市预设，按城市代码索引，同一城市按生效日期升序
+var cityPresets = struct {
+	sync.RWMutex
+	items map[string][]CityPreset
+}{items: make(map[string][]CityPreset)}
+
+// RegisterCityPreset 注册城市预设，同一城市同一生效日期的预设被替换
+// 当地公布新的缴费基数或费率后注册新预设即可，历史薪资期仍按原预设计算
+func RegisterCityPreset(preset CityPreset) error {
+	if preset.Policy.City == "" || preset.Policy.EffectiveFrom.IsZero() {
+		return errors.New("城市预设须指定城市代码和生效日期")
+	}
+	if err := preset.Policy.Validate(); err != nil {
+		return err
+	}
+	cityPresets.Lock()
+	defer cityPresets.Unlock()
+	list := cityPresets.items[preset.Policy.City]
+	for i, p := range list {
+		if p.Policy.EffectiveFrom.Equal(preset.Policy.EffectiveFrom) {
+			list[i] = preset
+			return nil
+		}
+	}
+	list = append(list, preset)
+	sort.Slice(list, func(i, j int) bool { return list[i].Policy.EffectiveFrom.Before(list[j].Policy.EffectiveFrom) })
+	cityPresets.items[preset.Policy.City] = list
+	return nil
+}
+
+// CityPresetFor 查询城市在指定日期生效的预设
+// city: 城市代码，如 shanghai
+// at: 查询日期
+// 返回值: 生效日期不晚于 at 的最近一版预设
+func CityPresetFor(city string, at time.Time) (CityPreset, error) {
+	cityPresets.RLock()
+	defer cityPresets.RUnlock()
+	list := cityPresets.items[city]
+	for i := len(list) - 1; i >= 0; i-- {
+		if !list[i].Policy.EffectiveFrom.After(at) {
+			return list[i], nil
+		}
+	}
+	return CityPreset{}, fmt.Errorf("%w: %s（%s）", ErrCityPresetMissing, city, at.Format("2006-01-02"))
+}
+
+// PresetCities 返回已注册预设的城市代码，按字母排序
+func PresetCities() []string {
+	cityPresets.RLock()
+	defer cityPresets.RUnlock()
+	cities := make([]string, 0, len(cityPresets.items))
+	for city := range cityPresets.items {
+		cities = append(cities, city)
+	}
+	sort.Strings(cities)
+	return cities
+}
+
+// CityPolicies 返回全部预设城市在指定日期生效的政策，可直接用作 PayrollRun.Regions
+func CityPolicies(at time.Time) map[string]RegionPolicy {
+	policies := make(map[string]RegionPolicy)
+	for _, city := range PresetCities() {
+		if preset, err := CityPresetFor(city, at); err == nil {
+			policies[city] = preset.Policy
+		}
+	}
+	return policies
+}
+
+// NewConfigForCity 按城市预设生成薪资配置
+// 标准工时按每月21.75天、每天8小时计算，加班倍数为法定的1.5、2、3倍，基本工资需另行设置
+// city: 城市代码
+// at: 适用日期，选用此日期生效的预设
+func NewConfigForCity(city string, at time.Time) (PayrollConfig, error) {
+	preset, err := CityPresetFor(city, at)
+	if err != nil {
+		return PayrollConfig{}, err
+	}
+	config := PayrollConfig{
+		FullMonthHours:      toMoney(decimal.NewFromInt(174)),
+		OvertimeWeekdayRate: decimal.RequireFromString("1.5"),
+		OvertimeWeekendRate: decimal.NewFromInt(2),
+		OvertimeHolidayRate: decimal.NewFromInt(3),
+		HousingFundBaseCap:  preset.HousingFundBase.Ceiling,
+		HousingFundBase:     preset.HousingFundBase,
+		Employer:            preset.Employer,
+	}
+	return preset.Policy.Apply(config), nil
+}
+
+// builtinCityPresets 内置的主要城市预设（金额单位为元），数据取自各地公布的缴费基数和费率，
+// 实际执行以当地最新公告为准，公布新标准后可通过 RegisterCityPreset 补充
+var builtinCityPresets = []struct {
+	city                                 string
+	effective                            string
+	pension, medical, unemployment, fund string // 个人费率
+	ePension, eMedical, eUnemployment    string // 单位费率
+	eInjury, eMaternity, eFund           string
+	baseFloor, baseCeiling               int64 // 社保缴费基数上下限
+	fundFloor, fundCeiling               int64 // 公积金缴存基数上下限
+}{
+	{"beijing", "2023-07-01", "0.08", "0.02", "0.005", "0.12", "0.16", "0.09", "0.005", "0.002", "0.008", "0.12", 6326, 33891, 2420, 33891},
+	{"beijing", "2024-07-01", "0.08", "0.02", "0.005", "0.12", "0.16", "0.09", "0.005", "0.002", "0.008", "0.12", 6821, 35283, 2540, 35283},
+	{"shanghai", "2023-07-01", "0.08", "0.02", "0.005", "0.07", "0.16", "0.09", "0.005", "0.0016", "0.01", "0.07", 7310, 36549, 2590, 36549},
+	{"shanghai", "2024-07-01", "0.08", "0.02", "0.005", "0.07", "0.16", "0.09", "0.005", "0.0016", "0.01", "0.07", 7384, 36921, 2690, 36921},
+	{"shenzhen", "2024-07-01", "0.08", "0.02", "0.003", "0.05", "0.16", "0.05", "0.007", "0.0014", "0.0045", "0.05", 4492, 27501, 2360, 41190},
+	{"guangzhou", "2024-07-01", "0.08", "0.02", "0.002", "0.05", "0.16", "0.055", "0.0032", "0.002", "0.0085", "0.05", 5284, 26421, 2300, 39270},
+	{"hangzhou", "2024-01-01", "0.08", "0.02", "0.005", "0.12", "0.16", "0.095", "0.005", "0.002", "0", "0.12", 4462, 24930, 2490, 39858},
+}
+
+// init 注册内置城市预设
+func init() {
+	yuan := func(v int64) Money { return toMoney(cenToDec(v * 100)) }
+	for _, b := range builtinCityPresets {
+		effective, _ := time.ParseInLocation("2006-01-02", b.effective, time.Local)
+		preset := CityPreset{
+			Policy: RegionPolicy{
+				City:             b.city,
+				EffectiveFrom:    effective,
+				PensionRate:      decimal.RequireFromString(b.pension),
+				MedicalRate:      decimal.RequireFromString(b.medical),
+				UnemploymentRate: decimal.RequireFromString(b.unemployment),
+				HousingFundRate:  decimal.RequireFromString(b.fund),
+				Base:             BaseLimits{Floor: yuan(b.baseFloor), Ceiling: yuan(b.baseCeiling)},
+			},
+			Employer: EmployerRates{
+				Pension:      decimal.RequireFromString(b.ePension),
+				Medical:      decimal.RequireFromString(b.eMedical),
+				Unemployment: decimal.RequireFromString(b.eUnemployment),
+				WorkInjury:   decimal.RequireFromString(b.eInjury),
+				Maternity:    decimal.RequireFromString(b.eMaternity),
+				HousingFund:  decimal.RequireFromString(b.eFund),
+			},
+			HousingFundBase: BaseLimits{Floor: yuan(b.fundFloor), Ceiling: yuan(b.fundCeiling)},
+		}
+		if err := RegisterCityPreset(preset); err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package salary
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewConfigForCity(t *testing.T) {
+	config, err := NewConfigForCity("shanghai", day("2024-09-01"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.HousingFundRate.String() != "0.07" || config.Employer.Pension.String() != "0.16" {
+		t.Errorf("rates = %s/%s, want 0.07/0.16", config.HousingFundRate, config.Employer.Pension)
+	}
+	assertMoney(t, "pension floor", config.PensionBase.Floor, "738400")
+	assertMoney(t, "housing fund ceiling", config.HousingFundBase.Ceiling, "3692100")
+
+	// 新基数公布前仍使用上一年度的预设
+	earlier, err := NewConfigForCity("shanghai", day("2024-06-30"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertMoney(t, "2023 pension floor", earlier.PensionBase.Floor, "731000")
+
+	if _, err := NewConfigForCity("lhasa", day("2024-09-01")); !errors.Is(err, ErrCityPresetMissing) {
+		t.Errorf("unknown city err = %v, want ErrCityPresetMissing", err)
+	}
+	if _, err := NewConfigForCity("beijing", day("2020-01-01")); !errors.Is(err, ErrCityPresetMissing) {
+		t.Errorf("date before presets err = %v, want ErrCityPresetMissing", err)
+	}
+}
+
+func TestRegisterCityPresetReplacesSameDate(t *testing.T) {
+	preset, err := CityPresetFor("hangzhou", day("2024-03-01"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	updated := preset
+	updated.Policy.Base.Floor = toMoney(cenToDec(450000))
+	if err := RegisterCityPreset(updated); err != nil {
+		t.Fatal(err)
+	}
+	defer RegisterCityPreset(preset)
+
+	got, _ := CityPresetFor("hangzhou", day("2024-03-01"))
+	assertMoney(t, "floor", got.Policy.Base.Floor, "450000")
+	if policies := CityPolicies(day("2024-09-01")); len(policies) != len(PresetCities()) {
+		t.Errorf("CityPolicies = %d cities, want %d", len(policies), len(PresetCities()))
+	}
+}
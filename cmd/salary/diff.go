@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"salary"
+
+	"github.com/shopspring/decimal"
+)
+
+// runDiff 比对两份工资结果导出：salary diff [-tolerance 分] runA.json runB.json
+// 指定 -mapping 时第二个文件为原薪资供应商的CSV，按核对配置映射列名并输出迁移记分卡：
+// salary diff -mapping recon.yaml engine.json vendor.csv
+// 返回值: 退出码，0 表示一致（或记分卡签核通过），1 表示存在差异，2 表示参数或文件错误
+func runDiff(args []string) int {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	tolerance := fs.Int64("tolerance", 0, "允许的差额（分），不超过该值视为一致；指定 -mapping 时以核对配置为准")
+	mapping := fs.String("mapping", "", "供应商文件核对配置（JSON或YAML），包含列映射、容差和重大差异阈值")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "用法: salary diff [-tolerance 分] runA.json runB.json")
+		fmt.Fprintln(fs.Output(), "      salary diff -mapping recon.yaml engine.json vendor.csv")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+
+	var config salary.ReconcileConfig
+	if *mapping != "" {
+		var err error
+		if config, err = salary.LoadReconcileConfig(*mapping); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+	}
+
+	var exports [2][]salary.ExportRecord
+	for i, path := range fs.Args() {
+		f, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		if i == 1 && *mapping != "" {
+			exports[i], err = salary.ImportVendorCSV(f, config)
+		} else {
+			exports[i], err = salary.ReadResultExport(f)
+		}
+		f.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+			return 2
+		}
+	}
+
+	fmt.Printf("A: %s\nB: %s\n", fs.Arg(0), fs.Arg(1))
+	if *mapping != "" {
+		card := salary.Reconcile(exports[0], exports[1], config)
+		if err := card.WriteReport(os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		if !card.SignOff() {
+			return 1
+		}
+		return 0
+	}
+
+	diff := salary.DiffResults(exports[0], exports[1], salary.Money(decimal.NewFromInt(*tolerance)))
+	if err := diff.WriteReport(os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if !diff.Empty() {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,145 @@
+// Command salary 薪资计算命令行：默认打印示例员工的薪资明细，-serve 以服务模式运行HTTP接口（-replay-log 启用重放日志），
+// salary diff 比对两份工资结果导出
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"salary"
+
+	"github.com/shopspring/decimal"
+)
+
+func main() {
+	serveAddr := flag.String("serve", "", "以服务模式运行并监听该地址，如 :8080")
+	sandbox := flag.Int("sandbox", 0, "服务模式下以沙箱租户运行，生成该人数的合成员工和最近12个月的发薪批次")
+	seed := flag.Uint64("seed", 1, "沙箱合成数据的随机种子")
+	configPath := flag.String("config", "", "薪资配置文件（JSON或YAML），省略时使用内置示例配置")
+	replayPath := flag.String("replay-log", "", "服务模式下的重放日志文件：启动时按日志重放重建数据，之后的变更请求先写入日志")
+	flag.Parse()
+
+	// 子命令：比对两份工资结果导出，用于切换薪资供应商时的并行核对
+	if flag.Arg(0) == "diff" {
+		os.Exit(runDiff(flag.Args()[1:]))
+	}
+
+	// 服务模式：提供健康检查等HTTP接口
+	if *serveAddr != "" {
+		store := salary.NewMemoryStore()
+		if *sandbox > 0 {
+			store = salary.NewSandboxStore(salary.SyntheticOptions{Headcount: *sandbox, Seed: *seed}, time.Now(), 12)
+			log.Printf("沙箱模式：已生成%d名合成员工", *sandbox)
+		}
+		server := salary.NewServer(store)
+		if *replayPath != "" {
+			// 沙箱数据按启动时间生成，重放日志中引用的批次编号在重启后不一定存在
+			if *sandbox > 0 {
+				log.Fatal("-sandbox 与 -replay-log 不能同时使用")
+			}
+			if err := openReplayLog(server, *replayPath); err != nil {
+				log.Fatal(err)
+			}
+		}
+		log.Fatal(http.ListenAndServe(*serveAddr, server))
+	}
+
+	// 初始化薪资配置（金额单位为分）
+	config := salary.PayrollConfig{
+		BaseSalary:          salary.Money(decimal.NewFromInt(800000)), // 8000元 = 8,00,000分
+		FullMonthHours:      salary.Money(decimal.NewFromInt(174)),    // 每月174工作小时
+		PensionRate:         decimal.RequireFromString("0.08"),        // 养老保险8%
+		MedicalRate:         decimal.RequireFromString("0.20"),        // 医疗保险20%
+		UnemploymentRate:    decimal.RequireFromString("0.05"),        // 失业保险5%
+		HousingFundRate:     decimal.RequireFromString("0.07"),        // 公积金7%
+		OvertimeWeekdayRate: decimal.RequireFromString("1.0"),         // 工作日加班1.5倍
+		OvertimeWeekendRate: decimal.RequireFromString("1.2"),         // 周末加班2倍
+		OvertimeHolidayRate: decimal.RequireFromString("3.0"),         // 节假日加班3倍
+	}
+	// 考勤记录
+	attendance := salary.AttendanceRecord{
+		WorkHours:       salary.Hours(decimal.RequireFromString("174")), // 全勤(小时)
+		OvertimeWeekday: salary.Hours(decimal.RequireFromString("1")),   // 1小时工作日加班
+		OvertimeWeekend: salary.Hours(decimal.RequireFromString("1")),   // 1小时周末加班
+		AbsenceHours:    salary.Hours(decimal.Zero),                     // 无缺勤
+	}
+
+	// 专项附加扣除（单位为分）
+	deductions := salary.SpecialDeductions{
+		ChildrenEducation:   salary.Money(decimal.Zero),              //子女教育(分)
+		ContinuingEducation: salary.Money(decimal.Zero),              //继续教育扣除金额(分)
+		HousingLoanInterest: salary.Money(decimal.NewFromInt(10000)), // 房贷利息扣除(分)
+		HousingRent:         salary.Money(decimal.Zero),              //住房租金扣除(分)
+		SupportElderly:      salary.Money(decimal.NewFromInt(20000)), // 赡养老人扣除(分)
+	}
+	// 指定配置文件时以文件中的配置替代示例配置
+	if *configPath != "" {
+		file, err := salary.LoadConfig(*configPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(file.TaxBrackets) > 0 {
+			if err := salary.SetTaxBrackets(file.TaxBrackets); err != nil {
+				log.Fatal(err)
+			}
+		}
+		config, deductions = file.Payroll, file.Deductions
+	}
+
+	// 计算薪资各项
+	grossSalary, netSalary, insuranceTax, incomeTax := salary.CalculateNetSalary(config, attendance, deductions)
+	// 计算加班工资单独显示
+	overtimePay := salary.CalculateOvertimePay(config, attendance)
+	// 打印薪资明细报表
+	fmt.Println("\n================ 梓博薪资明细报表 ================")
+	fmt.Printf("%-15s %15s\n", "项目", "金额")
+	fmt.Println("----------------------------------------")
+	fmt.Printf("%-15s %15s\n", "梓博基本工资", salary.FormatMoneyCenToYuan(config.BaseSalary))
+	fmt.Printf("%-15s %15s\n", "梓博加班工资", salary.FormatMoneyCenToYuan(overtimePay))
+	fmt.Printf("%-15s %15s\n", "梓博税前工资", salary.FormatMoneyCenToYuan(grossSalary))
+	fmt.Printf("%-15s %15s\n", "梓博社保公积金", salary.FormatMoneyCenToYuan(insuranceTax))
+	fmt.Printf("%-15s %15s\n", "梓博个人所得税", salary.FormatMoneyCenToYuan(incomeTax))
+	fmt.Println("----------------------------------------")
+	fmt.Printf("%-15s %15s\n", "梓博实发工资", salary.FormatMoneyCenToYuan(netSalary))
+	/*fmt.Println("========================================")
+
+	// 打印详细说明
+	fmt.Println("\n计算说明：")
+	fmt.Println("1. 基本工资 = 合同约定月薪")
+	fmt.Println("2. 加班工资 = ∑(加班小时 × 小时工资 × 加班倍数)")
+	fmt.Println("3. 税前工资 = 基本工资 + 加班工资")
+	fmt.Println("4. 社保公积金 = 养老保险 + 医疗保险 + 失业保险 + 住房公积金")
+	fmt.Println("5. 应纳税所得额 = 税前工资 - 社保公积金 - 5000(起征点) - 专项扣除")
+	fmt.Println("6. 个人所得税按累进税率计算")
+	fmt.Println("7. 实发工资 = 税前工资 - 社保公积金 - 个人所得税")*/
+}
+
+// openReplayLog 打开重放日志，按已有记录重放重建数据后，为服务启用重放日志
+// 逐条报告重放失败的记录；原请求本身失败的记录重放时同样失败，全部记录均失败时视为日志与服务不匹配，拒绝启动
+func openReplayLog(server *salary.Server, path string) error {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	replay, entries, err := salary.OpenReplayLog(file)
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for i, result := range salary.Replay(entries, server) {
+		if result.Failed() {
+			failed++
+			log.Printf("重放日志第%d条 %s %s 失败: HTTP %d", result.Seq, entries[i].Method, entries[i].URI, result.Status)
+		}
+	}
+	if failed > 0 && failed == len(entries) {
+		return fmt.Errorf("重放日志%d条记录全部重放失败", failed)
+	}
+	server.SetReplayLog(replay)
+	log.Printf("已按重放日志重放%d条变更记录，%d条失败", len(entries), failed)
+	return nil
+}
@@ -0,0 +1,236 @@
+package salary
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFile 从配置文件加载的薪资配置、税率表和专项附加扣除
+type ConfigFile struct {
+	Payroll     PayrollConfig     // 薪资配置
+	TaxBrackets []TaxBracket      // 税率表，为空表示未配置，沿用当前生效的税率表；需要时通过 SetTaxBrackets 生效
+	Deductions  SpecialDeductions // 专项附加扣除
+}
+
+// configValue 配置文件中的数值，JSON和YAML中可写为数字或字符串，如 8000、"8,000元"、"8%"
+type configValue string
+
+// UnmarshalJSON 接受JSON字符串或数字
+func (v *configValue) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*v = configValue(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("应为数字或字符串: %s", data)
+	}
+	*v = configValue(n)
+	return nil
+}
+
+// UnmarshalYAML 接受YAML标量
+func (v *configValue) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.ScalarNode {
+		return fmt.Errorf("第%d行: 应为数字或字符串", node.Line)
+	}
+	*v = configValue(node.Value)
+	return nil
+}
+
+// rawConfig 配置文件结构，金额单位为元，比例可写为百分数或小数
+type rawConfig struct {
+	BaseSalary         configValue `json:"base_salary" yaml:"base_salary"`                     // 基本工资（元）
+	FullMonthHours     configValue `json:"full_month_hours" yaml:"full_month_hours"`           // 每月标准工时
+	HousingFundBaseCap configValue `json:"housing_fund_base_cap" yaml:"housing_fund_base_cap"` // 公积金免税基数上限（元）
+	Rates              struct {
+		Pension      configValue `json:"pension" yaml:"pension"`
+		Medical      configValue `json:"medical" yaml:"medical"`
+		Unemployment configValue `json:"unemployment" yaml:"unemployment"`
+		HousingFund  configValue `json:"housing_fund" yaml:"housing_fund"`
+	} `json:"rates" yaml:"rates"` // 社保公积金个人费率
+	Overtime struct {
+		Weekday configValue `json:"weekday" yaml:"weekday"`
+		Weekend configValue `json:"weekend" yaml:"weekend"`
+		Holiday configValue `json:"holiday" yaml:"holiday"`
+	} `json:"overtime" yaml:"overtime"` // 加班费倍数
+	Tax *struct {
+		StandardDeduction configValue `json:"standard_deduction" yaml:"standard_deduction"` // 每月减除费用（元）
+		ProRate           bool        `json:"pro_rate" yaml:"pro_rate"`                     // 入职、离职当月折算减除费用
+		Brackets          []struct {
+			Threshold configValue `json:"threshold" yaml:"threshold"` // 档次下限（元）
+			Rate      configValue `json:"rate" yaml:"rate"`
+			Deduction configValue `json:"deduction" yaml:"deduction"` // 速算扣除数（元）
+		} `json:"brackets" yaml:"brackets"`
+	} `json:"tax" yaml:"tax"` // 个税配置
+	Deductions struct {
+		ChildrenEducation   configValue `json:"children_education" yaml:"children_education"`
+		ContinuingEducation configValue `json:"continuing_education" yaml:"continuing_education"`
+		HousingLoanInterest configValue `json:"housing_loan_interest" yaml:"housing_loan_interest"`
+		HousingRent         configValue `json:"housing_rent" yaml:"housing_rent"`
+		SupportElderly      configValue `json:"support_elderly" yaml:"support_elderly"`
+	} `json:"deductions" yaml:"deductions"` // 专项附加扣除（元/月）
+}
+
+// LoadConfig 读取JSON或YAML格式的配置文件（按扩展名 .json、.yaml、.yml 识别）
+// 未知配置项、金额或比例格式错误、比例超出范围等问题会列出配置项路径，全部问题一并返回
+// 示例见仓库中的 salary.example.yaml
+func LoadConfig(path string) (*ConfigFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config, err := ParseConfig(data, strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), "."))
+	if err != nil {
+		return nil, fmt.Errorf("配置文件 %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// ParseConfig 解析配置文件内容
+// format: json、yaml 或 yml
+func ParseConfig(data []byte, format string) (*ConfigFile, error) {
+	var raw rawConfig
+	if err := decodeConfigData(data, format, &raw); err != nil {
+		return nil, err
+	}
+	return raw.build()
+}
+
+// decodeConfigData 按格式解码配置文件内容，拒绝未知配置项
+// format: json、yaml 或 yml
+func decodeConfigData(data []byte, format string, v any) error {
+	switch format {
+	case "json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(v); err != nil {
+			return fmt.Errorf("格式错误: %w", err)
+		}
+	case "yaml", "yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(v); err != nil {
+			return fmt.Errorf("格式错误: %w", err)
+		}
+	default:
+		return fmt.Errorf("不支持的配置文件格式 %q，应为 json 或 yaml", format)
+	}
+	return nil
+}
+
+// configParser 逐项解析配置值并收集错误
+type configParser struct {
+	errs []error
+}
+
+// money 解析金额（元），未填写时为0
+func (p *configParser) money(path string, v configValue) Money {
+	if v == "" {
+		return toMoney(decimal.Zero)
+	}
+	m, err := ParseMoney(string(v))
+	if err == nil && moneyToDec(m).IsNegative() {
+		err = errors.New("金额不能为负数")
+	}
+	if err != nil {
+		p.errs = append(p.errs, fmt.Errorf("%s: %w", path, err))
+	}
+	return m
+}
+
+// rate 解析[0,1]区间内的比例，必填
+func (p *configParser) rate(path string, v configValue) decimal.Decimal {
+	if v == "" {
+		p.errs = append(p.errs, fmt.Errorf("%s: 缺少比例", path))
+		return decimal.Zero
+	}
+	r, err := ParseRate(string(v))
+	switch {
+	case err != nil:
+		p.errs = append(p.errs, fmt.Errorf("%s: %w", path, err))
+	case r.GreaterThan(decimal.NewFromInt(1)):
+		// ParseRate 接受超过100%的百分比（如加班倍数），配置中的费率不能超过1
+		p.errs = append(p.errs, fmt.Errorf("%s: 比例 %s 超出[0,1]范围", path, v))
+	}
+	return r
+}
+
+// number 解析不小于 min 的数值，如工时和加班倍数，必填
+func (p *configParser) number(path string, v configValue, min decimal.Decimal) decimal.Decimal {
+	d, err := decimal.NewFromString(normalizeNumber(string(v)))
+	switch {
+	case v == "":
+		p.errs = append(p.errs, fmt.Errorf("%s: 缺少数值", path))
+	case err != nil:
+		p.errs = append(p.errs, fmt.Errorf("%s: 无法识别的数字 %q", path, v))
+	case d.LessThan(min):
+		p.errs = append(p.errs, fmt.Errorf("%s: %s 不能小于 %s", path, d, min))
+	}
+	return d
+}
+
+// build 校验并转换为薪资配置
+func (raw rawConfig) build() (*ConfigFile, error) {
+	var p configParser
+	one := decimal.NewFromInt(1)
+	config := &ConfigFile{
+		Payroll: PayrollConfig{
+			BaseSalary:          p.money("base_salary", raw.BaseSalary),
+			FullMonthHours:      toMoney(p.number("full_month_hours", raw.FullMonthHours, one)),
+			PensionRate:         p.rate("rates.pension", raw.Rates.Pension),
+			MedicalRate:         p.rate("rates.medical", raw.Rates.Medical),
+			UnemploymentRate:    p.rate("rates.unemployment", raw.Rates.Unemployment),
+			HousingFundRate:     p.rate("rates.housing_fund", raw.Rates.HousingFund),
+			OvertimeWeekdayRate: p.number("overtime.weekday", raw.Overtime.Weekday, one),
+			OvertimeWeekendRate: p.number("overtime.weekend", raw.Overtime.Weekend, one),
+			OvertimeHolidayRate: p.number("overtime.holiday", raw.Overtime.Holiday, one),
+			HousingFundBaseCap:  p.money("housing_fund_base_cap", raw.HousingFundBaseCap),
+		},
+		Deductions: SpecialDeductions{
+			ChildrenEducation:   p.money("deductions.children_education", raw.Deductions.ChildrenEducation),
+			ContinuingEducation: p.money("deductions.continuing_education", raw.Deductions.ContinuingEducation),
+			HousingLoanInterest: p.money("deductions.housing_loan_interest", raw.Deductions.HousingLoanInterest),
+			HousingRent:         p.money("deductions.housing_rent", raw.Deductions.HousingRent),
+			SupportElderly:      p.money("deductions.support_elderly", raw.Deductions.SupportElderly),
+		},
+	}
+	if !moneyToDec(config.Deductions.HousingLoanInterest).IsZero() && !moneyToDec(config.Deductions.HousingRent).IsZero() {
+		p.errs = append(p.errs, fmt.Errorf("deductions: %w", ErrHousingDeductionConflict))
+	}
+
+	if raw.Tax != nil {
+		tax := DefaultTaxConfig()
+		if raw.Tax.StandardDeduction != "" {
+			tax.StandardDeduction = p.money("tax.standard_deduction", raw.Tax.StandardDeduction)
+		}
+		tax.ProRate = raw.Tax.ProRate
+		config.Payroll.Tax = &tax
+		for i, b := range raw.Tax.Brackets {
+			path := fmt.Sprintf("tax.brackets[%d]", i)
+			config.TaxBrackets = append(config.TaxBrackets, TaxBracket{
+				Threshold: p.money(path+".threshold", b.Threshold),
+				Rate:      p.rate(path+".rate", b.Rate),
+				Deduction: p.money(path+".deduction", b.Deduction),
+			})
+		}
+		if len(p.errs) == 0 && len(config.TaxBrackets) > 0 {
+			if err := validateTaxBrackets(config.TaxBrackets); err != nil {
+				p.errs = append(p.errs, fmt.Errorf("tax.brackets: %w", err))
+			}
+		}
+	}
+	if len(p.errs) > 0 {
+		return nil, errors.Join(p.errs...)
+	}
+	return config, nil
+}
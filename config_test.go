@@ -0,0 +1,55 @@
+package salary
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadConfigExample(t *testing.T) {
+	config, err := LoadConfig("salary.example.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertMoney(t, "BaseSalary", config.Payroll.BaseSalary, "800000")
+	assertMoney(t, "SupportElderly", config.Deductions.SupportElderly, "200000")
+	if got := config.Payroll.UnemploymentRate.String(); got != "0.005" {
+		t.Errorf("UnemploymentRate = %s, want 0.005", got)
+	}
+	if len(config.TaxBrackets) != 7 {
+		t.Fatalf("brackets = %d, want 7", len(config.TaxBrackets))
+	}
+	assertMoney(t, "bracket 2 deduction", config.TaxBrackets[1].Deduction, "21000")
+}
+
+func TestParseConfigJSON(t *testing.T) {
+	data := `{"base_salary": "8,000元", "full_month_hours": 174,
+		"rates": {"pension": 0.08, "medical": "2%", "unemployment": "0.5%", "housing_fund": "7%"},
+		"overtime": {"weekday": 1.5, "weekend": 2, "holiday": 3}}`
+	config, err := ParseConfig([]byte(data), "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Payroll.Tax != nil || config.TaxBrackets != nil {
+		t.Error("tax settings should be unset when omitted")
+	}
+}
+
+func TestParseConfigErrors(t *testing.T) {
+	tests := []struct {
+		name, format, data, want string
+	}{
+		{"unknown key", "yaml", "base_salary: 8000\nbonus_rate: 1\n", "bonus_rate"},
+		{"unknown key json", "json", `{"rates": {"pensoin": 0.08}}`, "pensoin"},
+		{"rate out of range", "yaml", "rates:\n  pension: 8\n", "rates.pension"},
+		{"percent rate out of range", "yaml", "rates:\n  pension: 150%\n", "rates.pension"},
+		{"bracket rate out of range", "yaml", "tax:\n  brackets:\n    - threshold: 0\n      rate: 120%\n", "tax.brackets[0].rate"},
+		{"overtime below 1", "yaml", "overtime:\n  weekday: 0.5\n", "overtime.weekday"},
+		{"unsupported format", "toml", "", "不支持"},
+	}
+	for _, tt := range tests {
+		_, err := ParseConfig([]byte(tt.data), tt.format)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error = %v, want mention of %q", tt.name, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package salary
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrNoConfigVersion 薪资期没有生效的配置版本
+var ErrNoConfigVersion = errors.New("薪资期没有生效的薪资配置版本")
+
+// ErrConfigVersionOverlap 配置版本的生效期间与已有版本重叠
+var ErrConfigVersionOverlap = errors.New("配置版本生效期间重叠")
+
+// ConfigVersion 一段生效期间内的公司级薪资配置：费率、缴费基数上下限、税率表等
+type ConfigVersion struct {
+	EffectiveFrom time.Time     // 生效日期
+	EffectiveTo   time.Time     // 失效日期（含当日），零值表示长期有效
+	Config        PayrollConfig // 费率和缴费基数上下限，BaseSalary 不使用，计算时保留员工自己的基本工资
+	TaxBrackets   []TaxBracket  // 本期间适用的税率表，为空表示使用当前生效的税率表
+	Note          string        // 版本说明，如“2024年7月起上海社保基数调整”
+}
+
+// covers 版本是否覆盖该日期
+func (v ConfigVersion) covers(t time.Time) bool {
+	return !t.Before(v.EffectiveFrom) && (v.EffectiveTo.IsZero() || !t.After(v.EffectiveTo))
+}
+
+// overlaps 两个版本的生效期间是否重叠
+func (v ConfigVersion) overlaps(o ConfigVersion) bool {
+	return (v.EffectiveTo.IsZero() || !v.EffectiveTo.Before(o.EffectiveFrom)) &&
+		(o.EffectiveTo.IsZero() || !o.EffectiveTo.Before(v.EffectiveFrom))
+}
+
+// ConfigStore 按生效日期管理的薪资配置版本
+// 计算时按薪资期选用版本，重新计算往期时自动使用当时的费率和税率表
+type ConfigStore struct {
+	mu       sync.RWMutex
+	versions []ConfigVersion // 按生效日期升序
+}
+
+// NewConfigStore 创建空的配置版本库
+func NewConfigStore() *ConfigStore {
+	return &ConfigStore{}
+}
+
+// Add 新增配置版本
+// 新版本晚于长期有效的最新版本时，最新版本自动在新版本生效前一日失效；其他与已有版本重叠的情况返回 ErrConfigVersionOverlap
+func (s *ConfigStore) Add(v ConfigVersion) error {
+	if v.EffectiveFrom.IsZero() {
+		return errors.New("配置版本须指定生效日期")
+	}
+	if !v.EffectiveTo.IsZero() && v.EffectiveTo.Before(v.EffectiveFrom) {
+		return fmt.Errorf("配置版本失效日期 %s 早于生效日期 %s", v.EffectiveTo.Format("2006-01-02"), v.EffectiveFrom.Format("2006-01-02"))
+	}
+	if len(v.TaxBrackets) > 0 {
+		if err := validateTaxBrackets(v.TaxBrackets); err != nil {
+			return err
+		}
+		v.TaxBrackets = append([]TaxBracket(nil), v.TaxBrackets...)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	versions := append([]ConfigVersion(nil), s.versions...)
+	if n := len(versions); n > 0 {
+		last := &versions[n-1]
+		if last.EffectiveTo.IsZero() && last.EffectiveFrom.Before(v.EffectiveFrom) {
+			last.EffectiveTo = v.EffectiveFrom.AddDate(0, 0, -1)
+		}
+	}
+	for _, existing := range versions {
+		if existing.overlaps(v) {
+			return fmt.Errorf("%w: %s 起的版本与 %s 起的版本", ErrConfigVersionOverlap, v.EffectiveFrom.Format("2006-01-02"), existing.EffectiveFrom.Format("2006-01-02"))
+		}
+	}
+	versions = append(versions, v)
+	sort.Slice(versions, func(i, j int) bool { return versions[i].EffectiveFrom.Before(versions[j].EffectiveFrom) })
+	s.versions = versions
+	return nil
+}
+
+// Versions 返回全部配置版本，按生效日期升序
+func (s *ConfigStore) Versions() []ConfigVersion {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]ConfigVersion(nil), s.versions...)
+}
+
+// At 查询薪资期适用的配置版本，以薪资期首日判断
+func (s *ConfigStore) At(period time.Time) (ConfigVersion, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	day := monthStart(period)
+	for _, v := range s.versions {
+		if v.covers(day) {
+			return v, nil
+		}
+	}
+	return ConfigVersion{}, fmt.Errorf("%w: %s", ErrNoConfigVersion, day.Format("2006-01"))
+}
+
+// ConfigFor 返回薪资期适用的薪资配置：使用版本中的费率、缴费基数上下限和税率表，保留员工自己的基本工资
+// period: 薪资期
+// employee: 员工的薪资配置
+func (s *ConfigStore) ConfigFor(period time.Time, employee PayrollConfig) (PayrollConfig, error) {
+	v, err := s.At(period)
+	if err != nil {
+		return employee, err
+	}
+	config := v.Config
+	config.BaseSalary = employee.BaseSalary
+	if len(v.TaxBrackets) > 0 {
+		tax := config.taxConfig()
+		tax.Brackets = v.TaxBrackets
+		config.Tax = &tax
+	}
+	return config, nil
+}
+
+// TaxConfigFor 返回薪资期适用的个税配置：版本的减除费用和税率表，
+// 供全年一次性奖金、一次性补偿收入等不经过批次计算的个税计算使用
+// period: 薪资期或收入发放日期
+func (s *ConfigStore) TaxConfigFor(period time.Time) (TaxConfig, error) {
+	v, err := s.At(period)
+	if err != nil {
+		return DefaultTaxConfig(), err
+	}
+	tax := v.Config.taxConfig()
+	if len(v.TaxBrackets) > 0 {
+		tax.Brackets = v.TaxBrackets
+	}
+	return tax, nil
+}
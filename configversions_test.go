@@ -0,0 +1,114 @@
+package salary
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestConfigStorePicksVersionByPeriod(t *testing.T) {
+	store := NewConfigStore()
+	old := testConfig()
+	old.BaseSalary = toMoney(decimal.Zero)
+	if err := store.Add(ConfigVersion{EffectiveFrom: day("2023-01-01"), Config: old}); err != nil {
+		t.Fatal(err)
+	}
+	raised := old
+	raised.HousingFundRate = decimal.RequireFromString("0.12")
+	// 2024年起税率表第一档改为2%
+	brackets := DefaultTaxBrackets()
+	brackets[0].Rate = decimal.RequireFromString("0.02")
+	if err := store.Add(ConfigVersion{EffectiveFrom: day("2024-07-01"), Config: raised, TaxBrackets: brackets}); err != nil {
+		t.Fatal(err)
+	}
+	if got := store.Versions()[0].EffectiveTo; !got.Equal(day("2024-06-30")) {
+		t.Errorf("previous version EffectiveTo = %s, want 2024-06-30", got.Format("2006-01-02"))
+	}
+
+	input := EmployeeInput{Employee: Employee{ID: "E001"}, Config: testConfig(), Attendance: AttendanceRecord{WorkHours: hours("174")}}
+	for _, tc := range []struct {
+		period, housingFund, tax string
+	}{
+		// 8000 - 840(社保) - 560(公积金) - 5000 = 1600，按3%为48元
+		{"2024-06-01", "56000", "4800"},
+		// 8000 - 840 - 960 - 5000 = 1200，按2%为24元
+		{"2024-07-01", "96000", "2400"},
+	} {
+		run := PayrollRun{Period: day(tc.period), Configs: store, Inputs: []EmployeeInput{input}}
+		result := run.Calculate()
+		if len(result.Errors) > 0 {
+			t.Fatalf("%s: %v", tc.period, result.Errors)
+		}
+		employee := result.Employees[0]
+		assertMoney(t, tc.period+" housing fund", employee.HousingFund, tc.housingFund)
+		assertMoney(t, tc.period+" tax", employee.IncomeTax, tc.tax)
+	}
+
+	run := PayrollRun{Period: day("2022-12-01"), Configs: store, Inputs: []EmployeeInput{input}}
+	if result := run.Calculate(); len(result.Errors) != 1 || !errors.Is(result.Errors[0], ErrNoConfigVersion) {
+		t.Errorf("period before first version: errors = %v", result.Errors)
+	}
+}
+
+func TestConfigStoreRejectsOverlap(t *testing.T) {
+	store := NewConfigStore()
+	if err := store.Add(ConfigVersion{EffectiveFrom: day("2024-01-01"), EffectiveTo: day("2024-12-31")}); err != nil {
+		t.Fatal(err)
+	}
+	err := store.Add(ConfigVersion{EffectiveFrom: day("2024-06-01"), EffectiveTo: day("2025-05-31")})
+	if !errors.Is(err, ErrConfigVersionOverlap) {
+		t.Errorf("err = %v, want ErrConfigVersionOverlap", err)
+	}
+	if len(store.Versions()) != 1 {
+		t.Errorf("rejected version was stored")
+	}
+}
+
+func TestConfigVersionBracketsApplyToAnnualTaxes(t *testing.T) {
+	yuan := func(v int64) Money { return toMoney(cenToDec(v * 100)) }
+	store := NewConfigStore()
+	config := testConfig()
+	config.HousingFundRate = decimal.RequireFromString("0.12")
+	brackets := DefaultTaxBrackets()
+	brackets[0].Rate = decimal.RequireFromString("0.02")
+	if err := store.Add(ConfigVersion{EffectiveFrom: day("2024-07-01"), Config: config, TaxBrackets: brackets}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 7月入职按累计预扣法：8000 - 840 - 960 - 5000 = 1200，按版本税率表2%为24元
+	input := EmployeeInput{
+		Employee:   Employee{ID: "E001", HireDate: day("2024-07-01")},
+		Config:     testConfig(),
+		Attendance: AttendanceRecord{WorkHours: hours("174")},
+		TaxState:   &TaxWithholdingState{},
+	}
+	run := PayrollRun{Period: day("2024-07-01"), Configs: store, Inputs: []EmployeeInput{input}}
+	result := run.Calculate()
+	if len(result.Errors) > 0 {
+		t.Fatal(result.Errors)
+	}
+	if result.Employees[0].TaxState == nil {
+		t.Fatal("cumulative method not used")
+	}
+	assertMoney(t, "cumulative tax", result.Employees[0].IncomeTax, "2400")
+
+	tax, err := store.TaxConfigFor(day("2024-12-31"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 36000 / 12 = 3000，适用第一档2%：720元
+	assertMoney(t, "bonus separate", CalculateAnnualBonusTax(yuan(36000), BonusTaxPolicy{Tax: &tax}), "72000")
+	assertMoney(t, "bonus merged", CalculateAnnualBonusTax(yuan(36000), BonusTaxPolicy{Method: BonusTaxMerged, Tax: &tax}), "72000")
+
+	// 补偿收入37万元，超过免税额36万元的1万元按年度第一档2%：200元
+	severance := CalculateSeverance(SeveranceInput{
+		HireDate: day("2004-01-01"), TerminationDate: day("2024-08-01"),
+		AverageWage: yuan(50000), LocalAverageWage: yuan(10000), PayInLieu: true, NoticeWage: yuan(10000), Tax: &tax,
+	})
+	assertMoney(t, "severance tax", severance.Tax, "20000")
+
+	if _, err := store.TaxConfigFor(day("2024-06-30")); !errors.Is(err, ErrNoConfigVersion) {
+		t.Errorf("before first version: err = %v, want ErrNoConfigVersion", err)
+	}
+}
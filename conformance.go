@@ -0,0 +1,90 @@
+package salary
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// ConformanceCase 官方公开算例
+type ConformanceCase struct {
+	ID          string       // 算例编号
+	Source      string       // 出处
+	Description string       // 算例说明
+	Expected    Money        // 官方结果（分）
+	compute     func() Money // 使用当前引擎和配置计算
+}
+
+// ConformanceResult 单个算例的校验结果
+type ConformanceResult struct {
+	ID          string // 算例编号
+	Source      string // 出处
+	Description string // 算例说明
+	Expected    Money  // 官方结果（分）
+	Actual      Money  // 当前引擎计算结果（分）
+	Passed      bool   // 是否一致
+}
+
+// conformanceCases 官方公开的个税和社保算例
+var conformanceCases = []ConformanceCase{
+	{
+		ID:          "IIT-001",
+		Source:      "国家税务总局《个人所得税扣缴申报管理办法（试行）》政策解读",
+		Description: "非居民个人月工资薪金20000元，减除费用5000元后按月度税率表计税",
+		Expected:    toMoney(cenToDec(159000)),
+		compute: func() Money {
+			return CalculateIncomeTax(toMoney(cenToDec(2000000)), SpecialDeductions{})
+		},
+	},
+	{
+		ID:          "IIT-002",
+		Source:      "国家税务总局《个人所得税扣缴申报管理办法（试行）》政策解读",
+		Description: "居民个人取得劳务报酬2000元，减除费用800元后按20%预扣",
+		Expected:    toMoney(cenToDec(24000)),
+		compute: func() Money {
+			return CalculateLaborRemunerationTax(toMoney(cenToDec(200000)))
+		},
+	},
+	{
+		ID:          "SI-001",
+		Source:      "上海市社会保险缴费个人部分计算口径",
+		Description: "缴费基数10000元，养老8%、医疗2%、失业0.5%，个人缴纳合计1050元",
+		Expected:    toMoney(cenToDec(105000)),
+		compute: func() Money {
+			config := PayrollConfig{
+				PensionRate:      decimal.RequireFromString("0.08"),
+				MedicalRate:      decimal.RequireFromString("0.02"),
+				UnemploymentRate: decimal.RequireFromString("0.005"),
+			}
+			socialInsurance, _ := CalculateSocialInsurance(config, toMoney(cenToDec(1000000)))
+			return socialInsurance
+		},
+	},
+	{
+		ID:          "HF-001",
+		Source:      "上海市住房公积金缴存计算口径",
+		Description: "缴存基数10000元，个人缴存比例7%，月缴存额700元",
+		Expected:    toMoney(cenToDec(70000)),
+		compute: func() Money {
+			config := PayrollConfig{HousingFundRate: decimal.RequireFromString("0.07")}
+			_, housingFund := CalculateSocialInsurance(config, toMoney(cenToDec(1000000)))
+			return housingFund
+		},
+	},
+}
+
+// RunConformanceSuite 使用当前引擎和已配置的税率表逐一计算官方公开算例，返回每个算例是否一致
+// 用于验证所配置的政策与主管部门公布的计算口径相符
+func RunConformanceSuite() []ConformanceResult {
+	results := make([]ConformanceResult, 0, len(conformanceCases))
+	for _, c := range conformanceCases {
+		actual := c.compute()
+		results = append(results, ConformanceResult{
+			ID:          c.ID,
+			Source:      c.Source,
+			Description: c.Description,
+			Expected:    c.Expected,
+			Actual:      actual,
+			Passed:      moneyToDec(actual).Equal(moneyToDec(c.Expected)),
+		})
+	}
+	return results
+}
@@ -0,0 +1,28 @@
+package salary
+
+import "testing"
+
+func TestRunConformanceSuite(t *testing.T) {
+	results := RunConformanceSuite()
+	if len(results) != len(conformanceCases) {
+		t.Fatalf("results = %d, cases = %d", len(results), len(conformanceCases))
+	}
+	for _, r := range results {
+		if !r.Passed {
+			t.Errorf("%s: expected %s, actual %s", r.ID, moneyToDec(r.Expected), moneyToDec(r.Actual))
+		}
+	}
+
+	// 配置了错误的税率表时个税算例不再通过
+	t.Cleanup(func() { SetTaxBrackets(DefaultTaxBrackets()) })
+	brackets := DefaultTaxBrackets()
+	brackets[2].Deduction = toMoney(cenToDec(0))
+	if err := SetTaxBrackets(brackets); err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range RunConformanceSuite() {
+		if r.ID == "IIT-001" && r.Passed {
+			t.Error("IIT-001 should fail with a wrong quick deduction")
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package salary
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// MinProbationRate 试用期工资不得低于约定工资的80%
+var MinProbationRate = decimal.RequireFromString("0.8")
+
+// ContractTerms 劳动合同中与薪资相关、但不属于薪资配置的约定
+type ContractTerms struct {
+	ProbationMonths int             // 试用期月数，0表示无试用期
+	ProbationRate   decimal.Decimal // 试用期工资占约定工资的比例，不得低于80%
+	PayDay          int             // 每月发薪日
+	MinimumWage     Money           // 当地月最低工资标准（分），试用期工资不得低于该标准，0表示不检查
+}
+
+// ContractAllowance 合同约定的固定津贴补贴
+type ContractAllowance struct {
+	Code    string `json:"code"`    // 项目代码
+	Name    string `json:"name"`    // 项目名称
+	Amount  Money  `json:"amount"`  // 每月金额（分）
+	Taxable bool   `json:"taxable"` // 是否计税
+}
+
+// OvertimeBasis 合同约定的加班工资计算基数和倍数
+type OvertimeBasis struct {
+	StandardHours Hours           `json:"standard_hours"` // 每月标准工时
+	HourlyRate    Money           `json:"hourly_rate"`    // 加班工资计算的小时工资（分）
+	WeekdayRate   decimal.Decimal `json:"weekday_rate"`   // 工作日加班倍数
+	WeekendRate   decimal.Decimal `json:"weekend_rate"`   // 休息日加班倍数
+	HolidayRate   decimal.Decimal `json:"holiday_rate"`   // 法定节假日加班倍数
+}
+
+// ContractSalaryClause 劳动合同薪资条款的结构化数据，用于填充合同模板
+type ContractSalaryClause struct {
+	EmployeeID      string              `json:"employee_id"`      // 工号
+	Name            string              `json:"name"`             // 姓名
+	Grade           string              `json:"grade"`            // 职级
+	EffectiveFrom   time.Time           `json:"effective_from"`   // 条款生效月份
+	BaseSalary      Money               `json:"base_salary"`      // 月基本工资（分）
+	Allowances      []ContractAllowance `json:"allowances"`       // 固定津贴补贴
+	MonthlyTotal    Money               `json:"monthly_total"`    // 月固定收入合计 = 基本工资 + 固定津贴补贴
+	ProbationMonths int                 `json:"probation_months"` // 试用期月数
+	ProbationRate   decimal.Decimal     `json:"probation_rate"`   // 试用期工资比例
+	ProbationSalary Money               `json:"probation_salary"` // 试用期月工资 = 月固定收入合计 × 试用期比例（分）
+	Overtime        OvertimeBasis       `json:"overtime"`         // 加班工资计算依据
+	PayDay          int                 `json:"pay_day"`          // 每月发薪日
+}
+
+// ErrProbationRateTooLow 试用期工资低于法定下限
+var ErrProbationRateTooLow = errors.New("试用期工资低于约定工资的80%或当地最低工资标准")
+
+// BuildContractClause 由员工薪资配置生成劳动合同薪资条款，合同与工资计算使用同一份配置，避免两者不一致
+// 固定津贴补贴取生效月份起有效、不限次数的周期性收入项目
+// input: 员工计算输入
+// terms: 合同约定
+// effective: 条款生效月份
+func BuildContractClause(input EmployeeInput, terms ContractTerms, effective time.Time) (ContractSalaryClause, error) {
+	config := input.Config
+	clause := ContractSalaryClause{
+		EmployeeID:      input.Employee.ID,
+		Name:            input.Employee.Name,
+		Grade:           input.Employee.Grade,
+		EffectiveFrom:   monthStart(effective),
+		BaseSalary:      config.BaseSalary,
+		ProbationMonths: terms.ProbationMonths,
+		ProbationRate:   terms.ProbationRate,
+		PayDay:          terms.PayDay,
+		Overtime: OvertimeBasis{
+			StandardHours: Hours(moneyToDec(config.FullMonthHours)),
+			HourlyRate:    toMoney(HourlyRate(config).Round(2)),
+			WeekdayRate:   config.OvertimeWeekdayRate,
+			WeekendRate:   config.OvertimeWeekendRate,
+			HolidayRate:   config.OvertimeHolidayRate,
+		},
+	}
+
+	total := moneyToDec(config.BaseSalary)
+	for _, e := range input.Elements {
+		if e.Kind != KindEarning || !e.ActiveIn(effective) || !e.End.IsZero() || e.Occurrences > 0 {
+			continue
+		}
+		clause.Allowances = append(clause.Allowances, ContractAllowance{Code: e.Code, Name: e.Name, Amount: e.Amount, Taxable: e.Taxable})
+		total = total.Add(moneyToDec(e.Amount))
+	}
+	clause.MonthlyTotal = toMoney(total)
+
+	if terms.ProbationMonths > 0 {
+		if terms.ProbationRate.LessThan(MinProbationRate) || terms.ProbationRate.GreaterThan(decimal.NewFromInt(1)) {
+			return clause, fmt.Errorf("%w: 试用期比例 %s", ErrProbationRateTooLow, terms.ProbationRate)
+		}
+		probation := total.Mul(terms.ProbationRate).Round(2)
+		if minimum := moneyToDec(terms.MinimumWage); minimum.IsPositive() && probation.LessThan(minimum) {
+			return clause, fmt.Errorf("%w: 试用期工资%s，最低工资%s", ErrProbationRateTooLow, FormatMoneyCenToYuan(toMoney(probation)), FormatMoneyCenToYuan(terms.MinimumWage))
+		}
+		clause.ProbationSalary = toMoney(probation)
+	}
+	return clause, nil
+}
+
+// ContractMismatches 比较已签合同条款与当前薪资配置重新生成的条款，返回不一致的项目
+// 薪资配置调整后应同步变更劳动合同，返回为空表示两者一致
+func ContractMismatches(signed, current ContractSalaryClause) []string {
+	var diffs []string
+	compare := func(name string, a, b decimal.Decimal) {
+		if !a.Equal(b) {
+			diffs = append(diffs, fmt.Sprintf("%s：合同 %s，薪资配置 %s", name, a, b))
+		}
+	}
+	compareMoney := func(name string, a, b Money) {
+		if !moneyToDec(a).Equal(moneyToDec(b)) {
+			diffs = append(diffs, fmt.Sprintf("%s：合同 %s，薪资配置 %s", name, FormatMoneyCenToYuan(a), FormatMoneyCenToYuan(b)))
+		}
+	}
+	compareMoney("基本工资", signed.BaseSalary, current.BaseSalary)
+	compareMoney("月固定收入合计", signed.MonthlyTotal, current.MonthlyTotal)
+	compareMoney("加班小时工资", signed.Overtime.HourlyRate, current.Overtime.HourlyRate)
+	compare("工作日加班倍数", signed.Overtime.WeekdayRate, current.Overtime.WeekdayRate)
+	compare("休息日加班倍数", signed.Overtime.WeekendRate, current.Overtime.WeekendRate)
+	compare("节假日加班倍数", signed.Overtime.HolidayRate, current.Overtime.HolidayRate)
+
+	allowances := make(map[string]Money, len(current.Allowances))
+	for _, a := range current.Allowances {
+		allowances[a.Code] = a.Amount
+	}
+	for _, a := range signed.Allowances {
+		amount, ok := allowances[a.Code]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("%s：合同约定，薪资配置中不存在", a.Name))
+			continue
+		}
+		compareMoney(a.Name, a.Amount, amount)
+		delete(allowances, a.Code)
+	}
+	for _, a := range current.Allowances {
+		if _, ok := allowances[a.Code]; ok {
+			diffs = append(diffs, fmt.Sprintf("%s：薪资配置中存在，合同未约定", a.Name))
+		}
+	}
+	return diffs
+}
@@ -0,0 +1,49 @@
+package salary
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestBuildContractClause(t *testing.T) {
+	input := EmployeeInput{
+		Employee: Employee{ID: "E1", Name: "张三"},
+		Config:   testConfig(),
+		Elements: []PayElement{
+			{Code: "MEAL", Name: "餐补", Kind: KindEarning, Amount: toMoney(cenToDec(50000)), Taxable: true, Start: day("2024-01-01")},
+			{Code: "RELOC", Name: "安家费", Kind: KindEarning, Amount: toMoney(cenToDec(100000)), Start: day("2024-01-01"), Occurrences: 3},
+		},
+	}
+	terms := ContractTerms{ProbationMonths: 3, ProbationRate: decimal.RequireFromString("0.8"), PayDay: 10}
+	clause, err := BuildContractClause(input, terms, day("2024-03-01"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 基本工资8000元 + 餐补500元，安家费有次数限制，不属于固定津贴
+	if len(clause.Allowances) != 1 || clause.Allowances[0].Code != "MEAL" {
+		t.Errorf("allowances = %+v", clause.Allowances)
+	}
+	assertMoney(t, "MonthlyTotal", clause.MonthlyTotal, "850000")
+	assertMoney(t, "ProbationSalary", clause.ProbationSalary, "680000")
+
+	terms.ProbationRate = decimal.RequireFromString("0.7")
+	if _, err := BuildContractClause(input, terms, day("2024-03-01")); !errors.Is(err, ErrProbationRateTooLow) {
+		t.Errorf("error = %v, want ErrProbationRateTooLow", err)
+	}
+}
+
+func TestContractMismatches(t *testing.T) {
+	input := EmployeeInput{Config: testConfig()}
+	signed, _ := BuildContractClause(input, ContractTerms{}, day("2024-01-01"))
+	if diffs := ContractMismatches(signed, signed); len(diffs) != 0 {
+		t.Errorf("identical clauses: %v", diffs)
+	}
+	input.Config.BaseSalary = toMoney(cenToDec(900000))
+	current, _ := BuildContractClause(input, ContractTerms{}, day("2024-06-01"))
+	// 基本工资、月固定收入合计和加班小时工资均不一致
+	if diffs := ContractMismatches(signed, current); len(diffs) != 3 {
+		t.Errorf("diffs = %v, want 3", diffs)
+	}
+}
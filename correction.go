@@ -0,0 +1,189 @@
+package salary
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrPeriodNotClosed 薪资期尚未关账，应直接修改考勤后重新计算
+var ErrPeriodNotClosed = errors.New("薪资期尚未关账，请直接修改考勤")
+
+// AttendanceField 可更正的考勤字段
+type AttendanceField string
+
+const (
+	FieldWorkHours       AttendanceField = "WorkHours"       // 正常工作时间
+	FieldOvertimeWeekday AttendanceField = "OvertimeWeekday" // 工作日加班时间
+	FieldOvertimeWeekend AttendanceField = "OvertimeWeekend" // 周末加班时间
+	FieldOvertimeHoliday AttendanceField = "OvertimeHoliday" // 节假日加班时间
+	FieldAbsenceHours    AttendanceField = "AbsenceHours"    // 缺勤时间
+)
+
+// field 返回考勤记录中对应字段的指针
+func (f AttendanceField) field(record *AttendanceRecord) (*Hours, error) {
+	switch f {
+	case FieldWorkHours:
+		return &record.WorkHours, nil
+	case FieldOvertimeWeekday:
+		return &record.OvertimeWeekday, nil
+	case FieldOvertimeWeekend:
+		return &record.OvertimeWeekend, nil
+	case FieldOvertimeHoliday:
+		return &record.OvertimeHoliday, nil
+	case FieldAbsenceHours:
+		return &record.AbsenceHours, nil
+	default:
+		return nil, fmt.Errorf("未知的考勤字段 %q", f)
+	}
+}
+
+// AttendanceCorrection 已关账薪资期的考勤更正单
+type AttendanceCorrection struct {
+	ID          string          // 更正单号
+	EmployeeID  string          // 工号
+	Period      time.Time       // 被更正的薪资期
+	Field       AttendanceField // 更正字段
+	Old         Hours           // 原值（小时），须与原考勤一致
+	New         Hours           // 新值（小时）
+	Reason      string          // 更正原因
+	RequestedBy string          // 申请人
+	ApprovedBy  string          // 第二审批人，差额超过四眼原则阈值时必填
+
+	Difference    Money     // 由更正产生的税前工资差额（分），负数表示多发
+	AppliedPeriod time.Time // 差额计入的薪资期，零值表示尚未计入
+}
+
+// adjustment 将更正差额转换为一次性调整，调整单号与更正单号相同以便追溯
+func (c AttendanceCorrection) adjustment() Adjustment {
+	amount := moneyToDec(c.Difference)
+	kind := KindEarning
+	if amount.IsNegative() {
+		kind = KindDeduction
+	}
+	return Adjustment{
+		ID:         c.ID,
+		Kind:       kind,
+		Amount:     toMoney(amount.Abs()),
+		Taxable:    true,
+		ReasonCode: ReasonCorrection,
+		Reason: fmt.Sprintf("更正%s考勤%s：%s→%s小时，%s",
+			c.Period.Format("2006-01"), c.Field, hoursToDec(c.Old).String(), hoursToDec(c.New).String(), c.Reason),
+		Approver:       c.RequestedBy,
+		SecondApprover: c.ApprovedBy,
+	}
+}
+
+// CorrectionLedger 考勤更正台账
+// 已关账薪资期不再重新计算，更正产生的工资差额排队计入下一个未关账的发薪批次
+type CorrectionLedger struct {
+	mu          sync.Mutex
+	closed      map[string]bool
+	corrections []AttendanceCorrection
+	seq         int
+}
+
+// NewCorrectionLedger 创建空的考勤更正台账
+func NewCorrectionLedger() *CorrectionLedger {
+	return &CorrectionLedger{closed: make(map[string]bool)}
+}
+
+// Close 将薪资期标记为已关账
+func (l *CorrectionLedger) Close(period time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.closed[period.Format("200601")] = true
+}
+
+// IsClosed 判断薪资期是否已关账
+func (l *CorrectionLedger) IsClosed(period time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.closed[period.Format("200601")]
+}
+
+// Submit 登记考勤更正，按原输入重新计算被更正月份并记录税前工资差额
+// original: 被更正月份的原计算输入
+// 返回值: 登记后的更正单，包含更正单号和差额
+func (l *CorrectionLedger) Submit(c AttendanceCorrection, original EmployeeInput) (AttendanceCorrection, error) {
+	if !l.IsClosed(c.Period) {
+		return c, ErrPeriodNotClosed
+	}
+	if c.EmployeeID != original.Employee.ID {
+		return c, fmt.Errorf("更正单工号 %s 与原输入 %s 不一致", c.EmployeeID, original.Employee.ID)
+	}
+
+	corrected := original
+	value, err := c.Field.field(&corrected.Attendance)
+	if err != nil {
+		return c, err
+	}
+	if !hoursToDec(*value).Equal(hoursToDec(c.Old)) {
+		return c, fmt.Errorf("%s 原值为 %s 小时，与更正单原值 %s 小时不一致",
+			c.Field, hoursToDec(*value).String(), hoursToDec(c.Old).String())
+	}
+	*value = c.New
+
+	before := CalculateEmployee(c.Period, original)
+	after := CalculateEmployee(c.Period, corrected)
+	c.Difference = toMoney(moneyToDec(after.GrossSalary).Sub(moneyToDec(before.GrossSalary)))
+	c.Period = monthStart(c.Period)
+	c.AppliedPeriod = time.Time{}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.seq++
+	c.ID = fmt.Sprintf("COR-%06d", l.seq)
+	l.corrections = append(l.corrections, c)
+	return c, nil
+}
+
+// Corrections 查询员工的全部更正单，按登记顺序排列
+func (l *CorrectionLedger) Corrections(employeeID string) []AttendanceCorrection {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var corrections []AttendanceCorrection
+	for _, c := range l.corrections {
+		if c.EmployeeID == employeeID {
+			corrections = append(corrections, c)
+		}
+	}
+	return corrections
+}
+
+// take 取出员工在指定薪资期之前尚未计入的更正差额，并标记为计入该期
+// 指定薪资期已关账时不计入，差额为0的更正单只标记不生成调整
+func (l *CorrectionLedger) take(employeeID string, period time.Time) []Adjustment {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed[period.Format("200601")] {
+		return nil
+	}
+	p := monthStart(period)
+	var adjustments []Adjustment
+	for i := range l.corrections {
+		c := &l.corrections[i]
+		if c.EmployeeID != employeeID || !c.AppliedPeriod.IsZero() || !c.Period.Before(p) {
+			continue
+		}
+		c.AppliedPeriod = p
+		if !moneyToDec(c.Difference).IsZero() {
+			adjustments = append(adjustments, c.adjustment())
+		}
+	}
+	return adjustments
+}
+
+// release 撤销 take 对员工在指定薪资期的计入标记，用于计算被取消时退回待计入的更正
+func (l *CorrectionLedger) release(employeeID string, period time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	p := monthStart(period)
+	for i := range l.corrections {
+		c := &l.corrections[i]
+		if c.EmployeeID == employeeID && c.AppliedPeriod.Equal(p) {
+			c.AppliedPeriod = time.Time{}
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package salary
+
+import (
+	"errors"
+	"testing"
+)
+
+func correctionInput() EmployeeInput {
+	return EmployeeInput{
+		Employee:   Employee{ID: "E1"},
+		Config:     testConfig(),
+		Attendance: AttendanceRecord{WorkHours: hours("174")},
+	}
+}
+
+func TestCorrectionLedgerSubmit(t *testing.T) {
+	ledger := NewCorrectionLedger()
+	correction := AttendanceCorrection{
+		EmployeeID: "E1", Period: day("2024-02-01"), Field: FieldOvertimeWeekday,
+		Old: hours("0"), New: hours("10"), Reason: "漏录加班", RequestedBy: "hr1",
+	}
+	if _, err := ledger.Submit(correction, correctionInput()); !errors.Is(err, ErrPeriodNotClosed) {
+		t.Fatalf("err = %v, want ErrPeriodNotClosed", err)
+	}
+
+	ledger.Close(day("2024-02-01"))
+	wrongOld := correction
+	wrongOld.Old = hours("2")
+	if _, err := ledger.Submit(wrongOld, correctionInput()); err == nil {
+		t.Error("mismatched old value should fail")
+	}
+	unknown := correction
+	unknown.Field = "LateMinutes"
+	if _, err := ledger.Submit(unknown, correctionInput()); err == nil {
+		t.Error("unknown field should fail")
+	}
+
+	submitted, err := ledger.Submit(correction, correctionInput())
+	if err != nil {
+		t.Fatal(err)
+	}
+	corrected := correctionInput()
+	corrected.Attendance.OvertimeWeekday = hours("10")
+	want := moneyToDec(CalculateEmployee(day("2024-02-01"), corrected).GrossSalary).
+		Sub(moneyToDec(CalculateEmployee(day("2024-02-01"), correctionInput()).GrossSalary))
+	if submitted.ID != "COR-000001" || !want.IsPositive() || !moneyToDec(submitted.Difference).Equal(want) {
+		t.Errorf("submitted = %+v, want difference %s", submitted, want)
+	}
+}
+
+func TestCorrectionAppliedInNextOpenRun(t *testing.T) {
+	ledger := NewCorrectionLedger()
+	ledger.Close(day("2024-02-01"))
+	submitted, err := ledger.Submit(AttendanceCorrection{
+		EmployeeID: "E1", Period: day("2024-02-01"), Field: FieldAbsenceHours,
+		Old: hours("0"), New: hours("8"), Reason: "补录事假", RequestedBy: "hr1",
+	}, correctionInput())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	run := PayrollRun{Period: day("2024-03-01"), Corrections: ledger, Inputs: []EmployeeInput{correctionInput()}}
+	lines := run.Calculate().Employees[0].Lines
+	// 补录缺勤导致多发，下期作为扣款计入
+	if len(lines) != 1 || lines[0].Code != "ADJ-CORRECTION" || lines[0].Kind != KindDeduction {
+		t.Fatalf("lines = %+v", lines)
+	}
+	if !moneyToDec(lines[0].Amount).Equal(moneyToDec(submitted.Difference).Neg()) {
+		t.Errorf("amount = %s, difference = %s", moneyToDec(lines[0].Amount), moneyToDec(submitted.Difference))
+	}
+	if c := ledger.Corrections("E1"); !c[0].AppliedPeriod.Equal(day("2024-03-01")) {
+		t.Errorf("applied period = %v", c[0].AppliedPeriod)
+	}
+
+	// 已计入的更正不重复计入
+	run.Period = day("2024-04-01")
+	if lines := run.Calculate().Employees[0].Lines; len(lines) != 0 {
+		t.Errorf("April lines = %+v", lines)
+	}
+}
@@ -0,0 +1,168 @@
+package salary
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/shopspring/decimal"
+)
+
+// CostDimension 人工成本分析的分组维度
+type CostDimension int
+
+const (
+	CostByEmployee   CostDimension = iota // 按员工
+	CostByDepartment                      // 按部门
+)
+
+// CostOptions 人工成本口径
+// 全口径人工成本以 CalculateEmployerCost 为准，即税前工资加实际的单位缴费
+type CostOptions struct {
+	OnCostRate decimal.Decimal // 兜底比例：结果未记录单位缴费明细时，单位社保公积金按税前工资的该比例估算，如0.3表示30%，0表示不估算
+}
+
+// loadedCost 员工本期全口径人工成本
+// 结果记录了单位缴费明细时取 EmployerCost.LoadedCost，否则在税前工资和已知单位缴费之上按 OnCostRate 估算单位社保公积金
+func (o CostOptions) loadedCost(r EmployeeResult) decimal.Decimal {
+	cost := moneyToDec(r.employerCost().LoadedCost)
+	if r.EmployerCost == nil {
+		cost = cost.Add(moneyToDec(r.GrossSalary).Mul(o.OnCostRate))
+	}
+	return cost
+}
+
+// workedHours 员工本期实际工作时长 = 正常工作时间 + 各类加班时间
+func workedHours(a AttendanceRecord) decimal.Decimal {
+	return hoursToDec(a.WorkHours).
+		Add(hoursToDec(a.OvertimeWeekday)).
+		Add(hoursToDec(a.OvertimeWeekend)).
+		Add(hoursToDec(a.OvertimeHoliday))
+}
+
+// CostPerHourRow 单位工时人工成本分析的一行结果
+type CostPerHourRow struct {
+	Group         string          // 分组：工号或部门
+	Headcount     int             // 人数
+	LoadedCost    Money           // 全口径人工成本合计（分）
+	WorkedHours   Hours           // 实际工作时长合计
+	StandardHours Hours           // 标准工时合计
+	CostPerHour   Money           // 每工作小时人工成本（分）
+	Utilization   decimal.Decimal // 工时利用率 = 实际工作时长 / 标准工时
+}
+
+// AnalyzeCostPerHour 根据历史发薪结果和考勤计算每工作小时的全口径人工成本和工时利用率，用于项目核算
+// 未在岗月份不计入
+// history: 历史发薪批次
+// dimension: 分组维度
+// opts: 人工成本口径
+// 返回值: 按分组名称排序的分析结果
+func AnalyzeCostPerHour(history []PayrollResult, dimension CostDimension, opts CostOptions) []CostPerHourRow {
+	type bucket struct {
+		employees              map[string]bool
+		cost, worked, standard decimal.Decimal
+	}
+	buckets := make(map[string]*bucket)
+	for _, run := range history {
+		for _, r := range run.Employees {
+			if r.Status != PeriodActive {
+				continue
+			}
+			group := r.Employee.ID
+			if dimension == CostByDepartment {
+				group = r.Employee.Department
+			}
+			b, ok := buckets[group]
+			if !ok {
+				b = &bucket{employees: make(map[string]bool)}
+				buckets[group] = b
+			}
+			b.employees[r.Employee.ID] = true
+			b.cost = b.cost.Add(opts.loadedCost(r))
+			b.worked = b.worked.Add(workedHours(r.Attendance))
+			b.standard = b.standard.Add(hoursToDec(r.StandardHours))
+		}
+	}
+
+	rows := make([]CostPerHourRow, 0, len(buckets))
+	for group, b := range buckets {
+		row := CostPerHourRow{
+			Group:         group,
+			Headcount:     len(b.employees),
+			LoadedCost:    toMoney(b.cost.Round(2)),
+			WorkedHours:   Hours(b.worked),
+			StandardHours: Hours(b.standard),
+			CostPerHour:   toMoney(decimal.Zero),
+		}
+		// 工作时长或标准工时为0时不计算比值，避免除零
+		if b.worked.IsPositive() {
+			row.CostPerHour = toMoney(b.cost.Div(b.worked).Round(2))
+		}
+		if b.standard.IsPositive() {
+			row.Utilization = b.worked.Div(b.standard).Round(4)
+		}
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Group < rows[j].Group })
+	return rows
+}
+
+// WriteCostPerHourCSV 将单位工时人工成本分析结果导出为CSV
+func WriteCostPerHourCSV(w io.Writer, rows []CostPerHourRow) error {
+	cw := csv.NewWriter(w)
+	header := []string{"分组", "人数", "全口径人工成本", "实际工时", "标准工时", "每小时成本", "工时利用率"}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := []string{
+			row.Group,
+			strconv.Itoa(row.Headcount),
+			FormatMoneyCenToYuan(row.LoadedCost),
+			hoursToDec(row.WorkedHours).String(),
+			hoursToDec(row.StandardHours).String(),
+			FormatMoneyCenToYuan(row.CostPerHour),
+			row.Utilization.String(),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// handleCostPerHour 单位工时人工成本分析接口：GET /analytics/cost-per-hour?by=department&on_cost_rate=0.3&format=csv
+// on_cost_rate 只用于未记录单位缴费明细的历史结果
+func (s *Server) handleCostPerHour(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	dimension := CostByEmployee
+	switch query.Get("by") {
+	case "", "employee":
+	case "department":
+		dimension = CostByDepartment
+	default:
+		writeError(w, fmt.Errorf("不支持的分组维度 %q", query.Get("by")))
+		return
+	}
+	var opts CostOptions
+	if rate := query.Get("on_cost_rate"); rate != "" {
+		d, err := ParseRate(rate)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		opts.OnCostRate = d
+	}
+
+	rows := AnalyzeCostPerHour(s.store.Runs(), dimension, opts)
+	if query.Get("format") == "csv" {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		WriteCostPerHourCSV(w, rows)
+		return
+	}
+	writeJSON(w, http.StatusOK, rows)
+}
@@ -0,0 +1,141 @@
+package salary
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// MonthlyBasicExemption 居民个人工资薪金每月减除费用5000元（分）
+var MonthlyBasicExemption = toMoney(cenToDec(500000))
+
+// AnnualTaxBrackets 居民个人工资薪金预扣率表（累计预扣预缴应纳税所得额，金额单位为分）
+// 税额 = 累计应纳税所得额 × 预扣率 - 速算扣除数；Threshold 为该档下限
+func AnnualTaxBrackets() []TaxBracket {
+	rows := []struct {
+		threshold int64
+		rate      string
+		deduction int64
+	}{
+		{0, "0.03", 0},
+		{3600000, "0.10", 252000},
+		{14400000, "0.20", 1692000},
+		{30000000, "0.25", 3192000},
+		{42000000, "0.30", 5292000},
+		{66000000, "0.35", 8592000},
+		{96000000, "0.45", 18192000},
+	}
+	brackets := make([]TaxBracket, len(rows))
+	for i, r := range rows {
+		brackets[i] = TaxBracket{
+			Threshold: toMoney(cenToDec(r.threshold)),
+			Rate:      decimal.RequireFromString(r.rate),
+			Deduction: toMoney(cenToDec(r.deduction)),
+		}
+	}
+	return brackets
+}
+
+// TaxWithholdingState 累计预扣法的本年累计数据，每月计算后更新并保存，供下月使用
+type TaxWithholdingState struct {
+	Year              int   `json:"year"`               // 纳税年度，0表示尚无数据
+	StartMonth        int   `json:"start_month"`        // 本年在本单位任职起始月份，用于计算累计减除费用
+	LastMonth         int   `json:"last_month"`         // 已预扣的最后月份
+	Income            Money `json:"income"`             // 累计收入（分）
+	SocialInsurance   Money `json:"social_insurance"`   // 累计专项扣除（个人缴纳的三险一金，分）
+	SpecialDeductions Money `json:"special_deductions"` // 累计专项附加扣除（分）
+	TaxWithheld       Money `json:"tax_withheld"`       // 累计已预扣税额（分）
+}
+
+// TaxMonth 累计预扣法的本月数据
+type TaxMonth struct {
+	Period            time.Time  // 薪资期
+	HireDate          time.Time  // 入职日期，年中入职的员工从入职月份起累计
+	Income            Money      // 本月收入（已扣除免税收入，分）
+	SocialInsurance   Money      // 本月专项扣除（个人缴纳的三险一金，不含超过免税限额的公积金，分）
+	SpecialDeductions Money      // 本月专项附加扣除（分）
+	Tax               *TaxConfig // 个税配置，决定每月减除费用和按年换算的税率表；为空时使用默认配置
+}
+
+// taxConfig 返回本月适用的个税配置，未设置时使用默认配置
+func (m TaxMonth) taxConfig() TaxConfig {
+	if m.Tax == nil {
+		return DefaultTaxConfig()
+	}
+	return *m.Tax
+}
+
+// ExemptionMonths 累计减除费用的月数 = 本月 - 任职起始月份 + 1
+func (s TaxWithholdingState) ExemptionMonths(month int) int {
+	return month - s.StartMonth + 1
+}
+
+// TaxableIncome 截至已预扣月份的累计应纳税所得额 = 累计收入 - 累计减除费用 - 累计专项扣除 - 累计专项附加扣除，可能为负数
+// config: 个税配置，累计减除费用 = 每月减除费用 × 任职月数
+func (s TaxWithholdingState) TaxableIncome(config TaxConfig) Money {
+	if s.Year == 0 {
+		return toMoney(decimal.Zero)
+	}
+	exemption := config.cumulativeDeduction(s.ExemptionMonths(s.LastMonth))
+	return toMoney(moneyToDec(s.Income).Sub(exemption).Sub(moneyToDec(s.SocialInsurance)).Sub(moneyToDec(s.SpecialDeductions)))
+}
+
+// start 返回本月使用的累计数据：同一年度沿用，新年度或首次计算时从零开始
+// 年初（1月）或年中入职的首月可以从零开始，其他月份缺少累计数据时返回 ErrMissingYearToDate
+func (s TaxWithholdingState) start(month TaxMonth) (TaxWithholdingState, error) {
+	year, m := month.Period.Year(), int(month.Period.Month())
+	switch {
+	case s.Year > year:
+		return s, fmt.Errorf("累计数据年度 %d 晚于薪资期 %s", s.Year, month.Period.Format("2006-01"))
+	case s.Year == year:
+		if m <= s.LastMonth {
+			return s, fmt.Errorf("薪资期 %s 已按累计预扣法预扣", month.Period.Format("2006-01"))
+		}
+		if m > s.LastMonth+1 {
+			return s, fmt.Errorf("%w: 已预扣至%d月，本期为%d月", ErrMissingYearToDate, s.LastMonth, m)
+		}
+		return s, nil
+	}
+
+	fresh := TaxWithholdingState{Year: year, StartMonth: 1}
+	if h := month.HireDate; !h.IsZero() && h.Year() == year {
+		fresh.StartMonth = int(h.Month())
+	}
+	if m != fresh.StartMonth {
+		return s, fmt.Errorf("%w: %s", ErrMissingYearToDate, month.Period.Format("2006-01"))
+	}
+	return fresh, nil
+}
+
+// CalculateCumulativeTax 按累计预扣法计算本月应预扣的个人所得税
+// 累计应纳税所得额 = 累计收入 - 累计减除费用(每月减除费用×任职月数) - 累计专项扣除 - 累计专项附加扣除
+// 本月预扣 = 累计应纳税所得额 × 预扣率 - 速算扣除数 - 累计已预扣税额，结果为负时本月不预扣、不退税
+// 每月减除费用和预扣率表取自 month.Tax，预扣率表由按月换算的税率表乘以12得到
+// state: 截至上月的累计数据，新年度或首次计算时传零值
+// month: 本月数据
+// 返回值: (本月应预扣税额, 更新后的累计数据, 错误)
+func CalculateCumulativeTax(state TaxWithholdingState, month TaxMonth) (Money, TaxWithholdingState, error) {
+	s, err := state.start(month)
+	if err != nil {
+		return toMoney(decimal.Zero), state, err
+	}
+	m := int(month.Period.Month())
+	config := month.taxConfig()
+
+	income := moneyToDec(s.Income).Add(moneyToDec(month.Income))
+	insurance := moneyToDec(s.SocialInsurance).Add(moneyToDec(month.SocialInsurance))
+	special := moneyToDec(s.SpecialDeductions).Add(moneyToDec(month.SpecialDeductions))
+	exemption := config.cumulativeDeduction(s.ExemptionMonths(m))
+	taxable := income.Sub(exemption).Sub(insurance).Sub(special)
+
+	owed := moneyToDec(calculateIncomeTaxWith(toMoney(taxable), toMoney(decimal.Zero), config.AnnualBrackets()))
+	current := decimal.Max(owed.Sub(moneyToDec(s.TaxWithheld)), decimal.Zero)
+
+	s.LastMonth = m
+	s.Income = toMoney(income)
+	s.SocialInsurance = toMoney(insurance)
+	s.SpecialDeductions = toMoney(special)
+	s.TaxWithheld = toMoney(moneyToDec(s.TaxWithheld).Add(current))
+	return toMoney(current), s, nil
+}
@@ -0,0 +1,120 @@
+package salary
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestCalculateCumulativeTax(t *testing.T) {
+	// 月收入3万元，三险一金4500元，专项附加扣除2000元：
+	// 1月累计应纳税所得额18500元 × 3% = 555元；2月累计37000元 × 10% - 2520 = 1180元，本月预扣625元；
+	// 3月累计55500元 × 10% - 2520 = 3030元，本月预扣1850元
+	wants := []string{"55500", "62500", "185000"}
+	var state TaxWithholdingState
+	for i, want := range wants {
+		month := TaxMonth{
+			Period:            time.Date(2024, time.Month(i+1), 1, 0, 0, 0, 0, time.Local),
+			Income:            toMoney(cenToDec(3000000)),
+			SocialInsurance:   toMoney(cenToDec(450000)),
+			SpecialDeductions: toMoney(cenToDec(200000)),
+		}
+		tax, next, err := CalculateCumulativeTax(state, month)
+		if err != nil {
+			t.Fatalf("month %d: %v", i+1, err)
+		}
+		assertMoney(t, month.Period.Format("2006-01"), tax, want)
+		state = next
+	}
+	assertMoney(t, "TaxWithheld", state.TaxWithheld, "303000")
+	if state.LastMonth != 3 {
+		t.Errorf("LastMonth = %d, want 3", state.LastMonth)
+	}
+}
+
+func TestCalculateCumulativeTaxMidYearHire(t *testing.T) {
+	// 7月入职，首月可以从零开始累计
+	month := TaxMonth{
+		Period:   time.Date(2024, 7, 1, 0, 0, 0, 0, time.Local),
+		HireDate: time.Date(2024, 7, 15, 0, 0, 0, 0, time.Local),
+		Income:   toMoney(cenToDec(1000000)),
+	}
+	tax, state, err := CalculateCumulativeTax(TaxWithholdingState{}, month)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// (10000 - 5000) × 3% = 150元
+	assertMoney(t, "tax", tax, "15000")
+	if state.StartMonth != 7 {
+		t.Errorf("StartMonth = %d, want 7", state.StartMonth)
+	}
+}
+
+func TestCalculateCumulativeTaxMissingYearToDate(t *testing.T) {
+	month := TaxMonth{Period: time.Date(2024, 5, 1, 0, 0, 0, 0, time.Local)}
+	if _, _, err := CalculateCumulativeTax(TaxWithholdingState{}, month); !errors.Is(err, ErrMissingYearToDate) {
+		t.Errorf("empty state in May: error = %v, want ErrMissingYearToDate", err)
+	}
+	state := TaxWithholdingState{Year: 2024, StartMonth: 1, LastMonth: 3}
+	if _, _, err := CalculateCumulativeTax(state, month); !errors.Is(err, ErrMissingYearToDate) {
+		t.Errorf("gap after March: error = %v, want ErrMissingYearToDate", err)
+	}
+	if ErrorCodeOf(ErrMissingYearToDate) != CodeMissingYearToDate {
+		t.Errorf("ErrorCodeOf = %s, want %s", ErrorCodeOf(ErrMissingYearToDate), CodeMissingYearToDate)
+	}
+}
+
+func TestCalculateCumulativeTaxUsesTaxConfig(t *testing.T) {
+	// 每月减除费用6000元：1月累计应纳税所得额 30000 - 6000 - 4500 - 2000 = 17500元 × 3% = 525元
+	config := TaxConfig{StandardDeduction: toMoney(cenToDec(600000))}
+	month := TaxMonth{
+		Period:            time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local),
+		Income:            toMoney(cenToDec(3000000)),
+		SocialInsurance:   toMoney(cenToDec(450000)),
+		SpecialDeductions: toMoney(cenToDec(200000)),
+		Tax:               &config,
+	}
+	tax, state, err := CalculateCumulativeTax(TaxWithholdingState{}, month)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertMoney(t, "tax", tax, "52500")
+	assertMoney(t, "TaxableIncome", state.TaxableIncome(config), "1750000")
+
+	// 税率表减半后按年换算：第一档上限为18000元
+	half := TaxConfig{StandardDeduction: MonthlyBasicExemption, Brackets: []TaxBracket{
+		{Threshold: toMoney(cenToDec(0)), Rate: decimal.RequireFromString("0.03"), Deduction: toMoney(cenToDec(0))},
+		{Threshold: toMoney(cenToDec(150000)), Rate: decimal.RequireFromString("0.10"), Deduction: toMoney(cenToDec(10500))},
+	}}
+	month.Tax = &half
+	// 累计应纳税所得额18500元：18500 × 10% - 1260 = 590元
+	tax, _, err = CalculateCumulativeTax(TaxWithholdingState{}, month)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertMoney(t, "half brackets", tax, "59000")
+}
+
+func TestCalculateEmployeeCumulativeStandardDeduction(t *testing.T) {
+	input := EmployeeInput{
+		Employee: Employee{ID: "E1"},
+		Config:   testConfig(),
+		Attendance: AttendanceRecord{
+			WorkHours: hours("174"),
+		},
+		TaxState: &TaxWithholdingState{},
+	}
+	period := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+	base := CalculateEmployee(period, input)
+
+	tax := TaxConfig{StandardDeduction: toMoney(cenToDec(600000))}
+	input.Config.Tax = &tax
+	raised := CalculateEmployee(period, input)
+	if raised.TaxState == nil {
+		t.Fatal("TaxState not carried")
+	}
+	// 减除费用多1000元，3%档少缴30元
+	assertMoney(t, "tax difference", toMoney(moneyToDec(base.IncomeTax).Sub(moneyToDec(raised.IncomeTax))), "3000")
+}
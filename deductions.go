@@ -0,0 +1,328 @@
+package salary
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// DeductionDeclaration 专项附加扣除申报，记录每月扣除金额及有效期
+// 例如住房租金扣除只在租赁合同备案期间有效
+type DeductionDeclaration struct {
+	ID         string            // 申报编号
+	Deductions SpecialDeductions // 每月扣除金额（分）
+	From       time.Time         // 起始月份
+	To         time.Time         // 截止月份（含），零值表示长期有效
+
+	ElderlySupport *ElderlySupportDeclaration // 赡养老人申报信息，非空时按分摊规则推导赡养老人扣除额
+	Children       []Child                    // 子女信息，非空时按每个子女推导子女教育扣除额（含3岁以下婴幼儿照护）
+
+	ContinuingEducation *ContinuingEducationDeclaration // 继续教育申报信息，非空时按类型和期限推导继续教育扣除额
+}
+
+// EffectiveIn 判断申报在指定薪资期是否有效
+func (d DeductionDeclaration) EffectiveIn(period time.Time) bool {
+	p := monthStart(period)
+	if p.Before(monthStart(d.From)) {
+		return false
+	}
+	return d.To.IsZero() || !p.After(monthStart(d.To))
+}
+
+// addDeductions 逐项累加专项附加扣除
+func addDeductions(a, b SpecialDeductions) SpecialDeductions {
+	return SpecialDeductions{
+		ChildrenEducation:   toMoney(moneyToDec(a.ChildrenEducation).Add(moneyToDec(b.ChildrenEducation))),
+		ContinuingEducation: toMoney(moneyToDec(a.ContinuingEducation).Add(moneyToDec(b.ContinuingEducation))),
+		HousingLoanInterest: toMoney(moneyToDec(a.HousingLoanInterest).Add(moneyToDec(b.HousingLoanInterest))),
+		HousingRent:         toMoney(moneyToDec(a.HousingRent).Add(moneyToDec(b.HousingRent))),
+		SupportElderly:      toMoney(moneyToDec(a.SupportElderly).Add(moneyToDec(b.SupportElderly))),
+	}
+}
+
+// EffectiveDeductions 汇总指定薪资期内有效的全部申报，得到当月适用的专项附加扣除
+func EffectiveDeductions(declarations []DeductionDeclaration, period time.Time) SpecialDeductions {
+	var total SpecialDeductions
+	for _, d := range declarations {
+		if d.EffectiveIn(period) {
+			total = addDeductions(total, d.monthly(period))
+		}
+	}
+	return total
+}
+
+// deductionsFor 返回员工本期适用的专项附加扣除：有申报记录时按有效期自动选取，否则使用直接填写的扣除额
+func (input EmployeeInput) deductionsFor(period time.Time) SpecialDeductions {
+	if len(input.Declarations) == 0 {
+		return input.Deductions
+	}
+	return EffectiveDeductions(input.Declarations, period)
+}
+
+// 赡养老人扣除标准（分）：每月合计3000元，非独生子女每人每月不超过1500元
+var (
+	ElderlySupportCap      = toMoney(cenToDec(300000))
+	ElderlySupportPersonal = toMoney(cenToDec(150000))
+)
+
+// ElderlySplit 非独生子女赡养老人扣除的分摊方式
+type ElderlySplit int
+
+const (
+	ElderlySplitEqual      ElderlySplit = iota // 平均分摊
+	ElderlySplitAgreed                         // 约定分摊
+	ElderlySplitDesignated                     // 被赡养人指定分摊
+)
+
+// ElderlySupportDeclaration 赡养老人扣除申报信息
+type ElderlySupportDeclaration struct {
+	OnlyChild bool         // 是否独生子女
+	Siblings  int          // 兄弟姐妹人数（含本人），平均分摊时使用
+	Split     ElderlySplit // 分摊方式
+	Share     Money        // 约定或指定分摊的每月金额（分）
+}
+
+// Amount 计算本人每月可扣除的赡养老人金额
+func (e ElderlySupportDeclaration) Amount() Money {
+	if e.OnlyChild {
+		return ElderlySupportCap
+	}
+	if e.Split == ElderlySplitEqual {
+		if e.Siblings < 2 {
+			return ElderlySupportPersonal
+		}
+		share := moneyToDec(ElderlySupportCap).Div(cenToDec(int64(e.Siblings))).Truncate(2)
+		return toMoney(decimal.Min(share, moneyToDec(ElderlySupportPersonal)))
+	}
+	return e.Share
+}
+
+// Validate 校验赡养老人申报是否符合分摊规则
+func (e ElderlySupportDeclaration) Validate() error {
+	if e.OnlyChild {
+		if !moneyToDec(e.Share).IsZero() && !moneyToDec(e.Share).Equal(moneyToDec(ElderlySupportCap)) {
+			return fmt.Errorf("独生子女赡养老人扣除固定为每月%s，不能约定分摊", FormatMoneyCenToYuan(ElderlySupportCap))
+		}
+		return nil
+	}
+	switch e.Split {
+	case ElderlySplitEqual:
+		if e.Siblings < 2 {
+			return errors.New("非独生子女平均分摊时兄弟姐妹人数（含本人）至少为2")
+		}
+	case ElderlySplitAgreed, ElderlySplitDesignated:
+		if !moneyToDec(e.Share).IsPositive() {
+			return errors.New("约定或指定分摊的赡养老人扣除金额必须大于0")
+		}
+	}
+	if moneyToDec(e.Amount()).GreaterThan(moneyToDec(ElderlySupportPersonal)) {
+		return fmt.Errorf("非独生子女每人每月赡养老人扣除不能超过%s", FormatMoneyCenToYuan(ElderlySupportPersonal))
+	}
+	return nil
+}
+
+// ValidateSiblingShares 校验兄弟姐妹各自申报的分摊金额：每人不超过1500元，合计不超过3000元
+func ValidateSiblingShares(shares []Money) error {
+	total := decimal.Zero
+	for i, share := range shares {
+		if moneyToDec(share).GreaterThan(moneyToDec(ElderlySupportPersonal)) {
+			return fmt.Errorf("第%d人分摊金额%s超过每人每月上限%s", i+1, FormatMoneyCenToYuan(share), FormatMoneyCenToYuan(ElderlySupportPersonal))
+		}
+		total = total.Add(moneyToDec(share))
+	}
+	if total.GreaterThan(moneyToDec(ElderlySupportCap)) {
+		return fmt.Errorf("兄弟姐妹分摊合计%s超过每月上限%s", FormatMoneyCenToYuan(toMoney(total)), FormatMoneyCenToYuan(ElderlySupportCap))
+	}
+	return nil
+}
+
+// Validate 校验申报内容
+func (d DeductionDeclaration) Validate() error {
+	if err := d.Deductions.Validate(); err != nil {
+		return fmt.Errorf("申报%s: %w", d.ID, err)
+	}
+	if d.ElderlySupport != nil {
+		if err := d.ElderlySupport.Validate(); err != nil {
+			return fmt.Errorf("申报%s: %w", d.ID, err)
+		}
+	}
+	for _, c := range d.Children {
+		if err := c.Validate(); err != nil {
+			return fmt.Errorf("申报%s: %w", d.ID, err)
+		}
+	}
+	return nil
+}
+
+// monthly 返回申报在指定薪资期的扣除金额，由明细信息推导的项目覆盖直接填写的金额
+func (d DeductionDeclaration) monthly(period time.Time) SpecialDeductions {
+	deductions := d.Deductions
+	if d.ElderlySupport != nil {
+		deductions.SupportElderly = d.ElderlySupport.Amount()
+	}
+	if len(d.Children) > 0 {
+		total := decimal.Zero
+		for _, c := range d.Children {
+			total = total.Add(moneyToDec(c.Amount(period)))
+		}
+		deductions.ChildrenEducation = toMoney(total)
+	}
+	if d.ContinuingEducation != nil {
+		deductions.ContinuingEducation = d.ContinuingEducation.Amount(period)
+	}
+	return deductions
+}
+
+// ChildDeductionAmount 子女教育和3岁以下婴幼儿照护扣除标准：每个子女每月2000元（分）
+var ChildDeductionAmount = toMoney(cenToDec(200000))
+
+// EducationStage 子女受教育阶段
+type EducationStage int
+
+const (
+	StagePreschool  EducationStage = iota // 学前教育（满3周岁至小学入学前）
+	StagePrimary                          // 义务教育
+	StageHighSchool                       // 高中阶段教育
+	StageHigher                           // 高等教育
+	StageFinished                         // 已结束全日制学历教育，不再扣除
+)
+
+// Child 子女信息，用于推导子女教育和婴幼儿照护扣除
+type Child struct {
+	Name       string          // 姓名
+	BirthDate  time.Time       // 出生日期
+	Stage      EducationStage  // 满3周岁后的受教育阶段
+	ShareRatio decimal.Decimal // 本人扣除比例：父母各扣50%为0.5，一方全额扣除为1；零值视为1
+}
+
+// ratio 本人扣除比例
+func (c Child) ratio() decimal.Decimal {
+	if c.ShareRatio.IsZero() {
+		return decimal.NewFromInt(1)
+	}
+	return c.ShareRatio
+}
+
+// Amount 计算该子女在指定薪资期的每月扣除额
+// 出生当月至满3周岁前一个月按婴幼儿照护扣除，满3周岁当月起按子女教育扣除，结束学历教育后不再扣除
+func (c Child) Amount(period time.Time) Money {
+	p := monthStart(period)
+	if p.Before(monthStart(c.BirthDate)) {
+		return toMoney(decimal.Zero)
+	}
+	threeYears := monthStart(c.BirthDate).AddDate(3, 0, 0)
+	if !p.Before(threeYears) && c.Stage == StageFinished {
+		return toMoney(decimal.Zero)
+	}
+	return toMoney(moneyToDec(ChildDeductionAmount).Mul(c.ratio()))
+}
+
+// Validate 校验扣除比例只能为50%或100%
+func (c Child) Validate() error {
+	r := c.ratio()
+	if !r.Equal(decimal.NewFromInt(1)) && !r.Equal(decimal.RequireFromString("0.5")) {
+		return fmt.Errorf("子女%s的扣除比例%s无效，只能为50%%或100%%", c.Name, r)
+	}
+	return nil
+}
+
+// ErrHousingDeductionConflict 同时申报住房贷款利息和住房租金扣除
+var ErrHousingDeductionConflict = errors.New("住房贷款利息和住房租金扣除不能同时享受")
+
+// Validate 校验专项附加扣除的项目间约束：住房贷款利息与住房租金只能选择其一
+func (d SpecialDeductions) Validate() error {
+	if moneyToDec(d.HousingLoanInterest).IsPositive() && moneyToDec(d.HousingRent).IsPositive() {
+		return ErrHousingDeductionConflict
+	}
+	return nil
+}
+
+// overlaps 判断两份申报的有效期是否有重叠
+func (d DeductionDeclaration) overlaps(other DeductionDeclaration) bool {
+	// 一方的起始月份晚于另一方的截止月份则不重叠
+	if !d.To.IsZero() && monthStart(other.From).After(monthStart(d.To)) {
+		return false
+	}
+	if !other.To.IsZero() && monthStart(d.From).After(monthStart(other.To)) {
+		return false
+	}
+	return true
+}
+
+// ValidateDeclarations 校验员工的全部申报：逐份校验内容，并检查有效期重叠的申报是否同时申报了住房贷款利息和住房租金
+func ValidateDeclarations(declarations []DeductionDeclaration) error {
+	for i, d := range declarations {
+		if err := d.Validate(); err != nil {
+			return err
+		}
+		for _, other := range declarations[i+1:] {
+			if !d.overlaps(other) {
+				continue
+			}
+			combined := addDeductions(d.Deductions, other.Deductions)
+			if err := combined.Validate(); err != nil {
+				return fmt.Errorf("申报%s与申报%s: %w", d.ID, other.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// 继续教育扣除标准（分）
+var (
+	ContinuingDegreeMonthly     = toMoney(cenToDec(40000))  // 学历（学位）继续教育每月400元
+	ContinuingCertificateAmount = toMoney(cenToDec(360000)) // 职业资格继续教育取得证书当年3600元
+)
+
+// ContinuingDegreeMaxMonths 同一学历（学位）继续教育的扣除期限最长48个月
+const ContinuingDegreeMaxMonths = 48
+
+// ContinuingEducationKind 继续教育类型
+type ContinuingEducationKind int
+
+const (
+	ContinuingDegree      ContinuingEducationKind = iota // 学历（学位）继续教育
+	ContinuingCertificate                                // 职业资格继续教育
+)
+
+// ContinuingEducationDeclaration 继续教育申报信息
+type ContinuingEducationDeclaration struct {
+	Kind       ContinuingEducationKind // 继续教育类型
+	StartMonth time.Time               // 学历教育入学月份；职业资格为取得证书的月份
+}
+
+// MonthsUsed 截至指定薪资期（含）已享受学历继续教育扣除的月数
+func (c ContinuingEducationDeclaration) MonthsUsed(period time.Time) int {
+	p := monthStart(period)
+	start := monthStart(c.StartMonth)
+	if p.Before(start) {
+		return 0
+	}
+	return min(monthsBetween(start, p)+1, ContinuingDegreeMaxMonths)
+}
+
+// Exhausted 判断学历继续教育的48个月扣除期限在指定薪资期是否已用完
+func (c ContinuingEducationDeclaration) Exhausted(period time.Time) bool {
+	p := monthStart(period)
+	return c.Kind == ContinuingDegree && !p.Before(monthStart(c.StartMonth).AddDate(0, ContinuingDegreeMaxMonths, 0))
+}
+
+// Amount 计算指定薪资期的继续教育扣除额
+// 学历继续教育自入学当月起每月扣除，满48个月后自动停止；职业资格继续教育在取得证书当月一次性扣除
+func (c ContinuingEducationDeclaration) Amount(period time.Time) Money {
+	p := monthStart(period)
+	start := monthStart(c.StartMonth)
+	switch c.Kind {
+	case ContinuingCertificate:
+		if p.Equal(start) {
+			return ContinuingCertificateAmount
+		}
+	default:
+		if !p.Before(start) && !c.Exhausted(p) {
+			return ContinuingDegreeMonthly
+		}
+	}
+	return toMoney(decimal.Zero)
+}
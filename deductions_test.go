@@ -0,0 +1,222 @@
+package salary
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestEffectiveDeductionsValidity(t *testing.T) {
+	rent := DeductionDeclaration{
+		ID:         "D1",
+		Deductions: SpecialDeductions{HousingRent: toMoney(cenToDec(150000))},
+		From:       day("2024-03-01"),
+		To:         day("2024-06-30"),
+	}
+	elderly := DeductionDeclaration{
+		ID:         "D2",
+		Deductions: SpecialDeductions{SupportElderly: toMoney(cenToDec(300000))},
+		From:       day("2024-01-01"),
+	}
+	declarations := []DeductionDeclaration{rent, elderly}
+	cases := []struct {
+		period, rent, elderly string
+	}{
+		{"2024-02-01", "0", "300000"},
+		{"2024-03-01", "150000", "300000"},
+		{"2024-06-01", "150000", "300000"},
+		{"2024-07-01", "0", "300000"},
+	}
+	for _, tc := range cases {
+		got := EffectiveDeductions(declarations, day(tc.period))
+		assertMoney(t, tc.period+" rent", got.HousingRent, tc.rent)
+		assertMoney(t, tc.period+" elderly", got.SupportElderly, tc.elderly)
+	}
+
+	// 有申报记录时替代直接填写的扣除额
+	input := EmployeeInput{Deductions: SpecialDeductions{ChildrenEducation: toMoney(cenToDec(200000))}, Declarations: declarations}
+	if got := input.deductionsFor(day("2024-07-01")); !moneyToDec(got.ChildrenEducation).IsZero() {
+		t.Errorf("declarations should replace direct deductions, got %+v", got)
+	}
+}
+
+func TestEffectiveDeductionsSumsOverlappingDeclarations(t *testing.T) {
+	declarations := []DeductionDeclaration{
+		{ID: "D1", Deductions: SpecialDeductions{SupportElderly: toMoney(cenToDec(150000))}, From: day("2024-01-15")},
+		{ID: "D2", Deductions: SpecialDeductions{ChildrenEducation: toMoney(cenToDec(200000))}, From: day("2024-01-01"), To: day("2024-01-31")},
+	}
+	// 起止日期按月比较：1月15日起的申报在1月整月有效
+	got := EffectiveDeductions(declarations, day("2024-01-01"))
+	assertMoney(t, "January total", got.Total(), "350000")
+	assertMoney(t, "February total", EffectiveDeductions(declarations, day("2024-02-01")).Total(), "150000")
+
+	// 计算工资时按薪资期选取申报
+	input := EmployeeInput{Employee: Employee{ID: "E1"}, Config: testConfig(), Attendance: AttendanceRecord{WorkHours: hours("174")}, Declarations: declarations}
+	assertMoney(t, "January result", CalculateEmployee(day("2024-01-01"), input).SpecialDeductionTotal, "350000")
+	assertMoney(t, "February result", CalculateEmployee(day("2024-02-01"), input).SpecialDeductionTotal, "150000")
+
+	// 没有申报记录时沿用直接填写的扣除额
+	input.Declarations = nil
+	input.Deductions = SpecialDeductions{HousingRent: toMoney(cenToDec(150000))}
+	assertMoney(t, "direct deductions", input.deductionsFor(day("2024-02-01")).HousingRent, "150000")
+}
+
+func TestElderlySupportSplit(t *testing.T) {
+	yuan := func(v int64) Money { return toMoney(cenToDec(v * 100)) }
+	cases := []struct {
+		name    string
+		decl    ElderlySupportDeclaration
+		amount  string
+		invalid bool
+	}{
+		{"only child", ElderlySupportDeclaration{OnlyChild: true}, "300000", false},
+		{"equal split of three", ElderlySupportDeclaration{Siblings: 3}, "100000", false},
+		{"equal split of two", ElderlySupportDeclaration{Siblings: 2}, "150000", false},
+		{"equal split without siblings", ElderlySupportDeclaration{Siblings: 1}, "150000", true},
+		{"agreed share", ElderlySupportDeclaration{Split: ElderlySplitAgreed, Share: yuan(1200)}, "120000", false},
+		{"agreed share over personal cap", ElderlySupportDeclaration{Split: ElderlySplitAgreed, Share: yuan(1800)}, "180000", true},
+		{"designated share missing", ElderlySupportDeclaration{Split: ElderlySplitDesignated}, "0", true},
+		{"only child cannot split", ElderlySupportDeclaration{OnlyChild: true, Share: yuan(1500)}, "300000", true},
+	}
+	for _, tc := range cases {
+		assertMoney(t, tc.name, tc.decl.Amount(), tc.amount)
+		if err := tc.decl.Validate(); (err != nil) != tc.invalid {
+			t.Errorf("%s: Validate() = %v, want invalid %v", tc.name, err, tc.invalid)
+		}
+	}
+
+	if err := ValidateSiblingShares([]Money{yuan(1500), yuan(1500)}); err != nil {
+		t.Errorf("1500 + 1500: %v", err)
+	}
+	if err := ValidateSiblingShares([]Money{yuan(1000), yuan(1600)}); err == nil {
+		t.Error("share over 1500 accepted")
+	}
+	if err := ValidateSiblingShares([]Money{yuan(1500), yuan(1000), yuan(1000)}); err == nil {
+		t.Error("total over 3000 accepted")
+	}
+}
+
+func TestElderlySupportDeclarationOverridesAmount(t *testing.T) {
+	decl := DeductionDeclaration{
+		ID:             "D1",
+		Deductions:     SpecialDeductions{SupportElderly: toMoney(cenToDec(300000))},
+		From:           day("2024-01-01"),
+		ElderlySupport: &ElderlySupportDeclaration{Siblings: 2},
+	}
+	// 按分摊规则推导的金额覆盖直接填写的3000元
+	assertMoney(t, "equal split", EffectiveDeductions([]DeductionDeclaration{decl}, day("2024-03-01")).SupportElderly, "150000")
+
+	decl.ElderlySupport = &ElderlySupportDeclaration{Split: ElderlySplitDesignated, Share: toMoney(cenToDec(160000))}
+	err := ValidateDeclarations([]DeductionDeclaration{decl})
+	if err == nil || !strings.Contains(err.Error(), "D1") {
+		t.Errorf("err = %v, want declaration D1 rejected", err)
+	}
+}
+
+func TestChildDeductionPerChild(t *testing.T) {
+	infant := Child{Name: "小明", BirthDate: day("2023-06-15")}
+	student := Child{Name: "小红", BirthDate: day("2015-02-01"), Stage: StagePrimary, ShareRatio: decimal.RequireFromString("0.5")}
+	graduate := Child{Name: "小刚", BirthDate: day("2000-01-01"), Stage: StageFinished}
+	cases := []struct {
+		name   string
+		child  Child
+		period string
+		want   string
+	}{
+		{"before birth", infant, "2023-05-01", "0"},
+		{"birth month infant care", infant, "2023-06-01", "200000"},
+		{"turns three", infant, "2026-06-01", "200000"},
+		{"half share", student, "2024-01-01", "100000"},
+		{"finished education", graduate, "2024-01-01", "0"},
+		// 满3周岁前按婴幼儿照护扣除，不受受教育阶段影响
+		{"infant marked finished", Child{BirthDate: day("2023-06-15"), Stage: StageFinished}, "2026-05-01", "200000"},
+		{"finished after three", Child{BirthDate: day("2023-06-15"), Stage: StageFinished}, "2026-06-01", "0"},
+	}
+	for _, tc := range cases {
+		assertMoney(t, tc.name, tc.child.Amount(day(tc.period)), tc.want)
+	}
+
+	// 子女明细覆盖直接填写的子女教育扣除额
+	decl := DeductionDeclaration{
+		ID:         "D1",
+		Deductions: SpecialDeductions{ChildrenEducation: toMoney(cenToDec(50000))},
+		From:       day("2024-01-01"),
+		Children:   []Child{infant, student},
+	}
+	assertMoney(t, "two children", EffectiveDeductions([]DeductionDeclaration{decl}, day("2024-01-01")).ChildrenEducation, "300000")
+
+	decl.Children = append(decl.Children, Child{Name: "小华", ShareRatio: decimal.RequireFromString("0.3")})
+	if err := decl.Validate(); err == nil {
+		t.Error("30% share accepted")
+	}
+}
+
+func TestHousingLoanRentExclusive(t *testing.T) {
+	both := SpecialDeductions{HousingLoanInterest: toMoney(cenToDec(100000)), HousingRent: toMoney(cenToDec(150000))}
+	if err := both.Validate(); !errors.Is(err, ErrHousingDeductionConflict) {
+		t.Errorf("Validate() = %v, want ErrHousingDeductionConflict", err)
+	}
+
+	loan := DeductionDeclaration{ID: "LOAN", Deductions: SpecialDeductions{HousingLoanInterest: toMoney(cenToDec(100000))}, From: day("2024-01-01"), To: day("2024-06-30")}
+	rent := DeductionDeclaration{ID: "RENT", Deductions: SpecialDeductions{HousingRent: toMoney(cenToDec(150000))}, From: day("2024-06-01")}
+	if err := ValidateDeclarations([]DeductionDeclaration{loan, rent}); !errors.Is(err, ErrHousingDeductionConflict) {
+		t.Errorf("overlapping declarations: %v, want ErrHousingDeductionConflict", err)
+	}
+	// 贷款申报截止后再申报租金，期间不重叠
+	rent.From = day("2024-07-01")
+	if err := ValidateDeclarations([]DeductionDeclaration{loan, rent}); err != nil {
+		t.Errorf("consecutive declarations: %v", err)
+	}
+	// 长期有效的贷款申报与之后任何租金申报都重叠
+	loan.To = time.Time{}
+	if err := ValidateDeclarations([]DeductionDeclaration{loan, rent}); !errors.Is(err, ErrHousingDeductionConflict) {
+		t.Errorf("open-ended loan: %v, want ErrHousingDeductionConflict", err)
+	}
+	// 单份申报同时填写两项
+	if err := ValidateDeclarations([]DeductionDeclaration{{ID: "BOTH", Deductions: both}}); !errors.Is(err, ErrHousingDeductionConflict) {
+		t.Errorf("single declaration: %v, want ErrHousingDeductionConflict", err)
+	}
+}
+
+func TestContinuingEducationLimit(t *testing.T) {
+	degree := ContinuingEducationDeclaration{Kind: ContinuingDegree, StartMonth: day("2020-01-01")}
+	cases := []struct {
+		period, want string
+	}{
+		{"2019-12-01", "0"},
+		{"2020-01-01", "40000"},
+		// 第48个月仍可扣除，第49个月起停止
+		{"2023-12-01", "40000"},
+		{"2024-01-01", "0"},
+	}
+	for _, tc := range cases {
+		assertMoney(t, "degree "+tc.period, degree.Amount(day(tc.period)), tc.want)
+	}
+	if !degree.Exhausted(day("2024-01-01")) || degree.Exhausted(day("2023-12-01")) {
+		t.Error("48-month limit boundary wrong")
+	}
+	if got := degree.MonthsUsed(day("2024-06-01")); got != ContinuingDegreeMaxMonths {
+		t.Errorf("MonthsUsed = %d, want %d", got, ContinuingDegreeMaxMonths)
+	}
+
+	certificate := ContinuingEducationDeclaration{Kind: ContinuingCertificate, StartMonth: day("2024-05-20")}
+	assertMoney(t, "certificate month", certificate.Amount(day("2024-05-01")), "360000")
+	assertMoney(t, "after certificate month", certificate.Amount(day("2024-06-01")), "0")
+
+	decl := DeductionDeclaration{ID: "D1", From: day("2020-01-01"), ContinuingEducation: &degree}
+	assertMoney(t, "declaration after limit", EffectiveDeductions([]DeductionDeclaration{decl}, day("2024-02-01")).ContinuingEducation, "0")
+
+	// 入学前未使用期限；职业资格继续教育不受48个月期限限制
+	if got := degree.MonthsUsed(day("2019-12-01")); got != 0 {
+		t.Errorf("MonthsUsed before start = %d, want 0", got)
+	}
+	if got := degree.MonthsUsed(day("2021-06-01")); got != 18 {
+		t.Errorf("MonthsUsed = %d, want 18", got)
+	}
+	if certificate.Exhausted(day("2030-01-01")) {
+		t.Error("certificate should never be exhausted")
+	}
+}
@@ -0,0 +1,10 @@
+// Package salary 薪资计算库：工资、加班、社保公积金、个人所得税计算，以及发薪批次、工资条、报表和服务模式HTTP接口
+//
+// 金额类型 Money 和工时类型 Hours 基于 decimal.Decimal，金额单位为分，可直接类型转换：
+//
+//	base := salary.Money(decimal.NewFromInt(800000)) // 8000元
+//	hours := salary.Hours(decimal.NewFromInt(174))
+//
+// 单个员工使用 CalculateEmployee 计算，整个发薪批次使用 PayrollRun.Calculate；
+// 命令行程序见 cmd/salary。
+package salary
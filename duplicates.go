@@ -0,0 +1,111 @@
+package salary
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// BankAccountAllowance 允许多名员工共用的工资卡，如家庭共用账户、监护人代收
+type BankAccountAllowance struct {
+	Account     string   // 工资卡号
+	EmployeeIDs []string // 允许共用该卡号的员工工号
+	Reason      string   // 允许原因，如监护人代收
+}
+
+// BankAccountAllowList 卡号共用白名单
+type BankAccountAllowList []BankAccountAllowance
+
+// allows 判断共用卡号的员工是否全部在白名单中该卡号的登记范围内
+func (l BankAccountAllowList) allows(account string, employeeIDs []string) bool {
+	for _, a := range l {
+		if NormalizeBankAccount(a.Account) != account {
+			continue
+		}
+		allowed := true
+		for _, id := range employeeIDs {
+			allowed = allowed && slices.Contains(a.EmployeeIDs, id)
+		}
+		if allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// DuplicateBankAccount 被多名员工共用的工资卡
+type DuplicateBankAccount struct {
+	Account     string   // 脱敏卡号
+	EmployeeIDs []string // 共用该卡号的员工工号，按工号排序
+}
+
+// String 返回便于展示的说明
+func (d DuplicateBankAccount) String() string {
+	return fmt.Sprintf("卡号 %s 被 %s 共用", d.Account, strings.Join(d.EmployeeIDs, "、"))
+}
+
+// FindDuplicateBankAccounts 查找被不同员工共用的工资卡，常见于录入错误或虚构员工
+// 白名单登记的共用（家庭账户、监护人代收等）不计入结果
+// records: 员工档案
+// allow: 卡号共用白名单
+// 返回值: 需人工核实的共用卡号，按卡号排序
+func FindDuplicateBankAccounts(records []EmployeeRecord, allow BankAccountAllowList) []DuplicateBankAccount {
+	holders := make(map[string][]string)
+	for _, r := range records {
+		account := NormalizeBankAccount(r.BankAccount)
+		if account == "" || slices.Contains(holders[account], r.Employee.ID) {
+			continue
+		}
+		holders[account] = append(holders[account], r.Employee.ID)
+	}
+
+	accounts := make([]string, 0, len(holders))
+	for account, ids := range holders {
+		if len(ids) > 1 {
+			accounts = append(accounts, account)
+		}
+	}
+	sort.Strings(accounts)
+
+	var duplicates []DuplicateBankAccount
+	for _, account := range accounts {
+		ids := holders[account]
+		sort.Strings(ids)
+		if allow.allows(account, ids) {
+			continue
+		}
+		duplicates = append(duplicates, DuplicateBankAccount{Account: MaskBankAccount(account), EmployeeIDs: ids})
+	}
+	return duplicates
+}
+
+// CheckBankAccounts 发薪前校验本批次员工的工资卡，返回未在白名单登记的共用卡号
+// 只检查本批次计算输入中的员工；设置了审计日志时每个共用卡号记录一条审计记录
+// records: 员工档案，通常为 MemoryStore.Employees() 的结果
+// allow: 卡号共用白名单
+func (r *PayrollRun) CheckBankAccounts(records []EmployeeRecord, allow BankAccountAllowList) []DuplicateBankAccount {
+	inRun := make(map[string]bool, len(r.Inputs))
+	for _, input := range r.Inputs {
+		inRun[input.Employee.ID] = true
+	}
+	var scoped []EmployeeRecord
+	for _, record := range records {
+		if inRun[record.Employee.ID] {
+			scoped = append(scoped, record)
+		}
+	}
+
+	duplicates := FindDuplicateBankAccounts(scoped, allow)
+	if r.Audit != nil {
+		for _, d := range duplicates {
+			r.Audit.Record(AuditEntry{
+				Actor:  "system",
+				Action: "bank_account.duplicate",
+				Period: monthStart(r.Period),
+				Detail: d.String(),
+			})
+		}
+	}
+	return duplicates
+}
@@ -0,0 +1,63 @@
+package salary
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFindDuplicateBankAccounts(t *testing.T) {
+	records := []EmployeeRecord{
+		{Employee: Employee{ID: "E3"}, BankAccount: "6222 0212 3456 7890 128"},
+		{Employee: Employee{ID: "E1"}, BankAccount: "6222021234567890128"},
+		{Employee: Employee{ID: "E2"}, BankAccount: "6217000010001234567"},
+		{Employee: Employee{ID: "E4"}, BankAccount: "6217-0000-1000-1234-567"},
+		{Employee: Employee{ID: "E5"}, BankAccount: "6228480000000000001"},
+		{Employee: Employee{ID: "E6"}},
+		{Employee: Employee{ID: "E7"}},
+	}
+
+	// 格式不同的同一卡号视为共用，未填写卡号的员工不计入
+	duplicates := FindDuplicateBankAccounts(records, nil)
+	if len(duplicates) != 2 {
+		t.Fatalf("duplicates = %+v", duplicates)
+	}
+	if !reflect.DeepEqual(duplicates[0].EmployeeIDs, []string{"E2", "E4"}) || !reflect.DeepEqual(duplicates[1].EmployeeIDs, []string{"E1", "E3"}) {
+		t.Errorf("duplicates = %+v", duplicates)
+	}
+	if duplicates[1].Account != MaskBankAccount("6222021234567890128") || !strings.Contains(duplicates[1].String(), "E1、E3") {
+		t.Errorf("duplicate = %s", duplicates[1])
+	}
+
+	// 白名单需覆盖全部共用员工
+	allow := BankAccountAllowList{
+		{Account: "6222 0212 3456 7890 128", EmployeeIDs: []string{"E1", "E3"}, Reason: "监护人代收"},
+		{Account: "6217000010001234567", EmployeeIDs: []string{"E2"}},
+	}
+	duplicates = FindDuplicateBankAccounts(records, allow)
+	if len(duplicates) != 1 || duplicates[0].EmployeeIDs[0] != "E2" {
+		t.Errorf("with allow list = %+v", duplicates)
+	}
+}
+
+func TestCheckBankAccountsScopedToRun(t *testing.T) {
+	records := []EmployeeRecord{
+		{Employee: Employee{ID: "E1"}, BankAccount: "6222021234567890128"},
+		{Employee: Employee{ID: "E2"}, BankAccount: "6222021234567890128"},
+		{Employee: Employee{ID: "E3"}, BankAccount: "6222021234567890128"},
+	}
+	run := PayrollRun{Period: day("2024-05-01"), Audit: NewAuditLog(), Inputs: []EmployeeInput{{Employee: Employee{ID: "E1"}}}}
+	if duplicates := run.CheckBankAccounts(records, nil); len(duplicates) != 0 {
+		t.Errorf("single employee in run = %+v", duplicates)
+	}
+
+	run.Inputs = append(run.Inputs, EmployeeInput{Employee: Employee{ID: "E2"}})
+	duplicates := run.CheckBankAccounts(records, nil)
+	if len(duplicates) != 1 || !reflect.DeepEqual(duplicates[0].EmployeeIDs, []string{"E1", "E2"}) {
+		t.Fatalf("duplicates = %+v", duplicates)
+	}
+	entries := run.Audit.Entries()
+	if len(entries) != 1 || entries[0].Action != "bank_account.duplicate" || !entries[0].Period.Equal(day("2024-05-01")) {
+		t.Errorf("audit = %+v", entries)
+	}
+}
@@ -0,0 +1,120 @@
+package salary
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ElementKind 工资项目类型
+type ElementKind int
+
+const (
+	KindEarning       ElementKind = iota // 收入项
+	KindDeduction                        // 扣款项
+	KindBenefitInKind                    // 非现金福利：按价值计入应纳税所得额，不发放现金
+)
+
+// PayElement 周期性工资项目，在有效期内的每个薪资期自动计入，无需每月手工录入
+// 例如：每月交通补贴；从3月起连续6个月扣除培训费
+type PayElement struct {
+	Code        string      // 项目代码
+	Name        string      // 项目名称
+	Kind        ElementKind // 收入项或扣款项
+	Amount      Money       // 每期金额（分）
+	Taxable     bool        // 收入项：是否计入应纳税所得额；扣款项：是否为税前扣除
+	Start       time.Time   // 首次计入的月份
+	End         time.Time   // 最后计入的月份，零值表示不限
+	Occurrences int         // 计入次数，0表示不限
+}
+
+// ActiveIn 判断项目是否在指定薪资期内生效
+func (e PayElement) ActiveIn(period time.Time) bool {
+	p := monthStart(period)
+	start := monthStart(e.Start)
+	if p.Before(start) {
+		return false
+	}
+	if !e.End.IsZero() && p.After(monthStart(e.End)) {
+		return false
+	}
+	if e.Occurrences > 0 && monthsBetween(start, p) >= e.Occurrences {
+		return false
+	}
+	return true
+}
+
+// PayLine 工资条上的一行明细
+type PayLine struct {
+	Code    string      // 项目代码
+	Name    string      // 项目名称
+	Kind    ElementKind // 收入项或扣款项
+	Amount  Money       // 金额（分）
+	Taxable bool        // 收入项：是否计税；扣款项：是否税前扣除
+}
+
+// elementLines 生成指定薪资期内生效的周期性项目明细
+func elementLines(elements []PayElement, period time.Time) []PayLine {
+	var lines []PayLine
+	for _, e := range elements {
+		if !e.ActiveIn(period) {
+			continue
+		}
+		lines = append(lines, PayLine{Code: e.Code, Name: e.Name, Kind: e.Kind, Amount: e.Amount, Taxable: e.Taxable})
+	}
+	return lines
+}
+
+// lineTotals 按类别汇总明细金额，非现金福利不计入，由 benefitTotal 单独汇总
+// 返回值: (计税收入, 免税收入, 税前扣款, 税后扣款)
+func lineTotals(lines []PayLine) (taxableEarnings, exemptEarnings, preTaxDeductions, postTaxDeductions decimal.Decimal) {
+	for _, line := range lines {
+		amount := moneyToDec(line.Amount)
+		switch {
+		case line.Kind == KindBenefitInKind:
+			continue
+		case line.Kind == KindEarning && line.Taxable:
+			taxableEarnings = taxableEarnings.Add(amount)
+		case line.Kind == KindEarning:
+			exemptEarnings = exemptEarnings.Add(amount)
+		case line.Taxable:
+			preTaxDeductions = preTaxDeductions.Add(amount)
+		default:
+			postTaxDeductions = postTaxDeductions.Add(amount)
+		}
+	}
+	return taxableEarnings, exemptEarnings, preTaxDeductions, postTaxDeductions
+}
+
+// benefitTotal 汇总非现金福利的应税价值
+func benefitTotal(lines []PayLine) decimal.Decimal {
+	total := decimal.Zero
+	for _, line := range lines {
+		if line.Kind == KindBenefitInKind {
+			total = total.Add(moneyToDec(line.Amount))
+		}
+	}
+	return total
+}
+
+// BenefitInKind 非现金福利，如公司车辆私用、公司提供住房、超过免税额的礼品
+type BenefitInKind struct {
+	Code      string // 项目代码
+	Name      string // 项目名称
+	Value     Money  // 福利的市场价值（分）
+	Exemption Money  // 免税额（分），超过部分计税
+}
+
+// Element 生成指定薪资期计入一次的非现金福利项目，金额为超过免税额的应税价值
+func (b BenefitInKind) Element(period time.Time) PayElement {
+	taxable := decimal.Max(moneyToDec(b.Value).Sub(moneyToDec(b.Exemption)), decimal.Zero)
+	return PayElement{
+		Code:        b.Code,
+		Name:        b.Name,
+		Kind:        KindBenefitInKind,
+		Amount:      toMoney(taxable),
+		Taxable:     true,
+		Start:       period,
+		Occurrences: 1,
+	}
+}
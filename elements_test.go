@@ -0,0 +1,110 @@
+package salary
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPayElementActiveIn(t *testing.T) {
+	cases := []struct {
+		name    string
+		element PayElement
+		period  string
+		want    bool
+	}{
+		{"before start", PayElement{Start: day("2024-03-15")}, "2024-02-01", false},
+		{"start month", PayElement{Start: day("2024-03-15")}, "2024-03-01", true},
+		{"open ended", PayElement{Start: day("2024-03-01")}, "2030-01-01", true},
+		{"end month", PayElement{Start: day("2024-03-01"), End: day("2024-05-31")}, "2024-05-01", true},
+		{"after end", PayElement{Start: day("2024-03-01"), End: day("2024-05-31")}, "2024-06-01", false},
+		{"last occurrence", PayElement{Start: day("2024-03-01"), Occurrences: 6}, "2024-08-01", true},
+		{"occurrences used", PayElement{Start: day("2024-03-01"), Occurrences: 6}, "2024-09-01", false},
+	}
+	for _, tc := range cases {
+		if got := tc.element.ActiveIn(day(tc.period)); got != tc.want {
+			t.Errorf("%s: ActiveIn(%s) = %v, want %v", tc.name, tc.period, got, tc.want)
+		}
+	}
+}
+
+func TestRecurringElementsInPayroll(t *testing.T) {
+	input := EmployeeInput{
+		Employee: Employee{ID: "E1"}, Config: testConfig(),
+		Attendance: AttendanceRecord{WorkHours: hours("174")},
+		Elements: []PayElement{
+			{Code: "TRANSPORT", Name: "交通补贴", Kind: KindEarning, Amount: toMoney(cenToDec(50000)), Start: day("2024-01-01")},
+			{Code: "MEAL", Name: "餐补", Kind: KindEarning, Amount: toMoney(cenToDec(30000)), Taxable: true, Start: day("2024-01-01")},
+			{Code: "TRAINING", Name: "培训费", Kind: KindDeduction, Amount: toMoney(cenToDec(20000)), Start: day("2024-03-01"), Occurrences: 6},
+		},
+	}
+	march := CalculateEmployee(day("2024-03-01"), input)
+	if len(march.Lines) != 3 {
+		t.Fatalf("lines = %+v", march.Lines)
+	}
+	// 免税的交通补贴和计税的餐补都计入税前工资，培训费从实发中扣除
+	base := CalculateEmployee(day("2024-03-01"), EmployeeInput{Employee: input.Employee, Config: input.Config, Attendance: input.Attendance})
+	assertMoney(t, "gross", march.GrossSalary, moneyToDec(base.GrossSalary).Add(cenToDec(80000)).String())
+
+	february := CalculateEmployee(day("2024-02-01"), input)
+	if len(february.Lines) != 2 {
+		t.Errorf("February lines = %+v, training deduction should not start yet", february.Lines)
+	}
+}
+
+func TestLineTotals(t *testing.T) {
+	lines := []PayLine{
+		{Kind: KindEarning, Amount: toMoney(cenToDec(100)), Taxable: true},
+		{Kind: KindEarning, Amount: toMoney(cenToDec(200))},
+		{Kind: KindDeduction, Amount: toMoney(cenToDec(300)), Taxable: true},
+		{Kind: KindDeduction, Amount: toMoney(cenToDec(400))},
+	}
+	taxable, exempt, preTax, postTax := lineTotals(lines)
+	for _, c := range []struct {
+		name string
+		got  Money
+		want string
+	}{
+		{"taxable", toMoney(taxable), "100"},
+		{"exempt", toMoney(exempt), "200"},
+		{"pre-tax", toMoney(preTax), "300"},
+		{"post-tax", toMoney(postTax), "400"},
+	} {
+		assertMoney(t, c.name, c.got, c.want)
+	}
+}
+
+func TestBenefitInKind(t *testing.T) {
+	car := BenefitInKind{Code: "CAR", Name: "公司车辆私用", Value: toMoney(cenToDec(250000)), Exemption: toMoney(cenToDec(50000))}
+	element := car.Element(day("2024-03-01"))
+	if element.Kind != KindBenefitInKind || !element.ActiveIn(day("2024-03-01")) || element.ActiveIn(day("2024-04-01")) {
+		t.Errorf("element = %+v", element)
+	}
+	assertMoney(t, "taxable value", element.Amount, "200000")
+	gift := BenefitInKind{Value: toMoney(cenToDec(30000)), Exemption: toMoney(cenToDec(50000))}
+	assertMoney(t, "under exemption", gift.Element(day("2024-03-01")).Amount, "0")
+
+	input := EmployeeInput{Employee: Employee{ID: "E1"}, Config: testConfig(), Attendance: AttendanceRecord{WorkHours: hours("174")}}
+	without := CalculateEmployee(day("2024-03-01"), input)
+	input.Elements = []PayElement{element}
+	with := CalculateEmployee(day("2024-03-01"), input)
+
+	// 只计税、不发放现金：税前工资不变，应纳税所得额增加，实发减少的只是多扣的个税
+	assertMoney(t, "gross", with.GrossSalary, moneyToDec(without.GrossSalary).String())
+	assertMoney(t, "benefits", with.BenefitsInKind, "200000")
+	assertMoney(t, "taxable", with.TaxableIncome, moneyToDec(without.TaxableIncome).Add(cenToDec(200000)).String())
+	extraTax := moneyToDec(with.IncomeTax).Sub(moneyToDec(without.IncomeTax))
+	if !extraTax.IsPositive() {
+		t.Fatalf("extra tax = %s", extraTax)
+	}
+	assertMoney(t, "net", with.NetSalary, moneyToDec(without.NetSalary).Sub(extraTax).String())
+
+	var b strings.Builder
+	RenderPayslipText(&b, with, PayslipOptions{})
+	if !strings.Contains(b.String(), "公司车辆私用（非现金，仅计税）") {
+		t.Errorf("payslip:\n%s", b.String())
+	}
+	bundle := BuildPortalBundle(with, nil, "zh-CN", nil)
+	if line := portalLine(t, bundle, "benefits", "CAR"); line.Label != "公司车辆私用" {
+		t.Errorf("portal line = %+v", line)
+	}
+}
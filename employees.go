@@ -0,0 +1,307 @@
+package salary
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrEmployeeNotFound 员工档案不存在
+var ErrEmployeeNotFound = errors.New("员工档案不存在")
+
+// ErrVersionConflict 员工档案已被他人修改，提交的版本号已过期
+var ErrVersionConflict = errors.New("员工档案版本冲突")
+
+// EmployeeRecord 员工主数据，Version 随每次修改递增，用于检测并发编辑
+type EmployeeRecord struct {
+	Employee     Employee               `json:"employee"`                // 员工档案
+	BaseSalary   Money                  `json:"base_salary"`             // 基本工资（分）
+	BankAccount  string                 `json:"bank_account"`            // 工资卡号，接口响应中脱敏
+	BankName     string                 `json:"bank_name"`               // 按卡号识别的发卡行，未收录时为空
+	PaymentSplit *PaymentSplit          `json:"payment_split,omitempty"` // 实发工资分账，为空表示全部发放到工资卡
+	Declarations []DeductionDeclaration `json:"declarations"`            // 专项附加扣除申报
+	PriorPension *PensionHistory        `json:"prior_pension,omitempty"` // 入职前在其他单位的养老保险缴费记录
+	Version      int                    `json:"version"`                 // 行版本号
+	UpdatedBy    string                 `json:"updated_by"`              // 最后修改人
+	UpdatedAt    time.Time              `json:"updated_at"`              // 最后修改时间
+}
+
+// EmployeeUpdate 单个员工的修改内容，为空的字段保持不变
+type EmployeeUpdate struct {
+	EmployeeID   string                  `json:"employee_id"`             // 工号
+	Version      int                     `json:"version"`                 // 修改前读取到的版本号
+	BaseSalary   *Money                  `json:"base_salary,omitempty"`   // 新的基本工资（分）
+	BankAccount  *string                 `json:"bank_account,omitempty"`  // 新的工资卡号
+	PaymentSplit *PaymentSplit           `json:"payment_split,omitempty"` // 新的分账设置，金额为0表示取消分账
+	Declarations *[]DeductionDeclaration `json:"declarations,omitempty"`  // 新的专项附加扣除申报，整体替换
+	ApprovedBy   string                  `json:"approved_by,omitempty"`   // 第二审批人，涨薪超过四眼原则阈值时必填
+}
+
+// UpdateConflict 批量修改中的一条冲突
+type UpdateConflict struct {
+	EmployeeID      string `json:"employee_id"`      // 工号
+	ExpectedVersion int    `json:"expected_version"` // 提交的版本号
+	CurrentVersion  int    `json:"current_version"`  // 当前版本号，0表示档案不存在
+}
+
+// BulkUpdateError 批量修改失败，列出全部冲突；任一冲突时整批不生效
+type BulkUpdateError struct {
+	Conflicts []UpdateConflict
+}
+
+// Error 实现 error 接口
+func (e *BulkUpdateError) Error() string {
+	ids := make([]string, len(e.Conflicts))
+	for i, c := range e.Conflicts {
+		ids[i] = c.EmployeeID
+	}
+	return fmt.Sprintf("%v: %s", ErrVersionConflict, strings.Join(ids, ", "))
+}
+
+// Unwrap 返回 ErrVersionConflict，便于 errors.Is 判断
+func (e *BulkUpdateError) Unwrap() error {
+	return ErrVersionConflict
+}
+
+// ErrEmployeeExists 新建的员工档案已存在
+var ErrEmployeeExists = errors.New("员工档案已存在")
+
+// PutEmployee 新建或覆盖员工档案，版本号重置为下一版本
+func (s *MemoryStore) PutEmployee(record EmployeeRecord) EmployeeRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.putEmployee(record)
+}
+
+// CreateEmployee 新建员工档案，校验工资卡号、分账设置和申报内容；工号已存在时返回 ErrEmployeeExists，
+// 修改已有档案应使用 BulkUpdateEmployees 以检测并发编辑
+func (s *MemoryStore) CreateEmployee(record EmployeeRecord) (EmployeeRecord, error) {
+	if record.Employee.ID == "" {
+		return EmployeeRecord{}, errors.New("员工档案缺少工号")
+	}
+	if record.BankAccount != "" {
+		if _, err := ValidateBankAccount(record.BankAccount); err != nil {
+			return EmployeeRecord{}, err
+		}
+	}
+	if record.PaymentSplit != nil {
+		if err := record.PaymentSplit.Validate(); err != nil {
+			return EmployeeRecord{}, err
+		}
+	}
+	if err := ValidateDeclarations(record.Declarations); err != nil {
+		return EmployeeRecord{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.employees[record.Employee.ID]; ok {
+		return EmployeeRecord{}, fmt.Errorf("%w: %s", ErrEmployeeExists, record.Employee.ID)
+	}
+	return s.putEmployee(record), nil
+}
+
+// putEmployee 保存员工档案，调用方需持有写锁
+func (s *MemoryStore) putEmployee(record EmployeeRecord) EmployeeRecord {
+	record.Version = s.employees[record.Employee.ID].Version + 1
+	if record.BankAccount != "" {
+		record.BankAccount = NormalizeBankAccount(record.BankAccount)
+		bank, _ := LookupBank(record.BankAccount)
+		record.BankName = bank.Name
+	}
+	if record.PaymentSplit != nil {
+		split := record.PaymentSplit.normalized()
+		record.PaymentSplit = &split
+	}
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = time.Now()
+	}
+	s.employees[record.Employee.ID] = record
+	return record
+}
+
+// Employee 查询员工档案
+func (s *MemoryStore) Employee(id string) (EmployeeRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	record, ok := s.employees[id]
+	if !ok {
+		return EmployeeRecord{}, fmt.Errorf("%w: %s", ErrEmployeeNotFound, id)
+	}
+	return record, nil
+}
+
+// Employees 查询全部员工档案，按工号排序
+func (s *MemoryStore) Employees() []EmployeeRecord {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	records := make([]EmployeeRecord, 0, len(s.employees))
+	for _, r := range s.employees {
+		records = append(records, r)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Employee.ID < records[j].Employee.ID })
+	return records
+}
+
+// BulkUpdateEmployees 批量修改员工档案
+// 先校验全部版本号、工资卡号、分账设置和申报内容，任一员工不存在、版本过期、卡号或申报无效时整批不生效，
+// 避免两位HR同时编辑时后提交者覆盖先提交者的修改
+// updates: 修改内容
+// operator: 操作人
+// 返回值: 修改后的档案，顺序与输入一致
+func (s *MemoryStore) BulkUpdateEmployees(updates []EmployeeUpdate, operator string) ([]EmployeeRecord, error) {
+	return s.bulkUpdateEmployees(updates, operator, time.Now())
+}
+
+// bulkUpdateEmployees 批量修改员工档案，now 为修改时间
+func (s *MemoryStore) bulkUpdateEmployees(updates []EmployeeUpdate, operator string, now time.Time) ([]EmployeeRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var conflicts []UpdateConflict
+	seen := make(map[string]bool, len(updates))
+	for _, u := range updates {
+		if seen[u.EmployeeID] {
+			return nil, fmt.Errorf("同一批次中重复修改员工 %s", u.EmployeeID)
+		}
+		seen[u.EmployeeID] = true
+
+		current, ok := s.employees[u.EmployeeID]
+		if !ok || current.Version != u.Version {
+			conflicts = append(conflicts, UpdateConflict{EmployeeID: u.EmployeeID, ExpectedVersion: u.Version, CurrentVersion: current.Version})
+			continue
+		}
+		if u.BaseSalary != nil && s.fourEyes != nil {
+			if err := s.fourEyes.CheckSalaryChange(current.BaseSalary, *u.BaseSalary, operator, u.ApprovedBy); err != nil {
+				return nil, fmt.Errorf("员工 %s: %w", u.EmployeeID, err)
+			}
+		}
+		if u.BankAccount != nil {
+			if _, err := ValidateBankAccount(*u.BankAccount); err != nil {
+				return nil, fmt.Errorf("员工 %s: %w", u.EmployeeID, err)
+			}
+		}
+		if u.PaymentSplit != nil && !moneyToDec(u.PaymentSplit.Amount).IsZero() {
+			if err := u.PaymentSplit.Validate(); err != nil {
+				return nil, fmt.Errorf("员工 %s: %w", u.EmployeeID, err)
+			}
+		}
+		if u.Declarations != nil {
+			if err := ValidateDeclarations(*u.Declarations); err != nil {
+				return nil, fmt.Errorf("员工 %s: %w", u.EmployeeID, err)
+			}
+		}
+	}
+	if len(conflicts) > 0 {
+		return nil, &BulkUpdateError{Conflicts: conflicts}
+	}
+
+	records := make([]EmployeeRecord, 0, len(updates))
+	for _, u := range updates {
+		record := s.employees[u.EmployeeID]
+		if u.BaseSalary != nil {
+			// 每次修改基本工资都记录变更，有第二审批人时另记审批
+			if s.audit != nil {
+				change := fmt.Sprintf("基本工资由%s调整为%s", FormatMoneyCenToYuan(record.BaseSalary), FormatMoneyCenToYuan(*u.BaseSalary))
+				s.audit.Record(AuditEntry{
+					Time:       now,
+					Actor:      operator,
+					Action:     "salary.change",
+					EmployeeID: u.EmployeeID,
+					Detail:     change,
+				})
+				if u.ApprovedBy != "" {
+					s.audit.Record(AuditEntry{
+						Time:       now,
+						Actor:      u.ApprovedBy,
+						Action:     "salary.second_approval",
+						EmployeeID: u.EmployeeID,
+						Detail:     fmt.Sprintf("%s，发起人%s", change, operator),
+					})
+				}
+			}
+			record.BaseSalary = *u.BaseSalary
+		}
+		if u.BankAccount != nil {
+			bank, _ := LookupBank(*u.BankAccount)
+			record.BankAccount = NormalizeBankAccount(*u.BankAccount)
+			record.BankName = bank.Name
+		}
+		if u.PaymentSplit != nil {
+			record.PaymentSplit = nil
+			if !moneyToDec(u.PaymentSplit.Amount).IsZero() {
+				split := u.PaymentSplit.normalized()
+				record.PaymentSplit = &split
+			}
+		}
+		if u.Declarations != nil {
+			record.Declarations = *u.Declarations
+		}
+		record.Version++
+		record.UpdatedBy = operator
+		record.UpdatedAt = now
+		s.employees[u.EmployeeID] = record
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// handleGetEmployee 查询员工档案及当前版本号
+func (s *Server) handleGetEmployee(w http.ResponseWriter, r *http.Request) {
+	record, err := s.store.Employee(r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, record.Masked())
+}
+
+// handleCreateEmployee 新建员工档案：POST /employees，请求体为员工档案，版本号和修改时间由服务端生成
+// 工号已存在时返回 409
+func (s *Server) handleCreateEmployee(w http.ResponseWriter, r *http.Request) {
+	var record EmployeeRecord
+	if err := json.NewDecoder(r.Body).Decode(&record); err != nil {
+		writeError(w, fmt.Errorf("请求格式错误: %w", err))
+		return
+	}
+	record.UpdatedAt = requestTime(r.Context())
+	record, err := s.store.CreateEmployee(record)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, record.Masked())
+}
+
+// handleBulkUpdateEmployees 批量修改员工档案：PATCH /employees
+// 请求体：{"operator": "...", "updates": [{"employee_id": "...", "version": 1, ...}]}
+// 版本冲突时返回 409 及冲突明细
+func (s *Server) handleBulkUpdateEmployees(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Operator string           `json:"operator"`
+		Updates  []EmployeeUpdate `json:"updates"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, err)
+		return
+	}
+	records, err := s.store.bulkUpdateEmployees(req.Updates, req.Operator, requestTime(r.Context()))
+	var conflict *BulkUpdateError
+	if errors.As(err, &conflict) {
+		body := errorBody(err)
+		body["conflicts"] = conflict.Conflicts
+		writeJSON(w, http.StatusConflict, body)
+		return
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	for i := range records {
+		records[i] = records[i].Masked()
+	}
+	writeJSON(w, http.StatusOK, records)
+}
@@ -0,0 +1,110 @@
+package salary
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestBulkUpdateEmployeesVersionConflict(t *testing.T) {
+	store := NewMemoryStore()
+	store.PutEmployee(EmployeeRecord{Employee: Employee{ID: "E1"}, BaseSalary: toMoney(cenToDec(800000))})
+	store.PutEmployee(EmployeeRecord{Employee: Employee{ID: "E2"}, BaseSalary: toMoney(cenToDec(900000))})
+	raise := toMoney(cenToDec(850000))
+
+	// 第一位HR修改 E1 后，第二位HR按旧版本提交，整批不生效
+	if _, err := store.BulkUpdateEmployees([]EmployeeUpdate{{EmployeeID: "E1", Version: 1, BaseSalary: &raise}}, "hr1"); err != nil {
+		t.Fatal(err)
+	}
+	account := "6222021234567890128"
+	_, err := store.BulkUpdateEmployees([]EmployeeUpdate{
+		{EmployeeID: "E2", Version: 1, BankAccount: &account},
+		{EmployeeID: "E1", Version: 1, BaseSalary: &raise},
+		{EmployeeID: "E9", Version: 1},
+	}, "hr2")
+	var conflict *BulkUpdateError
+	if !errors.As(err, &conflict) || !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("err = %v, want BulkUpdateError", err)
+	}
+	want := []UpdateConflict{{EmployeeID: "E1", ExpectedVersion: 1, CurrentVersion: 2}, {EmployeeID: "E9", ExpectedVersion: 1, CurrentVersion: 0}}
+	if len(conflict.Conflicts) != 2 || conflict.Conflicts[0] != want[0] || conflict.Conflicts[1] != want[1] {
+		t.Errorf("conflicts = %+v", conflict.Conflicts)
+	}
+	if e2, _ := store.Employee("E2"); e2.Version != 1 || e2.BankAccount != "" {
+		t.Errorf("E2 changed despite conflict: %+v", e2)
+	}
+
+	records, err := store.BulkUpdateEmployees([]EmployeeUpdate{{EmployeeID: "E2", Version: 1, BankAccount: &account}}, "hr2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if records[0].Version != 2 || records[0].UpdatedBy != "hr2" || records[0].BankAccount != account {
+		t.Errorf("record = %+v", records[0])
+	}
+}
+
+func TestBulkUpdateEmployeesRejectsInvalidBatch(t *testing.T) {
+	store := NewMemoryStore()
+	store.PutEmployee(EmployeeRecord{Employee: Employee{ID: "E1"}})
+	store.PutEmployee(EmployeeRecord{Employee: Employee{ID: "E2"}})
+	valid, invalid := "6222021234567890128", "6222021234567890123"
+
+	if _, err := store.BulkUpdateEmployees([]EmployeeUpdate{
+		{EmployeeID: "E1", Version: 1, BankAccount: &valid},
+		{EmployeeID: "E1", Version: 1, BankAccount: &valid},
+	}, "hr"); err == nil || errors.Is(err, ErrVersionConflict) {
+		t.Errorf("duplicate employee err = %v", err)
+	}
+	if _, err := store.BulkUpdateEmployees([]EmployeeUpdate{
+		{EmployeeID: "E1", Version: 1, BankAccount: &valid},
+		{EmployeeID: "E2", Version: 1, BankAccount: &invalid},
+	}, "hr"); err == nil || !strings.Contains(err.Error(), "E2") {
+		t.Errorf("invalid account err = %v", err)
+	}
+	conflicting := []DeductionDeclaration{{ID: "D1", Deductions: SpecialDeductions{
+		HousingLoanInterest: toMoney(cenToDec(100000)),
+		HousingRent:         toMoney(cenToDec(150000)),
+	}}}
+	if _, err := store.BulkUpdateEmployees([]EmployeeUpdate{
+		{EmployeeID: "E1", Version: 1, BankAccount: &valid},
+		{EmployeeID: "E2", Version: 1, Declarations: &conflicting},
+	}, "hr"); !errors.Is(err, ErrHousingDeductionConflict) {
+		t.Errorf("conflicting declarations err = %v", err)
+	}
+	if e1, _ := store.Employee("E1"); e1.Version != 1 || e1.BankAccount != "" {
+		t.Errorf("E1 changed by rejected batch: %+v", e1)
+	}
+}
+
+func TestPutEmployeeBumpsVersion(t *testing.T) {
+	store := NewMemoryStore()
+	first := store.PutEmployee(EmployeeRecord{Employee: Employee{ID: "E1"}, BankAccount: "6222 0212 3456 7890 128"})
+	if first.Version != 1 || first.BankAccount != "6222021234567890128" || first.BankName == "" {
+		t.Errorf("first = %+v", first)
+	}
+	// 覆盖档案同样递增版本号，持有旧版本的批量修改随之冲突
+	if second := store.PutEmployee(first); second.Version != 2 {
+		t.Errorf("second version = %d, want 2", second.Version)
+	}
+	if _, err := store.BulkUpdateEmployees([]EmployeeUpdate{{EmployeeID: "E1", Version: 1}}, "hr"); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("err = %v, want ErrVersionConflict", err)
+	}
+}
+
+func TestHandleBulkUpdateEmployeesConflict(t *testing.T) {
+	store := NewMemoryStore()
+	store.PutEmployee(EmployeeRecord{Employee: Employee{ID: "E1"}})
+	server := NewServer(store)
+
+	rec := serve(server, http.MethodPatch, "/employees", "application/json",
+		`{"operator":"hr","updates":[{"employee_id":"E1","version":3,"bank_account":"6222021234567890128"}]}`)
+	if rec.Code != http.StatusConflict || !strings.Contains(rec.Body.String(), `"current_version":1`) {
+		t.Errorf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+	rec = serve(server, http.MethodPatch, "/employees", "application/json",
+		`{"operator":"hr","updates":[{"employee_id":"E1","version":1,"bank_account":"6222021234567890128"}]}`)
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+}
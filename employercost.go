@@ -0,0 +1,91 @@
+package salary
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// EmployerRates 单位缴费费率
+type EmployerRates struct {
+	Pension      decimal.Decimal // 养老保险单位费率
+	Medical      decimal.Decimal // 医疗保险单位费率
+	Unemployment decimal.Decimal // 失业保险单位费率
+	WorkInjury   decimal.Decimal // 工伤保险单位费率，按行业风险类别确定
+	Maternity    decimal.Decimal // 生育保险单位费率，已并入医疗保险的城市为0
+	HousingFund  decimal.Decimal // 公积金单位费率
+}
+
+// EmployerCost 单位承担的社保公积金及员工全口径人工成本（分）
+type EmployerCost struct {
+	Pension            Money // 养老保险单位部分
+	Medical            Money // 医疗保险单位部分
+	Unemployment       Money // 失业保险单位部分
+	WorkInjury         Money // 工伤保险
+	Maternity          Money // 生育保险
+	HousingFund        Money // 公积金单位部分
+	SocialInsurance    Money // 社保单位部分合计（不含公积金）
+	OtherContributions Money // 其他单位缴费，如企业年金、香港强积金雇主供款
+	TotalContributions Money // 单位缴费合计 = 社保 + 公积金 + 其他单位缴费
+	GrossSalary        Money // 税前工资
+	EmployerTax        Money // 公司承担的个税，如税后工资合同、税收均衡中公司承担的部分
+	LoadedCost         Money // 全口径人工成本 = 税前工资 + 单位缴费合计
+}
+
+// CalculateEmployerCost 计算员工本期的单位缴费和全口径人工成本，用于预算和财务报表
+// 单位社保公积金的缴费基数与个人部分相同，按配置的上下限封顶保底；工伤保险按养老保险基数、生育保险按医疗保险基数缴纳
+// 停薪期间选择继续缴纳时按合同工资计算；中国内地以外辖区（如香港）的员工只计入结果中的 EmployerContributions
+// config: 员工适用的薪资配置
+// result: 员工本期计算结果
+func CalculateEmployerCost(config PayrollConfig, result EmployeeResult) EmployerCost {
+	zero := toMoney(decimal.Zero)
+	cost := EmployerCost{
+		Pension: zero, Medical: zero, Unemployment: zero, WorkInjury: zero, Maternity: zero,
+		HousingFund: zero, SocialInsurance: zero,
+		OtherContributions: result.EmployerContributions,
+		GrossSalary:        result.GrossSalary,
+		EmployerTax:        zero,
+	}
+
+	wage := result.BaseSalary
+	if result.Status == PeriodInactive {
+		wage = zero
+		if moneyToDec(result.SocialInsurance).IsPositive() || moneyToDec(result.HousingFund).IsPositive() {
+			wage = config.BaseSalary
+		}
+	}
+	if result.Employee.Jurisdiction == JurisdictionCN && moneyToDec(wage).IsPositive() {
+		contribution := func(limits BaseLimits, rate decimal.Decimal) Money {
+			base, _ := limits.Clamp(wage)
+			return toMoney(moneyToDec(base).Mul(rate).Round(2))
+		}
+		rates := config.Employer
+		cost.Pension = contribution(config.PensionBase, rates.Pension)
+		cost.Medical = contribution(config.MedicalBase, rates.Medical)
+		cost.Unemployment = contribution(config.UnemploymentBase, rates.Unemployment)
+		cost.WorkInjury = contribution(config.PensionBase, rates.WorkInjury)
+		cost.Maternity = contribution(config.MedicalBase, rates.Maternity)
+		cost.HousingFund = contribution(config.HousingFundBase, rates.HousingFund)
+		cost.SocialInsurance = toMoney(moneyToDec(cost.Pension).Add(moneyToDec(cost.Medical)).
+			Add(moneyToDec(cost.Unemployment)).Add(moneyToDec(cost.WorkInjury)).Add(moneyToDec(cost.Maternity)))
+	}
+
+	// 公司承担的个税已作为应税收入项计入税前工资，此处单独列示便于分析
+	for _, line := range result.Lines {
+		if line.Code == "TAX-BORNE" || line.Code == "TAXEQ-GROSSUP" {
+			cost.EmployerTax = toMoney(moneyToDec(cost.EmployerTax).Add(moneyToDec(line.Amount)))
+		}
+	}
+
+	total := moneyToDec(cost.SocialInsurance).Add(moneyToDec(cost.HousingFund)).Add(moneyToDec(cost.OtherContributions))
+	cost.TotalContributions = toMoney(total)
+	cost.LoadedCost = toMoney(moneyToDec(result.GrossSalary).Add(total))
+	return cost
+}
+
+// employerCost 员工本期的单位缴费和全口径人工成本，人工成本分析、项目分摊和薪资指标统一按此口径
+// 结果未记录 EmployerCost 时无法取得薪资配置，只计入税前工资和结果中的 EmployerContributions
+func (r EmployeeResult) employerCost() EmployerCost {
+	if r.EmployerCost != nil {
+		return *r.EmployerCost
+	}
+	return CalculateEmployerCost(PayrollConfig{}, r)
+}
@@ -0,0 +1,85 @@
+package salary
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func employerRatesConfig() PayrollConfig {
+	config := testConfig()
+	config.Employer = EmployerRates{
+		Pension:      decimal.RequireFromString("0.16"),
+		Medical:      decimal.RequireFromString("0.09"),
+		Unemployment: decimal.RequireFromString("0.005"),
+		WorkInjury:   decimal.RequireFromString("0.002"),
+		Maternity:    decimal.RequireFromString("0.01"),
+		HousingFund:  decimal.RequireFromString("0.07"),
+	}
+	return config
+}
+
+func TestCalculateEmployerCost(t *testing.T) {
+	config := employerRatesConfig()
+	config.PensionBase = BaseLimits{Ceiling: toMoney(cenToDec(500000))}
+	result := CalculateEmployee(day("2024-03-01"), EmployeeInput{Config: config, Attendance: AttendanceRecord{WorkHours: hours("174")}})
+
+	cost := CalculateEmployerCost(config, result)
+	// 养老、工伤按5000元封顶：800 + 10 = 810元；医疗、生育、失业按8000元：720 + 80 + 40 = 840元；公积金560元
+	assertMoney(t, "Pension", cost.Pension, "80000")
+	assertMoney(t, "WorkInjury", cost.WorkInjury, "1000")
+	assertMoney(t, "SocialInsurance", cost.SocialInsurance, "165000")
+	assertMoney(t, "TotalContributions", cost.TotalContributions, "221000")
+	if want := moneyToDec(result.GrossSalary).Add(moneyToDec(cost.TotalContributions)); !moneyToDec(cost.LoadedCost).Equal(want) {
+		t.Errorf("LoadedCost = %s, want gross + contributions %s", moneyToDec(cost.LoadedCost), want)
+	}
+
+	hk := result
+	hk.Employee.Jurisdiction = JurisdictionHK
+	hk.EmployerContributions = toMoney(cenToDec(150000))
+	assertMoney(t, "HK TotalContributions", CalculateEmployerCost(config, hk).TotalContributions, "150000")
+}
+
+func TestLabourCostUsesEmployerCost(t *testing.T) {
+	run := PayrollRun{Period: day("2024-03-01"), Inputs: []EmployeeInput{{
+		Employee: Employee{ID: "E1", Department: "研发"}, Config: employerRatesConfig(),
+		Attendance: AttendanceRecord{WorkHours: hours("174")},
+	}}}
+	result := run.Calculate()
+	if len(result.Employees) != 1 || result.Employees[0].EmployerCost == nil {
+		t.Fatalf("employees = %+v", result.Employees)
+	}
+	// 单位缴费按8000元：1280 + 720 + 40 + 16 + 80 + 560 = 2696元
+	gross := moneyToDec(result.Employees[0].GrossSalary)
+	loaded := gross.Add(decimal.NewFromInt(269600))
+	assertMoney(t, "recorded LoadedCost", result.Employees[0].EmployerCost.LoadedCost, loaded.String())
+
+	kpi := ComputeKPI(run.Period, []PayrollResult{result})
+	assertMoney(t, "KPI TotalCost", kpi.TotalCost, loaded.String())
+	assertMoney(t, "KPI EmployerContributions", kpi.EmployerContributions, "269600")
+
+	// 记录了单位缴费明细时不使用兜底比例
+	opts := CostOptions{OnCostRate: decimal.RequireFromString("0.5")}
+	rows := AnalyzeCostPerHour([]PayrollResult{result}, CostByEmployee, opts)
+	if len(rows) != 1 {
+		t.Fatalf("rows = %+v", rows)
+	}
+	assertMoney(t, "cost per hour LoadedCost", rows[0].LoadedCost, "1069600")
+	allocations := AllocateLaborCost([]PayrollResult{result}, []TimesheetEntry{
+		{EmployeeID: "E1", Period: day("2024-03-01"), Project: "P1", Hours: hours("100")},
+		{EmployeeID: "E1", Period: day("2024-03-01"), Project: "P2", Hours: hours("74")},
+	}, opts)
+	allocated := decimal.Zero
+	for _, a := range allocations {
+		allocated = allocated.Add(moneyToDec(a.Cost))
+	}
+	assertMoney(t, "allocated cost", toMoney(allocated), "1069600")
+
+	// 未记录单位缴费明细的结果按兜底比例估算
+	legacy := result
+	legacy.Employees = []EmployeeResult{result.Employees[0]}
+	legacy.Employees[0].EmployerCost = nil
+	opts.OnCostRate = decimal.RequireFromString("0.3")
+	assertMoney(t, "fallback LoadedCost", AnalyzeCostPerHour([]PayrollResult{legacy}, CostByEmployee, opts)[0].LoadedCost, "1040000")
+	assertMoney(t, "fallback KPI TotalCost", ComputeKPI(run.Period, []PayrollResult{legacy}).TotalCost, gross.String())
+}
@@ -0,0 +1,83 @@
+package salary
+
+import (
+	"slices"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// TaxEqualization 外派员工税收均衡协议
+// 员工只承担按派出国税制计算的假设税，由公司从工资中代扣；派驻地实际个税由公司承担，
+// 公司承担的税款本身计入应税收入，按税上税（gross-up）计算
+type TaxEqualization struct {
+	HomeCountry   string       // 派出国家或地区
+	Start         time.Time    // 派驻开始月份
+	End           time.Time    // 派驻结束月份，零值表示未定
+	HomeBrackets  []TaxBracket // 派出国月度税率表，计算方式同 calculateIncomeTaxWith
+	HomeAllowance Money        // 派出国每月免税额（分）
+	ExcludedCodes []string     // 不计入假设收入的派驻津贴项目代码，如住房、子女教育补贴
+}
+
+// ActiveIn 判断派驻协议是否在指定薪资期内有效
+func (e TaxEqualization) ActiveIn(period time.Time) bool {
+	p := monthStart(period)
+	if p.Before(monthStart(e.Start)) {
+		return false
+	}
+	return e.End.IsZero() || !p.After(monthStart(e.End))
+}
+
+// hypotheticalIncome 计算假设收入 = 税前工资 - 派驻津贴 - 社保 - 公积金
+func (e TaxEqualization) hypotheticalIncome(r EmployeeResult) decimal.Decimal {
+	income := moneyToDec(r.GrossSalary).Sub(moneyToDec(r.SocialInsurance)).Sub(moneyToDec(r.HousingFund))
+	for _, line := range r.Lines {
+		if line.Kind == KindEarning && slices.Contains(e.ExcludedCodes, line.Code) {
+			income = income.Sub(moneyToDec(line.Amount))
+		}
+	}
+	return income
+}
+
+// apply 对员工本期结果执行税收均衡
+// 公司承担的实际个税计为应税收入（TAXEQ-GROSSUP），假设税从工资中代扣（HYPO-TAX），
+// 实发工资 = 原实发工资 + 原个税 - 假设税；brackets 为派驻地适用的税率表
+func (e TaxEqualization) apply(r *EmployeeResult, brackets []TaxBracket) {
+	hypo := calculateIncomeTaxWith(toMoney(e.hypotheticalIncome(*r)), e.HomeAllowance, e.HomeBrackets)
+	bearIncomeTax(r, brackets, "TAXEQ-GROSSUP", "公司承担个税")
+	r.HypotheticalTax = hypo
+	r.NetSalary = toMoney(moneyToDec(r.NetSalary).Sub(moneyToDec(hypo)))
+	r.Lines = append(r.Lines, PayLine{Code: "HYPO-TAX", Name: "假设税", Kind: KindDeduction, Amount: hypo})
+}
+
+// EqualizationSummary 派驻期间税收均衡汇总
+type EqualizationSummary struct {
+	EmployeeID      string // 工号
+	Months          int    // 已发薪月数
+	HypotheticalTax Money  // 累计代扣假设税（分）
+	ActualTax       Money  // 累计公司承担的实际个税（分）
+	EmployerCost    Money  // 公司净承担 = 实际个税 - 假设税
+}
+
+// SummarizeEqualization 汇总员工派驻期间各月的假设税和公司承担的实际个税，用于年度均衡结算
+// history: 历史发薪批次
+// employeeID: 工号
+// agreement: 税收均衡协议
+func SummarizeEqualization(history []PayrollResult, employeeID string, agreement TaxEqualization) EqualizationSummary {
+	summary := EqualizationSummary{EmployeeID: employeeID}
+	hypo, actual := decimal.Zero, decimal.Zero
+	for _, run := range history {
+		for _, r := range run.Employees {
+			if r.Employee.ID != employeeID || r.Status != PeriodActive || !agreement.ActiveIn(r.Period) {
+				continue
+			}
+			summary.Months++
+			hypo = hypo.Add(moneyToDec(r.HypotheticalTax))
+			actual = actual.Add(moneyToDec(r.IncomeTax))
+		}
+	}
+	summary.HypotheticalTax = toMoney(hypo)
+	summary.ActualTax = toMoney(actual)
+	summary.EmployerCost = toMoney(actual.Sub(hypo))
+	return summary
+}
@@ -0,0 +1,73 @@
+package salary
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func flatBrackets(rate string) []TaxBracket {
+	return []TaxBracket{{Threshold: toMoney(decimal.Zero), Rate: decimal.RequireFromString(rate), Deduction: toMoney(decimal.Zero)}}
+}
+
+func TestTaxEqualizationApply(t *testing.T) {
+	agreement := TaxEqualization{
+		HomeCountry:   "DE",
+		Start:         day("2024-01-01"),
+		HomeBrackets:  flatBrackets("0.2"),
+		HomeAllowance: toMoney(cenToDec(100000)),
+		ExcludedCodes: []string{"HOUSING"},
+	}
+	r := EmployeeResult{
+		GrossSalary:       toMoney(cenToDec(2000000)),
+		SocialInsurance:   toMoney(cenToDec(100000)),
+		HousingFund:       toMoney(cenToDec(70000)),
+		TaxableIncome:     toMoney(cenToDec(1830000)),
+		StandardDeduction: toMoney(cenToDec(500000)),
+		IncomeTax:         toMoney(cenToDec(125000)),
+		NetSalary:         toMoney(cenToDec(1705000)),
+		Lines:             []PayLine{{Code: "HOUSING", Kind: KindEarning, Amount: toMoney(cenToDec(300000)), Taxable: true}},
+	}
+	agreement.apply(&r, DefaultTaxBrackets())
+
+	// 假设税：(20000 - 1000 - 700 - 3000 - 1000) × 20% = 2860
+	assertMoney(t, "hypothetical tax", r.HypotheticalTax, "286000")
+	// 税上税：(13300 × 20% - 1410) / (1 - 20%) = 1562.5
+	assertMoney(t, "income tax", r.IncomeTax, "156250")
+	assertMoney(t, "gross", r.GrossSalary, "2156250")
+	// 实发 = 17050 + 1250 - 2860
+	assertMoney(t, "net", r.NetSalary, "1544000")
+	if len(r.Lines) != 3 || r.Lines[1].Code != "TAXEQ-GROSSUP" || r.Lines[2].Code != "HYPO-TAX" {
+		t.Errorf("lines = %+v", r.Lines)
+	}
+}
+
+func TestTaxEqualizationInPayroll(t *testing.T) {
+	agreement := &TaxEqualization{Start: day("2024-02-01"), End: day("2024-03-01"), HomeBrackets: flatBrackets("0.3")}
+	input := EmployeeInput{
+		Employee:     Employee{ID: "E1"},
+		Config:       testConfig(),
+		Attendance:   AttendanceRecord{WorkHours: hours("174")},
+		Equalization: agreement,
+	}
+	var history []PayrollResult
+	for _, period := range []string{"2024-01-01", "2024-02-01", "2024-03-01", "2024-04-01"} {
+		run := PayrollRun{Period: day(period), Inputs: []EmployeeInput{input}}
+		history = append(history, run.Calculate())
+	}
+	if moneyToDec(history[0].Employees[0].HypotheticalTax).IsPositive() || !moneyToDec(history[1].Employees[0].HypotheticalTax).IsPositive() ||
+		moneyToDec(history[3].Employees[0].HypotheticalTax).IsPositive() {
+		t.Error("equalization should only apply from February to March")
+	}
+
+	summary := SummarizeEqualization(history, "E1", *agreement)
+	if summary.Months != 2 {
+		t.Errorf("months = %d", summary.Months)
+	}
+	feb, mar := history[1].Employees[0], history[2].Employees[0]
+	hypo := moneyToDec(feb.HypotheticalTax).Add(moneyToDec(mar.HypotheticalTax))
+	actual := moneyToDec(feb.IncomeTax).Add(moneyToDec(mar.IncomeTax))
+	if !moneyToDec(summary.HypotheticalTax).Equal(hypo) || !moneyToDec(summary.EmployerCost).Equal(actual.Sub(hypo)) {
+		t.Errorf("summary = %+v", summary)
+	}
+}
@@ -0,0 +1,100 @@
+package salary
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// ErrorCode 稳定的机器可读错误码，供集成方按类别处理失败，错误信息文字可能调整，错误码不变
+// 前缀：PAY 计算输入，TAX 个税，RUL 规则与政策，DAT 数据存储，SYS 其他
+type ErrorCode string
+
+const (
+	CodeInvalidRate         ErrorCode = "PAY001" // 比例格式错误或超出范围
+	CodeInvalidMoney        ErrorCode = "PAY002" // 金额格式错误
+	CodeHousingConflict     ErrorCode = "PAY003" // 住房贷款利息和住房租金扣除冲突
+	CodePeriodNotClosed     ErrorCode = "PAY004" // 薪资期未关账，不能提交考勤更正
+	CodePeriodCloseBlocked  ErrorCode = "PAY005" // 关账检查未通过
+	CodeInvalidBankAccount  ErrorCode = "PAY006" // 工资卡号无效
+	CodeSecondApproval      ErrorCode = "PAY007" // 高额变更缺少第二审批人
+	CodeWarningEscalated    ErrorCode = "PAY008" // 预警按批次设置升级为错误
+	CodeMissingYearToDate   ErrorCode = "TAX014" // 缺少本年累计数据
+	CodeRegionPolicyMissing ErrorCode = "RUL001" // 员工所在城市缺少政策
+	CodeRulesPackSignature  ErrorCode = "RUL002" // 规则包签名校验失败
+	CodeCityPresetMissing   ErrorCode = "RUL003" // 城市未收录费率预设
+	CodeNoConfigVersion     ErrorCode = "RUL004" // 薪资期没有生效的配置版本
+	CodeRunNotFound         ErrorCode = "DAT001" // 发薪批次不存在
+	CodeEmployeeNotFound    ErrorCode = "DAT002" // 员工档案不存在
+	CodeAttachmentNotFound  ErrorCode = "DAT003" // 附件不存在
+	CodeVersionConflict     ErrorCode = "DAT004" // 员工档案版本冲突
+	CodeNoClosedPeriod      ErrorCode = "DAT005" // 尚无已关账的薪资期
+	CodeNoIncomeRecords     ErrorCode = "DAT006" // 证明期间内没有发薪记录
+	CodeHoldNotFound        ErrorCode = "DAT007" // 暂扣记录不存在或已解除
+	CodeNoRunInputs         ErrorCode = "DAT008" // 批次未保存计算输入，无法重算预览
+	CodeEmployeeExists      ErrorCode = "DAT009" // 新建的员工档案已存在
+	CodeUnknown             ErrorCode = "SYS000" // 未分类错误
+)
+
+// ErrMissingYearToDate 累计预扣需要的本年累计数据缺失
+var ErrMissingYearToDate = errors.New("缺少本年累计收入和已预扣税额")
+
+// ErrAttachmentNotFound 批次附件不存在
+var ErrAttachmentNotFound = errors.New("附件不存在")
+
+// errorCodes 错误类别与错误码的对应关系
+var errorCodes = []struct {
+	err  error
+	code ErrorCode
+}{
+	{ErrInvalidRate, CodeInvalidRate},
+	{ErrInvalidMoney, CodeInvalidMoney},
+	{ErrHousingDeductionConflict, CodeHousingConflict},
+	{ErrPeriodNotClosed, CodePeriodNotClosed},
+	{ErrPeriodCloseBlocked, CodePeriodCloseBlocked},
+	{ErrInvalidBankAccount, CodeInvalidBankAccount},
+	{ErrSecondApprovalRequired, CodeSecondApproval},
+	{ErrWarningEscalated, CodeWarningEscalated},
+	{ErrMissingYearToDate, CodeMissingYearToDate},
+	{ErrRegionPolicyMissing, CodeRegionPolicyMissing},
+	{ErrRulesPackSignature, CodeRulesPackSignature},
+	{ErrCityPresetMissing, CodeCityPresetMissing},
+	{ErrNoConfigVersion, CodeNoConfigVersion},
+	{ErrRunNotFound, CodeRunNotFound},
+	{ErrEmployeeNotFound, CodeEmployeeNotFound},
+	{ErrAttachmentNotFound, CodeAttachmentNotFound},
+	{ErrVersionConflict, CodeVersionConflict},
+	{ErrNoClosedPeriod, CodeNoClosedPeriod},
+	{ErrNoIncomeRecords, CodeNoIncomeRecords},
+	{ErrHoldNotFound, CodeHoldNotFound},
+	{ErrNoRunInputs, CodeNoRunInputs},
+	{ErrEmployeeExists, CodeEmployeeExists},
+}
+
+// ErrorCodeOf 返回错误对应的错误码，未分类的错误返回 CodeUnknown
+func ErrorCodeOf(err error) ErrorCode {
+	for _, c := range errorCodes {
+		if errors.Is(err, c.err) {
+			return c.code
+		}
+	}
+	return CodeUnknown
+}
+
+// errorBody 生成包含错误码和错误信息的响应体
+func errorBody(err error) map[string]any {
+	return map[string]any{"code": ErrorCodeOf(err), "error": err.Error()}
+}
+
+// Code 返回员工计算错误的错误码
+func (e EmployeeError) Code() ErrorCode {
+	return ErrorCodeOf(e.Err)
+}
+
+// MarshalJSON 输出工号、错误码和错误信息，error 接口本身无法序列化
+func (e EmployeeError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		EmployeeID string    `json:"employee_id"`
+		Code       ErrorCode `json:"code"`
+		Message    string    `json:"message"`
+	}{e.EmployeeID, e.Code(), e.Err.Error()})
+}
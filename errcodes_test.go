@@ -0,0 +1,54 @@
+package salary
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestErrorCodeOf(t *testing.T) {
+	seen := make(map[ErrorCode]bool)
+	for _, c := range errorCodes {
+		if seen[c.code] {
+			t.Errorf("duplicate error code %s", c.code)
+		}
+		seen[c.code] = true
+		// 包装后的错误同样能识别
+		if got := ErrorCodeOf(fmt.Errorf("员工E1: %w", c.err)); got != c.code {
+			t.Errorf("ErrorCodeOf(%v) = %s, want %s", c.err, got, c.code)
+		}
+	}
+	if got := ErrorCodeOf(errors.New("其他错误")); got != CodeUnknown {
+		t.Errorf("unknown error code = %s", got)
+	}
+}
+
+func TestEmployeeErrorJSON(t *testing.T) {
+	data, err := json.Marshal(EmployeeError{EmployeeID: "E1", Err: fmt.Errorf("%w: lhasa", ErrRegionPolicyMissing)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body map[string]string
+	if err := json.Unmarshal(data, &body); err != nil {
+		t.Fatal(err)
+	}
+	if body["employee_id"] != "E1" || body["code"] != string(CodeRegionPolicyMissing) || body["message"] == "" {
+		t.Errorf("body = %v", body)
+	}
+}
+
+func TestAPIErrorsCarryCode(t *testing.T) {
+	rec := serve(NewServer(NewMemoryStore()), http.MethodGet, "/employees/E404", "", "")
+	var body struct {
+		Code  ErrorCode `json:"code"`
+		Error string    `json:"error"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusNotFound || body.Code != CodeEmployeeNotFound || body.Error == "" {
+		t.Errorf("status = %d, body = %+v", rec.Code, body)
+	}
+}
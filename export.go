@@ -0,0 +1,142 @@
+package salary
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ExportRecord 导出给下游系统（财务、HRIS）的员工工资结果
+type ExportRecord struct {
+	Seq             int64     `json:"seq"`              // 变更序号，下次增量导出从最大序号之后开始
+	RunID           string    `json:"run_id"`           // 最近一次产生该结果的批次
+	EmployeeID      string    `json:"employee_id"`      // 工号
+	Name            string    `json:"name"`             // 姓名
+	Department      string    `json:"department"`       // 部门
+	Period          time.Time `json:"period"`           // 薪资期
+	GrossSalary     Money     `json:"gross_salary"`     // 税前工资（分）
+	SocialInsurance Money     `json:"social_insurance"` // 社保个人部分（分）
+	HousingFund     Money     `json:"housing_fund"`     // 公积金个人部分（分）
+	IncomeTax       Money     `json:"income_tax"`       // 个人所得税（分）
+	NetSalary       Money     `json:"net_salary"`       // 实发工资（分）
+}
+
+// exportRecord 由员工结果生成导出记录，不含序号和批次
+func exportRecord(r EmployeeResult) ExportRecord {
+	return ExportRecord{
+		EmployeeID:      r.Employee.ID,
+		Name:            r.Employee.Name,
+		Department:      r.Employee.Department,
+		Period:          monthStart(r.Period),
+		GrossSalary:     r.GrossSalary,
+		SocialInsurance: r.SocialInsurance,
+		HousingFund:     r.HousingFund,
+		IncomeTax:       r.IncomeTax,
+		NetSalary:       r.NetSalary,
+	}
+}
+
+// fingerprint 导出内容的摘要，用于判断重算后结果是否变化
+func (e ExportRecord) fingerprint() string {
+	e.Seq, e.RunID = 0, ""
+	data, _ := json.Marshal(e)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// exportKey 导出记录的唯一键：工号 + 薪资期
+func exportKey(employeeID string, period time.Time) string {
+	return employeeID + "|" + period.Format("200601")
+}
+
+// trackExports 保存批次时登记内容有变化的员工结果并分配新的变更序号，调用方需持有写锁
+func (s *MemoryStore) trackExports(run PayrollResult) {
+	for _, r := range run.Employees {
+		record := exportRecord(r)
+		key := exportKey(record.EmployeeID, record.Period)
+		if previous, ok := s.exports[key]; ok && previous.fingerprint() == record.fingerprint() {
+			continue
+		}
+		s.exportSeq++
+		record.Seq = s.exportSeq
+		record.RunID = run.ID
+		s.exports[key] = record
+	}
+}
+
+// ExportChanges 增量导出：返回变更序号大于 since 的员工结果，按序号排序
+// since 为0时导出全部；下游系统保存返回的游标，下次从该游标继续，避免每次全量导出
+// 返回值: (变更的结果, 新的游标，即已导出的最大序号，无变更时等于 since)
+func (s *MemoryStore) ExportChanges(since int64) ([]ExportRecord, int64) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var records []ExportRecord
+	cursor := since
+	for _, record := range s.exports {
+		if record.Seq <= since {
+			continue
+		}
+		records = append(records, record)
+		cursor = max(cursor, record.Seq)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Seq < records[j].Seq })
+	return records, cursor
+}
+
+// WriteExportCSV 导出员工工资结果为CSV，金额单位为元
+func WriteExportCSV(w io.Writer, records []ExportRecord) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"序号", "批次", "工号", "姓名", "部门", "薪资期", "税前工资", "社保个人", "公积金个人", "个人所得税", "实发工资"}); err != nil {
+		return err
+	}
+	for _, r := range records {
+		if err := cw.Write([]string{
+			strconv.FormatInt(r.Seq, 10),
+			r.RunID,
+			r.EmployeeID,
+			r.Name,
+			r.Department,
+			r.Period.Format("2006-01"),
+			moneyToDec(r.GrossSalary).Div(decimal.NewFromInt(100)).StringFixed(2),
+			moneyToDec(r.SocialInsurance).Div(decimal.NewFromInt(100)).StringFixed(2),
+			moneyToDec(r.HousingFund).Div(decimal.NewFromInt(100)).StringFixed(2),
+			moneyToDec(r.IncomeTax).Div(decimal.NewFromInt(100)).StringFixed(2),
+			moneyToDec(r.NetSalary).Div(decimal.NewFromInt(100)).StringFixed(2),
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// handleExportResults 工资结果导出接口：GET /exports/results?since=<游标>&format=csv
+// 不传 since 时全量导出；新的游标通过响应头 X-Export-Cursor 返回
+func (s *Server) handleExportResults(w http.ResponseWriter, r *http.Request) {
+	var since int64
+	if v := r.URL.Query().Get("since"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			writeError(w, fmt.Errorf("游标 since 应为非负整数: %q", v))
+			return
+		}
+		since = n
+	}
+	records, cursor := s.store.ExportChanges(since)
+	w.Header().Set("X-Export-Cursor", strconv.FormatInt(cursor, 10))
+	if r.URL.Query().Get("format") == "csv" {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		WriteExportCSV(w, records)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"cursor": cursor, "records": records})
+}
@@ -0,0 +1,69 @@
+package salary
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func exportResult(id string, net int64) EmployeeResult {
+	return EmployeeResult{Employee: Employee{ID: id, Name: id}, Period: day("2024-05-01"), NetSalary: toMoney(cenToDec(net))}
+}
+
+func TestExportChangesIncremental(t *testing.T) {
+	store := NewMemoryStore()
+	first := &PayrollResult{Period: day("2024-05-01"), Employees: []EmployeeResult{exportResult("E1", 600000), exportResult("E2", 500000)}}
+	store.SaveRun(first)
+
+	records, cursor := store.ExportChanges(0)
+	if len(records) != 2 || cursor != 2 || records[0].Seq != 1 || records[0].RunID != first.ID {
+		t.Fatalf("records = %+v, cursor = %d", records, cursor)
+	}
+
+	// 重算后只有内容变化的结果进入下一次增量导出
+	rerun := &PayrollResult{Period: day("2024-05-01"), Employees: []EmployeeResult{exportResult("E1", 600000), exportResult("E2", 520000)}}
+	store.SaveRun(rerun)
+	records, next := store.ExportChanges(cursor)
+	if len(records) != 1 || records[0].EmployeeID != "E2" || records[0].Seq != 3 || records[0].RunID != rerun.ID || next != 3 {
+		t.Fatalf("incremental records = %+v, cursor = %d", records, next)
+	}
+	assertMoney(t, "updated net", records[0].NetSalary, "520000")
+
+	// 无变更时游标不变
+	if records, same := store.ExportChanges(next); len(records) != 0 || same != next {
+		t.Errorf("no changes: records = %+v, cursor = %d", records, same)
+	}
+	// 全量导出每个员工薪资期只保留最新结果
+	if records, _ := store.ExportChanges(0); len(records) != 2 {
+		t.Errorf("full export = %+v", records)
+	}
+}
+
+func TestHandleExportResults(t *testing.T) {
+	store := NewMemoryStore()
+	store.SaveRun(&PayrollResult{Period: day("2024-05-01"), Employees: []EmployeeResult{exportResult("E1", 600000)}})
+	server := NewServer(store)
+
+	rec := serve(server, http.MethodGet, "/exports/results", "", "")
+	var body struct {
+		Cursor  int64          `json:"cursor"`
+		Records []ExportRecord `json:"records"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Header().Get("X-Export-Cursor") != "1" || body.Cursor != 1 || len(body.Records) != 1 {
+		t.Errorf("status = %d, body = %+v", rec.Code, body)
+	}
+
+	rec = serve(server, http.MethodGet, "/exports/results?since=0&format=csv", "", "")
+	lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	if len(lines) != 2 || !strings.HasSuffix(lines[1], ",E1,E1,,2024-05,0.00,0.00,0.00,0.00,6000.00") {
+		t.Errorf("csv = %q", lines)
+	}
+
+	if rec := serve(server, http.MethodGet, "/exports/results?since=-1", "", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("negative cursor status = %d", rec.Code)
+	}
+}
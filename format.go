@@ -0,0 +1,39 @@
+package salary
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// MoneyUnit 金额显示单位
+type MoneyUnit int
+
+const (
+	UnitYuan    MoneyUnit = iota // 元
+	UnitWanYuan                  // 万元
+)
+
+// MoneyFormatter 金额格式化器，统一负责分到元/万元的换算和显示，报表代码不应自行做除法
+type MoneyFormatter struct {
+	Unit      MoneyUnit // 显示单位
+	Precision int32     // 保留小数位数
+}
+
+// PayslipFormatter 工资条使用的格式：元，保留两位小数
+var PayslipFormatter = MoneyFormatter{Unit: UnitYuan, Precision: 2}
+
+// SummaryFormatter 公司级汇总使用的默认格式：万元，保留两位小数
+var SummaryFormatter = MoneyFormatter{Unit: UnitWanYuan, Precision: 2}
+
+// Format 将以分为单位的金额格式化为显示字符串
+func (f MoneyFormatter) Format(m Money) string {
+	// 分转元
+	yuan := moneyToDec(m).Div(decimal.NewFromInt(100))
+
+	switch f.Unit {
+	case UnitWanYuan:
+		// 元转万元，使用银行家舍入法
+		return yuan.Div(decimal.NewFromInt(10000)).StringFixedBank(f.Precision) + "万元"
+	default:
+		return "¥" + yuan.StringFixedBank(f.Precision)
+	}
+}
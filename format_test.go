@@ -0,0 +1,25 @@
+package salary
+
+import "testing"
+
+func TestMoneyFormatter(t *testing.T) {
+	cases := []struct {
+		name      string
+		formatter MoneyFormatter
+		cents     int64
+		want      string
+	}{
+		{"payslip", PayslipFormatter, 800050, "¥8000.50"},
+		{"payslip negative", PayslipFormatter, -150, "¥-1.50"},
+		{"summary", SummaryFormatter, 123456789, "123.46万元"},
+		// 万元汇总使用银行家舍入：12.345万元舍入到偶数位
+		{"summary banker's rounding", SummaryFormatter, 12345000, "12.34万元"},
+		{"summary round half up to even", SummaryFormatter, 12355000, "12.36万元"},
+		{"yuan without decimals", MoneyFormatter{Unit: UnitYuan}, 800050, "¥8000"},
+	}
+	for _, tc := range cases {
+		if got := tc.formatter.Format(toMoney(cenToDec(tc.cents))); got != tc.want {
+			t.Errorf("%s: Format(%d) = %q, want %q", tc.name, tc.cents, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package salary
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrSecondApprovalRequired 高额变更缺少第二审批人
+var ErrSecondApprovalRequired = errors.New("变更超过四眼原则阈值，需要第二审批人")
+
+// FourEyesPolicy 四眼原则：超过阈值的变更必须由发起人以外的第二人审批
+type FourEyesPolicy struct {
+	SalaryIncreaseRate  decimal.Decimal // 基本工资涨幅阈值，如0.2表示涨幅超过20%需第二审批，0表示不检查
+	AdjustmentThreshold Money           // 一次性调整金额阈值（分），不论类别，金额绝对值超过时需第二审批，0表示不检查
+}
+
+// secondApproval 校验第二审批人：不能为空，也不能与发起人相同
+func secondApproval(requester, approver string) error {
+	if approver == "" {
+		return ErrSecondApprovalRequired
+	}
+	if approver == requester {
+		return fmt.Errorf("%w: 审批人不能是发起人本人 %s", ErrSecondApprovalRequired, requester)
+	}
+	return nil
+}
+
+// CheckSalaryChange 校验基本工资调整
+// 原工资为0（新员工定薪）时不按涨幅检查
+// operator: 发起修改的人
+// approver: 第二审批人
+func (p FourEyesPolicy) CheckSalaryChange(current, proposed Money, operator, approver string) error {
+	if !p.SalaryIncreaseRate.IsPositive() {
+		return nil
+	}
+	old := moneyToDec(current)
+	if !old.IsPositive() {
+		return nil
+	}
+	increase := moneyToDec(proposed).Sub(old).Div(old)
+	if increase.LessThanOrEqual(p.SalaryIncreaseRate) {
+		return nil
+	}
+	if err := secondApproval(operator, approver); err != nil {
+		return fmt.Errorf("基本工资由%s调整为%s，涨幅%s%%: %w", FormatMoneyCenToYuan(current), FormatMoneyCenToYuan(proposed),
+			increase.Mul(decimal.NewFromInt(100)).StringFixed(1), err)
+	}
+	return nil
+}
+
+// CheckAdjustment 校验一次性调整：不论收入项还是扣款项，金额绝对值超过阈值时需第二审批人
+func (p FourEyesPolicy) CheckAdjustment(a Adjustment) error {
+	threshold := moneyToDec(p.AdjustmentThreshold)
+	if !threshold.IsPositive() || moneyToDec(a.Amount).Abs().LessThanOrEqual(threshold) {
+		return nil
+	}
+	if err := secondApproval(a.Approver, a.SecondApprover); err != nil {
+		return fmt.Errorf("调整单%s金额%s: %w", a.ID, FormatMoneyCenToYuan(a.Amount), err)
+	}
+	return nil
+}
+
+// checkAdjustments 校验员工本期全部一次性调整，校验通过且设置了审计日志时记录第二审批
+func (p FourEyesPolicy) checkAdjustments(r *PayrollRun, input EmployeeInput) error {
+	for _, a := range input.Adjustments {
+		if err := p.CheckAdjustment(a); err != nil {
+			return err
+		}
+		if r.Audit != nil && a.SecondApprover != "" {
+			r.Audit.Record(AuditEntry{
+				Actor:      a.SecondApprover,
+				Action:     "adjustment.second_approval",
+				EmployeeID: input.Employee.ID,
+				Period:     monthStart(r.Period),
+				Detail:     fmt.Sprintf("调整单%s %s，发起审批人%s", a.ID, FormatMoneyCenToYuan(a.Amount), a.Approver),
+			})
+		}
+	}
+	return nil
+}
+
+// fourEyesPolicy 返回存储启用的四眼原则和审计日志
+func (s *MemoryStore) fourEyesPolicy() (*FourEyesPolicy, *AuditLog) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.fourEyes, s.audit
+}
+
+// SetFourEyesPolicy 启用员工档案修改和 POST /runs 提交批次的四眼原则检查
+// audit: 记录基本工资变更和第二审批的审计日志，为空表示不记录
+func (s *MemoryStore) SetFourEyesPolicy(policy *FourEyesPolicy, audit *AuditLog) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fourEyes = policy
+	s.audit = audit
+}
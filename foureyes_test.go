@@ -0,0 +1,155 @@
+package salary
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestFourEyesAdjustmentThreshold(t *testing.T) {
+	policy := FourEyesPolicy{AdjustmentThreshold: toMoney(cenToDec(1000000))}
+	large := toMoney(cenToDec(1500000))
+	cases := []struct {
+		name string
+		adj  Adjustment
+		want error
+	}{
+		{"large earning", Adjustment{ID: "A1", Kind: KindEarning, Amount: large, Approver: "hr1"}, ErrSecondApprovalRequired},
+		{"large deduction", Adjustment{ID: "A2", Kind: KindDeduction, Amount: large, Approver: "hr1"}, ErrSecondApprovalRequired},
+		{"negative amount", Adjustment{ID: "A3", Kind: KindEarning, Amount: toMoney(moneyToDec(large).Neg()), Approver: "hr1"}, ErrSecondApprovalRequired},
+		{"self approval", Adjustment{ID: "A4", Kind: KindEarning, Amount: large, Approver: "hr1", SecondApprover: "hr1"}, ErrSecondApprovalRequired},
+		{"approved", Adjustment{ID: "A5", Kind: KindDeduction, Amount: large, Approver: "hr1", SecondApprover: "hr2"}, nil},
+		{"below threshold", Adjustment{ID: "A6", Kind: KindEarning, Amount: toMoney(cenToDec(1000000)), Approver: "hr1"}, nil},
+	}
+	for _, tc := range cases {
+		if err := policy.CheckAdjustment(tc.adj); !errors.Is(err, tc.want) {
+			t.Errorf("%s: error = %v, want %v", tc.name, err, tc.want)
+		}
+	}
+}
+
+func TestFourEyesSalaryChange(t *testing.T) {
+	policy := FourEyesPolicy{SalaryIncreaseRate: decimal.RequireFromString("0.2")}
+	current := toMoney(cenToDec(1000000))
+	if err := policy.CheckSalaryChange(current, toMoney(cenToDec(1200000)), "hr1", ""); err != nil {
+		t.Errorf("20%% increase: %v", err)
+	}
+	if err := policy.CheckSalaryChange(current, toMoney(cenToDec(1300000)), "hr1", ""); !errors.Is(err, ErrSecondApprovalRequired) {
+		t.Errorf("30%% increase without approver: %v", err)
+	}
+	if err := policy.CheckSalaryChange(current, toMoney(cenToDec(1300000)), "hr1", "hr2"); err != nil {
+		t.Errorf("30%% increase approved: %v", err)
+	}
+}
+
+func TestFourEyesBlocksRunAndAudits(t *testing.T) {
+	audit := NewAuditLog()
+	adjustments := []Adjustment{{ID: "A1", Kind: KindDeduction, Amount: toMoney(cenToDec(2000000)), Approver: "hr1"}}
+	run := PayrollRun{
+		Period:   day("2024-03-01"),
+		Audit:    audit,
+		FourEyes: &FourEyesPolicy{AdjustmentThreshold: toMoney(cenToDec(1000000))},
+		Inputs: []EmployeeInput{{
+			Employee: Employee{ID: "E1"}, Config: testConfig(),
+			Attendance: AttendanceRecord{WorkHours: hours("174")}, Adjustments: adjustments,
+		}},
+	}
+	if result := run.Calculate(); len(result.Errors) != 1 || !errors.Is(result.Errors[0], ErrSecondApprovalRequired) {
+		t.Fatalf("errors = %v", result.Errors)
+	}
+	run.Inputs[0].Adjustments[0].SecondApprover = "hr2"
+	if result := run.Calculate(); len(result.Errors) != 0 {
+		t.Fatalf("approved adjustment: errors = %v", result.Errors)
+	}
+	found := false
+	for _, e := range audit.Entries() {
+		found = found || (e.Action == "adjustment.second_approval" && e.Actor == "hr2")
+	}
+	if !found {
+		t.Error("second approval not audited")
+	}
+}
+
+func TestFourEyesChecksCorrectionDifferences(t *testing.T) {
+	ledger := NewCorrectionLedger()
+	ledger.Close(day("2024-02-01"))
+	original := func(id string) EmployeeInput {
+		return EmployeeInput{
+			Employee: Employee{ID: id}, Config: testConfig(),
+			Attendance: AttendanceRecord{WorkHours: hours("94"), AbsenceHours: hours("80")},
+		}
+	}
+	submit := func(id, approvedBy string) AttendanceCorrection {
+		c, err := ledger.Submit(AttendanceCorrection{
+			EmployeeID: id, Period: day("2024-02-01"), Field: FieldAbsenceHours,
+			Old: hours("80"), New: hours("0"), Reason: "补登请假", RequestedBy: "hr1", ApprovedBy: approvedBy,
+		}, original(id))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	unapproved := submit("E1", "")
+	submit("E2", "hr2")
+	threshold := toMoney(cenToDec(100000))
+	if !moneyToDec(unapproved.Difference).GreaterThan(moneyToDec(threshold)) {
+		t.Fatalf("difference %s should exceed the threshold", moneyToDec(unapproved.Difference))
+	}
+
+	audit := NewAuditLog()
+	run := PayrollRun{
+		Period:      day("2024-03-01"),
+		Audit:       audit,
+		Corrections: ledger,
+		FourEyes:    &FourEyesPolicy{AdjustmentThreshold: threshold},
+		Inputs: []EmployeeInput{
+			{Employee: Employee{ID: "E1"}, Config: testConfig(), Attendance: AttendanceRecord{WorkHours: hours("174")}},
+			{Employee: Employee{ID: "E2"}, Config: testConfig(), Attendance: AttendanceRecord{WorkHours: hours("174")}},
+		},
+	}
+	result := run.Calculate()
+	if len(result.Errors) != 1 || result.Errors[0].EmployeeID != "E1" || !errors.Is(result.Errors[0], ErrSecondApprovalRequired) {
+		t.Fatalf("errors = %v", result.Errors)
+	}
+	// 未通过检查的更正退回待计入，下期补齐审批后再计入
+	if c := ledger.Corrections("E1"); !c[0].AppliedPeriod.IsZero() {
+		t.Errorf("blocked correction applied to %v", c[0].AppliedPeriod)
+	}
+	if c := ledger.Corrections("E2"); !c[0].AppliedPeriod.Equal(day("2024-03-01")) {
+		t.Errorf("approved correction applied to %v", c[0].AppliedPeriod)
+	}
+	found := false
+	for _, e := range audit.Entries() {
+		found = found || (e.Action == "adjustment.second_approval" && e.Actor == "hr2" && e.EmployeeID == "E2")
+	}
+	if !found {
+		t.Error("correction second approval not audited")
+	}
+}
+
+func TestBulkUpdateAuditsEverySalaryChange(t *testing.T) {
+	store := NewMemoryStore()
+	audit := NewAuditLog()
+	store.SetFourEyesPolicy(&FourEyesPolicy{SalaryIncreaseRate: decimal.RequireFromString("0.2")}, audit)
+	store.PutEmployee(EmployeeRecord{Employee: Employee{ID: "E1"}, BaseSalary: toMoney(cenToDec(1000000))})
+	store.PutEmployee(EmployeeRecord{Employee: Employee{ID: "E2"}, BaseSalary: toMoney(cenToDec(1000000))})
+
+	small, large := toMoney(cenToDec(1100000)), toMoney(cenToDec(1500000))
+	_, err := store.BulkUpdateEmployees([]EmployeeUpdate{
+		{EmployeeID: "E1", Version: 1, BaseSalary: &small},
+		{EmployeeID: "E2", Version: 1, BaseSalary: &large, ApprovedBy: "hr2"},
+	}, "hr1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range audit.Entries() {
+		got = append(got, e.EmployeeID+" "+e.Action+" "+e.Actor)
+	}
+	want := []string{"E1 salary.change hr1", "E2 salary.change hr1", "E2 salary.second_approval hr2"}
+	if strings.Join(got, "; ") != strings.Join(want, "; ") {
+		t.Errorf("audit = %v, want %v", got, want)
+	}
+}
@@ -2,4 +2,7 @@ module salary
 
 go 1.24
 
-require github.com/shopspring/decimal v1.4.0
+require (
+	github.com/shopspring/decimal v1.4.0
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -0,0 +1,237 @@
+package salary
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrHoldNotFound 暂扣记录不存在或已解除
+var ErrHoldNotFound = errors.New("暂扣记录不存在或已解除")
+
+// HeldAmount 暂扣在某一批次中实际扣下的金额
+type HeldAmount struct {
+	RunID  string    `json:"run_id"` // 批次编号
+	Period time.Time `json:"period"` // 薪资期
+	Amount Money     `json:"amount"` // 扣下的金额（分）
+}
+
+// PaymentHold 实发工资暂扣，如存在争议或离职交接未完成；暂扣金额不进入代发文件，解除后另行发放
+type PaymentHold struct {
+	ID         string       `json:"id"`          // 暂扣编号
+	EmployeeID string       `json:"employee_id"` // 工号
+	Amount     Money        `json:"amount"`      // 每期暂扣金额（分），0表示暂扣全部实发
+	Reason     string       `json:"reason"`      // 暂扣原因
+	PlacedBy   string       `json:"placed_by"`   // 操作人
+	PlacedAt   time.Time    `json:"placed_at"`   // 暂扣时间
+	ReleasedBy string       `json:"released_by"` // 解除人
+	ReleasedAt time.Time    `json:"released_at"` // 解除时间，零值表示仍在暂扣
+	Held       []HeldAmount `json:"held"`        // 各批次实际扣下的金额
+}
+
+// Active 判断暂扣是否仍然有效
+func (h PaymentHold) Active() bool {
+	return h.ReleasedAt.IsZero()
+}
+
+// Total 返回累计扣下的金额
+func (h PaymentHold) Total() Money {
+	total := decimal.Zero
+	for _, held := range h.Held {
+		total = total.Add(moneyToDec(held.Amount))
+	}
+	return toMoney(total)
+}
+
+// HoldLedger 暂扣台账，记录暂扣设置和各批次扣下的金额，直至解除
+type HoldLedger struct {
+	mu    sync.Mutex
+	holds []PaymentHold
+	seq   int
+}
+
+// NewHoldLedger 创建空的暂扣台账
+func NewHoldLedger() *HoldLedger {
+	return &HoldLedger{}
+}
+
+// Place 登记暂扣，自动生成编号，未设置暂扣时间时使用当前时间
+func (l *HoldLedger) Place(hold PaymentHold) (PaymentHold, error) {
+	if hold.EmployeeID == "" {
+		return PaymentHold{}, errors.New("暂扣缺少工号")
+	}
+	if moneyToDec(hold.Amount).IsNegative() {
+		return PaymentHold{}, errors.New("暂扣金额不能为负数")
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.seq++
+	hold.ID = fmt.Sprintf("H-%06d", l.seq)
+	if hold.PlacedAt.IsZero() {
+		hold.PlacedAt = time.Now()
+	}
+	hold.ReleasedAt, hold.ReleasedBy, hold.Held = time.Time{}, "", nil
+	l.holds = append(l.holds, hold)
+	return hold, nil
+}
+
+// Release 解除暂扣，返回含累计扣下金额的暂扣记录，由调用方安排补发
+func (l *HoldLedger) Release(id, releasedBy string) (PaymentHold, error) {
+	return l.release(id, releasedBy, time.Now())
+}
+
+// release 解除暂扣，at 为解除时间
+func (l *HoldLedger) release(id, releasedBy string, at time.Time) (PaymentHold, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i := range l.holds {
+		h := &l.holds[i]
+		if h.ID == id && h.Active() {
+			h.ReleasedBy = releasedBy
+			h.ReleasedAt = at
+			return *h, nil
+		}
+	}
+	return PaymentHold{}, fmt.Errorf("%w: %s", ErrHoldNotFound, id)
+}
+
+// Holds 查询员工的全部暂扣记录，按登记顺序排列
+func (l *HoldLedger) Holds(employeeID string) []PaymentHold {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var holds []PaymentHold
+	for _, h := range l.holds {
+		if h.EmployeeID == employeeID {
+			holds = append(holds, h)
+		}
+	}
+	return holds
+}
+
+// Balance 返回员工尚未解除的暂扣累计金额
+func (l *HoldLedger) Balance(employeeID string) Money {
+	total := decimal.Zero
+	for _, h := range l.Holds(employeeID) {
+		if h.Active() {
+			total = total.Add(moneyToDec(h.Total()))
+		}
+	}
+	return toMoney(total)
+}
+
+// Withholding 代发时从员工实发中扣下的一笔暂扣金额
+type Withholding struct {
+	HoldID string // 暂扣编号
+	Amount Money  // 本批次扣下的金额（分）
+}
+
+// withholding 计算员工本批次实发中应扣下的暂扣金额，不修改台账
+// 同一批次重复计算时按有效暂扣重新计算，不与该批次已记录的金额累计；
+// 已解除的暂扣在本批次扣下的金额已随解除补发，仍从本批次代发中扣除
+// 返回值: (可发放的金额, 各有效暂扣本批次应扣下的金额，由 commit 记入台账)
+func (l *HoldLedger) withholding(run PayrollResult, employeeID string, net decimal.Decimal) (decimal.Decimal, []Withholding) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var withholdings []Withholding
+	for _, h := range l.holds {
+		if h.EmployeeID != employeeID {
+			continue
+		}
+		if !h.Active() {
+			for _, held := range h.Held {
+				if held.RunID == run.ID {
+					net = net.Sub(decimal.Min(moneyToDec(held.Amount), net))
+				}
+			}
+			continue
+		}
+		amount := net
+		if moneyToDec(h.Amount).IsPositive() {
+			amount = decimal.Min(moneyToDec(h.Amount), net)
+		}
+		withholdings = append(withholdings, Withholding{HoldID: h.ID, Amount: toMoney(amount)})
+		net = net.Sub(amount)
+	}
+	return net, withholdings
+}
+
+// commit 将本批次扣下的暂扣金额记入台账，同一批次再次记入时覆盖该批次已记录的金额；已解除的暂扣不再记入
+func (l *HoldLedger) commit(run PayrollResult, withholdings []Withholding) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, w := range withholdings {
+		for i := range l.holds {
+			h := &l.holds[i]
+			if h.ID != w.HoldID || !h.Active() {
+				continue
+			}
+			recorded := false
+			for j := range h.Held {
+				if h.Held[j].RunID == run.ID {
+					h.Held[j].Amount, recorded = w.Amount, true
+				}
+			}
+			if !recorded && moneyToDec(w.Amount).IsPositive() {
+				h.Held = append(h.Held, HeldAmount{RunID: run.ID, Period: run.Period, Amount: w.Amount})
+			}
+		}
+	}
+}
+
+// ReleasePayment 生成解除暂扣后补发的代发付款，发放到员工工资卡
+func ReleasePayment(hold PaymentHold, record EmployeeRecord) (BankPayment, error) {
+	if record.BankAccount == "" {
+		return BankPayment{}, ErrMissingBankAccount
+	}
+	return BankPayment{
+		Reference:   "HOLD-" + hold.ID,
+		EmployeeID:  hold.EmployeeID,
+		Name:        record.Employee.Name,
+		BankAccount: record.BankAccount,
+		BankName:    record.BankName,
+		Amount:      hold.Total(),
+	}, nil
+}
+
+// handlePlaceHold 暂扣员工实发工资：POST /employees/{id}/holds
+// 请求体：{"amount": "100000", "reason": "...", "placed_by": "..."}，amount 为0或省略表示暂扣全部实发
+func (s *Server) handlePlaceHold(w http.ResponseWriter, r *http.Request) {
+	var hold PaymentHold
+	if err := json.NewDecoder(r.Body).Decode(&hold); err != nil {
+		writeError(w, fmt.Errorf("请求格式错误: %w", err))
+		return
+	}
+	if _, err := s.store.Employee(r.PathValue("id")); err != nil {
+		writeError(w, err)
+		return
+	}
+	hold.EmployeeID = r.PathValue("id")
+	hold.PlacedAt = requestTime(r.Context())
+	hold, err := s.store.holds.Place(hold)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, hold)
+}
+
+// handleReleaseHold 解除暂扣：POST /holds/{id}/release?released_by=...，返回解除的暂扣及补发付款
+func (s *Server) handleReleaseHold(w http.ResponseWriter, r *http.Request) {
+	hold, err := s.store.holds.release(r.PathValue("id"), r.URL.Query().Get("released_by"), requestTime(r.Context()))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	body := map[string]any{"hold": hold}
+	if record, err := s.store.Employee(hold.EmployeeID); err == nil {
+		if payment, err := ReleasePayment(hold, record); err == nil && moneyToDec(payment.Amount).IsPositive() {
+			body["payment"] = payment
+		}
+	}
+	writeJSON(w, http.StatusOK, body)
+}
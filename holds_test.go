@@ -0,0 +1,209 @@
+package salary
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestPaymentHolds(t *testing.T) {
+	const account = "6222021234567890128"
+	records := map[string]EmployeeRecord{
+		"E1": {Employee: Employee{ID: "E1"}, BankAccount: account},
+		"E2": {Employee: Employee{ID: "E2"}, BankAccount: account},
+	}
+	run := PayrollResult{ID: "202406-000001", Employees: []EmployeeResult{
+		{Employee: Employee{ID: "E1"}, NetSalary: toMoney(cenToDec(600000))},
+		{Employee: Employee{ID: "E2"}, NetSalary: toMoney(cenToDec(500000))},
+	}}
+	ledger := NewHoldLedger()
+	partial, _ := ledger.Place(PaymentHold{EmployeeID: "E1", Amount: toMoney(cenToDec(200000)), Reason: "工资争议"})
+	ledger.Place(PaymentHold{EmployeeID: "E2", Reason: "离职交接未完成"})
+
+	// 重复确认代发不重复累计暂扣金额
+	CommitBankPayments(run, records, ledger)
+	payments, errs := CommitBankPayments(run, records, ledger)
+	if len(errs) != 0 || len(payments) != 1 {
+		t.Fatalf("payments = %+v, errors = %v", payments, errs)
+	}
+	assertMoney(t, "E1 payment", payments[0].Amount, "400000")
+	assertMoney(t, "E1 held", ledger.Balance("E1"), "200000")
+	assertMoney(t, "E2 held", ledger.Balance("E2"), "500000")
+
+	released, err := ledger.Release(partial.ID, "hr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	payment, err := ReleasePayment(released, records["E1"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertMoney(t, "release payment", payment.Amount, "200000")
+	assertMoney(t, "E1 balance after release", ledger.Balance("E1"), "0")
+	// 解除后重新生成本批次代发文件，已补发的金额不再计入
+	payments, _ = CommitBankPayments(run, records, ledger)
+	assertMoney(t, "E1 payment after release", payments[0].Amount, "400000")
+	if _, err := ledger.Release(partial.ID, "hr"); !errors.Is(err, ErrHoldNotFound) {
+		t.Errorf("second release error = %v, want ErrHoldNotFound", err)
+	}
+}
+
+func TestBankPaymentsDoesNotTouchHoldLedger(t *testing.T) {
+	const account = "6222021234567890128"
+	records := map[string]EmployeeRecord{"E1": {Employee: Employee{ID: "E1"}, BankAccount: account}}
+	run := PayrollResult{ID: "202406-000001", Employees: []EmployeeResult{
+		{Employee: Employee{ID: "E1"}, NetSalary: toMoney(cenToDec(600000))},
+	}}
+	ledger := NewHoldLedger()
+	ledger.Place(PaymentHold{EmployeeID: "E1", Amount: toMoney(cenToDec(200000)), Reason: "工资争议"})
+
+	// 对账时重新计算代发付款，暂扣金额照常扣下但不记入台账
+	payments, _ := BankPayments(run, records, ledger)
+	assertMoney(t, "payment", payments[0].Amount, "400000")
+	assertMoney(t, "balance before commit", ledger.Balance("E1"), "0")
+
+	// 缺少档案的员工导致代发无法确认时，也不记入台账
+	failing := run
+	failing.Employees = append(failing.Employees, EmployeeResult{Employee: Employee{ID: "E9"}, NetSalary: toMoney(cenToDec(100000))})
+	if _, errs := CommitBankPayments(failing, records, ledger); len(errs) != 1 {
+		t.Fatalf("errors = %v", errs)
+	}
+	assertMoney(t, "balance after failed commit", ledger.Balance("E1"), "0")
+
+	CommitBankPayments(run, records, ledger)
+	assertMoney(t, "balance after commit", ledger.Balance("E1"), "200000")
+}
+
+func TestBankFileDownloadDoesNotCommitHolds(t *testing.T) {
+	store := NewMemoryStore()
+	store.PutEmployee(EmployeeRecord{Employee: Employee{ID: "E1"}, BankAccount: "6222021234567890128"})
+	store.SaveRun(&PayrollResult{ID: "202406-000001", Period: day("2024-06-01"), Employees: []EmployeeResult{
+		{Employee: Employee{ID: "E1"}, NetSalary: toMoney(cenToDec(600000))},
+	}})
+	store.Holds().Place(PaymentHold{EmployeeID: "E1", Amount: toMoney(cenToDec(200000)), Reason: "工资争议"})
+	var buf bytes.Buffer
+	server := NewServer(store)
+	server.SetReplayLog(NewReplayLog(&buf))
+
+	// 重复下载（浏览器预取、代理重试）不修改暂扣台账
+	for i := 0; i < 2; i++ {
+		rec := serve(server, http.MethodGet, "/runs/202406-000001/bank-file", "", "")
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "4000.00") {
+			t.Fatalf("download %d: status = %d, body = %s", i+1, rec.Code, rec.Body)
+		}
+	}
+	assertMoney(t, "balance after downloads", store.Holds().Balance("E1"), "0")
+
+	// 确认代发后记入暂扣金额，重复确认不重复累计，确认请求写入重放日志
+	for i := 0; i < 2; i++ {
+		if rec := serve(server, http.MethodPost, "/runs/202406-000001/payments/commit", "", ""); rec.Code != http.StatusOK {
+			t.Fatalf("commit %d: status = %d, body = %s", i+1, rec.Code, rec.Body)
+		}
+	}
+	assertMoney(t, "balance after commit", store.Holds().Balance("E1"), "200000")
+	entries, err := ReadReplayLog(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].URI != "/runs/202406-000001/payments/commit" {
+		t.Errorf("replay entries = %+v", entries)
+	}
+}
+
+func TestPartialHoldEdgeCases(t *testing.T) {
+	const account = "6222021234567890128"
+	records := map[string]EmployeeRecord{"E1": {Employee: Employee{ID: "E1"}, BankAccount: account}}
+	ledger := NewHoldLedger()
+	partial, _ := ledger.Place(PaymentHold{EmployeeID: "E1", Amount: toMoney(cenToDec(300000)), Reason: "借款未还"})
+	full, _ := ledger.Place(PaymentHold{EmployeeID: "E1", Reason: "离职交接未完成"})
+
+	// 部分暂扣先扣，全额暂扣扣下剩余实发，不生成代发付款
+	june := PayrollResult{ID: "202406-000001", Period: day("2024-06-01"), Employees: []EmployeeResult{
+		{Employee: Employee{ID: "E1"}, NetSalary: toMoney(cenToDec(500000))},
+	}}
+	if payments, errs := CommitBankPayments(june, records, ledger); len(errs) != 0 || len(payments) != 0 {
+		t.Fatalf("payments = %+v, errors = %v", payments, errs)
+	}
+	holds := ledger.Holds("E1")
+	assertMoney(t, "partial held", holds[0].Total(), "300000")
+	assertMoney(t, "full held", holds[1].Total(), "200000")
+
+	// 实发低于部分暂扣金额时以实发为限
+	july := PayrollResult{ID: "202407-000001", Period: day("2024-07-01"), Employees: []EmployeeResult{
+		{Employee: Employee{ID: "E1"}, NetSalary: toMoney(cenToDec(100000))},
+	}}
+	CommitBankPayments(july, records, ledger)
+	assertMoney(t, "partial held across runs", ledger.Holds("E1")[0].Total(), "400000")
+	assertMoney(t, "balance", ledger.Balance("E1"), "600000")
+
+	// 解除全额暂扣只补发该暂扣扣下的金额，部分暂扣继续有效
+	released, err := ledger.Release(full.ID, "hr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	payment, _ := ReleasePayment(released, records["E1"])
+	assertMoney(t, "full release payment", payment.Amount, "200000")
+	if payment.Reference != "HOLD-"+full.ID {
+		t.Errorf("reference = %q", payment.Reference)
+	}
+	assertMoney(t, "balance after full release", ledger.Balance("E1"), "400000")
+
+	august := PayrollResult{ID: "202408-000001", Period: day("2024-08-01"), Employees: []EmployeeResult{
+		{Employee: Employee{ID: "E1"}, NetSalary: toMoney(cenToDec(500000))},
+	}}
+	payments, _ := CommitBankPayments(august, records, ledger)
+	assertMoney(t, "August payment", payments[0].Amount, "200000")
+	if _, err := ledger.Release(partial.ID, "hr"); err != nil {
+		t.Fatal(err)
+	}
+	assertMoney(t, "balance after all released", ledger.Balance("E1"), "0")
+}
+
+func TestPlaceHoldValidation(t *testing.T) {
+	ledger := NewHoldLedger()
+	if _, err := ledger.Place(PaymentHold{Reason: "缺少工号"}); err == nil {
+		t.Error("hold without employee accepted")
+	}
+	if _, err := ledger.Place(PaymentHold{EmployeeID: "E1", Amount: toMoney(cenToDec(-1))}); err == nil {
+		t.Error("negative hold accepted")
+	}
+}
+
+func TestHoldEndpoints(t *testing.T) {
+	store := NewMemoryStore()
+	store.PutEmployee(EmployeeRecord{Employee: Employee{ID: "E1"}, BankAccount: "6222021234567890128"})
+	store.SaveRun(&PayrollResult{ID: "202406-000001", Period: day("2024-06-01"), Employees: []EmployeeResult{
+		{Employee: Employee{ID: "E1"}, NetSalary: toMoney(cenToDec(600000))},
+	}})
+	server := NewServer(store)
+
+	if rec := serve(server, http.MethodPost, "/employees/E9/holds", "application/json", `{"reason":"x"}`); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown employee status = %d", rec.Code)
+	}
+	rec := serve(server, http.MethodPost, "/employees/E1/holds", "application/json", `{"amount":"100000","reason":"工资争议","placed_by":"hr"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("place status = %d, body = %s", rec.Code, rec.Body)
+	}
+	var hold PaymentHold
+	json.NewDecoder(rec.Body).Decode(&hold)
+	serve(server, http.MethodPost, "/runs/202406-000001/payments/commit", "", "")
+
+	rec = serve(server, http.MethodPost, "/holds/"+hold.ID+"/release?released_by=hr", "", "")
+	var body struct {
+		Hold    PaymentHold `json:"hold"`
+		Payment BankPayment `json:"payment"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Hold.ReleasedBy != "hr" {
+		t.Errorf("hold = %+v", body.Hold)
+	}
+	assertMoney(t, "release payment", body.Payment.Amount, "100000")
+	if rec := serve(server, http.MethodPost, "/holds/"+hold.ID+"/release", "", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("second release status = %d", rec.Code)
+	}
+}
@@ -0,0 +1,99 @@
+package salary
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// chinaYear 国务院办公厅公布的某一年度放假安排
+type chinaYear struct {
+	holidays []string // 法定节假日，出勤按300%支付加班工资
+	restDays []string // 调休休息日，出勤可安排补休或按200%支付加班工资
+	workdays []string // 调休上班日
+}
+
+// chinaHolidays 内置的中国法定节假日和调休安排，按年份登记
+// 2025年起春节增加除夕、劳动节增加5月2日为法定节假日
+var chinaHolidays = map[int]chinaYear{
+	2024: {
+		holidays: []string{
+			"2024-01-01",
+			"2024-02-10", "2024-02-11", "2024-02-12",
+			"2024-04-04",
+			"2024-05-01",
+			"2024-06-10",
+			"2024-09-17",
+			"2024-10-01", "2024-10-02", "2024-10-03",
+		},
+		restDays: []string{
+			"2024-02-13", "2024-02-14", "2024-02-15", "2024-02-16", "2024-02-17",
+			"2024-04-05", "2024-04-06",
+			"2024-05-02", "2024-05-03", "2024-05-04", "2024-05-05",
+			"2024-09-15", "2024-09-16",
+			"2024-10-04", "2024-10-05", "2024-10-06", "2024-10-07",
+		},
+		workdays: []string{"2024-02-04", "2024-02-18", "2024-04-07", "2024-04-28", "2024-05-11", "2024-09-14", "2024-09-29", "2024-10-12"},
+	},
+	2025: {
+		holidays: []string{
+			"2025-01-01",
+			"2025-01-28", "2025-01-29", "2025-01-30", "2025-01-31",
+			"2025-04-04",
+			"2025-05-01", "2025-05-02",
+			"2025-05-31",
+			"2025-10-01", "2025-10-02", "2025-10-03",
+			"2025-10-06",
+		},
+		restDays: []string{
+			"2025-02-01", "2025-02-02", "2025-02-03", "2025-02-04",
+			"2025-04-05", "2025-04-06",
+			"2025-05-03", "2025-05-04", "2025-05-05",
+			"2025-06-01", "2025-06-02",
+			"2025-10-04", "2025-10-05", "2025-10-07", "2025-10-08",
+		},
+		workdays: []string{"2025-01-26", "2025-02-08", "2025-04-27", "2025-09-28", "2025-10-11"},
+	},
+}
+
+// ChinaHolidayYears 返回内置放假安排的年份，按年份排序
+func ChinaHolidayYears() []int {
+	years := make([]int, 0, len(chinaHolidays))
+	for year := range chinaHolidays {
+		years = append(years, year)
+	}
+	sort.Ints(years)
+	return years
+}
+
+// LoadChinaHolidays 将内置的中国法定节假日和调休安排登记到日历
+// 未内置的年份返回错误，可通过 AddHoliday、AddRestDay、AddWorkday 自行登记
+func (c *Calendar) LoadChinaHolidays(years ...int) error {
+	for _, year := range years {
+		data, ok := chinaHolidays[year]
+		if !ok {
+			return fmt.Errorf("未内置%d年的法定节假日安排", year)
+		}
+		c.AddHoliday(parseDates(data.holidays)...)
+		c.AddRestDay(parseDates(data.restDays)...)
+		c.AddWorkday(parseDates(data.workdays)...)
+	}
+	return nil
+}
+
+// NewChinaCalendar 创建载入全部内置年份放假安排的节假日日历
+func NewChinaCalendar() *Calendar {
+	c := NewCalendar()
+	// 内置年份必然存在，不会返回错误
+	_ = c.LoadChinaHolidays(ChinaHolidayYears()...)
+	return c
+}
+
+// parseDates 解析内置数据中的日期
+func parseDates(values []string) []time.Time {
+	dates := make([]time.Time, len(values))
+	for i, v := range values {
+		dates[i], _ = time.ParseInLocation("2006-01-02", v, time.Local)
+	}
+	return dates
+}
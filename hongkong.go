@@ -0,0 +1,103 @@
+package salary
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// Jurisdiction 员工适用的税收和社保辖区
+type Jurisdiction string
+
+const (
+	JurisdictionCN Jurisdiction = ""   // 中国内地（默认）
+	JurisdictionHK Jurisdiction = "HK" // 中国香港
+)
+
+// 香港强积金（MPF）强制性供款参数，金额单位为港币分
+var (
+	MPFRate                = decimal.RequireFromString("0.05") // 雇主、雇员供款比例各5%
+	MPFMinRelevantIncome   = cenToDec(710000)                  // 每月有关入息下限7,100港元，低于此额雇员无须供款
+	MPFMaxRelevantIncome   = cenToDec(3000000)                 // 每月有关入息上限30,000港元，供款以此为上限
+	HKMPFDeductionCap      = cenToDec(1800000)                 // 薪俸税中强积金供款每年扣除上限18,000港元
+	HKBasicAllowance       = cenToDec(13200000)                // 薪俸税基本免税额132,000港元
+	HKStandardRate         = decimal.RequireFromString("0.15") // 标准税率，应缴税款不超过按标准税率计算的税款
+	HKProgressiveBandWidth = cenToDec(5000000)                 // 累进税率每档应课税入息50,000港元
+)
+
+// HKProgressiveRates 薪俸税累进税率，最后一档适用于剩余应课税入息
+var HKProgressiveRates = []decimal.Decimal{
+	decimal.RequireFromString("0.02"),
+	decimal.RequireFromString("0.06"),
+	decimal.RequireFromString("0.10"),
+	decimal.RequireFromString("0.14"),
+	decimal.RequireFromString("0.17"),
+}
+
+// CalculateMPF 计算香港强积金每月强制性供款
+// relevantIncome: 每月有关入息（港币分）
+// 返回值: (雇员供款, 雇主供款)
+func CalculateMPF(relevantIncome Money) (employee, employer Money) {
+	income := decimal.Min(moneyToDec(relevantIncome), MPFMaxRelevantIncome)
+	if !income.IsPositive() {
+		return toMoney(decimal.Zero), toMoney(decimal.Zero)
+	}
+	contribution := income.Mul(MPFRate).Round(2)
+	employer = toMoney(contribution)
+	if moneyToDec(relevantIncome).LessThan(MPFMinRelevantIncome) {
+		return toMoney(decimal.Zero), employer
+	}
+	return toMoney(contribution), employer
+}
+
+// EstimateHKSalariesTax 估算香港年度薪俸税
+// 应缴税款取累进税率计算结果与标准税率计算结果中的较低者
+// annualIncome: 全年入息（港币分）
+// annualMPF: 全年雇员强积金供款
+// 返回值: 全年薪俸税
+func EstimateHKSalariesTax(annualIncome, annualMPF Money) Money {
+	net := moneyToDec(annualIncome).Sub(decimal.Min(moneyToDec(annualMPF), HKMPFDeductionCap))
+	if !net.IsPositive() {
+		return toMoney(decimal.Zero)
+	}
+
+	// 累进税率：扣除基本免税额后按每档50,000港元累进
+	chargeable := net.Sub(HKBasicAllowance)
+	progressive := decimal.Zero
+	for i, rate := range HKProgressiveRates {
+		if !chargeable.IsPositive() {
+			break
+		}
+		band := chargeable
+		if i < len(HKProgressiveRates)-1 {
+			band = decimal.Min(chargeable, HKProgressiveBandWidth)
+		}
+		progressive = progressive.Add(band.Mul(rate))
+		chargeable = chargeable.Sub(band)
+	}
+
+	standard := net.Mul(HKStandardRate)
+	return toMoney(decimal.Min(progressive, standard).Round(2))
+}
+
+// init 注册香港法定扣缴项目：强积金供款和薪俸税预估
+func init() {
+	RegisterStatutoryDeduction(JurisdictionHK, StatutoryDeduction{
+		Code:  "MPF",
+		Label: "强积金供款",
+		Compute: func(ctx StatutoryContext) StatutoryAmount {
+			employee, employer := CalculateMPF(toMoney(ctx.Gross))
+			return StatutoryAmount{Employee: employee, Employer: employer, Provision: toMoney(decimal.Zero)}
+		},
+	})
+	// 香港薪俸税由员工自行缴纳，雇主不代扣，按本月入息年化估算月度税款
+	RegisterStatutoryDeduction(JurisdictionHK, StatutoryDeduction{
+		Code:  "HK-SALARIES-TAX",
+		Label: "薪俸税预估",
+		Compute: func(ctx StatutoryContext) StatutoryAmount {
+			twelve := decimal.NewFromInt(12)
+			mpf, _ := CalculateMPF(toMoney(ctx.Gross))
+			annual := EstimateHKSalariesTax(toMoney(ctx.Taxable.Mul(twelve)), toMoney(moneyToDec(mpf).Mul(twelve)))
+			zero := toMoney(decimal.Zero)
+			return StatutoryAmount{Employee: zero, Employer: zero, Provision: toMoney(moneyToDec(annual).Div(twelve).Round(2))}
+		},
+	})
+}
@@ -0,0 +1,97 @@
+package salary
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestCalculateMPF(t *testing.T) {
+	cases := []struct {
+		name               string
+		income             int64
+		employee, employer string
+	}{
+		{"低于有关入息下限仅雇主供款", 500000, "0", "25000"},
+		{"略低于有关入息下限", 709999, "0", "35499.95"},
+		{"有关入息下限", 710000, "35500", "35500"},
+		{"有关入息上限", 3000000, "150000", "150000"},
+		{"一般入息", 2000000, "100000", "100000"},
+		{"超过有关入息上限按上限供款", 4000000, "150000", "150000"},
+		{"无入息", 0, "0", "0"},
+	}
+	for _, c := range cases {
+		employee, employer := CalculateMPF(toMoney(cenToDec(c.income)))
+		assertMoney(t, c.name+" employee", employee, c.employee)
+		assertMoney(t, c.name+" employer", employer, c.employer)
+	}
+}
+
+func TestEstimateHKSalariesTax(t *testing.T) {
+	cases := []struct {
+		name        string
+		income, mpf int64
+		want        string
+	}{
+		// 300,000 - 18,000(强积金扣除上限) - 132,000 = 150,000：1,000 + 3,000 + 5,000 = 9,000
+		{"累进税率", 30000000, 2400000, "900000"},
+		// 标准税率 2,982,000 × 15% = 447,300 低于累进税率计算的 466,500
+		{"标准税率封顶", 300000000, 1800000, "44730000"},
+		{"低于基本免税额", 10000000, 500000, "0"},
+	}
+	for _, c := range cases {
+		assertMoney(t, c.name, EstimateHKSalariesTax(toMoney(cenToDec(c.income)), toMoney(cenToDec(c.mpf))), c.want)
+	}
+}
+
+func TestHKStatutoryLines(t *testing.T) {
+	config := testConfig()
+	config.BaseSalary = toMoney(cenToDec(2500000))
+	result := CalculateEmployee(day("2024-06-01"), EmployeeInput{
+		Employee:   Employee{ID: "HK1", Jurisdiction: JurisdictionHK},
+		Config:     config,
+		Attendance: AttendanceRecord{WorkHours: hours("174")},
+	})
+	if len(result.Statutory) != 2 || result.Statutory[0].Code != "MPF" || result.Statutory[1].Code != "HK-SALARIES-TAX" {
+		t.Fatalf("statutory = %+v", result.Statutory)
+	}
+	// 强积金 25,000 × 5% = 1,250，雇员部分从实发中扣除；香港雇主不代扣薪俸税
+	assertMoney(t, "MPF", result.SocialInsurance, "125000")
+	assertMoney(t, "employer MPF", result.EmployerContributions, "125000")
+	assertMoney(t, "income tax", result.IncomeTax, "0")
+	assertMoney(t, "net", toMoney(moneyToDec(result.NetSalary).Round(2)), "2375000")
+	// 年入息 300,000，强积金扣除 15,000(1,250×12)：300,000 - 15,000 - 132,000 = 153,000
+	// 1,000 + 3,000 + 5,000 + 3,000 × 14% = 9,420，按月 785
+	assertMoney(t, "provision", result.TaxProvision, "78500")
+}
+
+func TestHKPensionPlans(t *testing.T) {
+	config := testConfig()
+	config.BaseSalary = toMoney(cenToDec(2500000))
+	plan := FixedRatePensionPlan("TOPUP", "强积金自愿性供款", decimal.RequireFromString("0.02"), decimal.RequireFromString("0.03"), nil)
+	run := PayrollRun{
+		Period:       day("2024-06-01"),
+		PensionPlans: []PensionPlan{plan},
+		Pensions:     NewPensionLedger(),
+		Inputs: []EmployeeInput{{
+			Employee:   Employee{ID: "HK1", Jurisdiction: JurisdictionHK},
+			Config:     config,
+			Attendance: AttendanceRecord{WorkHours: hours("174")},
+		}},
+	}
+	result := run.Calculate()
+	if len(result.Employees) != 1 {
+		t.Fatalf("errors = %v", result.Errors)
+	}
+	hk := result.Employees[0]
+	if len(hk.Pension) != 1 || hk.Pension[0].Code != "TOPUP" {
+		t.Fatalf("pension = %+v", hk.Pension)
+	}
+	// 自愿性供款个人 500、单位 750，与强积金一起计入
+	assertMoney(t, "employee top-up", hk.Pension[0].Employee, "50000")
+	assertMoney(t, "employer contributions", hk.EmployerContributions, "200000")
+	assertMoney(t, "net", toMoney(moneyToDec(hk.NetSalary).Round(2)), "2325000")
+	if accounts := run.Pensions.Accounts("HK1"); len(accounts) != 1 || !moneyToDec(accounts[0].EmployerBalance).Equal(cenToDec(75000)) {
+		t.Errorf("accounts = %+v", accounts)
+	}
+}
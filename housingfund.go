@@ -0,0 +1,146 @@
+package salary
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// 公积金租房提取的默认规则
+const (
+	DefaultRentMinMonths      = 3 // 连续足额缴存满3个月方可提取
+	DefaultRentIntervalMonths = 3 // 每3个月可提取一次
+)
+
+// HousingFundPolicy 公积金账户估算和租房提取规则，各地规定不同，以当地公积金中心为准
+type HousingFundPolicy struct {
+	EmployerMatch      decimal.Decimal // 单位缴存额与个人缴存额之比，0表示1（单位与个人费率相同）
+	RentMonthlyCap     Money           // 租房提取每月限额（分），0表示不限额
+	RentMinMonths      int             // 提取前须连续缴存的月数，0表示3个月
+	RentIntervalMonths int             // 提取间隔月数，单次最多提取间隔内的限额，0表示3个月
+}
+
+// defaults 填充未配置的参数
+func (p HousingFundPolicy) defaults() HousingFundPolicy {
+	if p.EmployerMatch.IsZero() {
+		p.EmployerMatch = decimal.NewFromInt(1)
+	}
+	if p.RentMinMonths == 0 {
+		p.RentMinMonths = DefaultRentMinMonths
+	}
+	if p.RentIntervalMonths == 0 {
+		p.RentIntervalMonths = DefaultRentIntervalMonths
+	}
+	return p
+}
+
+// HousingFundAccount 按已保存的发薪结果累计的公积金账户，不含利息和已提取金额
+type HousingFundAccount struct {
+	EmployeeID        string     `json:"employee_id"`        // 工号
+	Months            int        `json:"months"`             // 累计缴存月数
+	ConsecutiveMonths int        `json:"consecutive_months"` // 截至最近一次缴存的连续缴存月数
+	LastContribution  *time.Time `json:"last_contribution"`  // 最近一次缴存的薪资期，从未缴存时为空
+	Personal          Money      `json:"personal"`           // 个人缴存累计（分）
+	Employer          Money      `json:"employer"`           // 单位缴存累计（分）
+	Balance           Money      `json:"balance"`            // 账户余额（分）
+}
+
+// HousingFundAccountFrom 按员工的发薪结果累计公积金缴存
+// 同一薪资期有多条结果（如补发批次）时缴存额合计、只计一个月
+// employeeID: 工号
+// results: 员工的发薪结果
+// policy: 公积金规则
+func HousingFundAccountFrom(employeeID string, results []EmployeeResult, policy HousingFundPolicy) HousingFundAccount {
+	policy = policy.defaults()
+	monthly := make(map[string]decimal.Decimal)
+	periods := make(map[string]time.Time)
+	for _, r := range results {
+		key := r.Period.Format("200601")
+		monthly[key] = monthly[key].Add(moneyToDec(r.HousingFund))
+		periods[key] = monthStart(r.Period)
+	}
+
+	var keys []string
+	personal := decimal.Zero
+	for key, amount := range monthly {
+		if amount.IsPositive() {
+			keys = append(keys, key)
+			personal = personal.Add(amount)
+		}
+	}
+	sort.Strings(keys)
+
+	employer := personal.Mul(policy.EmployerMatch).Round(2)
+	account := HousingFundAccount{
+		EmployeeID: employeeID,
+		Months:     len(keys),
+		Personal:   toMoney(personal),
+		Employer:   toMoney(employer),
+		Balance:    toMoney(personal.Add(employer)),
+	}
+	if len(keys) > 0 {
+		last := periods[keys[len(keys)-1]]
+		account.LastContribution = &last
+		account.ConsecutiveMonths = 1
+		for i := len(keys) - 1; i > 0 && periods[keys[i-1]].AddDate(0, 1, 0).Equal(periods[keys[i]]); i-- {
+			account.ConsecutiveMonths++
+		}
+	}
+	return account
+}
+
+// RentWithdrawal 租房提取资格核验结果
+type RentWithdrawal struct {
+	Eligible  bool   `json:"eligible"`         // 是否符合提取条件
+	Reason    string `json:"reason,omitempty"` // 不符合条件的原因
+	MaxAmount Money  `json:"max_amount"`       // 本次最多可提取金额（分）
+}
+
+// RentWithdrawal 核验租房提取资格：截至 at 的上月仍在缴存、连续缴存满规定月数，单次提取不超过余额和间隔内的限额
+// 无房证明等材料需另行核验
+// policy: 公积金规则
+// at: 申请日期
+func (a HousingFundAccount) RentWithdrawal(policy HousingFundPolicy, at time.Time) RentWithdrawal {
+	policy = policy.defaults()
+	zero := toMoney(decimal.Zero)
+	switch {
+	case a.LastContribution == nil:
+		return RentWithdrawal{Reason: "没有缴存记录", MaxAmount: zero}
+	case a.LastContribution.Before(monthStart(at).AddDate(0, -1, 0)):
+		return RentWithdrawal{Reason: fmt.Sprintf("最近一次缴存为%s，当前未正常缴存", a.LastContribution.Format("2006-01")), MaxAmount: zero}
+	case a.ConsecutiveMonths < policy.RentMinMonths:
+		return RentWithdrawal{Reason: fmt.Sprintf("连续缴存%d个月，不足%d个月", a.ConsecutiveMonths, policy.RentMinMonths), MaxAmount: zero}
+	}
+	amount := moneyToDec(a.Balance)
+	if limit := moneyToDec(policy.RentMonthlyCap); limit.IsPositive() {
+		amount = decimal.Min(amount, limit.Mul(decimal.NewFromInt(int64(policy.RentIntervalMonths))))
+	}
+	return RentWithdrawal{Eligible: amount.IsPositive(), MaxAmount: toMoney(amount)}
+}
+
+// SetHousingFundPolicy 设置公积金规则，用于员工自助查询公积金账户和提取资格
+func (s *Server) SetHousingFundPolicy(policy HousingFundPolicy) {
+	s.housingFund = &policy
+}
+
+// handleHousingFund 员工自助查询公积金账户：GET /employees/{id}/housing-fund
+// 返回按已保存批次累计的缴存额、余额和租房提取资格
+func (s *Server) handleHousingFund(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if _, err := s.store.Employee(id); err != nil {
+		writeError(w, err)
+		return
+	}
+	var policy HousingFundPolicy
+	if s.housingFund != nil {
+		policy = *s.housingFund
+	}
+	account := HousingFundAccountFrom(id, s.store.EmployeeResults(id), policy)
+	writeJSON(w, http.StatusOK, map[string]any{
+		"account": account,
+		"rent":    account.RentWithdrawal(policy, time.Now()),
+	})
+}
@@ -0,0 +1,38 @@
+package salary
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestHousingFundAccountAndRentWithdrawal(t *testing.T) {
+	contribution := toMoney(decimal.NewFromInt(56000))
+	var results []EmployeeResult
+	for _, period := range []string{"2024-01-01", "2024-03-01", "2024-04-01", "2024-05-01"} {
+		results = append(results, EmployeeResult{Period: day(period), HousingFund: contribution})
+	}
+	policy := HousingFundPolicy{RentMonthlyCap: toMoney(decimal.NewFromInt(150000))}
+	account := HousingFundAccountFrom("E1", results, policy)
+	if account.Months != 4 || account.ConsecutiveMonths != 3 {
+		t.Fatalf("account = %+v", account)
+	}
+	assertMoney(t, "balance", account.Balance, "448000")
+
+	rent := account.RentWithdrawal(policy, day("2024-06-10"))
+	if !rent.Eligible {
+		t.Fatalf("rent = %+v", rent)
+	}
+	assertMoney(t, "max", rent.MaxAmount, "448000")
+
+	policy.RentMonthlyCap = toMoney(decimal.NewFromInt(100000))
+	assertMoney(t, "capped", account.RentWithdrawal(policy, day("2024-06-10")).MaxAmount, "300000")
+
+	if rent := account.RentWithdrawal(policy, day("2024-09-01")); rent.Eligible {
+		t.Error("stopped contributions should not be eligible")
+	}
+	short := HousingFundAccountFrom("E1", results[:2], policy)
+	if rent := short.RentWithdrawal(policy, day("2024-04-01")); rent.Eligible || rent.Reason == "" {
+		t.Errorf("rent = %+v, want ineligible", rent)
+	}
+}
@@ -0,0 +1,96 @@
+package salary
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrNoClosedPeriod 尚无已关账的薪资期
+var ErrNoClosedPeriod = errors.New("尚无已关账的薪资期")
+
+// PayrollKPI HR管理层每月查看的薪资指标，按已关账薪资期的全部批次计算
+type PayrollKPI struct {
+	Period                time.Time       `json:"period"`                 // 薪资期
+	Headcount             int             `json:"headcount"`              // 发薪人数（正常在岗）
+	TotalCost             Money           `json:"total_cost"`             // 人工总成本，按 CalculateEmployerCost 口径 = 税前工资 + 单位缴费（分）
+	CostPerHead           Money           `json:"cost_per_head"`          // 人均人工成本（分）
+	OvertimePay           Money           `json:"overtime_pay"`           // 加班工资合计（分）
+	OvertimeCostRatio     decimal.Decimal `json:"overtime_cost_ratio"`    // 加班工资占税前工资比例
+	IncomeTax             Money           `json:"income_tax"`             // 个人所得税合计（分）
+	SocialInsurance       Money           `json:"social_insurance"`       // 社保个人部分合计（分）
+	HousingFund           Money           `json:"housing_fund"`           // 公积金个人部分合计（分）
+	EmployerContributions Money           `json:"employer_contributions"` // 单位缴费合计，含单位社保公积金和其他单位缴费（分）
+	Errors                int             `json:"errors"`                 // 未能计算的员工数
+	Corrections           int             `json:"corrections"`            // 本期计入的往期考勤更正笔数
+}
+
+// ComputeKPI 汇总指定薪资期全部批次的指标
+// runs: 同一薪资期的批次
+func ComputeKPI(period time.Time, runs []PayrollResult) PayrollKPI {
+	kpi := PayrollKPI{Period: monthStart(period)}
+	var gross, cost, employer, overtime, tax, si, fund decimal.Decimal
+	for _, run := range runs {
+		kpi.Errors += len(run.Errors)
+		for _, r := range run.Employees {
+			if r.Status != PeriodActive {
+				continue
+			}
+			kpi.Headcount++
+			loaded := r.employerCost()
+			gross = gross.Add(moneyToDec(r.GrossSalary))
+			cost = cost.Add(moneyToDec(loaded.LoadedCost))
+			employer = employer.Add(moneyToDec(loaded.TotalContributions))
+			overtime = overtime.Add(moneyToDec(r.OvertimePay))
+			tax = tax.Add(moneyToDec(r.IncomeTax))
+			si = si.Add(moneyToDec(r.SocialInsurance))
+			fund = fund.Add(moneyToDec(r.HousingFund))
+			for _, line := range r.Lines {
+				if line.Code == "ADJ-"+string(ReasonCorrection) {
+					kpi.Corrections++
+				}
+			}
+		}
+	}
+
+	kpi.TotalCost = toMoney(cost)
+	kpi.OvertimePay = toMoney(overtime)
+	kpi.IncomeTax = toMoney(tax)
+	kpi.SocialInsurance = toMoney(si)
+	kpi.HousingFund = toMoney(fund)
+	kpi.EmployerContributions = toMoney(employer)
+	if kpi.Headcount > 0 {
+		kpi.CostPerHead = toMoney(cost.Div(decimal.NewFromInt(int64(kpi.Headcount))).Round(0))
+	}
+	if gross.IsPositive() {
+		kpi.OvertimeCostRatio = overtime.Div(gross).Round(4)
+	}
+	return kpi
+}
+
+// latestClosedPeriod 查找存在批次的最近一个已关账薪资期
+func (s *MemoryStore) latestClosedPeriod(ledger *CorrectionLedger) (time.Time, bool) {
+	var latest time.Time
+	for _, run := range s.Runs() {
+		if ledger.IsClosed(run.Period) && run.Period.After(latest) {
+			latest = run.Period
+		}
+	}
+	return latest, !latest.IsZero()
+}
+
+// handleKPI 薪资指标接口：GET /analytics/kpi，返回最近一个已关账薪资期的指标
+func (s *Server) handleKPI(w http.ResponseWriter, r *http.Request) {
+	if s.closer == nil {
+		writeJSON(w, http.StatusNotImplemented, errorBody(errors.New("未启用关账")))
+		return
+	}
+	period, ok := s.store.latestClosedPeriod(s.closer.Ledger)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, errorBody(ErrNoClosedPeriod))
+		return
+	}
+	writeJSON(w, http.StatusOK, ComputeKPI(period, s.store.periodRuns(period)))
+}
@@ -0,0 +1,121 @@
+package salary
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// LatePaymentPolicy 逾期支付工资的法定补偿规则，按辖区配置
+type LatePaymentPolicy struct {
+	DueDays               int             // 薪资期结束后最迟支付工资的天数，如次月15日发薪为15
+	EarlierOnRestDay      bool            // 发薪日遇休息日或法定节假日时提前到最近的工作日
+	AnnualInterestRate    decimal.Decimal // 逾期工资的年利率，按实际逾期天数计息，0表示不计利息
+	CompensationRate      decimal.Decimal // 逾期工资加付赔偿金的比例，0表示不加付
+	CompensationAfterDays int             // 逾期超过该天数后加付赔偿金，如劳动行政部门责令限期支付的期限
+}
+
+// LatePaymentPolicies 内置的各辖区逾期支付规则，利率等参数随法规调整，可按需覆盖
+var LatePaymentPolicies = map[Jurisdiction]LatePaymentPolicy{
+	// 中国内地：工资至少每月支付一次，发薪日遇节假日或休息日应提前支付；
+	// 逾期且经责令限期支付仍未支付的，按应付金额50%以上100%以下加付赔偿金，此处取下限
+	JurisdictionCN: {
+		DueDays:               15,
+		EarlierOnRestDay:      true,
+		CompensationRate:      decimal.RequireFromString("0.5"),
+		CompensationAfterDays: 15,
+	},
+	// 中国香港：工资须在工资期届满后7日内支付，逾期按判定债项利率计息
+	JurisdictionHK: {
+		DueDays:            7,
+		AnnualInterestRate: decimal.RequireFromString("0.081"),
+	},
+}
+
+// LatePaymentCharge 逾期支付工资应付的利息和赔偿金
+type LatePaymentCharge struct {
+	Period       time.Time `json:"period"`       // 薪资期
+	DueDate      time.Time `json:"due_date"`     // 最迟支付日期
+	PaidOn       time.Time `json:"paid_on"`      // 实际支付日期
+	DaysLate     int       `json:"days_late"`    // 逾期天数
+	Amount       Money     `json:"amount"`       // 逾期支付的工资（分）
+	Interest     Money     `json:"interest"`     // 逾期利息（分）
+	Compensation Money     `json:"compensation"` // 赔偿金（分）
+	Total        Money     `json:"total"`        // 利息和赔偿金合计（分）
+}
+
+// DueDate 计算薪资期工资的最迟支付日期
+// period: 薪资期
+// calendar: 节假日日历，为空时只顺延周末
+func (p LatePaymentPolicy) DueDate(period time.Time, calendar *Calendar) time.Time {
+	due := monthStart(period).AddDate(0, 1, -1).AddDate(0, 0, p.DueDays)
+	if p.EarlierOnRestDay {
+		for isRestDay(due, calendar) {
+			due = due.AddDate(0, 0, -1)
+		}
+	}
+	return due
+}
+
+// isRestDay 判断日期是否为休息日或法定节假日，已登记调休时按调休安排判断
+func isRestDay(date time.Time, calendar *Calendar) bool {
+	return calendar.DayType(date) != Workday
+}
+
+// LatePayment 计算逾期支付工资应付的利息和赔偿金，按时支付时金额均为0
+// period: 薪资期
+// amount: 逾期支付的工资（分）
+// paidOn: 实际支付日期
+// calendar: 节假日日历，为空时只顺延周末
+func (p LatePaymentPolicy) LatePayment(period time.Time, amount Money, paidOn time.Time, calendar *Calendar) LatePaymentCharge {
+	zero := toMoney(decimal.Zero)
+	due := p.DueDate(period, calendar)
+	charge := LatePaymentCharge{
+		Period:       monthStart(period),
+		DueDate:      due,
+		PaidOn:       paidOn,
+		Amount:       amount,
+		Interest:     zero,
+		Compensation: zero,
+		Total:        zero,
+	}
+	paid := dateOnly(paidOn)
+	if !paid.After(due) {
+		return charge
+	}
+	charge.DaysLate = daysBetween(due, paid)
+
+	unpaid := moneyToDec(amount)
+	interest := unpaid.Mul(p.AnnualInterestRate).Mul(decimal.NewFromInt(int64(charge.DaysLate))).Div(decimal.NewFromInt(365)).Round(2)
+	compensation := decimal.Zero
+	if charge.DaysLate > p.CompensationAfterDays {
+		compensation = unpaid.Mul(p.CompensationRate).Round(2)
+	}
+	charge.Interest = toMoney(interest)
+	charge.Compensation = toMoney(compensation)
+	charge.Total = toMoney(interest.Add(compensation))
+	return charge
+}
+
+// daysBetween 计算两个日期之间相差的自然日天数
+func daysBetween(from, to time.Time) int {
+	a := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	b := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(b.Sub(a).Hours() / 24)
+}
+
+// Adjustment 将利息和赔偿金转换为一次性收入调整，在补发工资的薪资期计入，按工资薪金所得计税
+// id: 调整单号
+// approver: 审批人
+func (c LatePaymentCharge) Adjustment(id, approver string) Adjustment {
+	return Adjustment{
+		ID:         id,
+		Kind:       KindEarning,
+		Amount:     c.Total,
+		Taxable:    true,
+		ReasonCode: ReasonBackPay,
+		Reason:     fmt.Sprintf("%s工资逾期%d天支付的利息及赔偿金", c.Period.Format("2006年01月"), c.DaysLate),
+		Approver:   approver,
+	}
+}
@@ -0,0 +1,49 @@
+package salary
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLatePaymentCN(t *testing.T) {
+	policy := LatePaymentPolicies[JurisdictionCN]
+	period := day("2024-08-01")
+	// 8月31日后15天为9月15日（周日），提前到9月13日（周五）
+	if due := policy.DueDate(period, nil); !due.Equal(day("2024-09-13")) {
+		t.Errorf("DueDate = %s, want 2024-09-13", due.Format("2006-01-02"))
+	}
+	calendar := NewCalendar()
+	calendar.AddHoliday(day("2024-09-13"))
+	if due := policy.DueDate(period, calendar); !due.Equal(day("2024-09-12")) {
+		t.Errorf("DueDate with holiday = %s, want 2024-09-12", due.Format("2006-01-02"))
+	}
+
+	amount := toMoney(cenToDec(1000000))
+	onTime := policy.LatePayment(period, amount, day("2024-09-13"), nil)
+	if onTime.DaysLate != 0 {
+		t.Errorf("on-time DaysLate = %d", onTime.DaysLate)
+	}
+	assertMoney(t, "on-time Total", onTime.Total, "0")
+
+	short := policy.LatePayment(period, amount, day("2024-09-20"), nil)
+	assertMoney(t, "short delay Compensation", short.Compensation, "0")
+
+	late := policy.LatePayment(period, amount, day("2024-10-10"), nil)
+	if late.DaysLate != 27 {
+		t.Errorf("DaysLate = %d, want 27", late.DaysLate)
+	}
+	assertMoney(t, "Compensation", late.Compensation, "500000")
+	if adj := late.Adjustment("LP-1", "hr"); adj.Kind != KindEarning || moneyToDec(adj.Amount).String() != "500000" {
+		t.Errorf("Adjustment = %+v", adj)
+	}
+}
+
+func TestLatePaymentHK(t *testing.T) {
+	policy := LatePaymentPolicies[JurisdictionHK]
+	charge := policy.LatePayment(day("2024-06-01"), toMoney(cenToDec(3650000)), time.Date(2024, 7, 17, 15, 0, 0, 0, time.Local), nil)
+	if charge.DaysLate != 10 {
+		t.Errorf("DaysLate = %d, want 10", charge.DaysLate)
+	}
+	// 36,500 × 8.1% × 10 ÷ 365 = 81
+	assertMoney(t, "Interest", charge.Interest, "8100")
+}
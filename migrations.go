@@ -0,0 +1,35 @@
+package salary
+
+import (
+	"embed"
+	"io/fs"
+	"sort"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migration 数据库迁移脚本
+type Migration struct {
+	Name string // 文件名，如 0002_reporting_views.sql，按名称顺序执行
+	SQL  string // 脚本内容
+}
+
+// Migrations 返回随程序发布的全部迁移脚本，按执行顺序排列
+// 0001 为薪资数据表结构；0002 为可选的报表视图（工资条明细平铺、员工年度累计），供BI工具直接查询
+func Migrations() ([]Migration, error) {
+	names, err := fs.Glob(migrationFiles, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	migrations := make([]Migration, 0, len(names))
+	for _, name := range names {
+		data, err := migrationFiles.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, Migration{Name: name[len("migrations/"):], SQL: string(data)})
+	}
+	return migrations, nil
+}
@@ -0,0 +1,45 @@
+-- 0001 薪资数据库表结构
+-- 金额字段单位为分，与计算引擎一致；批次和员工结果分表存储，明细行按批次、工号、序号定位
+
+CREATE TABLE IF NOT EXISTS payroll_runs (
+    run_id          VARCHAR(64) PRIMARY KEY,  -- 批次编号
+    period          DATE        NOT NULL,     -- 薪资期（当月1日）
+    engine_version  VARCHAR(32) NOT NULL,     -- 计算引擎版本
+    rules_version   VARCHAR(32) NOT NULL,     -- 规则包版本
+    anonymized      BOOLEAN     NOT NULL DEFAULT FALSE
+);
+
+CREATE TABLE IF NOT EXISTS payroll_employee_results (
+    run_id                  VARCHAR(64) NOT NULL REFERENCES payroll_runs (run_id),
+    employee_id             VARCHAR(64) NOT NULL,  -- 工号
+    employee_name           VARCHAR(128),          -- 姓名
+    department              VARCHAR(128),          -- 部门
+    status                  SMALLINT    NOT NULL,  -- 在岗状态，0为正常在岗
+    category                SMALLINT    NOT NULL,  -- 计算类别，0为工资薪金，1为实习津贴
+    base_salary             BIGINT      NOT NULL DEFAULT 0,
+    overtime_pay            BIGINT      NOT NULL DEFAULT 0,
+    gross_salary            BIGINT      NOT NULL DEFAULT 0,
+    social_insurance        BIGINT      NOT NULL DEFAULT 0,
+    housing_fund            BIGINT      NOT NULL DEFAULT 0,
+    taxable_income          BIGINT      NOT NULL DEFAULT 0,
+    special_deduction_total BIGINT      NOT NULL DEFAULT 0,
+    income_tax              BIGINT      NOT NULL DEFAULT 0,
+    net_salary              BIGINT      NOT NULL DEFAULT 0,
+    employer_contributions  BIGINT      NOT NULL DEFAULT 0,
+    PRIMARY KEY (run_id, employee_id)
+);
+
+-- 基础工资、加班工资以外的收入和扣款明细
+-- kind：0 收入项，1 扣款项，2 非现金福利
+CREATE TABLE IF NOT EXISTS payroll_lines (
+    run_id      VARCHAR(64)  NOT NULL,
+    employee_id VARCHAR(64)  NOT NULL,
+    line_no     INTEGER      NOT NULL,
+    code        VARCHAR(64)  NOT NULL,
+    name        VARCHAR(128) NOT NULL,
+    kind        SMALLINT     NOT NULL,
+    amount      BIGINT       NOT NULL,
+    taxable     BOOLEAN      NOT NULL,
+    PRIMARY KEY (run_id, employee_id, line_no),
+    FOREIGN KEY (run_id, employee_id) REFERENCES payroll_employee_results (run_id, employee_id)
+);
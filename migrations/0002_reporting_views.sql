@@ -0,0 +1,58 @@
+-- 0002 报表视图（可选）
+-- 供BI工具直接查询，无需了解批次、员工结果、明细行的分表结构；金额同时提供分和元两列
+-- 回滚：DROP VIEW rpt_employee_ytd; DROP VIEW rpt_payslip_lines;
+
+-- 工资条明细平铺：每个员工每期的每个工资项目一行，包括基础工资、加班工资、社保、公积金、个税、实发工资
+CREATE OR REPLACE VIEW rpt_payslip_lines AS
+SELECT r.run_id, r.period, e.employee_id, e.employee_name, e.department,
+       l.sort_key AS line_order, l.code, l.item_name, l.item_kind,
+       l.amount_cents, l.amount_cents / 100.0 AS amount_yuan, l.taxable
+FROM payroll_runs r
+JOIN payroll_employee_results e ON e.run_id = r.run_id
+JOIN (
+    SELECT run_id, employee_id, 'BASE' AS code, '基础工资' AS item_name, 'earning' AS item_kind,
+           base_salary AS amount_cents, TRUE AS taxable, 0 AS sort_key
+    FROM payroll_employee_results
+    UNION ALL
+    SELECT run_id, employee_id, 'OVERTIME', '加班工资', 'earning', overtime_pay, TRUE, 1
+    FROM payroll_employee_results WHERE overtime_pay <> 0
+    UNION ALL
+    SELECT run_id, employee_id, code, name,
+           CASE kind WHEN 0 THEN 'earning' WHEN 1 THEN 'deduction' ELSE 'benefit_in_kind' END,
+           amount, taxable, 10 + line_no
+    FROM payroll_lines
+    UNION ALL
+    SELECT run_id, employee_id, 'SI', '社会保险', 'statutory', social_insurance, TRUE, 100000
+    FROM payroll_employee_results
+    UNION ALL
+    SELECT run_id, employee_id, 'HF', '住房公积金', 'statutory', housing_fund, TRUE, 100001
+    FROM payroll_employee_results
+    UNION ALL
+    SELECT run_id, employee_id, 'IIT', '个人所得税', 'tax', income_tax, FALSE, 100002
+    FROM payroll_employee_results
+    UNION ALL
+    SELECT run_id, employee_id, 'NET', '实发工资', 'net', net_salary, FALSE, 100003
+    FROM payroll_employee_results
+) l ON l.run_id = e.run_id AND l.employee_id = e.employee_id;
+
+-- 员工年度累计：按自然年汇总正常在岗各期的金额
+CREATE OR REPLACE VIEW rpt_employee_ytd AS
+SELECT e.employee_id,
+       MAX(e.employee_name)                    AS employee_name,
+       EXTRACT(YEAR FROM r.period)             AS tax_year,
+       COUNT(*)                                AS months,
+       MAX(r.period)                           AS last_period,
+       SUM(e.gross_salary)                     AS gross_salary_cents,
+       SUM(e.social_insurance)                 AS social_insurance_cents,
+       SUM(e.housing_fund)                     AS housing_fund_cents,
+       SUM(e.taxable_income)                   AS taxable_income_cents,
+       SUM(e.special_deduction_total)          AS special_deduction_cents,
+       SUM(e.income_tax)                       AS income_tax_cents,
+       SUM(e.net_salary)                       AS net_salary_cents,
+       SUM(e.gross_salary) / 100.0             AS gross_salary_yuan,
+       SUM(e.income_tax) / 100.0               AS income_tax_yuan,
+       SUM(e.net_salary) / 100.0               AS net_salary_yuan
+FROM payroll_runs r
+JOIN payroll_employee_results e ON e.run_id = r.run_id
+WHERE e.status = 0
+GROUP BY e.employee_id, EXTRACT(YEAR FROM r.period);
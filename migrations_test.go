@@ -0,0 +1,27 @@
+package salary
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMigrations(t *testing.T) {
+	migrations, err := Migrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) != 2 || migrations[0].Name != "0001_payroll_tables.sql" || migrations[1].Name != "0002_reporting_views.sql" {
+		t.Fatalf("migrations = %v", migrations)
+	}
+	// 报表视图依赖的表须在之前的脚本中创建
+	for _, table := range []string{"payroll_runs", "payroll_employee_results", "payroll_lines"} {
+		if !strings.Contains(migrations[0].SQL, "CREATE TABLE IF NOT EXISTS "+table) {
+			t.Errorf("0001 does not create %s", table)
+		}
+	}
+	for _, view := range []string{"rpt_payslip_lines", "rpt_employee_ytd"} {
+		if !strings.Contains(migrations[1].SQL, "CREATE OR REPLACE VIEW "+view) {
+			t.Errorf("0002 does not create %s", view)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package salary
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrNetUnreachable 无法在容差内求得与目标实发工资对应的税前工资
+var ErrNetUnreachable = errors.New("无法求得目标实发工资对应的税前工资")
+
+// GrossFromNetOptions 反算税前工资的求解选项
+type GrossFromNetOptions struct {
+	Tolerance     Money // 实发工资允许的误差（分），零值为1分
+	MaxIterations int   // 最大迭代次数，零值为100
+}
+
+// GrossFromNet 由目标实发工资反算的工资明细（分）
+type GrossFromNet struct {
+	GrossSalary       Money // 税前工资，即应约定的月基本工资
+	SocialInsurance   Money // 社保个人部分
+	HousingFund       Money // 公积金个人部分
+	HousingFundExcess Money // 公积金超过免税限额、并入应纳税所得额的部分
+	TaxableIncome     Money // 应纳税所得额（未扣减除费用和专项附加扣除）
+	IncomeTax         Money // 个人所得税
+	NetSalary         Money // 实发工资
+	Iterations        int   // 迭代次数
+}
+
+// grossBreakdown 按全勤、无加班计算给定税前工资的明细，口径与 CalculateEmployee 的单月计税一致
+func grossBreakdown(config PayrollConfig, gross Money, deductions SpecialDeductions) GrossFromNet {
+	socialInsurance, housingFund, _ := calculateSocialInsurance(config, gross)
+	excess := HousingFundExcess(config, gross, housingFund)
+	taxable := moneyToDec(gross).Sub(moneyToDec(socialInsurance)).Sub(moneyToDec(housingFund)).Add(moneyToDec(excess))
+	tax := config.taxConfig().IncomeTax(toMoney(taxable), deductions, decimal.NewFromInt(1))
+	net := moneyToDec(gross).Sub(moneyToDec(socialInsurance)).Sub(moneyToDec(housingFund)).Sub(moneyToDec(tax))
+	return GrossFromNet{
+		GrossSalary:       gross,
+		SocialInsurance:   socialInsurance,
+		HousingFund:       housingFund,
+		HousingFundExcess: excess,
+		TaxableIncome:     toMoney(taxable),
+		IncomeTax:         tax,
+		NetSalary:         toMoney(net),
+	}
+}
+
+// CalculateGrossFromNet 由目标实发工资反算税前工资，误差不超过1分
+// config: 薪资配置，BaseSalary 不使用
+// deductions: 专项附加扣除
+// targetNet: 目标实发工资（分）
+// 返回值: 反算得到的完整工资明细
+func CalculateGrossFromNet(config PayrollConfig, deductions SpecialDeductions, targetNet Money) (GrossFromNet, error) {
+	return CalculateGrossFromNetWithOptions(config, deductions, targetNet, GrossFromNetOptions{})
+}
+
+// CalculateGrossFromNetWithOptions 按指定容差反算税前工资
+// 实发工资随税前工资单调不减（缴费基数封顶、累进税率均不会使实发减少），按分二分查找最接近目标的税前工资
+func CalculateGrossFromNetWithOptions(config PayrollConfig, deductions SpecialDeductions, targetNet Money, opts GrossFromNetOptions) (GrossFromNet, error) {
+	tolerance := moneyToDec(opts.Tolerance).Abs()
+	if tolerance.IsZero() {
+		tolerance = decimal.NewFromInt(1)
+	}
+	maxIterations := opts.MaxIterations
+	if maxIterations <= 0 {
+		maxIterations = 100
+	}
+	target := moneyToDec(targetNet)
+	if target.IsNegative() {
+		return GrossFromNet{}, fmt.Errorf("%w: 目标实发工资 %s 为负数", ErrNetUnreachable, FormatMoneyCenToYuan(targetNet))
+	}
+
+	iterations := 0
+	at := func(cents decimal.Decimal) GrossFromNet {
+		iterations++
+		return grossBreakdown(config, toMoney(cents), deductions)
+	}
+	// 实发不超过税前，下界为目标实发；上界逐次翻倍直到实发达到目标
+	lo, hi := target.Ceil(), target.Ceil().Mul(decimal.NewFromInt(2)).Add(decimal.NewFromInt(100))
+	for moneyToDec(at(hi).NetSalary).LessThan(target) {
+		if iterations >= maxIterations {
+			return GrossFromNet{}, fmt.Errorf("%w: %d 次迭代内未找到上界", ErrNetUnreachable, maxIterations)
+		}
+		lo, hi = hi, hi.Mul(decimal.NewFromInt(2))
+	}
+	one := decimal.NewFromInt(1)
+	for hi.Sub(lo).GreaterThan(one) && iterations < maxIterations {
+		mid := lo.Add(hi).Div(decimal.NewFromInt(2)).Floor()
+		if moneyToDec(at(mid).NetSalary).LessThan(target) {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+
+	best := at(hi)
+	if low := at(lo); moneyToDec(low.NetSalary).Sub(target).Abs().LessThan(moneyToDec(best.NetSalary).Sub(target).Abs()) {
+		best = low
+	}
+	best.Iterations = iterations
+	if moneyToDec(best.NetSalary).Sub(target).Abs().GreaterThan(tolerance) {
+		return best, fmt.Errorf("%w: 最接近的实发工资为 %s，目标 %s", ErrNetUnreachable, FormatMoneyCenToYuan(best.NetSalary), FormatMoneyCenToYuan(targetNet))
+	}
+	return best, nil
+}
@@ -0,0 +1,40 @@
+package salary
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCalculateGrossFromNet(t *testing.T) {
+	config := testConfig()
+	for _, target := range []int64{0, 450000, 689200, 1500000, 6000000} {
+		result, err := CalculateGrossFromNet(config, SpecialDeductions{}, toMoney(cenToDec(target)))
+		if err != nil {
+			t.Fatalf("target %d: %v", target, err)
+		}
+		diff := moneyToDec(result.NetSalary).Sub(cenToDec(target)).Abs()
+		if diff.GreaterThan(cenToDec(1)) {
+			t.Errorf("target %d: net %s, diff %s", target, moneyToDec(result.NetSalary), diff)
+		}
+		// 反算结果代入正向计算应得到相同的实发工资
+		forward := grossBreakdown(config, result.GrossSalary, SpecialDeductions{})
+		if !moneyToDec(forward.NetSalary).Equal(moneyToDec(result.NetSalary)) {
+			t.Errorf("target %d: forward net %s != %s", target, moneyToDec(forward.NetSalary), moneyToDec(result.NetSalary))
+		}
+	}
+
+	// 8000元税前：社保840 + 公积金560，个税48，实发6552
+	result, err := CalculateGrossFromNet(config, SpecialDeductions{}, toMoney(cenToDec(655200)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertMoney(t, "gross", result.GrossSalary, "800000")
+	assertMoney(t, "tax", result.IncomeTax, "4800")
+
+	if _, err := CalculateGrossFromNet(config, SpecialDeductions{}, toMoney(cenToDec(-1))); !errors.Is(err, ErrNetUnreachable) {
+		t.Errorf("negative target err = %v", err)
+	}
+	if _, err := CalculateGrossFromNetWithOptions(config, SpecialDeductions{}, toMoney(cenToDec(2000000)), GrossFromNetOptions{MaxIterations: 3}); !errors.Is(err, ErrNetUnreachable) {
+		t.Errorf("iteration limit err = %v", err)
+	}
+}
@@ -0,0 +1,243 @@
+package salary
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// RunStatus 发薪批次的生命周期状态
+type RunStatus int
+
+const (
+	RunCalculated       RunStatus = iota // 已核算
+	RunAwaitingApproval                  // 待审批
+	RunPaid                              // 已发放
+	RunFailed                            // 失败
+)
+
+// String 返回批次状态的中文名称
+func (s RunStatus) String() string {
+	switch s {
+	case RunCalculated:
+		return "已核算"
+	case RunAwaitingApproval:
+		return "待审批"
+	case RunPaid:
+		return "已发放"
+	case RunFailed:
+		return "失败"
+	default:
+		return "未知"
+	}
+}
+
+// RunEvent 发薪批次状态变更事件
+type RunEvent struct {
+	Company       string    // 公司名称
+	Period        time.Time // 薪资期
+	Status        RunStatus // 新状态
+	EmployeeCount int       // 员工人数
+	TotalNet      Money     // 实发合计（分）
+	Message       string    // 附加说明，如失败原因
+}
+
+// NewRunEvent 根据批次计算结果生成状态事件
+func NewRunEvent(company string, result PayrollResult, status RunStatus) RunEvent {
+	total := decimal.Zero
+	for _, r := range result.Employees {
+		total = total.Add(moneyToDec(r.NetSalary))
+	}
+	return RunEvent{
+		Company:       company,
+		Period:        result.Period,
+		Status:        status,
+		EmployeeCount: len(result.Employees),
+		TotalNet:      toMoney(total),
+	}
+}
+
+// Text 生成群机器人消息正文
+func (e RunEvent) Text() string {
+	text := fmt.Sprintf("【薪资核算】%s %s 批次%s：员工%d人，实发合计%s",
+		e.Company, e.Period.Format("2006-01"), e.Status, e.EmployeeCount, FormatMoneyCenToYuan(e.TotalNet))
+	if e.Message != "" {
+		text += "\n" + e.Message
+	}
+	return text
+}
+
+// Notifier 批次状态通知插件
+type Notifier interface {
+	Notify(ctx context.Context, event RunEvent) error
+}
+
+// robotResponse 企业微信、钉钉群机器人的通用响应
+type robotResponse struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+// postRobotMessage 向群机器人Webhook发送JSON消息并检查返回码
+func postRobotMessage(ctx context.Context, client *http.Client, webhook string, payload any) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("群机器人返回HTTP状态 %d", resp.StatusCode)
+	}
+
+	var result robotResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return err
+	}
+	if result.ErrCode != 0 {
+		return fmt.Errorf("群机器人返回错误 %d: %s", result.ErrCode, result.ErrMsg)
+	}
+	return nil
+}
+
+// WeComNotifier 企业微信群机器人通知
+type WeComNotifier struct {
+	Webhook string       // 机器人Webhook地址
+	Client  *http.Client // HTTP客户端，为空时使用默认客户端
+}
+
+// Notify 发送文本消息到企业微信群
+func (n WeComNotifier) Notify(ctx context.Context, event RunEvent) error {
+	payload := map[string]any{
+		"msgtype": "text",
+		"text":    map[string]string{"content": event.Text()},
+	}
+	return postRobotMessage(ctx, n.Client, n.Webhook, payload)
+}
+
+// DingTalkNotifier 钉钉群机器人通知
+type DingTalkNotifier struct {
+	Webhook string       // 机器人Webhook地址
+	Secret  string       // 加签密钥，为空表示未开启加签
+	Client  *http.Client // HTTP客户端，为空时使用默认客户端
+}
+
+// signedWebhook 按钉钉加签规则在Webhook地址上追加timestamp和sign参数
+func (n DingTalkNotifier) signedWebhook(now time.Time) (string, error) {
+	if n.Secret == "" {
+		return n.Webhook, nil
+	}
+	timestamp := strconv.FormatInt(now.UnixMilli(), 10)
+	mac := hmac.New(sha256.New, []byte(n.Secret))
+	mac.Write([]byte(timestamp + "\n" + n.Secret))
+	sign := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	u, err := url.Parse(n.Webhook)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set("timestamp", timestamp)
+	query.Set("sign", sign)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// Notify 发送文本消息到钉钉群
+func (n DingTalkNotifier) Notify(ctx context.Context, event RunEvent) error {
+	webhook, err := n.signedWebhook(time.Now())
+	if err != nil {
+		return err
+	}
+	payload := map[string]any{
+		"msgtype": "text",
+		"text":    map[string]string{"content": event.Text()},
+	}
+	return postRobotMessage(ctx, n.Client, webhook, payload)
+}
+
+// NotificationConfig 单个公司的通知配置
+type NotificationConfig struct {
+	Company        string      // 公司名称
+	WeComWebhook   string      // 企业微信机器人Webhook，为空表示不启用
+	DingTalkHook   string      // 钉钉机器人Webhook，为空表示不启用
+	DingTalkSecret string      // 钉钉加签密钥
+	Events         []RunStatus // 需要通知的状态，为空表示全部通知
+}
+
+// Notifiers 根据配置创建通知插件
+func (c NotificationConfig) Notifiers() []Notifier {
+	var notifiers []Notifier
+	if c.WeComWebhook != "" {
+		notifiers = append(notifiers, WeComNotifier{Webhook: c.WeComWebhook})
+	}
+	if c.DingTalkHook != "" {
+		notifiers = append(notifiers, DingTalkNotifier{Webhook: c.DingTalkHook, Secret: c.DingTalkSecret})
+	}
+	return notifiers
+}
+
+// wants 判断配置是否订阅了该状态
+func (c NotificationConfig) wants(status RunStatus) bool {
+	if len(c.Events) == 0 {
+		return true
+	}
+	for _, s := range c.Events {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationDispatcher 按公司分发批次状态通知
+type NotificationDispatcher struct {
+	configs map[string]NotificationConfig
+}
+
+// NewNotificationDispatcher 根据各公司的通知配置创建分发器
+func NewNotificationDispatcher(configs ...NotificationConfig) *NotificationDispatcher {
+	d := &NotificationDispatcher{configs: make(map[string]NotificationConfig, len(configs))}
+	for _, c := range configs {
+		d.configs[c.Company] = c
+	}
+	return d
+}
+
+// Dispatch 将事件发送到该公司配置的全部通知插件，单个插件失败不影响其他插件
+func (d *NotificationDispatcher) Dispatch(ctx context.Context, event RunEvent) error {
+	config, ok := d.configs[event.Company]
+	if !ok || !config.wants(event.Status) {
+		return nil
+	}
+	var errs []error
+	for _, n := range config.Notifiers() {
+		if err := n.Notify(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
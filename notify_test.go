@@ -0,0 +1,134 @@
+package salary
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// robotMessage 群机器人收到的一条消息
+type robotMessage struct {
+	Query   url.Values
+	MsgType string
+	Content string
+}
+
+// newRobotServer 模拟群机器人Webhook，记录收到的消息并按 errcode 响应
+func newRobotServer(t *testing.T, errcode int) (*httptest.Server, *[]robotMessage) {
+	var messages []robotMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			MsgType string `json:"msgtype"`
+			Text    struct {
+				Content string `json:"content"`
+			} `json:"text"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Error(err)
+		}
+		messages = append(messages, robotMessage{Query: r.URL.Query(), MsgType: payload.MsgType, Content: payload.Text.Content})
+		json.NewEncoder(w).Encode(map[string]any{"errcode": errcode, "errmsg": "invalid webhook"})
+	}))
+	t.Cleanup(server.Close)
+	return server, &messages
+}
+
+func TestRunEventText(t *testing.T) {
+	result := PayrollResult{Period: day("2024-06-01"), Employees: []EmployeeResult{
+		{NetSalary: toMoney(cenToDec(600000))},
+		{NetSalary: toMoney(cenToDec(500000))},
+	}}
+	event := NewRunEvent("示例公司", result, RunFailed)
+	event.Message = "银行退票"
+	text := event.Text()
+	for _, want := range []string{"示例公司", "2024-06", "失败", "员工2人", FormatMoneyCenToYuan(toMoney(cenToDec(1100000))), "\n银行退票"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text %q missing %q", text, want)
+		}
+	}
+}
+
+func TestNotificationDispatcher(t *testing.T) {
+	wecom, wecomMessages := newRobotServer(t, 0)
+	dingtalk, dingtalkMessages := newRobotServer(t, 0)
+	dispatcher := NewNotificationDispatcher(NotificationConfig{
+		Company:        "示例公司",
+		WeComWebhook:   wecom.URL,
+		DingTalkHook:   dingtalk.URL + "?access_token=abc",
+		DingTalkSecret: "SEC123",
+		Events:         []RunStatus{RunPaid, RunFailed},
+	})
+	ctx := context.Background()
+
+	// 未订阅的状态和未配置的公司不发送
+	if err := dispatcher.Dispatch(ctx, RunEvent{Company: "示例公司", Status: RunCalculated}); err != nil {
+		t.Fatal(err)
+	}
+	if err := dispatcher.Dispatch(ctx, RunEvent{Company: "其他公司", Status: RunPaid}); err != nil {
+		t.Fatal(err)
+	}
+	if len(*wecomMessages) != 0 || len(*dingtalkMessages) != 0 {
+		t.Fatalf("unexpected messages: %+v %+v", *wecomMessages, *dingtalkMessages)
+	}
+
+	event := RunEvent{Company: "示例公司", Period: day("2024-06-01"), Status: RunPaid}
+	if err := dispatcher.Dispatch(ctx, event); err != nil {
+		t.Fatal(err)
+	}
+	if len(*wecomMessages) != 1 || (*wecomMessages)[0].MsgType != "text" || (*wecomMessages)[0].Content != event.Text() {
+		t.Errorf("wecom messages = %+v", *wecomMessages)
+	}
+	if len(*dingtalkMessages) != 1 {
+		t.Fatalf("dingtalk messages = %+v", *dingtalkMessages)
+	}
+	query := (*dingtalkMessages)[0].Query
+	if query.Get("access_token") != "abc" || query.Get("timestamp") == "" || query.Get("sign") == "" {
+		t.Errorf("signed webhook query = %v", query)
+	}
+}
+
+func TestNotifierRobotError(t *testing.T) {
+	robot, _ := newRobotServer(t, 310000)
+	err := WeComNotifier{Webhook: robot.URL}.Notify(context.Background(), RunEvent{Status: RunPaid})
+	if err == nil || !strings.Contains(err.Error(), "310000") {
+		t.Errorf("err = %v, want robot error", err)
+	}
+}
+
+func TestNotifierHTTPError(t *testing.T) {
+	robot := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer robot.Close()
+	err := DingTalkNotifier{Webhook: robot.URL}.Notify(context.Background(), RunEvent{Status: RunFailed})
+	if err == nil || !strings.Contains(err.Error(), "502") {
+		t.Errorf("err = %v, want HTTP status error", err)
+	}
+}
+
+func TestDispatchContinuesAfterNotifierFailure(t *testing.T) {
+	failing, _ := newRobotServer(t, 310000)
+	working, messages := newRobotServer(t, 0)
+	dispatcher := NewNotificationDispatcher(NotificationConfig{Company: "示例公司", WeComWebhook: failing.URL, DingTalkHook: working.URL})
+
+	// 企业微信失败不影响钉钉发送，错误汇总返回
+	err := dispatcher.Dispatch(context.Background(), RunEvent{Company: "示例公司", Status: RunCalculated})
+	if err == nil || !strings.Contains(err.Error(), "310000") {
+		t.Errorf("err = %v, want joined robot error", err)
+	}
+	if len(*messages) != 1 {
+		t.Errorf("dingtalk messages = %d, want 1", len(*messages))
+	}
+}
+
+func TestDingTalkUnsignedWebhook(t *testing.T) {
+	n := DingTalkNotifier{Webhook: "https://oapi.dingtalk.com/robot/send?access_token=abc"}
+	got, err := n.signedWebhook(day("2024-06-01"))
+	if err != nil || got != n.Webhook {
+		t.Errorf("webhook = %q, %v; want unchanged without secret", got, err)
+	}
+}
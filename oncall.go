@@ -0,0 +1,156 @@
+package salary
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// 值班表生成的工资项目代码，重新导入值班表时按代码替换
+const (
+	OnCallStandbyCode = "ONCALL-STANDBY" // 待命津贴
+	OnCallCallOutCode = "ONCALL-CALLOUT" // 召回出勤工资
+)
+
+// OnCallShift 值班表中的一条记录
+type OnCallShift struct {
+	EmployeeID     string    // 工号
+	Date           time.Time // 值班日期
+	Standby        bool      // 是否在岗待命
+	CallOutMinutes []int     // 当天各次被召回出勤的分钟数
+}
+
+// OnCallPolicy 值班津贴规则
+type OnCallPolicy struct {
+	StandbyDailyRate      Money           // 每个待命日的待命津贴（分）
+	CallOutMultiplier     decimal.Decimal // 召回出勤按小时工资的倍数计发，如1.5
+	CallOutMinimumMinutes int             // 每次召回的最低计发分钟数，如120，实际出勤不足时按最低分钟数计发
+}
+
+// callOutMinutes 按最低计发分钟数汇总召回出勤
+func (p OnCallPolicy) callOutMinutes(calls []int) int {
+	total := 0
+	for _, m := range calls {
+		if m <= 0 {
+			continue
+		}
+		total += max(m, p.CallOutMinimumMinutes)
+	}
+	return total
+}
+
+// ApplyOnCallRoster 根据值班表为批次内员工生成待命津贴和召回出勤工资，作为本期一次性收入项目计入
+// 值班日期不在本批次薪资期内的记录忽略；重复调用时先移除上次生成的项目，更正后的值班表可以重新导入
+// shifts: 值班表
+// policy: 值班津贴规则
+func (r *PayrollRun) ApplyOnCallRoster(shifts []OnCallShift, policy OnCallPolicy) error {
+	index := make(map[string]int, len(r.Inputs))
+	for i, input := range r.Inputs {
+		index[input.Employee.ID] = i
+	}
+
+	standbyDays := make(map[string]int)
+	callOut := make(map[string]int)
+	var errs []error
+	for _, s := range shifts {
+		if !sameMonth(s.Date, r.Period) {
+			continue
+		}
+		if _, ok := index[s.EmployeeID]; !ok {
+			errs = append(errs, fmt.Errorf("值班记录 %s 对应的员工 %s 不在本批次中", s.Date.Format("2006-01-02"), s.EmployeeID))
+			continue
+		}
+		if s.Standby {
+			standbyDays[s.EmployeeID]++
+		}
+		callOut[s.EmployeeID] += policy.callOutMinutes(s.CallOutMinutes)
+	}
+
+	for id, i := range index {
+		input := &r.Inputs[i]
+		input.Elements = withoutOnCallElements(input.Elements)
+		if days := standbyDays[id]; days > 0 {
+			input.Elements = append(input.Elements, PayElement{
+				Code:        OnCallStandbyCode,
+				Name:        fmt.Sprintf("待命津贴（%d天）", days),
+				Kind:        KindEarning,
+				Amount:      toMoney(moneyToDec(policy.StandbyDailyRate).Mul(decimal.NewFromInt(int64(days)))),
+				Taxable:     true,
+				Start:       r.Period,
+				Occurrences: 1,
+			})
+		}
+		if minutes := callOut[id]; minutes > 0 {
+			hours := hoursToDec(minutesToHours(minutes))
+			amount := attendanceHourlyRate(input.Config, input.Attendance).Mul(hours).Mul(policy.CallOutMultiplier).Round(2)
+			input.Elements = append(input.Elements, PayElement{
+				Code:        OnCallCallOutCode,
+				Name:        fmt.Sprintf("召回出勤（%s小时）", hours.Round(2).String()),
+				Kind:        KindEarning,
+				Amount:      toMoney(amount),
+				Taxable:     true,
+				Start:       r.Period,
+				Occurrences: 1,
+			})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// withoutOnCallElements 返回移除值班表生成项目后的工资项目，不修改原切片
+func withoutOnCallElements(elements []PayElement) []PayElement {
+	kept := make([]PayElement, 0, len(elements))
+	for _, e := range elements {
+		if e.Code != OnCallStandbyCode && e.Code != OnCallCallOutCode {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
+
+// ImportOnCallRosterCSV 导入值班表CSV
+// 列：工号, 日期(YYYY-MM-DD), 是否待命(1/0), 召回出勤分钟数（多次召回以分号分隔，可为空）；首行为表头
+func ImportOnCallRosterCSV(r io.Reader) ([]OnCallShift, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	var shifts []OnCallShift
+	for i, record := range records {
+		if i == 0 {
+			continue
+		}
+		line := i + 1
+		if len(record) < 3 {
+			return nil, fmt.Errorf("第%d行列数不足", line)
+		}
+		date, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(record[1]), time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("第%d行日期格式错误: %w", line, err)
+		}
+		shift := OnCallShift{
+			EmployeeID: strings.TrimSpace(record[0]),
+			Date:       date,
+			Standby:    strings.TrimSpace(record[2]) == "1",
+		}
+		if len(record) > 3 {
+			for _, field := range strings.Split(record[3], ";") {
+				if field = strings.TrimSpace(field); field == "" {
+					continue
+				}
+				minutes, err := parseMinutes(field)
+				if err != nil {
+					return nil, fmt.Errorf("第%d行召回分钟数格式错误: %w", line, err)
+				}
+				shift.CallOutMinutes = append(shift.CallOutMinutes, minutes)
+			}
+		}
+		shifts = append(shifts, shift)
+	}
+	return shifts, nil
+}
@@ -0,0 +1,54 @@
+package salary
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestApplyOnCallRosterReplacesPreviousImport(t *testing.T) {
+	roster := "工号,日期,待命,召回分钟\nE1,2024-03-02,1,30;150\nE1,2024-03-03,1,\n"
+	shifts, err := ImportOnCallRosterCSV(strings.NewReader(roster))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !shifts[0].Date.Equal(day("2024-03-02")) {
+		t.Errorf("date = %s, want local 2024-03-02", shifts[0].Date)
+	}
+
+	policy := OnCallPolicy{
+		StandbyDailyRate:      toMoney(cenToDec(10000)),
+		CallOutMultiplier:     decimal.RequireFromString("1.5"),
+		CallOutMinimumMinutes: 120,
+	}
+	fixed := PayElement{Code: "MEAL", Name: "餐补", Kind: KindEarning, Amount: toMoney(cenToDec(30000)), Start: day("2024-01-01")}
+	run := PayrollRun{Period: day("2024-03-01"), Inputs: []EmployeeInput{{
+		Employee:   Employee{ID: "E1"},
+		Config:     testConfig(),
+		Attendance: AttendanceRecord{WorkHours: hours("174")},
+		Elements:   []PayElement{fixed},
+	}}}
+	for i := 0; i < 2; i++ {
+		if err := run.ApplyOnCallRoster(shifts, policy); err != nil {
+			t.Fatal(err)
+		}
+	}
+	elements := run.Inputs[0].Elements
+	if len(elements) != 3 {
+		t.Fatalf("elements = %+v, want MEAL plus one standby and one call-out", elements)
+	}
+	// 2天待命200元；召回30分钟按120分钟计，合计270分钟 = 4.5小时 × 8000/174 × 1.5 = 310.34元
+	assertMoney(t, "standby", elements[1].Amount, "20000")
+	assertMoney(t, "call-out", elements[2].Amount, "31034.48")
+
+	// 更正后的值班表只保留一天待命
+	if err := run.ApplyOnCallRoster(shifts[1:], policy); err != nil {
+		t.Fatal(err)
+	}
+	elements = run.Inputs[0].Elements
+	if len(elements) != 2 || elements[1].Code != OnCallStandbyCode {
+		t.Fatalf("corrected elements = %+v", elements)
+	}
+	assertMoney(t, "corrected standby", elements[1].Amount, "10000")
+}
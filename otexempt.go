@@ -0,0 +1,45 @@
+package salary
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// OvertimeTreatment 加班的处理方式
+type OvertimeTreatment int
+
+const (
+	OvertimePaid     OvertimeTreatment = iota // 支付加班工资（默认）
+	OvertimeUnpaid                            // 不支付加班工资，如实行不定时工作制的管理岗位
+	OvertimeCompTime                          // 工作日和休息日加班转为调休，法定节假日加班仍支付加班工资
+)
+
+// splitOvertime 按加班处理方式拆分考勤
+// 返回值: (计发加班工资的考勤, 不计发的加班小时, 转为调休的加班小时)
+func splitOvertime(treatment OvertimeTreatment, attendance AttendanceRecord) (AttendanceRecord, Hours, Hours) {
+	zero := Hours(decimal.Zero)
+	switch treatment {
+	case OvertimeUnpaid:
+		unpaid := workedOvertime(attendance)
+		attendance.OvertimeWeekday, attendance.OvertimeWeekend, attendance.OvertimeHoliday = zero, zero, zero
+		return attendance, Hours(unpaid), zero
+	case OvertimeCompTime:
+		comp := hoursToDec(attendance.OvertimeWeekday).Add(hoursToDec(attendance.OvertimeWeekend))
+		attendance.OvertimeWeekday, attendance.OvertimeWeekend = zero, zero
+		return attendance, zero, Hours(comp)
+	default:
+		return attendance, zero, zero
+	}
+}
+
+// workedOvertime 加班小时合计
+func workedOvertime(a AttendanceRecord) decimal.Decimal {
+	return hoursToDec(a.OvertimeWeekday).Add(hoursToDec(a.OvertimeWeekend)).Add(hoursToDec(a.OvertimeHoliday))
+}
+
+// overtimeTreatment 确定员工的加班处理方式：员工输入未指定时按职级查找批次配置
+func (r *PayrollRun) overtimeTreatment(input EmployeeInput) OvertimeTreatment {
+	if input.OvertimeTreatment != OvertimePaid {
+		return input.OvertimeTreatment
+	}
+	return r.OvertimeByGrade[input.Employee.Grade]
+}
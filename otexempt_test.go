@@ -0,0 +1,45 @@
+package salary
+
+import "testing"
+
+func TestOvertimeTreatmentByGrade(t *testing.T) {
+	attendance := AttendanceRecord{
+		WorkHours:       hours("174"),
+		OvertimeWeekday: hours("10"),
+		OvertimeWeekend: hours("4"),
+		OvertimeHoliday: hours("2"),
+	}
+	input := func(id, grade string) EmployeeInput {
+		return EmployeeInput{Employee: Employee{ID: id, Grade: grade}, Config: testConfig(), Attendance: attendance}
+	}
+	compTimeOverride := input("E4", "M1")
+	compTimeOverride.OvertimeTreatment = OvertimeCompTime
+
+	run := PayrollRun{
+		Period:          day("2024-03-01"),
+		OvertimeByGrade: map[string]OvertimeTreatment{"M1": OvertimeUnpaid, "P5": OvertimeCompTime},
+		Inputs:          []EmployeeInput{input("E1", "M1"), input("E2", "P5"), input("E3", "P3"), compTimeOverride},
+	}
+	employees := run.Calculate().Employees
+
+	// 不定时工作制：不计发加班工资，记录加班小时
+	assertMoney(t, "E1 overtime", employees[0].OvertimePay, "0")
+	if !hoursToDec(employees[0].UnpaidOvertimeHours).Equal(hoursToDec(hours("16"))) {
+		t.Errorf("E1 unpaid hours = %s", hoursToDec(employees[0].UnpaidOvertimeHours))
+	}
+
+	// 调休：工作日和休息日加班转调休，法定节假日加班照常支付
+	holidayOnly := CalculateOvertimePay(testConfig(), AttendanceRecord{OvertimeHoliday: hours("2")})
+	assertMoney(t, "E2 overtime", employees[1].OvertimePay, moneyToDec(holidayOnly).String())
+	if !hoursToDec(employees[1].CompTimeHours).Equal(hoursToDec(hours("14"))) {
+		t.Errorf("E2 comp time = %s", hoursToDec(employees[1].CompTimeHours))
+	}
+
+	// 未配置的职级支付全部加班工资
+	assertMoney(t, "E3 overtime", employees[2].OvertimePay, moneyToDec(CalculateOvertimePay(testConfig(), attendance)).String())
+
+	// 员工输入指定的处理方式优先于职级配置
+	if !hoursToDec(employees[3].CompTimeHours).Equal(hoursToDec(hours("14"))) || hoursToDec(employees[3].UnpaidOvertimeHours).IsPositive() {
+		t.Errorf("E4 = %+v", employees[3])
+	}
+}
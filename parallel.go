@@ -0,0 +1,103 @@
+package salary
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// CalculateParallel 使用工作池并发计算批次内所有员工的工资，适用于数万人规模的批次
+// 员工结果和错误的顺序与输入一致，与 Calculate 的结果相同；单个员工计算出错（含panic）只记入 Errors，不影响其他员工
+// 批次级设置的应用和台账、审计日志的登记仍按输入顺序依次进行，只有工资计算本身并发执行
+// 上下文取消后未计算的员工以 ctx.Err() 记入 Errors，已取出的考勤更正退回待计入，同时返回 ctx.Err()
+// ctx: 上下文，用于取消计算
+// concurrency: 并发数，不大于0时取 GOMAXPROCS
+func (r *PayrollRun) CalculateParallel(ctx context.Context, concurrency int) (PayrollResult, error) {
+	if concurrency <= 0 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
+	result := r.newResult()
+
+	type job struct {
+		input     EmployeeInput
+		residency TaxResidency
+		employee  EmployeeResult
+		err       error
+		prepared  bool // 已应用批次级设置，可能已取出考勤更正
+		done      bool
+	}
+	jobs := make([]job, len(r.Inputs))
+	for i, input := range r.Inputs {
+		jobs[i].input, jobs[i].residency, jobs[i].err = r.prepare(input, &result)
+		jobs[i].prepared = jobs[i].err == nil
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				j := &jobs[i]
+				j.employee, j.err = calculateEmployeeSafe(r.Period, j.input)
+				j.employee.TaxResidency = j.residency
+				j.done = true
+			}
+		}()
+	}
+	cancelled := false
+dispatch:
+	for i := range jobs {
+		if !jobs[i].prepared {
+			continue
+		}
+		if ctx.Err() != nil {
+			cancelled = true
+			break
+		}
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			cancelled = true
+			break dispatch
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	for i := range jobs {
+		j := &jobs[i]
+		if j.prepared && !j.done {
+			j.err = ctx.Err()
+		}
+		if j.err != nil {
+			if j.prepared && r.Corrections != nil {
+				r.Corrections.release(j.input.Employee.ID, r.Period)
+			}
+			result.Errors = append(result.Errors, EmployeeError{EmployeeID: j.input.Employee.ID, Err: j.err})
+			continue
+		}
+		r.finalize(j.input, &j.employee)
+		result.Employees = append(result.Employees, j.employee)
+		result.Inputs = append(result.Inputs, j.input)
+	}
+	result.WageFloorViolations = r.WageFloors.Violations(result.Employees)
+	r.collectWarnings(&result)
+	if cancelled {
+		return result, ctx.Err()
+	}
+	return result, nil
+}
+
+// calculateEmployeeSafe 计算单个员工的工资，计算过程中的panic转换为错误
+func calculateEmployeeSafe(period time.Time, input EmployeeInput) (employee EmployeeResult, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("计算员工 %s 工资失败: %v", input.Employee.ID, p)
+		}
+	}()
+	return CalculateEmployee(period, input), nil
+}
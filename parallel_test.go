@@ -0,0 +1,52 @@
+package salary
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func syntheticRun(headcount int) PayrollRun {
+	period := time.Date(2024, 6, 1, 0, 0, 0, 0, time.Local)
+	g := NewSyntheticGenerator(SyntheticOptions{Headcount: headcount, Seed: 3, HireBefore: period.AddDate(0, -1, 0)})
+	return PayrollRun{Period: period, Inputs: g.Inputs(g.Employees(), period)}
+}
+
+func TestCalculateParallelMatchesSequential(t *testing.T) {
+	run := syntheticRun(200)
+	want := run.Calculate()
+	got, err := run.CalculateParallel(context.Background(), 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Employees, want.Employees) {
+		t.Error("parallel results differ from sequential results")
+	}
+}
+
+func TestCalculateParallelCancelled(t *testing.T) {
+	run := syntheticRun(50)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, err := run.CalculateParallel(ctx, 4)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("error = %v, want context.Canceled", err)
+	}
+	if len(result.Employees)+len(result.Errors) != len(run.Inputs) {
+		t.Errorf("employees %d + errors %d != inputs %d", len(result.Employees), len(result.Errors), len(run.Inputs))
+	}
+}
+
+func TestCalculateParallelRecoversPanic(t *testing.T) {
+	run := syntheticRun(3)
+	run.Inputs[1].PensionPlans = []PensionPlan{{Code: "BAD", Compute: func(PensionContext) PensionAmount { panic("boom") }}}
+	result, err := run.CalculateParallel(context.Background(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Employees) != 2 || len(result.Errors) != 1 || result.Errors[0].EmployeeID != run.Inputs[1].Employee.ID {
+		t.Errorf("employees = %d, errors = %v", len(result.Employees), result.Errors)
+	}
+}
@@ -0,0 +1,176 @@
+package salary
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// 输入解析错误的类别，可通过 errors.Is 判断
+var (
+	ErrInvalidMoney = errors.New("金额格式错误")
+	ErrInvalidRate  = errors.New("比例格式错误")
+)
+
+// ParseError 用户输入解析错误
+type ParseError struct {
+	Input  string // 原始输入
+	Kind   error  // 错误类别：ErrInvalidMoney、ErrInvalidRate 或 ErrInvalidHours
+	Reason string // 具体原因
+}
+
+// Error 实现 error 接口
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%v %q: %s", e.Kind, e.Input, e.Reason)
+}
+
+// Unwrap 返回错误类别
+func (e *ParseError) Unwrap() error {
+	return e.Kind
+}
+
+// normalizeNumber 统一数字输入的写法：全角字符转半角，去除空白和千分位分隔符
+func normalizeNumber(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= '０' && r <= '９':
+			b.WriteRune(r - '０' + '0')
+		case r == '．':
+			b.WriteRune('.')
+		case r == '％':
+			b.WriteRune('%')
+		case r == '－':
+			b.WriteRune('-')
+		case r == ',' || r == '，' || r == '_' || r == ' ' || r == '\t' || r == '　':
+			// 千分位分隔符和空白
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// ParseMoney 解析用户输入的金额（元），返回以分为单位的金额
+// 可接受 "8000"、"8,000.50"、"8000.5元"、"¥8000"、"RMB 8000"、"1.2万"、全角数字等写法，金额最多两位小数
+func ParseMoney(s string) (Money, error) {
+	d, err := parseAmount(s)
+	if err != nil {
+		return toMoney(decimal.Zero), err
+	}
+	cents := d.Mul(decimal.NewFromInt(100))
+	if !cents.Equal(cents.Truncate(0)) {
+		return toMoney(decimal.Zero), &ParseError{Input: s, Kind: ErrInvalidMoney, Reason: "金额最多精确到分"}
+	}
+	return toMoney(cents), nil
+}
+
+// parseAmount 去掉货币符号和"元"后解析金额数字，"万"按一万倍换算，不限制小数位数
+// 返回值: 与输入同单位的数值，ParseMoney 再按元换算为分
+func parseAmount(s string) (decimal.Decimal, error) {
+	fail := func(reason string) (decimal.Decimal, error) {
+		return decimal.Zero, &ParseError{Input: s, Kind: ErrInvalidMoney, Reason: reason}
+	}
+
+	v := normalizeNumber(strings.TrimSpace(s))
+	for _, prefix := range []string{"¥", "￥", "RMB", "CNY"} {
+		v = strings.TrimPrefix(v, prefix)
+	}
+	multiplier := decimal.NewFromInt(1)
+	switch {
+	case strings.HasSuffix(v, "万元"), strings.HasSuffix(v, "万"):
+		v = strings.TrimSuffix(strings.TrimSuffix(v, "元"), "万")
+		multiplier = decimal.NewFromInt(10000)
+	default:
+		v = strings.TrimSuffix(v, "元")
+	}
+	if v == "" {
+		return fail("缺少数字")
+	}
+
+	d, err := decimal.NewFromString(v)
+	if err != nil {
+		return fail("无法识别的数字")
+	}
+	return d.Mul(multiplier), nil
+}
+
+// ParseRate 解析用户输入的比例，返回小数形式（如 0.08）
+// 可接受 "8%"、"8 %"、"８％"、"0.08"；不带百分号时按小数理解，须在[0,1]区间内，避免把 8 误当作 800%
+func ParseRate(s string) (decimal.Decimal, error) {
+	fail := func(reason string) (decimal.Decimal, error) {
+		return decimal.Zero, &ParseError{Input: s, Kind: ErrInvalidRate, Reason: reason}
+	}
+
+	v := normalizeNumber(strings.TrimSpace(s))
+	percent := strings.HasSuffix(v, "%")
+	v = strings.TrimSuffix(v, "%")
+	if v == "" {
+		return fail("缺少数字")
+	}
+
+	d, err := decimal.NewFromString(v)
+	if err != nil {
+		return fail("无法识别的数字")
+	}
+	if percent {
+		d = d.Div(decimal.NewFromInt(100))
+	}
+	if d.IsNegative() {
+		return fail("比例不能为负数")
+	}
+	if !percent && d.GreaterThan(decimal.NewFromInt(1)) {
+		return fail("比例超出[0,1]范围，百分比请加 % 号")
+	}
+	return d, nil
+}
+
+// ParseHours 解析用户输入的工时
+// 可接受 "11"、"7.5小时"、"7.5h"、全角数字等写法，不能为负数
+func ParseHours(s string) (Hours, error) {
+	d, err := parseDuration(s, "小时", "h", "H")
+	if err != nil {
+		return Hours(decimal.Zero), err
+	}
+	return Hours(d), nil
+}
+
+// parseMinutes 解析分钟数，可接受 "90"、"90分钟"、"90min"，须为非负整数
+func parseMinutes(s string) (int, error) {
+	d, err := parseDuration(s, "分钟", "min")
+	if err != nil {
+		return 0, err
+	}
+	if !d.Equal(d.Truncate(0)) {
+		return 0, &ParseError{Input: s, Kind: ErrInvalidHours, Reason: "分钟数须为整数"}
+	}
+	return int(d.IntPart()), nil
+}
+
+// parseDuration 统一写法并去掉时间单位后解析非负数值
+func parseDuration(s string, units ...string) (decimal.Decimal, error) {
+	fail := func(reason string) (decimal.Decimal, error) {
+		return decimal.Zero, &ParseError{Input: s, Kind: ErrInvalidHours, Reason: reason}
+	}
+
+	v := normalizeNumber(strings.TrimSpace(s))
+	for _, unit := range units {
+		if strings.HasSuffix(v, unit) {
+			v = strings.TrimSuffix(v, unit)
+			break
+		}
+	}
+	if v == "" {
+		return fail("缺少数字")
+	}
+	d, err := decimal.NewFromString(v)
+	if err != nil {
+		return fail("无法识别的数字")
+	}
+	if d.IsNegative() {
+		return fail("不能为负数")
+	}
+	return d, nil
+}
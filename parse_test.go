@@ -0,0 +1,91 @@
+package salary
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"8000", "800000"},
+		{"¥8,000.50", "800050"},
+		{"RMB 12.3", "1230"},
+		{"1.5万", "1500000"},
+		{"８０００元", "800000"},
+	}
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.input)
+		if err != nil {
+			t.Errorf("ParseMoney(%q) error: %v", tt.input, err)
+			continue
+		}
+		assertMoney(t, "ParseMoney("+tt.input+")", got, tt.want)
+	}
+}
+
+func TestParseMoneyInvalid(t *testing.T) {
+	for _, input := range []string{"", "abc", "1.234"} {
+		if _, err := ParseMoney(input); !errors.Is(err, ErrInvalidMoney) {
+			t.Errorf("ParseMoney(%q) error = %v, want ErrInvalidMoney", input, err)
+		}
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"8%", "0.08"},
+		{"0.075", "0.075"},
+		{"12.5%", "0.125"},
+	}
+	for _, tt := range tests {
+		got, err := ParseRate(tt.input)
+		if err != nil {
+			t.Errorf("ParseRate(%q) error: %v", tt.input, err)
+			continue
+		}
+		if got.String() != tt.want {
+			t.Errorf("ParseRate(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+	if _, err := ParseRate("8"); !errors.Is(err, ErrInvalidRate) {
+		t.Errorf("ParseRate(\"8\") error = %v, want ErrInvalidRate", err)
+	}
+}
+
+func TestParseHours(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"11", "11"},
+		{"7.5小时", "7.5"},
+		{"７．５h", "7.5"},
+	}
+	for _, tt := range tests {
+		got, err := ParseHours(tt.input)
+		if err != nil {
+			t.Errorf("ParseHours(%q) error: %v", tt.input, err)
+			continue
+		}
+		if !hoursToDec(got).Equal(hoursToDec(hours(tt.want))) {
+			t.Errorf("ParseHours(%q) = %s, want %s", tt.input, hoursToDec(got), tt.want)
+		}
+	}
+	for _, input := range []string{"", "-1", "七小时"} {
+		if _, err := ParseHours(input); !errors.Is(err, ErrInvalidHours) {
+			t.Errorf("ParseHours(%q) error = %v, want ErrInvalidHours", input, err)
+		}
+	}
+	if m, err := parseMinutes("90分钟"); err != nil || m != 90 {
+		t.Errorf("parseMinutes(\"90分钟\") = %d, %v", m, err)
+	}
+	if _, err := parseMinutes("1.5"); !errors.Is(err, ErrInvalidHours) {
+		t.Errorf("parseMinutes(\"1.5\") error = %v, want ErrInvalidHours", err)
+	}
+}
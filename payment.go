@@ -0,0 +1,318 @@
+package salary
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// PaymentSplit 实发工资分账：固定金额发放到第二个账户，其余发放到工资卡
+type PaymentSplit struct {
+	BankAccount string `json:"bank_account"` // 第二个账户卡号，接口响应中脱敏
+	BankName    string `json:"bank_name"`    // 按卡号识别的发卡行，未收录时为空
+	Amount      Money  `json:"amount"`       // 每月发放到第二个账户的固定金额（分），实发不足时以实发为限
+}
+
+// Validate 校验分账设置：卡号有效且金额为正数
+func (p PaymentSplit) Validate() error {
+	if _, err := ValidateBankAccount(p.BankAccount); err != nil {
+		return fmt.Errorf("分账账户: %w", err)
+	}
+	if !moneyToDec(p.Amount).IsPositive() {
+		return errors.New("分账金额必须大于0")
+	}
+	return nil
+}
+
+// normalized 返回卡号规范化并识别发卡行后的分账设置
+func (p PaymentSplit) normalized() PaymentSplit {
+	p.BankAccount = NormalizeBankAccount(p.BankAccount)
+	bank, _ := LookupBank(p.BankAccount)
+	p.BankName = bank.Name
+	return p
+}
+
+// BankPayment 银行代发文件中的一笔付款
+type BankPayment struct {
+	Reference   string `json:"reference"`    // 付款附言，批次内唯一，用于与银行回单对账
+	EmployeeID  string `json:"employee_id"`  // 工号
+	Name        string `json:"name"`         // 户名
+	BankAccount string `json:"bank_account"` // 收款账号
+	BankName    string `json:"bank_name"`    // 开户行
+	Amount      Money  `json:"amount"`       // 金额（分）
+}
+
+// ErrMissingBankAccount 员工档案中没有工资卡号，无法生成代发记录
+var ErrMissingBankAccount = errors.New("员工未登记工资卡号")
+
+// BankPayments 按员工档案中的收款账户生成批次的代发付款，设置了分账的员工拆分为两笔
+// 分账金额优先发放到第二个账户，实发不足时以实发为限，剩余部分发放到工资卡；金额为0的付款不生成
+// 有效暂扣的金额先从实发中扣下，不进入代发文件；本函数不修改暂扣台账，可用于对账等只读场景
+// 缺少档案或工资卡号的员工不生成付款并记录错误
+// run: 批次结果
+// records: 按工号索引的员工档案
+// holds: 暂扣台账，为空表示没有暂扣
+func BankPayments(run PayrollResult, records map[string]EmployeeRecord, holds *HoldLedger) ([]BankPayment, []EmployeeError) {
+	payments, _, errs := bankPayments(run, records, holds)
+	return payments, errs
+}
+
+// CommitBankPayments 确认批次代发：生成代发付款并将扣下的暂扣金额记入暂扣台账，仅在代发文件提交银行后调用
+// 同一批次重复确认时覆盖该批次已记录的暂扣金额；存在无法生成付款的员工时不记入台账
+func CommitBankPayments(run PayrollResult, records map[string]EmployeeRecord, holds *HoldLedger) ([]BankPayment, []EmployeeError) {
+	payments, withholdings, errs := bankPayments(run, records, holds)
+	if len(errs) == 0 && holds != nil {
+		holds.commit(run, withholdings)
+	}
+	return payments, errs
+}
+
+// bankPayments 计算代发付款和各员工应扣下的暂扣金额，不修改暂扣台账
+func bankPayments(run PayrollResult, records map[string]EmployeeRecord, holds *HoldLedger) ([]BankPayment, []Withholding, []EmployeeError) {
+	var payments []BankPayment
+	var withholdings []Withholding
+	var errs []EmployeeError
+	prefix := run.ID
+	if prefix == "" {
+		prefix = run.Period.Format("200601")
+	}
+	for _, r := range run.Employees {
+		id := r.Employee.ID
+		record, ok := records[id]
+		if !ok {
+			errs = append(errs, EmployeeError{EmployeeID: id, Err: fmt.Errorf("%w: %s", ErrEmployeeNotFound, id)})
+			continue
+		}
+		if record.BankAccount == "" {
+			errs = append(errs, EmployeeError{EmployeeID: id, Err: ErrMissingBankAccount})
+			continue
+		}
+		net := moneyToDec(r.NetSalary)
+		if holds != nil && net.IsPositive() {
+			var held []Withholding
+			net, held = holds.withholding(run, id, net)
+			withholdings = append(withholdings, held...)
+		}
+		if !net.IsPositive() {
+			continue
+		}
+		if split := record.PaymentSplit; split != nil {
+			amount := decimal.Min(moneyToDec(split.Amount), net)
+			if amount.IsPositive() {
+				payments = append(payments, BankPayment{
+					Reference:   fmt.Sprintf("%s-%s-2", prefix, id),
+					EmployeeID:  id,
+					Name:        r.Employee.Name,
+					BankAccount: split.BankAccount,
+					BankName:    split.BankName,
+					Amount:      toMoney(amount),
+				})
+				net = net.Sub(amount)
+			}
+		}
+		if net.IsPositive() {
+			payments = append(payments, BankPayment{
+				Reference:   fmt.Sprintf("%s-%s-1", prefix, id),
+				EmployeeID:  id,
+				Name:        r.Employee.Name,
+				BankAccount: record.BankAccount,
+				BankName:    record.BankName,
+				Amount:      toMoney(net),
+			})
+		}
+	}
+	return payments, withholdings, errs
+}
+
+// WriteBankFile 输出银行代发CSV文件，金额单位为元
+// 列：序号, 附言, 工号, 户名, 账号, 开户行, 金额
+func WriteBankFile(w io.Writer, payments []BankPayment) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"序号", "附言", "工号", "户名", "账号", "开户行", "金额"})
+	for i, p := range payments {
+		cw.Write([]string{
+			fmt.Sprint(i + 1), p.Reference, p.EmployeeID, p.Name, p.BankAccount, p.BankName,
+			moneyToDec(p.Amount).Shift(-2).StringFixed(2),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// BankReturn 银行回单中的一笔付款结果
+type BankReturn struct {
+	Reference   string // 付款附言
+	BankAccount string // 收款账号
+	Amount      Money  // 实际付款金额（分）
+	Success     bool   // 是否付款成功
+	Message     string // 银行返回的说明，如失败原因
+}
+
+// ImportBankReturnCSV 导入银行回单，首行为表头
+// 列：附言, 账号, 金额（元）, 状态（成功/失败）, 说明
+func ImportBankReturnCSV(r io.Reader) ([]BankReturn, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	var returns []BankReturn
+	for i, row := range rows {
+		if i == 0 {
+			continue
+		}
+		line := i + 1
+		if len(row) < 4 {
+			return nil, fmt.Errorf("第%d行列数不足", line)
+		}
+		amount, err := ParseMoney(row[2])
+		if err != nil {
+			return nil, fmt.Errorf("第%d行金额: %w", line, err)
+		}
+		ret := BankReturn{
+			Reference:   strings.TrimSpace(row[0]),
+			BankAccount: NormalizeBankAccount(row[1]),
+			Amount:      amount,
+			Success:     strings.TrimSpace(row[3]) == "成功",
+		}
+		if len(row) > 4 {
+			ret.Message = strings.TrimSpace(row[4])
+		}
+		returns = append(returns, ret)
+	}
+	return returns, nil
+}
+
+// PaymentMismatch 代发付款与银行回单不一致的一笔记录
+type PaymentMismatch struct {
+	Reference  string `json:"reference"`   // 付款附言
+	EmployeeID string `json:"employee_id"` // 工号，回单中多出的付款为空
+	Reason     string `json:"reason"`      // 不一致原因
+}
+
+// PaymentReconciliation 代发对账结果
+type PaymentReconciliation struct {
+	Paid       Money             `json:"paid"`       // 成功付款合计（分）
+	Matched    int               `json:"matched"`    // 成功且金额、账号一致的付款笔数
+	Mismatches []PaymentMismatch `json:"mismatches"` // 失败、缺失、金额或账号不一致以及回单中多出的付款
+	Unpaid     []string          `json:"unpaid"`     // 至少有一笔付款未成功的员工，分账员工须两笔均成功才算发放完毕
+}
+
+// ReconcilePayments 按附言核对代发付款与银行回单
+// payments: 代发付款
+// returns: 银行回单
+func ReconcilePayments(payments []BankPayment, returns []BankReturn) PaymentReconciliation {
+	byRef := make(map[string]BankReturn, len(returns))
+	for _, ret := range returns {
+		byRef[ret.Reference] = ret
+	}
+	var rec PaymentReconciliation
+	paid := decimal.Zero
+	unpaid := make(map[string]bool)
+	for _, p := range payments {
+		ret, ok := byRef[p.Reference]
+		delete(byRef, p.Reference)
+		reason := ""
+		switch {
+		case !ok:
+			reason = "银行回单中没有该笔付款"
+		case !ret.Success:
+			reason = "付款失败：" + ret.Message
+		case NormalizeBankAccount(ret.BankAccount) != p.BankAccount:
+			reason = "收款账号不一致"
+		case !moneyToDec(ret.Amount).Equal(moneyToDec(p.Amount)):
+			reason = fmt.Sprintf("金额不一致：应付%s，实付%s", FormatMoneyCenToYuan(p.Amount), FormatMoneyCenToYuan(ret.Amount))
+		}
+		if ok && ret.Success {
+			paid = paid.Add(moneyToDec(ret.Amount))
+		}
+		if reason != "" {
+			rec.Mismatches = append(rec.Mismatches, PaymentMismatch{Reference: p.Reference, EmployeeID: p.EmployeeID, Reason: reason})
+			if !unpaid[p.EmployeeID] {
+				unpaid[p.EmployeeID] = true
+				rec.Unpaid = append(rec.Unpaid, p.EmployeeID)
+			}
+			continue
+		}
+		rec.Matched++
+	}
+	for _, ret := range returns {
+		if _, extra := byRef[ret.Reference]; extra {
+			rec.Mismatches = append(rec.Mismatches, PaymentMismatch{Reference: ret.Reference, Reason: "代发文件中没有该笔付款"})
+			if ret.Success {
+				paid = paid.Add(moneyToDec(ret.Amount))
+			}
+		}
+	}
+	rec.Paid = toMoney(paid)
+	return rec
+}
+
+// employeeRecords 返回按工号索引的员工档案
+func (s *MemoryStore) employeeRecords() map[string]EmployeeRecord {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	records := make(map[string]EmployeeRecord, len(s.employees))
+	for id, r := range s.employees {
+		records[id] = r
+	}
+	return records
+}
+
+// handleBankFile 下载批次的银行代发文件：GET /runs/{id}/bank-file
+// 下载不修改暂扣台账，可重复下载；代发文件提交银行后通过 POST /runs/{id}/payments/commit 记入暂扣金额
+// 存在无法生成付款的员工时返回错误明细，不输出不完整的代发文件
+func (s *Server) handleBankFile(w http.ResponseWriter, r *http.Request) {
+	run, err := s.store.Run(r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	payments, errs := BankPayments(run, s.store.employeeRecords(), s.store.holds)
+	if len(errs) > 0 {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]any{"errors": errs})
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="bank-%s.csv"`, run.ID))
+	WriteBankFile(w, payments)
+}
+
+// handleCommitPayments 确认批次代发并将扣下的暂扣金额记入暂扣台账：POST /runs/{id}/payments/commit
+// 返回确认的代发付款；存在无法生成付款的员工时返回错误明细，不记入台账
+func (s *Server) handleCommitPayments(w http.ResponseWriter, r *http.Request) {
+	run, err := s.store.Run(r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	payments, errs := CommitBankPayments(run, s.store.employeeRecords(), s.store.holds)
+	if len(errs) > 0 {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]any{"errors": errs})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"run_id": run.ID, "payments": payments})
+}
+
+// handleReconcileBankReturn 上传银行回单并与批次代发付款对账：POST /runs/{id}/bank-return
+// 只按暂扣台账重新计算代发付款，不修改暂扣余额
+func (s *Server) handleReconcileBankReturn(w http.ResponseWriter, r *http.Request) {
+	run, err := s.store.Run(r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	returns, err := ImportBankReturnCSV(r.Body)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	payments, _ := BankPayments(run, s.store.employeeRecords(), s.store.holds)
+	writeJSON(w, http.StatusOK, ReconcilePayments(payments, returns))
+}
@@ -0,0 +1,59 @@
+package salary
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestBankPaymentsSplitAndReconcile(t *testing.T) {
+	const primary, secondary = "6222021234567890128", "6228481234567890128"
+	if err := (PaymentSplit{BankAccount: secondary, Amount: toMoney(cenToDec(100000))}).Validate(); err != nil {
+		t.Fatal(err)
+	}
+	run := PayrollResult{ID: "202406-000001", Employees: []EmployeeResult{
+		{Employee: Employee{ID: "E1", Name: "张三"}, NetSalary: toMoney(cenToDec(650000))},
+		{Employee: Employee{ID: "E2", Name: "李四"}, NetSalary: toMoney(cenToDec(50000))},
+		{Employee: Employee{ID: "E3", Name: "王五"}, NetSalary: toMoney(cenToDec(50000))},
+	}}
+	split := &PaymentSplit{BankAccount: secondary, Amount: toMoney(cenToDec(100000))}
+	records := map[string]EmployeeRecord{
+		"E1": {BankAccount: primary, PaymentSplit: split},
+		"E2": {BankAccount: primary, PaymentSplit: split}, // 实发不足分账金额，全部发放到第二个账户
+	}
+	payments, errs := BankPayments(run, records, nil)
+	if len(errs) != 1 || errs[0].EmployeeID != "E3" {
+		t.Errorf("errors = %v, want E3 missing record", errs)
+	}
+	if len(payments) != 3 {
+		t.Fatalf("payments = %+v, want 3", payments)
+	}
+	assertMoney(t, "E1 secondary", payments[0].Amount, "100000")
+	assertMoney(t, "E1 primary", payments[1].Amount, "550000")
+	if payments[2].BankAccount != secondary {
+		t.Errorf("E2 paid to %s, want secondary account", payments[2].BankAccount)
+	}
+	assertMoney(t, "E2 secondary", payments[2].Amount, "50000")
+
+	var buf bytes.Buffer
+	if err := WriteBankFile(&buf, payments); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "5500.00") {
+		t.Errorf("bank file lacks E1 primary payment:\n%s", buf.String())
+	}
+
+	returns, err := ImportBankReturnCSV(strings.NewReader(`附言,账号,金额,状态,说明
+202406-000001-E1-2,` + secondary + `,1000,成功,
+202406-000001-E1-1,` + primary + `,5500,失败,账户冻结
+202406-000001-E2-2,` + secondary + `,500,成功,
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := ReconcilePayments(payments, returns)
+	if rec.Matched != 2 || len(rec.Mismatches) != 1 || len(rec.Unpaid) != 1 || rec.Unpaid[0] != "E1" {
+		t.Errorf("reconciliation = %+v", rec)
+	}
+	assertMoney(t, "Paid", rec.Paid, "150000")
+}
@@ -0,0 +1,503 @@
+package salary
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Gender 性别
+type Gender int
+
+const (
+	GenderUnknown Gender = iota // 未知
+	GenderMale                  // 男
+	GenderFemale                // 女
+)
+
+// String 返回性别的中文名称
+func (g Gender) String() string {
+	switch g {
+	case GenderMale:
+		return "男"
+	case GenderFemale:
+		return "女"
+	default:
+		return "未知"
+	}
+}
+
+// Employee 员工档案
+type Employee struct {
+	ID              string       // 工号
+	Name            string       // 姓名
+	Department      string       // 部门
+	Grade           string       // 职级
+	Gender          Gender       // 性别
+	HireDate        time.Time    // 入职日期
+	TerminationDate time.Time    // 离职日期，零值表示在职
+	City            string       // 社保缴纳城市代码，如 shanghai
+	Jurisdiction    Jurisdiction // 适用的税收和社保辖区，零值为中国内地
+	BirthDate       time.Time    // 出生日期，用于按年龄确定缴费率
+	JobCategory     string       // 岗位类别，用于检查集体合同约定的岗位最低工资
+}
+
+// PeriodStatus 员工在薪资期内的在岗状态
+type PeriodStatus int
+
+const (
+	PeriodActive   PeriodStatus = iota // 正常在岗
+	PeriodInactive                     // 本期未在岗（整月停薪留职、停职等），不产生收入
+)
+
+// InactiveInsurance 未在岗期间的社保公积金处理方式
+type InactiveInsurance int
+
+const (
+	InactiveInsuranceNone     InactiveInsurance = iota // 停缴社保公积金
+	InactiveInsuranceContinue                          // 按合同工资基数继续缴纳，由公司代缴
+)
+
+// EmployeeInput 单个员工某一薪资期的计算输入
+type EmployeeInput struct {
+	Employee          Employee               // 员工档案
+	Config            PayrollConfig          // 薪资配置
+	Attendance        AttendanceRecord       // 考勤记录
+	Deductions        SpecialDeductions      // 专项附加扣除
+	Declarations      []DeductionDeclaration // 带有效期的专项附加扣除申报，非空时替代 Deductions
+	Status            PeriodStatus           // 本期在岗状态
+	InactiveInsurance InactiveInsurance      // 未在岗期间的社保公积金处理方式
+	Elements          []PayElement           // 周期性工资项目，按薪资期自动计入
+	Adjustments       []Adjustment           // 本期一次性调整
+	Stipend           Money                  // 入职前每月实习津贴（分），入职月份起自动转为正式工资
+	PenaltyCapRate    decimal.Decimal        // 违纪扣款占当月工资的上限比例，0表示不限
+	Equalization      *TaxEqualization       // 外派税收均衡协议，为空表示不适用
+	CPFYearToDate     CPFYearToDate          // 新加坡员工本年度截至上期已计缴公积金的工资
+	OvertimeTreatment OvertimeTreatment      // 加班处理方式，零值为支付加班工资
+	EmployerBearsTax  bool                   // 是否为税后工资合同，个人所得税由公司承担
+	TaxState          *TaxWithholdingState   // 累计预扣法截至上月的本年累计数据，为空表示按单月计算个税
+	PensionPlans      []PensionPlan          // 参加的企业补充养老计划，在法定社保之后计算
+	Relocations       []Relocation           // 跨城市调动记录，按薪资期确定适用的城市政策
+	Proration         ProrationMethod        // 入职、离职当月基本工资的折算方式，零值沿用批次设置，ProrateNone 表示不折算
+	Calendar          *Calendar              // 节假日日历，按工作日折算时使用
+	TaxResidency      TaxResidency           // 纳税人身份，由批次按境内居住天数判定；非居民个人按月计税且不扣除专项附加扣除
+}
+
+// EmployeeResult 单个员工某一薪资期的计算结果（金额单位为分）
+type EmployeeResult struct {
+	Employee              Employee             // 员工档案
+	Period                time.Time            // 薪资期（当月1日）
+	Status                PeriodStatus         // 本期在岗状态
+	Category              PayCategory          // 计算类别
+	BaseSalary            Money                // 基础工资
+	OvertimePay           Money                // 加班工资
+	GrossSalary           Money                // 税前工资
+	SocialInsurance       Money                // 社保个人部分
+	HousingFund           Money                // 公积金个人部分
+	TaxableIncome         Money                // 应纳税所得额（未扣专项附加扣除）
+	SpecialDeductionTotal Money                // 专项附加扣除总额
+	StandardDeduction     Money                // 本月减除费用（起征点，按单月计税时使用）
+	IncomeTax             Money                // 个人所得税
+	NetSalary             Money                // 实发工资
+	Lines                 []PayLine            // 基础工资、加班工资以外的收入和扣款明细
+	PenaltyTruncated      Money                // 超过上限未扣除的违纪扣款
+	Attendance            AttendanceRecord     // 本期考勤
+	StandardHours         Hours                // 本期标准工时
+	HypotheticalTax       Money                // 税收均衡下代扣的假设税
+	TaxProvision          Money                // 雇主不代扣、由员工自行申报的税款预估，如香港薪俸税
+	EmployerContributions Money                // 单位缴费合计，如香港强积金雇主供款
+	Statutory             []StatutoryLine      // 辖区法定扣缴明细，中国内地员工为空
+	UnpaidOvertimeHours   Hours                // 不计发加班工资的加班小时
+	CompTimeHours         Hours                // 转为调休的加班小时
+	HousingFundExcess     Money                // 公积金超过免税限额、并入应纳税所得额的部分
+	BenefitsInKind        Money                // 非现金福利应税价值，计入应纳税所得额，不计入税前工资和实发工资
+	TaxState              *TaxWithholdingState // 按累计预扣法计算时，包含本期的本年累计数据，保存后作为下期输入
+	Pension               []PensionLine        // 企业补充养老计划缴费明细，单位缴费同时计入 EmployerContributions
+	TaxResidency          TaxResidency         // 按境内居住天数判定的纳税人身份，未跟踪时为零值
+	TaxMethod             TaxMethod            // 本期个税的计算方法
+	CPFYearToDate         *CPFYearToDate       // 新加坡员工计入本期后的公积金本年度累计工资，保存后作为下期输入；其他员工为空
+	BaseClamps            []InsuranceBaseClamp // 缴费基数按上下限封顶保底的险种，未调整时为空
+	Relocation            *Relocation          // 本期生效的跨城市调动，在工资条上说明缴纳城市变更
+	Proration             *Proration           // 入职、离职当月的基本工资折算明细，未折算时为空
+	PensionBase           Money                // 基本养老保险缴费基数，不缴纳时为0，用于累计缴费年限和估算养老金
+	EmployerCost          *EmployerCost        // 单位缴费和全口径人工成本，批次计算时按员工适用的薪资配置记录；直接调用 CalculateEmployee 时为空
+
+	ReceivableAccrued   Money // 本期新增员工欠款（停薪期间公司代缴的个人社保公积金）
+	ReceivableRecovered Money // 本期从实发工资中抵扣的欠款
+}
+
+// PayrollResult 一次发薪批次的计算结果
+type PayrollResult struct {
+	ID                  string                // 批次编号，保存时生成
+	Period              time.Time             // 薪资期（当月1日）
+	EngineVersion       string                // 计算引擎版本
+	RulesVersion        string                // 规则包版本
+	Employees           []EmployeeResult      // 各员工计算结果，顺序与输入一致
+	Inputs              []EmployeeInput       // 各员工已应用批次级设置的计算输入，顺序与计算结果一致，用于重算预览
+	Errors              []EmployeeError       // 未能计算的员工及原因，以及升级为错误的预警
+	Warnings            []Warning             // 不阻止关账的预警，升级为错误的类别不在此列出
+	PolicyMisses        []PolicyMiss          // 缺少城市政策的员工汇总
+	Announcements       []Announcement        // 本期工资条公告，随批次存档
+	ResidencyWarnings   []ResidencyWarning    // 境内居住天数接近183天的外籍员工
+	ConfigWarnings      []ConfigReviewWarning // 薪资配置费率与城市政策不一致的配置复核预警
+	WageFloorViolations []WageFloorViolation  // 工资低于集体合同岗位最低工资的员工，审批前需处理
+	Anonymized          bool                  // 员工明细是否已按保留策略匿名化
+	Retained            *RunTotals            // 员工明细按保留策略删除后保留的批次汇总
+}
+
+// EmployeeError 单个员工的计算错误，不影响批次内其他员工
+type EmployeeError struct {
+	EmployeeID string // 工号
+	Err        error  // 错误原因
+}
+
+// Error 实现 error 接口
+func (e EmployeeError) Error() string {
+	return e.EmployeeID + ": " + e.Err.Error()
+}
+
+// Unwrap 返回原始错误
+func (e EmployeeError) Unwrap() error {
+	return e.Err
+}
+
+// PayrollRun 发薪批次，包含某一薪资期内全部员工的计算输入
+type PayrollRun struct {
+	Period       time.Time         // 薪资期
+	Inputs       []EmployeeInput   // 员工计算输入
+	Receivables  *ReceivableLedger // 员工欠款台账，为空表示不跟踪代缴欠款
+	Audit        *AuditLog         // 审计日志，为空表示不记录
+	Corrections  *CorrectionLedger // 考勤更正台账，为空表示不计入往期更正差额
+	RulesVersion string            // 使用的规则包版本，为空表示内置规则
+	Configs      *ConfigStore      // 按生效日期管理的薪资配置，为空表示直接使用员工输入中的配置
+
+	Regions       map[string]RegionPolicy // 城市政策，按城市代码索引；为空表示直接使用员工薪资配置中的费率
+	PolicyMiss    PolicyMissStrategy      // 员工所在城市缺少政策时的处理方式
+	DefaultPolicy RegionPolicy            // PolicyMissUseDefault 时使用的默认政策
+	RateTolerance *decimal.Decimal        // 员工薪资配置费率与城市政策的允许偏差，超出时记入配置复核预警；为空表示不检查
+
+	Announcements []Announcement // 公司配置的工资条公告，适用于本期的公告随结果存档
+
+	PenaltyCapRate decimal.Decimal // 违纪扣款上限比例，员工输入未设置时使用，0表示不限
+
+	OvertimeByGrade map[string]OvertimeTreatment // 按职级的加班处理方式，员工输入未指定时使用
+
+	FourEyes *FourEyesPolicy // 四眼原则，为空表示不检查；未通过的员工不计算并记录错误
+
+	PensionPlans []PensionPlan  // 企业补充养老计划，员工输入未指定时使用
+	Pensions     *PensionLedger // 补充养老计划个人账户台账，为空表示不记录
+
+	Residency *ResidencyTracker // 外籍员工境内居住天数台账，为空表示不判定纳税人身份
+
+	WageFloors *CollectiveAgreement // 工会集体合同岗位工资标准，为空表示不检查
+
+	EscalateWarnings []WarningCode // 升级为错误的预警类别，对应员工记入 Errors，批次处理前不能关账
+
+	Proration ProrationMethod // 入职、离职当月基本工资的折算方式，员工输入未指定时使用，零值为不折算
+	Calendar  *Calendar       // 节假日日历，员工输入未指定时使用
+}
+
+// monthStart 返回日期所在月份的1日零点
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// CalculateEmployee 计算单个员工某一薪资期的工资明细
+// period: 薪资期
+// input: 员工计算输入
+// 返回值: 员工计算结果
+func CalculateEmployee(period time.Time, input EmployeeInput) EmployeeResult {
+	config := input.Config
+
+	// 合同开始前的月份按实习津贴计算
+	if isPreBoarding(period, input.Employee) && moneyToDec(input.Stipend).IsPositive() {
+		return calculateStipend(period, input)
+	}
+
+	// 本期未在岗或标准工时为0时，不计算收入，避免除零和无意义的结果
+	if input.Status == PeriodInactive || moneyToDec(config.FullMonthHours).IsZero() {
+		return calculateInactive(period, input)
+	}
+
+	// 注册了法定扣缴项目的辖区（如香港、新加坡）按各自的项目计算
+	if deductions := StatutoryDeductions(input.Employee.Jurisdiction); len(deductions) > 0 {
+		return calculateStatutory(period, input, deductions)
+	}
+
+	// 1. 计算基础工资和加班工资，不计发加班工资的岗位只记录加班小时
+	baseSalary, proration := proratedBaseSalary(period, input)
+	payable, unpaidOvertime, compTime := splitOvertime(input.OvertimeTreatment, input.Attendance)
+	overtimePay := CalculateOvertimePay(config, payable)
+
+	// 2. 计算社保和公积金
+	socialInsurance, housingFund, baseClamps := calculateSocialInsurance(config, baseSalary)
+
+	// 2.1 企业补充养老计划：个人缴费作为扣款项，单位缴费计入单位缴费合计
+	pension, pensionLines, pensionEmployer := pensionContributions(period, input, baseSalary)
+
+	// 3. 汇总本期生效的工资项目、班次津贴和一次性调整
+	lines := append(elementLines(input.Elements, period), shiftLines(config, input.Attendance)...)
+	lines = append(lines, adjustmentLines(input.Adjustments)...)
+	lines = append(lines, pensionLines...)
+	taxableEarnings, exemptEarnings, preTaxDeductions, postTaxDeductions := lineTotals(lines)
+
+	// 4. 税前工资 = 基础工资 + 加班工资 + 其他收入项
+	gross := moneyToDec(baseSalary).Add(moneyToDec(overtimePay)).Add(taxableEarnings).Add(exemptEarnings)
+
+	// 违纪扣款不超过当月工资的上限比例
+	lines, penaltyTruncated := capPenalties(lines, gross, input.PenaltyCapRate)
+	if penaltyTruncated.IsPositive() {
+		taxableEarnings, exemptEarnings, preTaxDeductions, postTaxDeductions = lineTotals(lines)
+	}
+
+	// 5. 应纳税所得额 = 税前工资 - 免税收入 - 社保 - 公积金 - 税前扣款 + 公积金超额部分 + 非现金福利
+	benefits := benefitTotal(lines)
+	housingFundExcess := HousingFundExcess(config, baseSalary, housingFund)
+	taxable := gross.Sub(exemptEarnings).
+		Sub(moneyToDec(socialInsurance)).
+		Sub(moneyToDec(housingFund)).
+		Sub(preTaxDeductions).
+		Add(moneyToDec(housingFundExcess)).
+		Add(benefits)
+
+	// 6. 计算个人所得税，提供了本年累计数据时按累计预扣法计算；非居民个人按月计税，不扣除专项附加扣除
+	deductions := input.deductionsFor(period)
+	taxMethod := TaxMethodMonthly
+	if input.TaxResidency == NonResident {
+		deductions, taxMethod = SpecialDeductions{}, TaxMethodNonResident
+	}
+	taxConfig := config.taxConfig()
+	employed := employedFraction(period, input.Employee)
+	standardDeduction := taxConfig.standardDeduction(employed)
+	incomeTax := taxConfig.IncomeTax(toMoney(taxable), deductions, employed)
+	var taxState *TaxWithholdingState
+	if input.TaxState != nil && taxMethod != TaxMethodNonResident {
+		month := TaxMonth{
+			Period:            period,
+			HireDate:          input.Employee.HireDate,
+			Income:            toMoney(gross.Sub(exemptEarnings).Sub(preTaxDeductions).Add(benefits)),
+			SocialInsurance:   toMoney(moneyToDec(socialInsurance).Add(moneyToDec(housingFund)).Sub(moneyToDec(housingFundExcess))),
+			SpecialDeductions: deductions.Total(),
+			Tax:               &taxConfig,
+		}
+		// 累计数据不连续时沿用单月计算，PayrollRun 在计算前已校验并记录错误
+		if tax, state, err := CalculateCumulativeTax(*input.TaxState, month); err == nil {
+			incomeTax, taxState, taxMethod = tax, &state, TaxMethodCumulative
+		}
+	}
+
+	// 7. 实发工资 = 税前工资 - 社保 - 公积金 - 个人所得税 - 全部扣款项
+	net := gross.Sub(moneyToDec(socialInsurance)).
+		Sub(moneyToDec(housingFund)).
+		Sub(moneyToDec(incomeTax)).
+		Sub(preTaxDeductions).
+		Sub(postTaxDeductions)
+
+	result := EmployeeResult{
+		Employee:              input.Employee,
+		Period:                monthStart(period),
+		BaseSalary:            baseSalary,
+		OvertimePay:           overtimePay,
+		GrossSalary:           toMoney(gross),
+		SocialInsurance:       socialInsurance,
+		HousingFund:           housingFund,
+		TaxableIncome:         toMoney(taxable),
+		SpecialDeductionTotal: deductions.Total(),
+		StandardDeduction:     standardDeduction,
+		IncomeTax:             incomeTax,
+		NetSalary:             toMoney(net),
+		Lines:                 lines,
+		PenaltyTruncated:      toMoney(penaltyTruncated),
+		Attendance:            input.Attendance,
+		StandardHours:         standardHours(config, input.Attendance),
+		UnpaidOvertimeHours:   unpaidOvertime,
+		CompTimeHours:         compTime,
+		HousingFundExcess:     housingFundExcess,
+		BenefitsInKind:        toMoney(benefits),
+		TaxState:              taxState,
+		TaxMethod:             taxMethod,
+		Pension:               pension,
+		EmployerContributions: toMoney(pensionEmployer),
+		BaseClamps:            baseClamps,
+		Proration:             proration,
+		PensionBase:           pensionBase(config, baseSalary),
+	}
+
+	// 8. 外派员工按税收均衡协议由公司承担实际个税，员工承担假设税；税后工资合同由公司承担全部个税
+	switch {
+	case input.Equalization != nil && input.Equalization.ActiveIn(period):
+		input.Equalization.apply(&result, taxConfig.brackets())
+	case input.EmployerBearsTax:
+		bearIncomeTax(&result, taxConfig.brackets(), "TAX-BORNE", "公司代付个税")
+	}
+	return result
+}
+
+// calculateInactive 生成未在岗员工的零收入结果
+// 选择继续缴纳时，社保公积金个人部分按合同工资基数计算并由公司代缴，不从实发工资中扣除
+func calculateInactive(period time.Time, input EmployeeInput) EmployeeResult {
+	zero := toMoney(decimal.Zero)
+	result := EmployeeResult{
+		Employee:        input.Employee,
+		Period:          monthStart(period),
+		Status:          PeriodInactive,
+		BaseSalary:      zero,
+		OvertimePay:     zero,
+		GrossSalary:     zero,
+		SocialInsurance: zero,
+		HousingFund:     zero,
+		TaxableIncome:   zero,
+		IncomeTax:       zero,
+		NetSalary:       zero,
+	}
+	if input.InactiveInsurance == InactiveInsuranceContinue {
+		result.SocialInsurance, result.HousingFund, result.BaseClamps = calculateSocialInsurance(input.Config, input.Config.BaseSalary)
+		result.PensionBase = pensionBase(input.Config, input.Config.BaseSalary)
+	}
+	// 新加坡员工未在岗月份不计缴公积金，本年度累计工资原样顺延
+	if input.Employee.Jurisdiction == JurisdictionSG {
+		ytd := input.CPFYearToDate.forYear(period.Year())
+		result.CPFYearToDate = &ytd
+	}
+	// 未在岗月份没有收入，累计数据仍需顺延，任职月数和专项附加扣除照常累计
+	if input.TaxResidency == NonResident {
+		result.TaxMethod = TaxMethodNonResident
+	} else if input.TaxState != nil {
+		tax := input.Config.taxConfig()
+		month := TaxMonth{Period: period, HireDate: input.Employee.HireDate, SpecialDeductions: input.deductionsFor(period).Total(), Tax: &tax}
+		if _, state, err := CalculateCumulativeTax(*input.TaxState, month); err == nil {
+			result.TaxState, result.TaxMethod = &state, TaxMethodCumulative
+		}
+	}
+	return result
+}
+
+// Calculate 依次计算批次内所有员工的工资
+func (r *PayrollRun) Calculate() PayrollResult {
+	result := r.newResult()
+	for _, input := range r.Inputs {
+		input, residency, err := r.prepare(input, &result)
+		if err != nil {
+			result.Errors = append(result.Errors, EmployeeError{EmployeeID: input.Employee.ID, Err: err})
+			continue
+		}
+		employee := CalculateEmployee(r.Period, input)
+		employee.TaxResidency = residency
+		r.finalize(input, &employee)
+		result.Employees = append(result.Employees, employee)
+		result.Inputs = append(result.Inputs, input)
+	}
+	result.WageFloorViolations = r.WageFloors.Violations(result.Employees)
+	r.collectWarnings(&result)
+	return result
+}
+
+// newResult 创建批次结果并填入版本和本期公告
+func (r *PayrollRun) newResult() PayrollResult {
+	result := PayrollResult{
+		Period:        monthStart(r.Period),
+		EngineVersion: EngineVersion,
+		RulesVersion:  r.RulesVersion,
+		Employees:     make([]EmployeeResult, 0, len(r.Inputs)),
+	}
+	if result.RulesVersion == "" {
+		result.RulesVersion = BuiltinRulesVersion
+	}
+	result.Announcements = announcementsFor(r.Announcements, result.Period)
+	return result
+}
+
+// prepare 将批次级设置应用到员工输入：城市政策、四眼原则、纳税人身份、待计入的考勤更正等
+// 缺少城市政策和居住天数预警记入批次结果，返回错误时该员工不参与计算
+func (r *PayrollRun) prepare(input EmployeeInput, result *PayrollResult) (EmployeeInput, TaxResidency, error) {
+	// 年中调动的员工按薪资期切换城市政策，本年累计预扣数据照常沿用
+	input.Employee.City, _ = relocatedCity(input.Employee.City, input.Relocations, r.Period)
+	// 按薪资期选用生效的配置版本，城市政策在此基础上覆盖费率
+	if r.Configs != nil {
+		config, err := r.Configs.ConfigFor(r.Period, input.Config)
+		if err != nil {
+			return input, ResidencyUntracked, err
+		}
+		input.Config = config
+	}
+	config, miss, err := r.resolveRegion(input.Employee)
+	if miss != nil {
+		result.PolicyMisses = append(result.PolicyMisses, *miss)
+	}
+	if err != nil {
+		return input, ResidencyUntracked, err
+	}
+	if config != nil {
+		if r.RateTolerance != nil {
+			deviations := CompareRates(input.Config, *config, *r.RateTolerance)
+			result.ConfigWarnings = addConfigWarning(result.ConfigWarnings, input.Employee.ID, input.Employee.City, deviations)
+		}
+		input.Config = config.Apply(input.Config)
+	}
+
+	// 登记了出入境记录的员工按截至薪资期末的境内居住天数判定纳税人身份，
+	// 非居民个人按月计税，不适用累计预扣法和专项附加扣除
+	residency := ResidencyUntracked
+	if r.Residency != nil {
+		var warning *ResidencyWarning
+		residency, warning = r.Residency.Status(input.Employee.ID, monthStart(r.Period).AddDate(0, 1, -1))
+		if warning != nil {
+			result.ResidencyWarnings = append(result.ResidencyWarnings, *warning)
+		}
+		input.TaxResidency = residency
+		if residency == NonResident {
+			input.TaxState = nil
+			input.Deductions, input.Declarations = SpecialDeductions{}, nil
+		}
+	}
+	if input.TaxState != nil {
+		month := TaxMonth{Period: r.Period, HireDate: input.Employee.HireDate}
+		if _, err := input.TaxState.start(month); err != nil {
+			return input, residency, err
+		}
+	}
+
+	input.OvertimeTreatment = r.overtimeTreatment(input)
+	if len(input.PensionPlans) == 0 {
+		input.PensionPlans = r.PensionPlans
+	}
+	if input.PenaltyCapRate.IsZero() {
+		input.PenaltyCapRate = r.PenaltyCapRate
+	}
+	if input.Proration == ProrateInherit {
+		input.Proration = r.Proration
+	}
+	if input.Calendar == nil {
+		input.Calendar = r.Calendar
+	}
+	if r.Corrections != nil && input.Status == PeriodActive {
+		pending := r.Corrections.take(input.Employee.ID, r.Period)
+		input.Adjustments = append(append([]Adjustment(nil), input.Adjustments...), pending...)
+	}
+	// 四眼原则在计入往期更正差额之后检查，更正差额与一次性调整适用同一阈值；未通过时退回待计入的更正
+	if r.FourEyes != nil {
+		if err := r.FourEyes.checkAdjustments(r, input); err != nil {
+			if r.Corrections != nil {
+				r.Corrections.release(input.Employee.ID, r.Period)
+			}
+			return input, residency, err
+		}
+	}
+	return input, residency, nil
+}
+
+// finalize 标记本期生效的跨城市调动，记录单位缴费和全口径人工成本，并记录员工计算结果对审计日志、欠款台账和年金台账的影响
+func (r *PayrollRun) finalize(input EmployeeInput, employee *EmployeeResult) {
+	_, employee.Relocation = relocatedCity(input.Employee.City, input.Relocations, r.Period)
+	cost := CalculateEmployerCost(input.Config, *employee)
+	employee.EmployerCost = &cost
+	if r.Audit != nil && employee.Status == PeriodActive {
+		recordAdjustments(r.Audit, r.Period, input)
+	}
+	if r.Receivables != nil {
+		r.Receivables.applyReceivable(employee)
+	}
+	if r.Pensions != nil {
+		r.Pensions.record(*employee)
+	}
+}
@@ -0,0 +1,53 @@
+package salary
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestCalculateEmployeeInactive(t *testing.T) {
+	input := EmployeeInput{Employee: Employee{ID: "E1"}, Config: testConfig(), Status: PeriodInactive}
+
+	// 默认停缴：零收入、零扣缴
+	result := CalculateEmployee(day("2024-03-01"), input)
+	if result.Status != PeriodInactive {
+		t.Fatalf("status = %v, want PeriodInactive", result.Status)
+	}
+	assertMoney(t, "gross", result.GrossSalary, "0")
+	assertMoney(t, "social insurance", result.SocialInsurance, "0")
+	assertMoney(t, "net", result.NetSalary, "0")
+
+	// 继续缴纳：按合同工资计算个人部分，不产生收入
+	input.InactiveInsurance = InactiveInsuranceContinue
+	result = CalculateEmployee(day("2024-03-01"), input)
+	assertMoney(t, "continued social insurance", result.SocialInsurance, "84000")
+	assertMoney(t, "continued housing fund", result.HousingFund, "56000")
+	assertMoney(t, "continued net", result.NetSalary, "0")
+}
+
+func TestCalculateEmployeeZeroStandardHours(t *testing.T) {
+	config := testConfig()
+	config.FullMonthHours = toMoney(cenToDec(0))
+	result := CalculateEmployee(day("2024-03-01"), EmployeeInput{
+		Employee: Employee{ID: "E1"}, Config: config, Attendance: AttendanceRecord{WorkHours: hours("174")},
+	})
+	// 标准工时为0时按未在岗处理，不做除法
+	if result.Status != PeriodInactive {
+		t.Errorf("status = %v, want PeriodInactive", result.Status)
+	}
+	assertMoney(t, "gross", result.GrossSalary, "0")
+}
+
+func TestCalculateEmployeeHousingFundExcessTaxable(t *testing.T) {
+	config := testConfig()
+	config.HousingFundRate = decimal.RequireFromString("0.15")
+	result := CalculateEmployee(day("2024-03-01"), EmployeeInput{
+		Employee: Employee{ID: "E1"}, Config: config, Attendance: AttendanceRecord{WorkHours: hours("174")},
+	})
+	// 8000 × 15% = 1200，超过12%免税限额 960 的 240 并入应纳税所得额
+	assertMoney(t, "housing fund", result.HousingFund, "120000")
+	assertMoney(t, "excess", result.HousingFundExcess, "24000")
+	want := moneyToDec(result.GrossSalary).Sub(moneyToDec(result.SocialInsurance)).Sub(moneyToDec(result.HousingFund)).Add(cenToDec(24000))
+	assertMoney(t, "taxable income", result.TaxableIncome, want.String())
+}
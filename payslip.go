@@ -0,0 +1,109 @@
+package salary
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// Announcement 工资条公告或免责声明，如政策通知、奖金说明
+type Announcement struct {
+	Title       string    `json:"title"`       // 标题
+	Body        string    `json:"body"`        // 正文
+	Period      time.Time `json:"period"`      // 适用的薪资期，零值表示每期都显示
+	Departments []string  `json:"departments"` // 适用的部门，为空表示全部部门
+}
+
+// appliesTo 判断公告是否适用于某员工的某一薪资期
+func (a Announcement) appliesTo(period time.Time, e Employee) bool {
+	if !a.Period.IsZero() && !sameMonth(a.Period, period) {
+		return false
+	}
+	if len(a.Departments) == 0 {
+		return true
+	}
+	for _, d := range a.Departments {
+		if d == e.Department {
+			return true
+		}
+	}
+	return false
+}
+
+// announcementsFor 筛选适用于薪资期的公告（不区分部门），随批次结果一并保存
+func announcementsFor(announcements []Announcement, period time.Time) []Announcement {
+	var result []Announcement
+	for _, a := range announcements {
+		if a.Period.IsZero() || sameMonth(a.Period, period) {
+			result = append(result, a)
+		}
+	}
+	return result
+}
+
+// PayslipOptions 工资条渲染选项
+type PayslipOptions struct {
+	Company       string          // 公司名称，显示在工资条抬头
+	Announcements []Announcement  // 公告和免责声明，按薪资期和部门筛选后显示
+	Template      PayslipTemplate // PDF工资条模板，零值为默认样式
+}
+
+// announcementsFor 返回适用于该员工结果的公告
+func (o PayslipOptions) announcementsFor(result EmployeeResult) []Announcement {
+	var list []Announcement
+	for _, a := range o.Announcements {
+		if a.appliesTo(result.Period, result.Employee) {
+			list = append(list, a)
+		}
+	}
+	return list
+}
+
+// RenderPayslipText 输出文本格式的工资条
+// w: 输出目标
+// result: 员工计算结果
+// opts: 渲染选项
+func RenderPayslipText(w io.Writer, result EmployeeResult, opts PayslipOptions) error {
+	row := func(name string, amount Money) {
+		fmt.Fprintf(w, "%-15s %15s\n", name, FormatMoneyCenToYuan(amount))
+	}
+
+	fmt.Fprintf(w, "\n================ %s工资条 ================\n", opts.Company)
+	fmt.Fprintf(w, "%s  %s  薪资期：%s\n", result.Employee.ID, result.Employee.Name, result.Period.Format("2006-01"))
+	fmt.Fprintln(w, "----------------------------------------")
+	row("基础工资", result.BaseSalary)
+	row("加班工资", result.OvertimePay)
+	for _, line := range result.Lines {
+		if line.Kind == KindEarning {
+			row(line.Name, line.Amount)
+		}
+	}
+	row("税前工资", result.GrossSalary)
+	// 非现金福利只计税、不发放现金，单独列示
+	for _, line := range result.Lines {
+		if line.Kind == KindBenefitInKind {
+			row(line.Name+"（非现金，仅计税）", line.Amount)
+		}
+	}
+	row("社会保险", result.SocialInsurance)
+	row("住房公积金", result.HousingFund)
+	row("个人所得税", result.IncomeTax)
+	for _, line := range result.Lines {
+		if line.Kind == KindDeduction {
+			row(line.Name, line.Amount)
+		}
+	}
+	fmt.Fprintln(w, "----------------------------------------")
+	row("实发工资", result.NetSalary)
+
+	if result.Relocation != nil {
+		fmt.Fprintf(w, "\n【缴纳城市变更】\n%s\n", result.Relocation.Note())
+	}
+
+	// 公告和免责声明
+	for _, a := range opts.announcementsFor(result) {
+		fmt.Fprintf(w, "\n【%s】\n%s\n", a.Title, a.Body)
+	}
+	_, err := fmt.Fprintln(w, "========================================")
+	return err
+}
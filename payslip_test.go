@@ -0,0 +1,46 @@
+package salary
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPayslipAnnouncements(t *testing.T) {
+	announcements := []Announcement{
+		{Title: "年终奖说明", Body: "年终奖于3月随工资发放", Period: day("2024-03-01")},
+		{Title: "免责声明", Body: "本工资条仅供个人查阅"},
+		{Title: "销售提成", Body: "提成按季度结算", Departments: []string{"销售部"}},
+		{Title: "调薪通知", Body: "4月起调整薪资", Period: day("2024-04-01")},
+	}
+
+	// 批次存档不区分部门，只按薪资期筛选
+	run := PayrollRun{Period: day("2024-03-01"), Announcements: announcements}
+	archived := run.Calculate().Announcements
+	if len(archived) != 3 || archived[0].Title != "年终奖说明" || archived[2].Title != "销售提成" {
+		t.Fatalf("archived = %+v", archived)
+	}
+
+	result := EmployeeResult{Employee: Employee{ID: "E1", Name: "张三", Department: "研发部"}, Period: day("2024-03-01")}
+	var b strings.Builder
+	if err := RenderPayslipText(&b, result, PayslipOptions{Company: "示例公司", Announcements: announcements}); err != nil {
+		t.Fatal(err)
+	}
+	text := b.String()
+	for _, want := range []string{"示例公司工资条", "【年终奖说明】", "【免责声明】\n本工资条仅供个人查阅"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("payslip missing %q:\n%s", want, text)
+		}
+	}
+	for _, unwanted := range []string{"销售提成", "调薪通知"} {
+		if strings.Contains(text, unwanted) {
+			t.Errorf("payslip should not contain %q", unwanted)
+		}
+	}
+
+	result.Employee.Department = "销售部"
+	b.Reset()
+	RenderPayslipText(&b, result, PayslipOptions{Announcements: announcements})
+	if !strings.Contains(b.String(), "【销售提成】") {
+		t.Errorf("sales payslip missing department announcement:\n%s", b.String())
+	}
+}
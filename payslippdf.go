@@ -0,0 +1,152 @@
+package salary
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// 工资条内置项目的代码，可在 PayslipTemplate.Labels 中覆盖显示名称
+const (
+	PayslipItemBase     = "BASE"     // 基础工资
+	PayslipItemOvertime = "OVERTIME" // 加班工资
+	PayslipItemGross    = "GROSS"    // 税前工资
+	PayslipItemSocial   = "SI"       // 社会保险
+	PayslipItemHousing  = "HF"       // 住房公积金
+	PayslipItemTax      = "TAX"      // 个人所得税
+	PayslipItemNet      = "NET"      // 实发工资
+)
+
+// PayslipTemplate PDF工资条模板
+type PayslipTemplate struct {
+	Title    string            // 标题，%s 替换为公司名称，为空时为“%s工资条”
+	Header   []string          // 标题下方的附加行，如公司地址、联系电话
+	Labels   map[string]string // 项目显示名称，键为内置项目代码或工资条明细的项目代码
+	HideZero bool              // 不显示金额为0的明细项目，税前工资和实发工资始终显示
+	Footer   []string          // 页脚，如“如对工资有疑问请于三日内联系人力资源部”
+	FontSize float64           // 正文字号（磅），0时为11
+}
+
+// payslipItem 工资条上的一项
+type payslipItem struct {
+	Code     string // 项目代码
+	Name     string // 默认显示名称
+	Amount   Money  // 金额（分）
+	Subtotal bool   // 是否为小计，小计前加分隔线
+}
+
+// payslipItems 按工资条顺序列出员工结果的全部项目：收入、税前工资、非现金福利、扣款、实发工资
+func payslipItems(result EmployeeResult) []payslipItem {
+	items := []payslipItem{
+		{Code: PayslipItemBase, Name: "基础工资", Amount: result.BaseSalary},
+		{Code: PayslipItemOvertime, Name: "加班工资", Amount: result.OvertimePay},
+	}
+	for _, line := range result.Lines {
+		if line.Kind == KindEarning {
+			items = append(items, payslipItem{Code: line.Code, Name: line.Name, Amount: line.Amount})
+		}
+	}
+	items = append(items, payslipItem{Code: PayslipItemGross, Name: "税前工资", Amount: result.GrossSalary})
+	// 非现金福利只计税、不发放现金，单独列示
+	for _, line := range result.Lines {
+		if line.Kind == KindBenefitInKind {
+			items = append(items, payslipItem{Code: line.Code, Name: line.Name + "（非现金，仅计税）", Amount: line.Amount})
+		}
+	}
+	items = append(items,
+		payslipItem{Code: PayslipItemSocial, Name: "社会保险", Amount: result.SocialInsurance},
+		payslipItem{Code: PayslipItemHousing, Name: "住房公积金", Amount: result.HousingFund},
+		payslipItem{Code: PayslipItemTax, Name: "个人所得税", Amount: result.IncomeTax})
+	for _, line := range result.Lines {
+		if line.Kind == KindDeduction {
+			items = append(items, payslipItem{Code: line.Code, Name: line.Name, Amount: line.Amount})
+		}
+	}
+	return append(items, payslipItem{Code: PayslipItemNet, Name: "实发工资", Amount: result.NetSalary, Subtotal: true})
+}
+
+// label 返回项目在模板中的显示名称
+func (t PayslipTemplate) label(item payslipItem) string {
+	if name, ok := t.Labels[item.Code]; ok && name != "" {
+		return name
+	}
+	return item.Name
+}
+
+// RenderPayslipPDF 生成员工的PDF工资条，包含公司抬头、薪资期、收入和扣款明细及实发工资，样式由 opts.Template 定制
+// 可直接作为邮件附件发送或打印
+// result: 员工计算结果
+// opts: 渲染选项
+func RenderPayslipPDF(result EmployeeResult, opts PayslipOptions) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writePDF(&buf, []pdfPage{payslipPage(result, opts)}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// payslipPage 按模板生成PDF工资条的页面
+func payslipPage(result EmployeeResult, opts PayslipOptions) pdfPage {
+	t := opts.Template
+	size := t.FontSize
+	if size <= 0 {
+		size = 11
+	}
+	left, right, lineHeight := 60.0, pdfPageWidth-60, size*1.8
+	var page pdfPage
+	y := pdfPageHeight - 70
+	text := func(s string, size float64) {
+		page.Texts = append(page.Texts, pdfText{X: left, Y: y, Size: size, Text: s})
+		y -= lineHeight
+	}
+	rule := func() {
+		page.Lines = append(page.Lines, pdfLine{X1: left, Y1: y + lineHeight/2, X2: right, Y2: y + lineHeight/2})
+		y -= lineHeight / 2
+	}
+
+	title := t.Title
+	if title == "" {
+		title = "%s工资条"
+	}
+	if strings.Contains(title, "%s") {
+		title = fmt.Sprintf(title, opts.Company)
+	}
+	text(title, size+5)
+	for _, line := range t.Header {
+		text(line, size-1)
+	}
+	text(fmt.Sprintf("%s  %s  薪资期：%s", result.Employee.ID, result.Employee.Name, result.Period.Format("2006-01")), size)
+	rule()
+	for _, item := range payslipItems(result) {
+		if t.HideZero && moneyToDec(item.Amount).IsZero() && item.Code != PayslipItemGross && item.Code != PayslipItemNet {
+			continue
+		}
+		if item.Subtotal {
+			rule()
+		}
+		page.Texts = append(page.Texts,
+			pdfText{X: left, Y: y, Size: size, Text: t.label(item)},
+			pdfText{X: right, Y: y, Size: size, Text: FormatMoneyCenToYuan(item.Amount), Right: true})
+		y -= lineHeight
+	}
+
+	if result.Relocation != nil {
+		y -= lineHeight / 2
+		text("【缴纳城市变更】", size)
+		text(result.Relocation.Note(), size-1)
+	}
+	for _, a := range opts.announcementsFor(result) {
+		y -= lineHeight / 2
+		text("【"+a.Title+"】", size)
+		for _, para := range strings.Split(a.Body, "\n") {
+			text(para, size-1)
+		}
+	}
+	if len(t.Footer) > 0 {
+		y -= lineHeight / 2
+		for _, line := range t.Footer {
+			text(line, size-2)
+		}
+	}
+	return page
+}
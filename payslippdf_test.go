@@ -0,0 +1,40 @@
+package salary
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRenderPayslipPDF(t *testing.T) {
+	result := CalculateEmployee(day("2024-03-01"), EmployeeInput{
+		Employee:   Employee{ID: "E1", Name: "张三"},
+		Config:     testConfig(),
+		Attendance: AttendanceRecord{WorkHours: hours("174")},
+	})
+	opts := PayslipOptions{
+		Company: "示例公司",
+		Template: PayslipTemplate{
+			Title:    "%s 薪资单",
+			Labels:   map[string]string{PayslipItemNet: "本月实发"},
+			HideZero: true,
+			Footer:   []string{"如有疑问请联系人力资源部"},
+		},
+	}
+	data, err := RenderPayslipPDF(result, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, []byte("%PDF-")) {
+		t.Fatal("output is not a PDF document")
+	}
+	for _, want := range []string{"示例公司 薪资单", "本月实发", "如有疑问请联系人力资源部", FormatMoneyCenToYuan(result.NetSalary)} {
+		if !bytes.Contains(data, []byte(pdfHexText(want))) {
+			t.Errorf("PDF lacks %q", want)
+		}
+	}
+	// 加班工资为0，HideZero 时不显示
+	if bytes.Contains(data, []byte(strings.Trim(pdfHexText("加班工资"), "<>"))) {
+		t.Error("zero overtime line should be hidden")
+	}
+}
@@ -0,0 +1,167 @@
+package salary
+
+import (
+	_ "embed"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+)
+
+// PayslipSchema 随程序发布的标准工资条 JSON Schema，见 schemas/payslip.v1.json
+//
+//go:embed schemas/payslip.v1.json
+var PayslipSchema []byte
+
+// PayslipSchemaVersion 标准工资条格式版本
+const PayslipSchemaVersion = "1.0"
+
+// 标准工资条的明细类别
+const (
+	PayslipCategoryEarning         = "earning"          // 收入
+	PayslipCategoryBenefitInKind   = "benefit_in_kind"  // 非现金福利，仅计税
+	PayslipCategorySocialInsurance = "social_insurance" // 社保个人部分
+	PayslipCategoryHousingFund     = "housing_fund"     // 公积金个人部分
+	PayslipCategoryIncomeTax       = "income_tax"       // 个人所得税
+	PayslipCategoryDeduction       = "deduction"        // 其他扣款
+)
+
+// StandardPayslipEmployee 标准工资条中的员工信息
+type StandardPayslipEmployee struct {
+	ID         string `json:"id"`                   // 工号
+	Name       string `json:"name"`                 // 姓名
+	Department string `json:"department,omitempty"` // 部门
+	HireDate   string `json:"hire_date,omitempty"`  // 入职日期，YYYY-MM-DD
+}
+
+// StandardPayslipPeriod 标准工资条的薪资期
+type StandardPayslipPeriod struct {
+	Start string `json:"start"` // 薪资期首日，YYYY-MM-DD
+	End   string `json:"end"`   // 薪资期末日，YYYY-MM-DD
+}
+
+// StandardPayslipLine 标准工资条的一行明细，金额为币种最小单位的整数
+type StandardPayslipLine struct {
+	Code     string `json:"code"`              // 项目代码
+	Name     string `json:"name"`              // 项目名称
+	Category string `json:"category"`          // 明细类别
+	Amount   int64  `json:"amount"`            // 金额，扣款类同样为正数
+	Taxable  *bool  `json:"taxable,omitempty"` // 收入项是否计税、扣款项是否税前扣除，法定项目省略
+}
+
+// StandardPayslip 符合 schemas/payslip.v1.json 的工资条，供第三方福利、信贷等应用直接读取
+type StandardPayslip struct {
+	SchemaVersion         string                  `json:"schema_version"`         // 格式版本
+	Employer              string                  `json:"employer,omitempty"`     // 发薪单位名称
+	Employee              StandardPayslipEmployee `json:"employee"`               // 员工信息
+	Period                StandardPayslipPeriod   `json:"period"`                 // 薪资期
+	Currency              string                  `json:"currency"`               // ISO 4217 币种代码
+	GrossPay              int64                   `json:"gross_pay"`              // 税前工资
+	NetPay                int64                   `json:"net_pay"`                // 实发工资
+	TaxableIncome         int64                   `json:"taxable_income"`         // 应纳税所得额
+	EmployerContributions int64                   `json:"employer_contributions"` // 单位缴费合计
+	Lines                 []StandardPayslipLine   `json:"lines"`                  // 明细
+}
+
+// jurisdictionCurrency 各辖区的发薪币种
+var jurisdictionCurrency = map[Jurisdiction]string{
+	JurisdictionCN: "CNY",
+	JurisdictionHK: "HKD",
+	JurisdictionSG: "SGD",
+}
+
+// minorUnits 将金额（分）四舍五入为整数
+func minorUnits(m Money) int64 {
+	return moneyToDec(m).Round(0).IntPart()
+}
+
+// BuildStandardPayslip 将员工计算结果转换为标准工资条
+// 金额为零的法定项目省略；辖区法定扣缴项目（如香港强积金）归入社保类别
+// result: 员工计算结果
+// employer: 发薪单位名称
+func BuildStandardPayslip(result EmployeeResult, employer string) StandardPayslip {
+	currency, ok := jurisdictionCurrency[result.Employee.Jurisdiction]
+	if !ok {
+		currency = "CNY"
+	}
+	slip := StandardPayslip{
+		SchemaVersion: PayslipSchemaVersion,
+		Employer:      employer,
+		Employee: StandardPayslipEmployee{
+			ID:         result.Employee.ID,
+			Name:       result.Employee.Name,
+			Department: result.Employee.Department,
+		},
+		Period: StandardPayslipPeriod{
+			Start: monthStart(result.Period).Format("2006-01-02"),
+			End:   monthStart(result.Period).AddDate(0, 1, -1).Format("2006-01-02"),
+		},
+		Currency:              currency,
+		GrossPay:              minorUnits(result.GrossSalary),
+		NetPay:                minorUnits(result.NetSalary),
+		TaxableIncome:         minorUnits(result.TaxableIncome),
+		EmployerContributions: minorUnits(result.EmployerContributions),
+		Lines:                 []StandardPayslipLine{},
+	}
+	if !result.Employee.HireDate.IsZero() {
+		slip.Employee.HireDate = result.Employee.HireDate.Format("2006-01-02")
+	}
+
+	statutory := func(code, name, category string, amount Money) {
+		if moneyToDec(amount).IsZero() {
+			return
+		}
+		slip.Lines = append(slip.Lines, StandardPayslipLine{Code: code, Name: name, Category: category, Amount: minorUnits(amount)})
+	}
+	statutory("BASE", "基础工资", PayslipCategoryEarning, result.BaseSalary)
+	statutory("OVERTIME", "加班工资", PayslipCategoryEarning, result.OvertimePay)
+	for _, line := range result.Lines {
+		taxable := line.Taxable
+		category := PayslipCategoryEarning
+		switch line.Kind {
+		case KindDeduction:
+			category = PayslipCategoryDeduction
+		case KindBenefitInKind:
+			category = PayslipCategoryBenefitInKind
+		}
+		slip.Lines = append(slip.Lines, StandardPayslipLine{Code: line.Code, Name: line.Name, Category: category, Amount: minorUnits(line.Amount), Taxable: &taxable})
+	}
+	if len(result.Statutory) > 0 {
+		for _, s := range result.Statutory {
+			statutory(s.Code, s.Label, PayslipCategorySocialInsurance, s.Employee)
+		}
+	} else {
+		statutory("SOCIAL", "社会保险", PayslipCategorySocialInsurance, result.SocialInsurance)
+	}
+	statutory("HOUSING", "住房公积金", PayslipCategoryHousingFund, result.HousingFund)
+	statutory("TAX", "个人所得税", PayslipCategoryIncomeTax, result.IncomeTax)
+	return slip
+}
+
+// WriteStandardPayslip 输出标准工资条 JSON
+func WriteStandardPayslip(w io.Writer, result EmployeeResult, employer string) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(BuildStandardPayslip(result, employer))
+}
+
+// handleStandardPayslip 查询标准格式的工资条：GET /employees/{id}/payslips/{period}/standard
+func (s *Server) handleStandardPayslip(w http.ResponseWriter, r *http.Request) {
+	period, err := time.Parse("2006-01", r.PathValue("period"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	current, _, _, ok := s.store.payslipContext(r.PathValue("id"), period)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]any{"code": CodeRunNotFound, "error": "未找到该员工本期工资结果"})
+		return
+	}
+	writeJSON(w, http.StatusOK, BuildStandardPayslip(current, r.URL.Query().Get("employer")))
+}
+
+// handlePayslipSchema 返回标准工资条 JSON Schema：GET /schemas/payslip.v1.json
+func (s *Server) handlePayslipSchema(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/schema+json")
+	w.Write(PayslipSchema)
+}
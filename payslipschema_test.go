@@ -0,0 +1,65 @@
+package salary
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestBuildStandardPayslipMatchesSchema(t *testing.T) {
+	input := EmployeeInput{
+		Employee:   Employee{ID: "E001", Name: "张三", HireDate: day("2023-03-15")},
+		Config:     testConfig(),
+		Attendance: AttendanceRecord{WorkHours: hours("174")},
+		Adjustments: []Adjustment{
+			{ID: "ADJ-1", Kind: KindEarning, Amount: toMoney(cenToDec(30000)), ReasonCode: ReasonBackPay},
+		},
+	}
+	result := CalculateEmployee(day("2024-02-01"), input)
+	slip := BuildStandardPayslip(result, "示例公司")
+
+	if slip.Period.End != "2024-02-29" || slip.Currency != "CNY" {
+		t.Errorf("period end %s currency %s", slip.Period.End, slip.Currency)
+	}
+	if slip.NetPay != minorUnits(result.NetSalary) {
+		t.Errorf("net pay = %d, want %d", slip.NetPay, minorUnits(result.NetSalary))
+	}
+
+	var schema struct {
+		Required   []string `json:"required"`
+		Properties struct {
+			Lines struct {
+				Items struct {
+					Properties struct {
+						Category struct {
+							Enum []string `json:"enum"`
+						} `json:"category"`
+					} `json:"properties"`
+				} `json:"items"`
+			} `json:"lines"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(PayslipSchema, &schema); err != nil {
+		t.Fatalf("schema: %v", err)
+	}
+	data, _ := json.Marshal(slip)
+	var doc map[string]any
+	json.Unmarshal(data, &doc)
+	for _, field := range schema.Required {
+		if _, ok := doc[field]; !ok {
+			t.Errorf("required field %s missing", field)
+		}
+	}
+	categories := map[string]bool{}
+	for _, line := range slip.Lines {
+		if !slices.Contains(schema.Properties.Lines.Items.Properties.Category.Enum, line.Category) {
+			t.Errorf("line %s category %q not in schema", line.Code, line.Category)
+		}
+		categories[line.Category] = true
+	}
+	for _, want := range []string{PayslipCategoryEarning, PayslipCategorySocialInsurance, PayslipCategoryHousingFund, PayslipCategoryIncomeTax} {
+		if !categories[want] {
+			t.Errorf("no %s line", want)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package salary
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A4纸张尺寸（磅）
+const (
+	pdfPageWidth  = 595.0
+	pdfPageHeight = 842.0
+)
+
+// pdfText PDF页面上的一段文字，坐标原点在页面左下角
+type pdfText struct {
+	X, Y  float64 // 起点坐标（磅）
+	Size  float64 // 字号（磅）
+	Text  string  // 文字内容
+	Right bool    // 为真时 X 为右对齐的终点坐标
+}
+
+// pdfLine PDF页面上的一条直线
+type pdfLine struct {
+	X1, Y1, X2, Y2 float64
+}
+
+// pdfPage PDF的一页
+type pdfPage struct {
+	Texts []pdfText
+	Lines []pdfLine
+}
+
+// pdfTextWidth 估算文字宽度：ASCII字符按半角、其他字符按全角计算
+func pdfTextWidth(s string, size float64) float64 {
+	width := 0.0
+	for _, r := range s {
+		if r < 0x80 {
+			width += size / 2
+		} else {
+			width += size
+		}
+	}
+	return width
+}
+
+// pdfHexText 将文字编码为 UniGB-UCS2-H 所需的UCS-2大端十六进制串，超出基本平面的字符以问号代替
+func pdfHexText(s string) string {
+	var b strings.Builder
+	b.WriteByte('<')
+	for _, r := range s {
+		if r > 0xFFFF {
+			r = '?'
+		}
+		fmt.Fprintf(&b, "%04X", r)
+	}
+	b.WriteByte('>')
+	return b.String()
+}
+
+// content 生成页面内容流
+func (p pdfPage) content() []byte {
+	var b bytes.Buffer
+	b.WriteString("0.5 w\n")
+	for _, l := range p.Lines {
+		fmt.Fprintf(&b, "%.2f %.2f m %.2f %.2f l S\n", l.X1, l.Y1, l.X2, l.Y2)
+	}
+	for _, t := range p.Texts {
+		x := t.X
+		if t.Right {
+			x -= pdfTextWidth(t.Text, t.Size)
+		}
+		fmt.Fprintf(&b, "BT /F1 %.1f Tf %.2f %.2f Td %s Tj ET\n", t.Size, x, t.Y, pdfHexText(t.Text))
+	}
+	return b.Bytes()
+}
+
+// writePDF 输出PDF文档
+// 中文使用阅读器内置的 STSong-Light 字体，不嵌入字体文件，文档体积小
+func writePDF(w io.Writer, pages []pdfPage) error {
+	var b bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, b.Len())
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	b.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	// 1 目录，2 页面树，3-5 字体，之后每页依次为页面对象和内容流
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 6+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type0 /BaseFont /STSong-Light /Encoding /UniGB-UCS2-H /DescendantFonts [4 0 R] >>")
+	object("<< /Type /Font /Subtype /CIDFontType0 /BaseFont /STSong-Light " +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (GB1) /Supplement 2 >> /FontDescriptor 5 0 R /DW 1000 /W [1 95 500] >>")
+	object("<< /Type /FontDescriptor /FontName /STSong-Light /Flags 6 /FontBBox [-25 -254 1000 880] " +
+		"/ItalicAngle 0 /Ascent 880 /Descent -120 /CapHeight 880 /StemV 93 >>")
+	for i, page := range pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, 7+2*i))
+		content := page.content()
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+	}
+
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	_, err := w.Write(b.Bytes())
+	return err
+}
@@ -0,0 +1,84 @@
+package salary
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// DefaultPenaltyCapRate 违纪扣款上限比例：《工资支付暂行规定》要求每月扣除部分不得超过当月工资的20%
+var DefaultPenaltyCapRate = decimal.RequireFromString("0.2")
+
+// PenaltyTruncation 违纪扣款超过上限被截断的记录
+type PenaltyTruncation struct {
+	EmployeeID string    // 工号
+	Period     time.Time // 薪资期
+	Requested  Money     // 申请扣除的违纪扣款合计（分）
+	Applied    Money     // 实际扣除金额（分）
+	Truncated  Money     // 被截断的金额（分）
+}
+
+// isPenalty 判断明细是否为违纪扣款
+func isPenalty(line PayLine) bool {
+	return line.Kind == KindDeduction && line.Code == "ADJ-"+string(ReasonPenalty)
+}
+
+// capPenalties 按当月工资的比例限制违纪扣款，超出部分从后往前依次截断
+// gross: 当月工资
+// rate: 上限比例，不大于0表示不限
+// 返回值: (截断后的明细, 被截断的金额)
+func capPenalties(lines []PayLine, gross decimal.Decimal, rate decimal.Decimal) ([]PayLine, decimal.Decimal) {
+	if !rate.IsPositive() {
+		return lines, decimal.Zero
+	}
+	limit := decimal.Max(gross.Mul(rate).Round(2), decimal.Zero)
+
+	total := decimal.Zero
+	for _, line := range lines {
+		if isPenalty(line) {
+			total = total.Add(moneyToDec(line.Amount))
+		}
+	}
+	excess := total.Sub(limit)
+	if !excess.IsPositive() {
+		return lines, decimal.Zero
+	}
+
+	capped := append([]PayLine(nil), lines...)
+	remaining := excess
+	for i := len(capped) - 1; i >= 0 && remaining.IsPositive(); i-- {
+		if !isPenalty(capped[i]) {
+			continue
+		}
+		amount := moneyToDec(capped[i].Amount)
+		cut := decimal.Min(amount, remaining)
+		capped[i].Amount = toMoney(amount.Sub(cut))
+		remaining = remaining.Sub(cut)
+	}
+	return capped, excess
+}
+
+// PenaltyTruncations 汇总批次内违纪扣款被截断的员工，供核对和告知员工
+func (r PayrollResult) PenaltyTruncations() []PenaltyTruncation {
+	var rows []PenaltyTruncation
+	for _, e := range r.Employees {
+		truncated := moneyToDec(e.PenaltyTruncated)
+		if !truncated.IsPositive() {
+			continue
+		}
+		applied := decimal.Zero
+		for _, line := range e.Lines {
+			if isPenalty(line) {
+				applied = applied.Add(moneyToDec(line.Amount))
+			}
+		}
+		rows = append(rows, PenaltyTruncation{
+			EmployeeID: e.Employee.ID,
+			Period:     e.Period,
+			Requested:  toMoney(applied.Add(truncated)),
+			Applied:    toMoney(applied),
+			Truncated:  e.PenaltyTruncated,
+		})
+	}
+	return rows
+}
@@ -0,0 +1,57 @@
+package salary
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func penaltyAdjustment(id string, cents int64) Adjustment {
+	return Adjustment{ID: id, Kind: KindDeduction, Amount: toMoney(cenToDec(cents)), ReasonCode: ReasonPenalty, Reason: "违纪", Approver: "hr1"}
+}
+
+func TestCapPenalties(t *testing.T) {
+	lines := []PayLine{
+		{Code: "ADJ-PENALTY", Kind: KindDeduction, Amount: toMoney(cenToDec(100000))},
+		{Code: "MEAL", Kind: KindDeduction, Amount: toMoney(cenToDec(50000))},
+		{Code: "ADJ-PENALTY", Kind: KindDeduction, Amount: toMoney(cenToDec(100000))},
+	}
+	capped, excess := capPenalties(lines, cenToDec(800000), DefaultPenaltyCapRate)
+	// 上限 8000 × 20% = 1600，超出400从最后一笔违纪扣款截断，其他扣款不受影响
+	if !excess.Equal(cenToDec(40000)) {
+		t.Errorf("excess = %s", excess)
+	}
+	assertMoney(t, "first penalty", capped[0].Amount, "100000")
+	assertMoney(t, "meal", capped[1].Amount, "50000")
+	assertMoney(t, "last penalty", capped[2].Amount, "60000")
+	assertMoney(t, "original untouched", lines[2].Amount, "100000")
+
+	if _, excess := capPenalties(lines, cenToDec(800000), decimal.Zero); !excess.IsZero() {
+		t.Errorf("uncapped excess = %s", excess)
+	}
+}
+
+func TestPenaltyCapInPayrollRun(t *testing.T) {
+	input := EmployeeInput{
+		Employee:    Employee{ID: "E1"},
+		Config:      testConfig(),
+		Attendance:  AttendanceRecord{WorkHours: hours("174")},
+		Adjustments: []Adjustment{penaltyAdjustment("P1", 100000), penaltyAdjustment("P2", 100000)},
+	}
+	run := PayrollRun{Period: day("2024-03-01"), PenaltyCapRate: DefaultPenaltyCapRate, Inputs: []EmployeeInput{input}}
+	result := run.Calculate()
+	assertMoney(t, "truncated", result.Employees[0].PenaltyTruncated, "40000")
+
+	rows := result.PenaltyTruncations()
+	if len(rows) != 1 || rows[0].EmployeeID != "E1" {
+		t.Fatalf("truncations = %+v", rows)
+	}
+	assertMoney(t, "requested", rows[0].Requested, "200000")
+	assertMoney(t, "applied", rows[0].Applied, "160000")
+
+	// 员工输入设置的比例优先于批次设置
+	run.Inputs[0].PenaltyCapRate = decimal.RequireFromString("0.5")
+	if rows := run.Calculate().PenaltyTruncations(); len(rows) != 0 {
+		t.Errorf("truncations with 50%% cap = %+v", rows)
+	}
+}
@@ -0,0 +1,173 @@
+package salary
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PensionContext 计算企业补充养老计划缴费所需的员工本期数据
+type PensionContext struct {
+	Period     time.Time     // 薪资期
+	Input      EmployeeInput // 员工计算输入
+	BaseSalary Money         // 本期基础工资（分）
+}
+
+// PensionAmount 补充养老计划本期缴费
+type PensionAmount struct {
+	Employee Money // 个人缴费，从工资中扣除（分）
+	Employer Money // 单位缴费，计入个人账户，按归属计划逐步归属员工（分）
+}
+
+// VestingStep 归属比例的一个台阶：服务满 ServiceMonths 个月后单位缴费归属 Vested 比例
+type VestingStep struct {
+	ServiceMonths int             // 服务月数
+	Vested        decimal.Decimal // 已归属比例，如0.5表示50%
+}
+
+// VestingSchedule 单位缴费归属计划，按服务月数从小到大排列；为空表示立即全部归属
+type VestingSchedule []VestingStep
+
+// VestedRate 返回服务满 serviceMonths 个月时的归属比例
+func (v VestingSchedule) VestedRate(serviceMonths int) decimal.Decimal {
+	if len(v) == 0 {
+		return decimal.NewFromInt(1)
+	}
+	rate := decimal.Zero
+	for _, step := range v {
+		if serviceMonths >= step.ServiceMonths {
+			rate = step.Vested
+		}
+	}
+	return rate
+}
+
+// PensionPlan 企业补充养老计划（如企业年金、职业年金），在法定社保之后计算
+type PensionPlan struct {
+	Code    string                                 // 计划代码，如 ANNUITY
+	Name    string                                 // 工资条显示名称
+	PreTax  bool                                   // 个人缴费是否税前扣除
+	Vesting VestingSchedule                        // 单位缴费归属计划
+	Compute func(ctx PensionContext) PensionAmount // 缴费计算函数
+}
+
+// FixedRatePensionPlan 创建按基础工资固定比例缴费的补充养老计划
+// employeeRate、employerRate: 个人和单位缴费比例，如企业年金常见的个人4%、单位8%
+func FixedRatePensionPlan(code, name string, employeeRate, employerRate decimal.Decimal, vesting VestingSchedule) PensionPlan {
+	return PensionPlan{
+		Code:    code,
+		Name:    name,
+		PreTax:  true,
+		Vesting: vesting,
+		Compute: func(ctx PensionContext) PensionAmount {
+			base := moneyToDec(ctx.BaseSalary)
+			return PensionAmount{
+				Employee: toMoney(base.Mul(employeeRate).Round(2)),
+				Employer: toMoney(base.Mul(employerRate).Round(2)),
+			}
+		},
+	}
+}
+
+// PensionLine 员工结果中的一项补充养老计划缴费
+type PensionLine struct {
+	Code     string // 计划代码
+	Name     string // 计划名称
+	Employee Money  // 个人缴费（分）
+	Employer Money  // 单位缴费（分）
+}
+
+// pensionContributions 计算员工本期各补充养老计划的缴费
+// 返回值: (缴费明细, 个人缴费对应的工资条扣款项, 单位缴费合计)
+func pensionContributions(period time.Time, input EmployeeInput, baseSalary Money) ([]PensionLine, []PayLine, decimal.Decimal) {
+	var pension []PensionLine
+	var lines []PayLine
+	employer := decimal.Zero
+	ctx := PensionContext{Period: monthStart(period), Input: input, BaseSalary: baseSalary}
+	for _, plan := range input.PensionPlans {
+		amount := plan.Compute(ctx)
+		pension = append(pension, PensionLine{Code: plan.Code, Name: plan.Name, Employee: amount.Employee, Employer: amount.Employer})
+		employer = employer.Add(moneyToDec(amount.Employer))
+		if moneyToDec(amount.Employee).IsPositive() {
+			lines = append(lines, PayLine{Code: "PENSION-" + plan.Code, Name: plan.Name, Kind: KindDeduction, Amount: amount.Employee, Taxable: plan.PreTax})
+		}
+	}
+	return pension, lines, employer
+}
+
+// PensionAccount 员工在某个补充养老计划中的个人账户
+type PensionAccount struct {
+	EmployeeID      string // 工号
+	PlanCode        string // 计划代码
+	EmployeeBalance Money  // 个人缴费累计，始终全部归属员工（分）
+	EmployerBalance Money  // 单位缴费累计（分）
+	Forfeited       Money  // 离职时未归属、退回企业的单位缴费（分）
+	Months          int    // 缴费月数
+}
+
+// VestedBalance 按归属计划计算账户在指定日期的已归属和未归属单位缴费
+// hireDate: 员工入职日期，用于计算服务月数
+func (a PensionAccount) VestedBalance(schedule VestingSchedule, hireDate, at time.Time) (vested, unvested Money) {
+	balance := moneyToDec(a.EmployerBalance)
+	v := balance.Mul(schedule.VestedRate(monthsBetween(hireDate, at))).Round(2)
+	return toMoney(v), toMoney(balance.Sub(v))
+}
+
+// PensionLedger 补充养老计划个人账户台账，按员工和计划记录累计缴费
+type PensionLedger struct {
+	mu       sync.Mutex
+	accounts map[string]*PensionAccount
+}
+
+// NewPensionLedger 创建空的补充养老计划台账
+func NewPensionLedger() *PensionLedger {
+	return &PensionLedger{accounts: make(map[string]*PensionAccount)}
+}
+
+// record 记入员工本期缴费
+func (l *PensionLedger) record(r EmployeeResult) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, p := range r.Pension {
+		key := r.Employee.ID + "|" + p.Code
+		account, ok := l.accounts[key]
+		if !ok {
+			account = &PensionAccount{EmployeeID: r.Employee.ID, PlanCode: p.Code}
+			l.accounts[key] = account
+		}
+		account.EmployeeBalance = toMoney(moneyToDec(account.EmployeeBalance).Add(moneyToDec(p.Employee)))
+		account.EmployerBalance = toMoney(moneyToDec(account.EmployerBalance).Add(moneyToDec(p.Employer)))
+		account.Months++
+	}
+}
+
+// Accounts 查询员工的全部补充养老计划账户，按计划代码排序
+func (l *PensionLedger) Accounts(employeeID string) []PensionAccount {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var accounts []PensionAccount
+	for _, a := range l.accounts {
+		if a.EmployeeID == employeeID {
+			accounts = append(accounts, *a)
+		}
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].PlanCode < accounts[j].PlanCode })
+	return accounts
+}
+
+// Forfeit 员工离职时按归属计划处理单位缴费：未归属部分退回企业，账户只保留已归属部分
+// 返回值: 退回企业的金额
+func (l *PensionLedger) Forfeit(employeeID string, plan PensionPlan, hireDate, leaveDate time.Time) Money {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	account, ok := l.accounts[employeeID+"|"+plan.Code]
+	if !ok {
+		return toMoney(decimal.Zero)
+	}
+	vested, unvested := account.VestedBalance(plan.Vesting, hireDate, leaveDate)
+	account.EmployerBalance = vested
+	account.Forfeited = toMoney(moneyToDec(account.Forfeited).Add(moneyToDec(unvested)))
+	return unvested
+}
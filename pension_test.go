@@ -0,0 +1,94 @@
+package salary
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestVestingScheduleVestedRate(t *testing.T) {
+	schedule := VestingSchedule{
+		{ServiceMonths: 12, Vested: decimal.RequireFromString("0.25")},
+		{ServiceMonths: 36, Vested: decimal.RequireFromString("1")},
+	}
+	tests := []struct {
+		months int
+		want   string
+	}{
+		{0, "0"},
+		{12, "0.25"},
+		{35, "0.25"},
+		{36, "1"},
+	}
+	for _, tt := range tests {
+		if got := schedule.VestedRate(tt.months); !got.Equal(decimal.RequireFromString(tt.want)) {
+			t.Errorf("VestedRate(%d) = %s, want %s", tt.months, got, tt.want)
+		}
+	}
+	if got := (VestingSchedule{}).VestedRate(0); !got.Equal(decimal.NewFromInt(1)) {
+		t.Errorf("empty schedule VestedRate = %s, want 1", got)
+	}
+}
+
+func TestPensionPlanInRun(t *testing.T) {
+	hire := time.Date(2023, 1, 1, 0, 0, 0, 0, time.Local)
+	plan := FixedRatePensionPlan("ANNUITY", "企业年金", decimal.RequireFromString("0.04"), decimal.RequireFromString("0.08"),
+		VestingSchedule{{ServiceMonths: 24, Vested: decimal.NewFromInt(1)}})
+	ledger := NewPensionLedger()
+	run := PayrollRun{
+		Period:       time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local),
+		Inputs:       []EmployeeInput{{Employee: Employee{ID: "E1", HireDate: hire}, Config: testConfig(), Attendance: AttendanceRecord{WorkHours: hours("174")}}},
+		PensionPlans: []PensionPlan{plan},
+		Pensions:     ledger,
+	}
+	result := run.Calculate()
+	r := result.Employees[0]
+	// 基础工资8000元：个人4% = 320元，单位8% = 640元
+	assertMoney(t, "EmployerContributions", r.EmployerContributions, "64000")
+	found := false
+	for _, line := range r.Lines {
+		if line.Code == "PENSION-ANNUITY" {
+			found = true
+			assertMoney(t, "employee contribution", line.Amount, "32000")
+		}
+	}
+	if !found {
+		t.Fatal("PENSION-ANNUITY line missing")
+	}
+
+	accounts := ledger.Accounts("E1")
+	if len(accounts) != 1 {
+		t.Fatalf("accounts = %d, want 1", len(accounts))
+	}
+	// 服务14个月离职，未满24个月，单位缴费全部退回
+	forfeited := ledger.Forfeit("E1", plan, hire, time.Date(2024, 3, 31, 0, 0, 0, 0, time.Local))
+	assertMoney(t, "forfeited", forfeited, "64000")
+	assertMoney(t, "employer balance", ledger.Accounts("E1")[0].EmployerBalance, "0")
+}
+
+func TestStatutoryDeductionSeesPensionLines(t *testing.T) {
+	// 测试专用辖区，避免影响已注册的香港、新加坡项目
+	jurisdiction := Jurisdiction("TEST-PENSION")
+	var seen []PensionLine
+	RegisterStatutoryDeduction(jurisdiction, StatutoryDeduction{
+		Code:  "RELIEF",
+		Label: "养老供款抵减",
+		Compute: func(ctx StatutoryContext) StatutoryAmount {
+			seen = ctx.Pension
+			return StatutoryAmount{Employee: toMoney(decimal.Zero), Employer: toMoney(decimal.Zero), Provision: toMoney(decimal.Zero)}
+		},
+	})
+	plan := FixedRatePensionPlan("ANNUITY", "企业年金", decimal.RequireFromString("0.04"), decimal.RequireFromString("0.08"), nil)
+	CalculateEmployee(day("2024-06-01"), EmployeeInput{
+		Employee:     Employee{ID: "T1", Jurisdiction: jurisdiction},
+		Config:       testConfig(),
+		Attendance:   AttendanceRecord{WorkHours: hours("174")},
+		PensionPlans: []PensionPlan{plan},
+	})
+	if len(seen) != 1 || seen[0].Code != "ANNUITY" {
+		t.Fatalf("pension lines in context = %+v", seen)
+	}
+	assertMoney(t, "employee annuity", seen[0].Employee, "32000")
+	assertMoney(t, "employer annuity", seen[0].Employer, "64000")
+}
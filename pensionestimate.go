@@ -0,0 +1,176 @@
+package salary
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// 基本养老保险的默认参数
+var (
+	DefaultPensionAccountRate   = decimal.RequireFromString("0.08") // 个人账户记账比例，等于个人缴费费率8%
+	DefaultPensionRetirementAge = 60                                // 默认退休年龄
+	DefaultPensionMinimumMonths = 180                               // 按月领取养老金的最低累计缴费月数（15年）
+)
+
+// pensionPayoutMonths 个人账户养老金计发月数，按退休年龄确定
+var pensionPayoutMonths = map[int]int{50: 195, 55: 170, 60: 139, 63: 117}
+
+// PensionFormula 基本养老金估算参数，估算结果仅供参考，以社保经办机构核定为准
+type PensionFormula struct {
+	LocalAverageWage Money           // 当地上年度职工月平均工资（计发基数，分），用于计算缴费指数和基础养老金
+	AccountRate      decimal.Decimal // 个人账户记账比例，0表示8%
+	RetirementAge    int             // 退休年龄，0表示60岁
+	PayoutMonths     int             // 个人账户养老金计发月数，0表示按退休年龄查表
+	MinimumMonths    int             // 按月领取的最低累计缴费月数，0表示180个月
+}
+
+// defaults 填充未配置的参数
+func (f PensionFormula) defaults() PensionFormula {
+	if f.AccountRate.IsZero() {
+		f.AccountRate = DefaultPensionAccountRate
+	}
+	if f.RetirementAge == 0 {
+		f.RetirementAge = DefaultPensionRetirementAge
+	}
+	if f.PayoutMonths == 0 {
+		f.PayoutMonths = pensionPayoutMonths[f.RetirementAge]
+		if f.PayoutMonths == 0 {
+			f.PayoutMonths = pensionPayoutMonths[DefaultPensionRetirementAge]
+		}
+	}
+	if f.MinimumMonths == 0 {
+		f.MinimumMonths = DefaultPensionMinimumMonths
+	}
+	return f
+}
+
+// PensionHistory 基本养老保险累计缴费记录
+type PensionHistory struct {
+	Months       int             `json:"months"`        // 累计缴费月数
+	Account      Money           `json:"account"`       // 个人账户储存额（分），不含利息
+	AverageIndex decimal.Decimal `json:"average_index"` // 平均缴费指数，缴费基数与社平工资之比的平均值
+}
+
+// add 累计一段缴费，按月数加权平均缴费指数
+func (h PensionHistory) add(months int, account decimal.Decimal, index decimal.Decimal) PensionHistory {
+	if months <= 0 {
+		return h
+	}
+	total := decimal.NewFromInt(int64(h.Months + months))
+	h.AverageIndex = h.AverageIndex.Mul(decimal.NewFromInt(int64(h.Months))).Add(index.Mul(decimal.NewFromInt(int64(months)))).Div(total)
+	h.Months += months
+	h.Account = toMoney(moneyToDec(h.Account).Add(account))
+	return h
+}
+
+// pensionBase 本期基本养老保险缴费基数，不缴纳养老保险时为0
+func pensionBase(config PayrollConfig, wage Money) Money {
+	if !config.PensionRate.IsPositive() {
+		return toMoney(decimal.Zero)
+	}
+	base, _ := config.PensionBase.Clamp(wage)
+	return base
+}
+
+// PensionContributionHistory 按已保存的发薪结果累计员工的养老保险缴费月数和个人账户
+// 同一薪资期有多条结果（如补发批次）时只计一个月，取最大的缴费基数
+// prior: 入职前在其他单位的缴费记录
+// results: 员工的发薪结果
+// formula: 估算参数
+func PensionContributionHistory(prior PensionHistory, results []EmployeeResult, formula PensionFormula) PensionHistory {
+	formula = formula.defaults()
+	bases := make(map[string]decimal.Decimal)
+	for _, r := range results {
+		key := r.Period.Format("200601")
+		if base := moneyToDec(r.PensionBase); base.GreaterThan(bases[key]) {
+			bases[key] = base
+		}
+	}
+	history := prior
+	average := moneyToDec(formula.LocalAverageWage)
+	for _, base := range bases {
+		index := decimal.Zero
+		if average.IsPositive() {
+			index = base.Div(average)
+		}
+		history = history.add(1, base.Mul(formula.AccountRate).Round(2), index)
+	}
+	history.AverageIndex = history.AverageIndex.Round(4)
+	return history
+}
+
+// PensionEstimate 基本养老金估算
+type PensionEstimate struct {
+	Contributed      PensionHistory `json:"contributed"`       // 截至目前的累计缴费
+	RetirementDate   *time.Time     `json:"retirement_date"`   // 预计退休日期，缺少出生日期时为空
+	ProjectedMonths  int            `json:"projected_months"`  // 按当前基数缴费至退休的累计缴费月数
+	ProjectedAccount Money          `json:"projected_account"` // 退休时个人账户储存额（分）
+	BasicPension     Money          `json:"basic_pension"`     // 基础养老金（分/月）
+	AccountPension   Money          `json:"account_pension"`   // 个人账户养老金（分/月）
+	MonthlyPension   Money          `json:"monthly_pension"`   // 基本养老金合计（分/月）
+	Eligible         bool           `json:"eligible"`          // 退休时累计缴费是否达到按月领取的最低年限
+}
+
+// EstimatePension 粗略估算员工退休时的基本养老金，假设按当前缴费基数连续缴费至退休、社平工资不变、不计利息
+// 基础养老金 = 计发基数 × (1 + 平均缴费指数) ÷ 2 × 缴费年限 × 1%；个人账户养老金 = 个人账户储存额 ÷ 计发月数
+// e: 员工档案，按出生日期和退休年龄确定退休日期
+// history: 截至目前的累计缴费
+// currentBase: 当前缴费基数（分）
+// at: 估算日期，之后的月份按当前基数预测
+// formula: 估算参数
+func EstimatePension(e Employee, history PensionHistory, currentBase Money, at time.Time, formula PensionFormula) PensionEstimate {
+	formula = formula.defaults()
+	estimate := PensionEstimate{Contributed: history}
+	projected := history
+	if !e.BirthDate.IsZero() {
+		retirement := dateOnly(e.BirthDate).AddDate(formula.RetirementAge, 0, 0)
+		estimate.RetirementDate = &retirement
+		if months := monthsBetween(monthStart(at).AddDate(0, 1, 0), monthStart(retirement)); months > 0 && moneyToDec(currentBase).IsPositive() {
+			index := decimal.Zero
+			if average := moneyToDec(formula.LocalAverageWage); average.IsPositive() {
+				index = moneyToDec(currentBase).Div(average)
+			}
+			account := moneyToDec(currentBase).Mul(formula.AccountRate).Round(2).Mul(decimal.NewFromInt(int64(months)))
+			projected = projected.add(months, account, index)
+		}
+	}
+
+	years := decimal.NewFromInt(int64(projected.Months)).Div(decimal.NewFromInt(12))
+	basic := moneyToDec(formula.LocalAverageWage).Mul(decimal.NewFromInt(1).Add(projected.AverageIndex)).Div(decimal.NewFromInt(2)).
+		Mul(years).Div(decimal.NewFromInt(100)).Round(2)
+	accountPension := moneyToDec(projected.Account).Div(decimal.NewFromInt(int64(formula.PayoutMonths))).Round(2)
+
+	estimate.ProjectedMonths = projected.Months
+	estimate.ProjectedAccount = projected.Account
+	estimate.BasicPension = toMoney(basic)
+	estimate.AccountPension = toMoney(accountPension)
+	estimate.MonthlyPension = toMoney(basic.Add(accountPension))
+	estimate.Eligible = projected.Months >= formula.MinimumMonths
+	return estimate
+}
+
+// SetPensionFormula 设置基本养老金估算参数，设置后员工端工资条包含社保一览
+func (s *Server) SetPensionFormula(formula PensionFormula) {
+	s.pension = &formula
+}
+
+// pensionOverview 生成员工端工资条的社保一览，按截至本期的发薪结果累计，未设置估算参数时为空
+func (s *Server) pensionOverview(current EmployeeResult) *PensionEstimate {
+	if s.pension == nil {
+		return nil
+	}
+	var prior PensionHistory
+	if record, err := s.store.Employee(current.Employee.ID); err == nil && record.PriorPension != nil {
+		prior = *record.PriorPension
+	}
+	var results []EmployeeResult
+	for _, r := range s.store.EmployeeResults(current.Employee.ID) {
+		if !r.Period.After(current.Period) {
+			results = append(results, r)
+		}
+	}
+	history := PensionContributionHistory(prior, results, *s.pension)
+	estimate := EstimatePension(current.Employee, history, current.PensionBase, current.Period, *s.pension)
+	return &estimate
+}
@@ -0,0 +1,42 @@
+package salary
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestEstimatePension(t *testing.T) {
+	formula := PensionFormula{LocalAverageWage: toMoney(decimal.NewFromInt(1000000))}
+	var results []EmployeeResult
+	for month := 1; month <= 12; month++ {
+		r := EmployeeResult{Period: day("2024-01-01").AddDate(0, month-1, 0), PensionBase: toMoney(decimal.NewFromInt(800000))}
+		results = append(results, r)
+	}
+	// 同月补发批次不重复计算缴费月数
+	results = append(results, EmployeeResult{Period: day("2024-12-01"), PensionBase: toMoney(decimal.NewFromInt(800000))})
+
+	history := PensionContributionHistory(PensionHistory{}, results, formula)
+	if history.Months != 12 || !history.AverageIndex.Equal(decimal.RequireFromString("0.8")) {
+		t.Fatalf("history = %+v", history)
+	}
+	assertMoney(t, "account", history.Account, "768000")
+
+	e := Employee{ID: "E1", BirthDate: day("1985-06-15")}
+	estimate := EstimatePension(e, history, toMoney(decimal.NewFromInt(800000)), day("2024-12-01"), formula)
+	if estimate.ProjectedMonths != 257 || !estimate.Eligible {
+		t.Fatalf("estimate = %+v", estimate)
+	}
+	assertMoney(t, "projected account", estimate.ProjectedAccount, "16448000")
+	assertMoney(t, "basic", estimate.BasicPension, "192750")
+	assertMoney(t, "account pension", estimate.AccountPension, "118330.94")
+	assertMoney(t, "monthly", estimate.MonthlyPension, "311080.94")
+}
+
+func TestEstimatePensionWithoutBirthDate(t *testing.T) {
+	history := PensionHistory{Months: 60, Account: toMoney(decimal.NewFromInt(3000000)), AverageIndex: decimal.NewFromInt(1)}
+	estimate := EstimatePension(Employee{ID: "E1"}, history, toMoney(decimal.NewFromInt(800000)), day("2024-12-01"), PensionFormula{LocalAverageWage: toMoney(decimal.NewFromInt(1000000))})
+	if estimate.RetirementDate != nil || estimate.ProjectedMonths != 60 || estimate.Eligible {
+		t.Errorf("estimate = %+v", estimate)
+	}
+}
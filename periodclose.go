@@ -0,0 +1,210 @@
+package salary
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrPeriodCloseBlocked 关账检查未全部通过，拒绝关账
+var ErrPeriodCloseBlocked = errors.New("关账检查未通过")
+
+// CloseCheck 关账检查项
+type CloseCheck interface {
+	Name() string
+	Check(ctx context.Context, period time.Time) error
+}
+
+// funcCloseCheck 以函数实现的关账检查项
+type funcCloseCheck struct {
+	name  string
+	check func(ctx context.Context, period time.Time) error
+}
+
+func (c funcCloseCheck) Name() string { return c.name }
+func (c funcCloseCheck) Check(ctx context.Context, period time.Time) error {
+	return c.check(ctx, period)
+}
+
+// NewCloseCheck 以函数创建关账检查项，如审批是否完成
+func NewCloseCheck(name string, check func(ctx context.Context, period time.Time) error) CloseCheck {
+	return funcCloseCheck{name: name, check: check}
+}
+
+// periodRuns 查询薪资期内的全部批次
+func (s *MemoryStore) periodRuns(period time.Time) []PayrollResult {
+	var runs []PayrollResult
+	for _, run := range s.Runs() {
+		if sameMonth(run.Period, period) {
+			runs = append(runs, run)
+		}
+	}
+	return runs
+}
+
+// AttendanceImportedCheck 考勤导入检查：员工档案中本期在职的员工均已计入本期批次
+func AttendanceImportedCheck(store *MemoryStore) CloseCheck {
+	return NewCloseCheck("attendance_imported", func(ctx context.Context, period time.Time) error {
+		calculated := make(map[string]bool)
+		for _, run := range store.periodRuns(period) {
+			for _, r := range run.Employees {
+				calculated[r.Employee.ID] = true
+			}
+		}
+		start := monthStart(period)
+		end := start.AddDate(0, 1, 0)
+		var missing []string
+		for _, record := range store.Employees() {
+			e := record.Employee
+			employed := e.HireDate.Before(end) && (e.TerminationDate.IsZero() || !e.TerminationDate.Before(start))
+			if employed && !calculated[e.ID] {
+				missing = append(missing, e.ID)
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("%d名员工本期未计算: %s", len(missing), strings.Join(missing, ", "))
+		}
+		return nil
+	})
+}
+
+// NoAnomaliesCheck 异常检查：本期存在批次，且没有未计算的员工和缺少城市政策的员工
+func NoAnomaliesCheck(store *MemoryStore) CloseCheck {
+	return NewCloseCheck("no_anomalies", func(ctx context.Context, period time.Time) error {
+		runs := store.periodRuns(period)
+		if len(runs) == 0 {
+			return errors.New("本期没有发薪批次")
+		}
+		var errs, misses int
+		for _, run := range runs {
+			errs += len(run.Errors)
+			misses += len(run.PolicyMisses)
+		}
+		if errs > 0 || misses > 0 {
+			return fmt.Errorf("存在%d条计算错误、%d名员工缺少城市政策", errs, misses)
+		}
+		return nil
+	})
+}
+
+// TotalsReconciledCheck 总额核对：本期实发合计与财务或银行的付款总额一致
+// expected: 返回本期应付总额（分）
+func TotalsReconciledCheck(store *MemoryStore, expected func(ctx context.Context, period time.Time) (Money, error)) CloseCheck {
+	return NewCloseCheck("totals_reconciled", func(ctx context.Context, period time.Time) error {
+		want, err := expected(ctx, period)
+		if err != nil {
+			return err
+		}
+		total := decimal.Zero
+		for _, run := range store.periodRuns(period) {
+			for _, r := range run.Employees {
+				total = total.Add(moneyToDec(r.NetSalary))
+			}
+		}
+		if !total.Equal(moneyToDec(want)) {
+			return fmt.Errorf("实发合计%s与应付总额%s不一致", FormatMoneyCenToYuan(toMoney(total)), FormatMoneyCenToYuan(want))
+		}
+		return nil
+	})
+}
+
+// CloseCheckResult 单个关账检查项的结果
+type CloseCheckResult struct {
+	Name    string `json:"name"`              // 检查项名称
+	Passed  bool   `json:"passed"`            // 是否通过
+	Message string `json:"message,omitempty"` // 未通过原因
+}
+
+// PeriodCloser 薪资期关账：依次执行检查项，全部通过后才锁定薪资期
+type PeriodCloser struct {
+	Checks []CloseCheck      // 关账检查项
+	Ledger *CorrectionLedger // 记录已关账薪资期，关账后的考勤修改只能通过更正单处理
+	Audit  *AuditLog         // 审计日志，为空表示不记录
+}
+
+// Close 执行关账检查并锁定薪资期
+// 任一检查未通过时不锁定，返回 ErrPeriodCloseBlocked 和全部检查结果；审计记录的时间取 ctx 中的请求时间
+// actor: 关账操作人
+func (c *PeriodCloser) Close(ctx context.Context, period time.Time, actor string) ([]CloseCheckResult, error) {
+	if c.Ledger.IsClosed(period) {
+		return nil, fmt.Errorf("薪资期 %s 已关账", period.Format("2006-01"))
+	}
+
+	results := make([]CloseCheckResult, 0, len(c.Checks))
+	var failed []string
+	for _, check := range c.Checks {
+		result := CloseCheckResult{Name: check.Name(), Passed: true}
+		if err := check.Check(ctx, period); err != nil {
+			result.Passed = false
+			result.Message = err.Error()
+			failed = append(failed, check.Name())
+		}
+		results = append(results, result)
+	}
+	if len(failed) > 0 {
+		return results, fmt.Errorf("%w: %s", ErrPeriodCloseBlocked, strings.Join(failed, ", "))
+	}
+
+	c.Ledger.Close(period)
+	if c.Audit != nil {
+		c.Audit.Record(AuditEntry{
+			Time:   requestTime(ctx),
+			Actor:  actor,
+			Action: "period.closed",
+			Period: monthStart(period),
+			Detail: fmt.Sprintf("薪资期%s关账，%d项检查全部通过", period.Format("2006-01"), len(results)),
+		})
+	}
+	return results, nil
+}
+
+// SetPeriodCloser 启用关账接口
+func (s *Server) SetPeriodCloser(closer *PeriodCloser) {
+	s.closer = closer
+}
+
+// handleClosePeriod 关账接口：POST /periods/{period}/close，请求体 {"actor": "..."}
+// 检查未通过时返回 409 及未通过的检查项
+func (s *Server) handleClosePeriod(w http.ResponseWriter, r *http.Request) {
+	if s.closer == nil {
+		writeJSON(w, http.StatusNotImplemented, errorBody(errors.New("未启用关账")))
+		return
+	}
+	period, err := time.Parse("2006-01", r.PathValue("period"))
+	if err != nil {
+		writeError(w, fmt.Errorf("薪资期格式应为 YYYY-MM: %w", err))
+		return
+	}
+	var req struct {
+		Actor string `json:"actor"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	results, err := s.closer.Close(r.Context(), period, req.Actor)
+	if errors.Is(err, ErrPeriodCloseBlocked) {
+		var failing []CloseCheckResult
+		for _, result := range results {
+			if !result.Passed {
+				failing = append(failing, result)
+			}
+		}
+		body := errorBody(err)
+		body["failing"] = failing
+		writeJSON(w, http.StatusConflict, body)
+		return
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"period": period.Format("2006-01"), "closed": true, "checks": results})
+}
@@ -0,0 +1,144 @@
+package salary
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPeriodCloserBlocksUntilChecksPass(t *testing.T) {
+	period := day("2024-06-01")
+	approved := false
+	closer := &PeriodCloser{
+		Checks: []CloseCheck{
+			NewCloseCheck("always", func(ctx context.Context, period time.Time) error { return nil }),
+			NewCloseCheck("approved", func(ctx context.Context, period time.Time) error {
+				if !approved {
+					return errors.New("审批未完成")
+				}
+				return nil
+			}),
+		},
+		Ledger: NewCorrectionLedger(),
+		Audit:  NewAuditLog(),
+	}
+
+	results, err := closer.Close(context.Background(), period, "finance")
+	if !errors.Is(err, ErrPeriodCloseBlocked) {
+		t.Fatalf("err = %v, want ErrPeriodCloseBlocked", err)
+	}
+	if len(results) != 2 || !results[0].Passed || results[1].Passed || results[1].Message != "审批未完成" {
+		t.Errorf("results = %+v", results)
+	}
+	if closer.Ledger.IsClosed(period) || len(closer.Audit.Entries()) != 0 {
+		t.Fatal("blocked close must not lock the period")
+	}
+
+	approved = true
+	if _, err := closer.Close(context.Background(), period, "finance"); err != nil {
+		t.Fatal(err)
+	}
+	if !closer.Ledger.IsClosed(period) {
+		t.Error("period should be closed")
+	}
+	entries := closer.Audit.Entries()
+	if len(entries) != 1 || entries[0].Action != "period.closed" || entries[0].Actor != "finance" {
+		t.Errorf("audit = %+v", entries)
+	}
+	if _, err := closer.Close(context.Background(), period, "finance"); err == nil {
+		t.Error("closing twice should fail")
+	}
+}
+
+func TestAttendanceImportedCheck(t *testing.T) {
+	store := NewMemoryStore()
+	store.PutEmployee(EmployeeRecord{Employee: Employee{ID: "E1", HireDate: day("2023-01-01")}})
+	store.PutEmployee(EmployeeRecord{Employee: Employee{ID: "E2", HireDate: day("2024-07-01")}})
+	check := AttendanceImportedCheck(store)
+
+	// E2 七月入职，六月只要求 E1
+	err := check.Check(context.Background(), day("2024-06-01"))
+	if err == nil || !strings.Contains(err.Error(), "E1") || strings.Contains(err.Error(), "E2") {
+		t.Fatalf("err = %v", err)
+	}
+	store.SaveRun(&PayrollResult{Period: day("2024-06-01"), Employees: []EmployeeResult{{Employee: Employee{ID: "E1"}}}})
+	if err := check.Check(context.Background(), day("2024-06-01")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestHandleClosePeriod(t *testing.T) {
+	server := NewServer(NewMemoryStore())
+	req := httptest.NewRequest(http.MethodPost, "/periods/2024-06/close", strings.NewReader(`{"actor":"finance"}`))
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotImplemented {
+		t.Fatalf("status without closer = %d", rec.Code)
+	}
+
+	ledger := NewCorrectionLedger()
+	server.SetPeriodCloser(&PeriodCloser{
+		Checks: []CloseCheck{NewCloseCheck("approved", func(ctx context.Context, period time.Time) error {
+			return errors.New("审批未完成")
+		})},
+		Ledger: ledger,
+	})
+	req = httptest.NewRequest(http.MethodPost, "/periods/2024-06/close", strings.NewReader(`{"actor":"finance"}`))
+	rec = httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+	var body struct {
+		Failing []CloseCheckResult `json:"failing"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Failing) != 1 || body.Failing[0].Name != "approved" {
+		t.Errorf("failing = %+v", body.Failing)
+	}
+	if ledger.IsClosed(day("2024-06-01")) {
+		t.Error("blocked close must not lock the period")
+	}
+}
+
+func TestNoAnomaliesAndTotalsChecks(t *testing.T) {
+	store := NewMemoryStore()
+	period := day("2024-06-01")
+	anomalies := NoAnomaliesCheck(store)
+	if err := anomalies.Check(context.Background(), period); err == nil {
+		t.Error("period without runs should fail")
+	}
+
+	store.SaveRun(&PayrollResult{Period: period, Employees: []EmployeeResult{
+		{Employee: Employee{ID: "E1"}, NetSalary: toMoney(cenToDec(600000))},
+		{Employee: Employee{ID: "E2"}, NetSalary: toMoney(cenToDec(500000))},
+	}})
+	if err := anomalies.Check(context.Background(), period); err != nil {
+		t.Error(err)
+	}
+	store.SaveRun(&PayrollResult{Period: period, Errors: []EmployeeError{{EmployeeID: "E3"}}})
+	if err := anomalies.Check(context.Background(), period); err == nil || !strings.Contains(err.Error(), "1条计算错误") {
+		t.Errorf("err = %v, want calculation error reported", err)
+	}
+
+	expected := toMoney(cenToDec(1100000))
+	totals := TotalsReconciledCheck(store, func(ctx context.Context, period time.Time) (Money, error) { return expected, nil })
+	if err := totals.Check(context.Background(), period); err != nil {
+		t.Error(err)
+	}
+	expected = toMoney(cenToDec(1000000))
+	if err := totals.Check(context.Background(), period); err == nil {
+		t.Error("mismatched totals should fail")
+	}
+	// 其他薪资期的批次不计入
+	if err := totals.Check(context.Background(), day("2024-05-01")); err == nil {
+		t.Error("May has no payments, totals should not reconcile to 10,000")
+	}
+}
@@ -0,0 +1,130 @@
+package salary
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// PolicyChange 政策配置中一项值的变化
+type PolicyChange struct {
+	Path string // 配置路径，如 regions.shanghai.pension_rate
+	Old  string // 原值，为空表示新增
+	New  string // 新值，为空表示删除
+}
+
+// String 生成一行可读的变更说明
+func (c PolicyChange) String() string {
+	switch {
+	case c.Old == "":
+		return fmt.Sprintf("+ %s: %s", c.Path, c.New)
+	case c.New == "":
+		return fmt.Sprintf("- %s: %s", c.Path, c.Old)
+	default:
+		return fmt.Sprintf("~ %s: %s -> %s", c.Path, c.Old, c.New)
+	}
+}
+
+// PolicyDiff 两份政策配置之间的差异
+type PolicyDiff []PolicyChange
+
+// String 生成可供审阅的差异文本，每行一项变更，按路径排序
+func (d PolicyDiff) String() string {
+	if len(d) == 0 {
+		return "无变更\n"
+	}
+	var b strings.Builder
+	for _, c := range d {
+		b.WriteString(c.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// flattenPolicy 将政策配置展开为 路径 -> 值 的映射，便于逐项比较
+func flattenPolicy(p RulesPack) map[string]string {
+	values := map[string]string{
+		"version": p.Version,
+	}
+	if !p.EffectiveFrom.IsZero() {
+		values["effective_from"] = p.EffectiveFrom.Format("2006-01-02")
+	}
+	for i, b := range p.TaxBrackets {
+		prefix := fmt.Sprintf("tax_brackets[%d].", i)
+		values[prefix+"threshold"] = FormatMoneyCenToYuan(b.Threshold)
+		values[prefix+"rate"] = b.Rate.String()
+		values[prefix+"deduction"] = FormatMoneyCenToYuan(b.Deduction)
+	}
+	for city, r := range p.Regions {
+		prefix := "regions." + city + "."
+		if !r.EffectiveFrom.IsZero() {
+			values[prefix+"effective_from"] = r.EffectiveFrom.Format("2006-01-02")
+		}
+		for name, rate := range map[string]decimal.Decimal{
+			"pension_rate":      r.PensionRate,
+			"medical_rate":      r.MedicalRate,
+			"unemployment_rate": r.UnemploymentRate,
+			"housing_fund_rate": r.HousingFundRate,
+		} {
+			values[prefix+name] = rate.String()
+		}
+		if moneyToDec(r.Base.Floor).IsPositive() {
+			values[prefix+"base.floor"] = FormatMoneyCenToYuan(r.Base.Floor)
+		}
+		if moneyToDec(r.Base.Ceiling).IsPositive() {
+			values[prefix+"base.ceiling"] = FormatMoneyCenToYuan(r.Base.Ceiling)
+		}
+	}
+	for name, limit := range p.Limits {
+		values["limits."+name] = FormatMoneyCenToYuan(limit)
+	}
+	return values
+}
+
+// DiffPolicies 比较两份政策配置的税率表、城市费率和各类限额
+// 返回值: 按路径排序的变更列表
+func DiffPolicies(current, incoming RulesPack) PolicyDiff {
+	old, updated := flattenPolicy(current), flattenPolicy(incoming)
+	var diff PolicyDiff
+	for path, value := range old {
+		if next, ok := updated[path]; !ok {
+			diff = append(diff, PolicyChange{Path: path, Old: value})
+		} else if next != value {
+			diff = append(diff, PolicyChange{Path: path, Old: value, New: next})
+		}
+	}
+	for path, value := range updated {
+		if _, ok := old[path]; !ok {
+			diff = append(diff, PolicyChange{Path: path, New: value})
+		}
+	}
+	sort.Slice(diff, func(i, j int) bool { return diff[i].Path < diff[j].Path })
+	return diff
+}
+
+// ExportPolicyBundle 将当前全部政策配置导出为格式化的JSON，便于纳入版本管理和审阅
+func ExportPolicyBundle(w io.Writer, policy RulesPack) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(policy)
+}
+
+// ImportPolicyBundle 读取待导入的政策配置并与当前配置比较
+// 导入内容需通过校验；调用方应在审阅差异并批准后再替换当前配置
+// r: 政策配置JSON
+// current: 当前配置
+// 返回值: (待导入配置, 与当前配置的差异)
+func ImportPolicyBundle(r io.Reader, current RulesPack) (RulesPack, PolicyDiff, error) {
+	var incoming RulesPack
+	if err := json.NewDecoder(r).Decode(&incoming); err != nil {
+		return RulesPack{}, nil, fmt.Errorf("解析政策配置失败: %w", err)
+	}
+	if err := incoming.Validate(); err != nil {
+		return RulesPack{}, nil, err
+	}
+	return incoming, DiffPolicies(current, incoming), nil
+}
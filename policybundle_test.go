@@ -0,0 +1,70 @@
+package salary
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestPolicyBundleRoundTripAndDiff(t *testing.T) {
+	current := RulesPack{
+		Version: "2024.01",
+		Regions: map[string]RegionPolicy{
+			"shanghai": {City: "shanghai", PensionRate: decimal.RequireFromString("0.08"), HousingFundRate: decimal.RequireFromString("0.07")},
+			"beijing":  {City: "beijing", PensionRate: decimal.RequireFromString("0.08")},
+		},
+		Limits: map[string]Money{"rent": toMoney(cenToDec(150000))},
+	}
+	var buf bytes.Buffer
+	if err := ExportPolicyBundle(&buf, current); err != nil {
+		t.Fatal(err)
+	}
+	if _, diff, err := ImportPolicyBundle(bytes.NewReader(buf.Bytes()), current); err != nil || len(diff) != 0 || diff.String() != "无变更\n" {
+		t.Fatalf("round trip diff = %v, err = %v", diff, err)
+	}
+
+	incoming := current
+	incoming.Version = "2024.07"
+	incoming.Regions = map[string]RegionPolicy{
+		"shanghai": {City: "shanghai", PensionRate: decimal.RequireFromString("0.08"), HousingFundRate: decimal.RequireFromString("0.05"),
+			Base: BaseLimits{Ceiling: toMoney(cenToDec(3655000))}},
+	}
+	incoming.Limits = map[string]Money{"rent": toMoney(cenToDec(150000))}
+	buf.Reset()
+	ExportPolicyBundle(&buf, incoming)
+	pack, diff, err := ImportPolicyBundle(&buf, current)
+	if err != nil || pack.Version != "2024.07" {
+		t.Fatalf("pack = %+v, err = %v", pack, err)
+	}
+
+	text := diff.String()
+	for _, want := range []string{
+		"~ version: 2024.01 -> 2024.07\n",
+		"~ regions.shanghai.housing_fund_rate: 0.07 -> 0.05\n",
+		"+ regions.shanghai.base.ceiling: " + FormatMoneyCenToYuan(toMoney(cenToDec(3655000))) + "\n",
+		"- regions.beijing.pension_rate: 0.08\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("diff missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "limits.rent") || strings.Contains(text, "regions.shanghai.pension_rate") {
+		t.Errorf("unchanged values should not appear:\n%s", text)
+	}
+	for i := 1; i < len(diff); i++ {
+		if diff[i-1].Path > diff[i].Path {
+			t.Fatalf("diff not sorted: %v", diff)
+		}
+	}
+}
+
+func TestImportPolicyBundleValidates(t *testing.T) {
+	if _, _, err := ImportPolicyBundle(strings.NewReader(`{"regions": {}}`), RulesPack{}); err == nil {
+		t.Error("bundle without version should fail")
+	}
+	if _, _, err := ImportPolicyBundle(strings.NewReader(`{`), RulesPack{}); err == nil {
+		t.Error("malformed JSON should fail")
+	}
+}
@@ -0,0 +1,181 @@
+package salary
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// portalLabels 员工端工资条的多语言标签
+var portalLabels = map[string]map[string]string{
+	"zh-CN": {
+		"earnings": "收入", "deductions": "扣款", "benefits": "非现金福利（仅计税）", "summary": "合计",
+		"base": "基础工资", "overtime": "加班工资", "social_insurance": "社会保险",
+		"housing_fund": "住房公积金", "income_tax": "个人所得税", "gross": "税前工资", "net": "实发工资",
+	},
+	"en": {
+		"earnings": "Earnings", "deductions": "Deductions", "benefits": "Benefits in kind (taxable, non-cash)", "summary": "Summary",
+		"base": "Base pay", "overtime": "Overtime", "social_insurance": "Social insurance",
+		"housing_fund": "Housing fund", "income_tax": "Income tax", "gross": "Gross pay", "net": "Net pay",
+	},
+}
+
+// portalLabel 查找标签，未知语言使用中文
+func portalLabel(locale, key string) string {
+	labels, ok := portalLabels[locale]
+	if !ok {
+		labels = portalLabels["zh-CN"]
+	}
+	if label, ok := labels[key]; ok {
+		return label
+	}
+	return key
+}
+
+// PortalLine 员工端工资条的一行
+type PortalLine struct {
+	Key    string `json:"key"`             // 项目标识
+	Label  string `json:"label"`           // 显示标签
+	Amount Money  `json:"amount"`          // 金额（分）
+	Text   string `json:"text"`            // 格式化后的金额
+	Delta  *Money `json:"delta,omitempty"` // 与上月相比的变化（分），无上月数据时省略
+}
+
+// PortalSection 员工端工资条的分组
+type PortalSection struct {
+	Key   string       `json:"key"`   // 分组标识：earnings、deductions、benefits、summary
+	Title string       `json:"title"` // 分组标题
+	Lines []PortalLine `json:"lines"` // 明细
+}
+
+// PortalBundle 面向移动端的精简工资条数据包
+type PortalBundle struct {
+	EmployeeID      string           `json:"employee_id"`                // 工号
+	Name            string           `json:"name"`                       // 姓名
+	Period          string           `json:"period"`                     // 薪资期，如 2024-05
+	Locale          string           `json:"locale"`                     // 标签语言
+	NetPay          Money            `json:"net_pay"`                    // 实发工资（分）
+	NetPayText      string           `json:"net_pay_text"`               // 格式化后的实发工资
+	Sections        []PortalSection  `json:"sections"`                   // 分组明细
+	Announcements   []Announcement   `json:"announcements"`              // 本期公告
+	SocialInsurance *PensionEstimate `json:"social_insurance,omitempty"` // 社保一览：养老保险累计缴费和养老金估算，未开启时省略
+}
+
+// portalItem 工资结果中的一个项目
+type portalItem struct {
+	key    string
+	amount Money
+}
+
+// portalItems 按分组列出工资结果中的项目，周期性项目和调整以项目代码为标识
+func portalItems(r EmployeeResult) map[string][]portalItem {
+	items := map[string][]portalItem{
+		"earnings": {
+			{"base", r.BaseSalary},
+			{"overtime", r.OvertimePay},
+		},
+		"deductions": {
+			{"social_insurance", r.SocialInsurance},
+			{"housing_fund", r.HousingFund},
+			{"income_tax", r.IncomeTax},
+		},
+		"summary": {
+			{"gross", r.GrossSalary},
+			{"net", r.NetSalary},
+		},
+	}
+	for _, line := range r.Lines {
+		section := "earnings"
+		switch line.Kind {
+		case KindDeduction:
+			section = "deductions"
+		case KindBenefitInKind:
+			section = "benefits"
+		}
+		items[section] = append(items[section], portalItem{key: line.Code, amount: line.Amount})
+	}
+	return items
+}
+
+// BuildPortalBundle 生成员工端工资条数据包，包含分组明细、本地化标签和与上月的变化
+// current: 本期结果
+// previous: 上期结果，为空表示不计算变化
+// locale: 标签语言，支持 zh-CN、en
+// announcements: 本期公告
+func BuildPortalBundle(current EmployeeResult, previous *EmployeeResult, locale string, announcements []Announcement) PortalBundle {
+	if _, ok := portalLabels[locale]; !ok {
+		locale = "zh-CN"
+	}
+
+	// 上期各项目金额，用于计算变化
+	var last map[string]decimal.Decimal
+	if previous != nil {
+		last = make(map[string]decimal.Decimal)
+		for _, items := range portalItems(*previous) {
+			for _, item := range items {
+				last[item.key] = last[item.key].Add(moneyToDec(item.amount))
+			}
+		}
+	}
+
+	// 周期性项目和调整使用工资条上的名称
+	names := make(map[string]string, len(current.Lines))
+	for _, line := range current.Lines {
+		names[line.Code] = line.Name
+	}
+
+	items := portalItems(current)
+	bundle := PortalBundle{
+		EmployeeID: current.Employee.ID,
+		Name:       current.Employee.Name,
+		Period:     current.Period.Format("2006-01"),
+		Locale:     locale,
+		NetPay:     current.NetSalary,
+		NetPayText: FormatMoneyCenToYuan(current.NetSalary),
+	}
+	for _, key := range []string{"earnings", "deductions", "benefits", "summary"} {
+		section := PortalSection{Key: key, Title: portalLabel(locale, key)}
+		for _, item := range items[key] {
+			label, ok := names[item.key]
+			if !ok {
+				label = portalLabel(locale, item.key)
+			}
+			line := PortalLine{Key: item.key, Label: label, Amount: item.amount, Text: FormatMoneyCenToYuan(item.amount)}
+			if last != nil {
+				delta := toMoney(moneyToDec(item.amount).Sub(last[item.key]))
+				line.Delta = &delta
+			}
+			section.Lines = append(section.Lines, line)
+		}
+		if len(section.Lines) == 0 {
+			continue
+		}
+		bundle.Sections = append(bundle.Sections, section)
+	}
+	for _, a := range announcements {
+		if a.appliesTo(current.Period, current.Employee) {
+			bundle.Announcements = append(bundle.Announcements, a)
+		}
+	}
+	return bundle
+}
+
+// handlePortalBundle 员工端工资条接口：GET /employees/{id}/payslips/{period}?locale=en
+func (s *Server) handlePortalBundle(w http.ResponseWriter, r *http.Request) {
+	period, err := time.Parse("2006-01", r.PathValue("period"))
+	if err != nil {
+		writeError(w, fmt.Errorf("薪资期格式应为 YYYY-MM: %w", err))
+		return
+	}
+
+	current, previous, announcements, ok := s.store.payslipContext(r.PathValue("id"), period)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]any{"code": CodeRunNotFound, "error": "未找到该员工本期工资结果"})
+		return
+	}
+	bundle := BuildPortalBundle(current, previous, r.URL.Query().Get("locale"), announcements)
+	bundle.SocialInsurance = s.pensionOverview(current)
+	writeJSON(w, http.StatusOK, bundle)
+}
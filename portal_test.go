@@ -0,0 +1,99 @@
+package salary
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func portalResult(period string, base, net int64) EmployeeResult {
+	return EmployeeResult{
+		Employee:    Employee{ID: "E1", Name: "张三", Department: "研发部"},
+		Period:      day(period),
+		BaseSalary:  toMoney(cenToDec(base)),
+		GrossSalary: toMoney(cenToDec(base)),
+		NetSalary:   toMoney(cenToDec(net)),
+	}
+}
+
+// portalLine 查找数据包中某分组的某一行
+func portalLine(t *testing.T, bundle PortalBundle, section, key string) PortalLine {
+	t.Helper()
+	for _, s := range bundle.Sections {
+		if s.Key != section {
+			continue
+		}
+		for _, line := range s.Lines {
+			if line.Key == key {
+				return line
+			}
+		}
+	}
+	t.Fatalf("line %s/%s not found in %+v", section, key, bundle.Sections)
+	return PortalLine{}
+}
+
+func TestBuildPortalBundle(t *testing.T) {
+	previous := portalResult("2024-04-01", 800000, 650000)
+	current := portalResult("2024-05-01", 850000, 690000)
+	current.Lines = []PayLine{{Code: "MEAL", Name: "餐补", Kind: KindEarning, Amount: toMoney(cenToDec(50000))}}
+
+	bundle := BuildPortalBundle(current, &previous, "en", []Announcement{
+		{Title: "通知", Body: "五一假期安排"},
+		{Title: "销售提成", Departments: []string{"销售部"}},
+	})
+	if bundle.Period != "2024-05" || bundle.Locale != "en" || bundle.NetPayText != FormatMoneyCenToYuan(current.NetSalary) {
+		t.Errorf("bundle = %+v", bundle)
+	}
+	// 无非现金福利时省略该分组
+	if len(bundle.Sections) != 3 || bundle.Sections[0].Title != "Earnings" {
+		t.Errorf("sections = %+v", bundle.Sections)
+	}
+	base := portalLine(t, bundle, "earnings", "base")
+	if base.Label != "Base pay" || base.Delta == nil {
+		t.Fatalf("base = %+v", base)
+	}
+	assertMoney(t, "base delta", *base.Delta, "50000")
+	meal := portalLine(t, bundle, "earnings", "MEAL")
+	if meal.Label != "餐补" {
+		t.Errorf("meal label = %q", meal.Label)
+	}
+	assertMoney(t, "new item delta", *meal.Delta, "50000")
+	if len(bundle.Announcements) != 1 || bundle.Announcements[0].Title != "通知" {
+		t.Errorf("announcements = %+v", bundle.Announcements)
+	}
+
+	// 未知语言使用中文，无上期数据不输出变化
+	bundle = BuildPortalBundle(current, nil, "fr", nil)
+	if bundle.Locale != "zh-CN" || portalLine(t, bundle, "summary", "net").Label != "实发工资" || portalLine(t, bundle, "summary", "net").Delta != nil {
+		t.Errorf("fallback bundle = %+v", bundle)
+	}
+}
+
+func TestHandlePortalBundle(t *testing.T) {
+	store := NewMemoryStore()
+	store.SaveRun(&PayrollResult{Period: day("2024-04-01"), Employees: []EmployeeResult{portalResult("2024-04-01", 800000, 650000)}})
+	store.SaveRun(&PayrollResult{Period: day("2024-05-01"), Employees: []EmployeeResult{portalResult("2024-05-01", 850000, 690000)}})
+	server := NewServer(store)
+
+	rec := serve(server, http.MethodGet, "/employees/E1/payslips/2024-05?locale=en", "", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+	var bundle PortalBundle
+	if err := json.NewDecoder(rec.Body).Decode(&bundle); err != nil {
+		t.Fatal(err)
+	}
+	net := portalLine(t, bundle, "summary", "net")
+	if net.Delta == nil {
+		t.Fatalf("net = %+v", net)
+	}
+	assertMoney(t, "net delta", *net.Delta, "40000")
+
+	if rec := serve(server, http.MethodGet, "/employees/E1/payslips/2024-06", "", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("missing period status = %d", rec.Code)
+	}
+	if rec := serve(server, http.MethodGet, "/employees/E1/payslips/202405", "", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("bad period status = %d", rec.Code)
+	}
+}
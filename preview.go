@@ -0,0 +1,125 @@
+package salary
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrNoRunInputs 批次未保存计算输入，无法重算预览
+var ErrNoRunInputs = errors.New("批次未保存计算输入")
+
+// FieldBaseSalary 预览时可修改的基本工资字段，值为元
+const FieldBaseSalary = "BaseSalary"
+
+// PreviewChange 预览的单个字段修改
+type PreviewChange struct {
+	Field string `json:"field"` // 考勤字段（如 OvertimeWeekday）或 BaseSalary
+	Value string `json:"value"` // 新值：考勤字段为小时，基本工资为元
+}
+
+// apply 将修改应用到员工输入的副本
+func (c PreviewChange) apply(input EmployeeInput) (EmployeeInput, error) {
+	if c.Field == FieldBaseSalary {
+		salary, err := ParseMoney(c.Value)
+		if err != nil {
+			return input, fmt.Errorf("%s: %w", c.Field, err)
+		}
+		if moneyToDec(salary).IsNegative() {
+			return input, fmt.Errorf("%s: 金额不能为负数", c.Field)
+		}
+		input.Config.BaseSalary = salary
+		return input, nil
+	}
+	value, err := AttendanceField(c.Field).field(&input.Attendance)
+	if err != nil {
+		return input, err
+	}
+	h, err := ParseHours(c.Value)
+	if err != nil {
+		return input, fmt.Errorf("%s: %w", c.Field, err)
+	}
+	*value = h
+	return input, nil
+}
+
+// EmployeePreview 单个字段修改后的重算预览，不保存
+type EmployeePreview struct {
+	Change    PreviewChange  // 修改内容
+	Before    EmployeeResult // 按批次保存的输入重算的原结果，与 After 口径一致
+	After     EmployeeResult // 修改后的重算结果
+	NetChange Money          // 实发工资变化（分）
+}
+
+// PreviewEmployee 按批次保存的计算输入修改单个字段后重算员工工资，不修改批次
+// 修改前后都从已应用批次级设置的输入重算，并经过同一套批次后处理，纳税人身份沿用原结果；
+// 欠款台账不随批次保存，原结果的欠款抵扣额记入临时台账作为欠款余额，修改前后各自按实发工资重新抵扣，
+// 原结果因实发工资不足只抵扣部分欠款时按已抵扣额估算
+// run: 发薪批次，需包含计算输入
+// employeeID: 工号
+// change: 修改内容
+func PreviewEmployee(run PayrollResult, employeeID string, change PreviewChange) (EmployeePreview, error) {
+	if len(run.Inputs) == 0 {
+		return EmployeePreview{}, fmt.Errorf("%w: %s", ErrNoRunInputs, run.ID)
+	}
+	var stored *EmployeeResult
+	for i := range run.Employees {
+		if run.Employees[i].Employee.ID == employeeID {
+			stored = &run.Employees[i]
+		}
+	}
+	var input *EmployeeInput
+	for i := range run.Inputs {
+		if run.Inputs[i].Employee.ID == employeeID {
+			input = &run.Inputs[i]
+		}
+	}
+	if stored == nil || input == nil {
+		return EmployeePreview{}, fmt.Errorf("%w: 批次 %s 中没有员工 %s", ErrEmployeeNotFound, run.ID, employeeID)
+	}
+
+	changed, err := change.apply(*input)
+	if err != nil {
+		return EmployeePreview{}, err
+	}
+	recalculate := func(input EmployeeInput) EmployeeResult {
+		scratch := PayrollRun{Period: run.Period}
+		if moneyToDec(stored.ReceivableRecovered).IsPositive() || moneyToDec(stored.ReceivableAccrued).IsPositive() {
+			scratch.Receivables = NewReceivableLedger()
+			scratch.Receivables.Accrue(employeeID, run.Period, stored.ReceivableRecovered)
+		}
+		result := CalculateEmployee(run.Period, input)
+		result.TaxResidency = stored.TaxResidency
+		scratch.finalize(input, &result)
+		return result
+	}
+	before, after := recalculate(*input), recalculate(changed)
+	return EmployeePreview{
+		Change:    change,
+		Before:    before,
+		After:     after,
+		NetChange: toMoney(moneyToDec(after.NetSalary).Sub(moneyToDec(before.NetSalary))),
+	}, nil
+}
+
+// handlePreviewEmployee 单字段修改的重算预览：POST /runs/{id}/employees/{employee}/preview
+// 请求体：{"field": "OvertimeWeekday", "value": "11"}，返回修改前后的结果，不保存
+func (s *Server) handlePreviewEmployee(w http.ResponseWriter, r *http.Request) {
+	var change PreviewChange
+	if err := json.NewDecoder(r.Body).Decode(&change); err != nil {
+		writeError(w, fmt.Errorf("请求格式错误: %w", err))
+		return
+	}
+	run, err := s.store.Run(r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	preview, err := PreviewEmployee(run, r.PathValue("employee"), change)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, preview)
+}
@@ -0,0 +1,80 @@
+package salary
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestPreviewEmployeeOvertime(t *testing.T) {
+	run := PayrollRun{
+		Period: day("2024-03-01"),
+		Inputs: []EmployeeInput{
+			{Employee: Employee{ID: "E1"}, Config: testConfig(), Attendance: AttendanceRecord{WorkHours: hours("174"), OvertimeWeekday: hours("10")}},
+		},
+	}
+	result := run.Calculate()
+
+	preview, err := PreviewEmployee(result, "E1", PreviewChange{Field: string(FieldOvertimeWeekday), Value: "11"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 1小时工作日加班 = 8000 / 174 × 1.5 ≈ 68.97元
+	assertMoney(t, "overtime delta", toMoney(moneyToDec(preview.After.OvertimePay).Sub(moneyToDec(preview.Before.OvertimePay)).Round(2)), "6896.55")
+	if !moneyToDec(preview.NetChange).IsPositive() {
+		t.Errorf("net change = %s, want positive", FormatMoneyCenToYuan(preview.NetChange))
+	}
+	if !hoursToDec(result.Inputs[0].Attendance.OvertimeWeekday).Equal(hoursToDec(hours("10"))) {
+		t.Error("preview must not modify the run inputs")
+	}
+}
+
+func TestPreviewEmployeeErrors(t *testing.T) {
+	run := PayrollRun{Period: day("2024-03-01"), Inputs: []EmployeeInput{{Employee: Employee{ID: "E1"}, Config: testConfig()}}}
+	result := run.Calculate()
+	if _, err := PreviewEmployee(result, "E2", PreviewChange{Field: FieldBaseSalary, Value: "9000"}); !errors.Is(err, ErrEmployeeNotFound) {
+		t.Errorf("err = %v, want ErrEmployeeNotFound", err)
+	}
+	if _, err := PreviewEmployee(result, "E1", PreviewChange{Field: "Bonus", Value: "1"}); err == nil {
+		t.Error("expected unknown field error")
+	}
+	result.Inputs = nil
+	if _, err := PreviewEmployee(result, "E1", PreviewChange{Field: FieldBaseSalary, Value: "9000"}); !errors.Is(err, ErrNoRunInputs) {
+		t.Errorf("err = %v, want ErrNoRunInputs", err)
+	}
+}
+
+func TestPreviewEmployeeWithReceivables(t *testing.T) {
+	receivables := NewReceivableLedger()
+	receivables.Accrue("E1", day("2024-02-01"), toMoney(cenToDec(50000)))
+	run := PayrollRun{
+		Period:      day("2024-03-01"),
+		Receivables: receivables,
+		Inputs: []EmployeeInput{
+			{Employee: Employee{ID: "E1"}, Config: testConfig(), Attendance: AttendanceRecord{WorkHours: hours("174"), OvertimeWeekday: hours("10")}},
+		},
+	}
+	result := run.Calculate()
+	stored := result.Employees[0]
+	assertMoney(t, "stored recovered", stored.ReceivableRecovered, "50000")
+
+	preview, err := PreviewEmployee(result, "E1", PreviewChange{Field: string(FieldOvertimeWeekday), Value: "11"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 修改前后都抵扣同样的欠款，实发变化只反映加班的变化
+	assertMoney(t, "before net", preview.Before.NetSalary, moneyToDec(stored.NetSalary).String())
+	assertMoney(t, "before recovered", preview.Before.ReceivableRecovered, "50000")
+	assertMoney(t, "after recovered", preview.After.ReceivableRecovered, "50000")
+	raw := func(value string) decimal.Decimal {
+		input := result.Inputs[0]
+		input.Attendance.OvertimeWeekday = hours(value)
+		return moneyToDec(CalculateEmployee(result.Period, input).NetSalary)
+	}
+	assertMoney(t, "net change", preview.NetChange, raw("11").Sub(raw("10")).String())
+	// 预览不影响真实台账
+	if !moneyToDec(receivables.Balance("E1")).IsZero() {
+		t.Errorf("ledger balance = %s", moneyToDec(receivables.Balance("E1")))
+	}
+}
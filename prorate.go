@@ -0,0 +1,89 @@
+package salary
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ProrationMethod 入职、离职当月基本工资的折算方式
+type ProrationMethod int
+
+const (
+	ProrateInherit      ProrationMethod = iota // 未指定：员工输入沿用批次设置，批次未设置时不折算
+	ProrateNone                                // 不折算，按考勤工时计算基础工资；员工输入指定时不沿用批次设置
+	ProrateWorkDays                            // 月工资 ÷ 21.75 × 在职期间的工作日数，工作日按日历扣除周末和法定节假日
+	ProrateCalendarDays                        // 月工资 × 在职自然日数 ÷ 当月自然日数
+)
+
+// prorates 是否按在职天数折算
+func (m ProrationMethod) prorates() bool {
+	return m == ProrateWorkDays || m == ProrateCalendarDays
+}
+
+// Proration 入职、离职当月的基本工资折算明细
+type Proration struct {
+	Method       ProrationMethod // 折算方式
+	EmployedDays decimal.Decimal // 在职期间的计薪天数（工作日或自然日）
+	BasisDays    decimal.Decimal // 折算基数：21.75 或当月自然日数
+}
+
+// employedRange 员工在薪资期内的在职区间 [from, to)，入职、离职当天均计为在职
+// 返回值: (起始日, 结束日的次日, 是否为不满整月的薪资期)
+func employedRange(period time.Time, e Employee) (time.Time, time.Time, bool) {
+	start := monthStart(period)
+	end := start.AddDate(0, 1, 0)
+	from, to := start, end
+	if !e.HireDate.IsZero() && dateOnly(e.HireDate).After(from) {
+		from = dateOnly(e.HireDate)
+	}
+	if !e.TerminationDate.IsZero() && dateOnly(e.TerminationDate).AddDate(0, 0, 1).Before(to) {
+		to = dateOnly(e.TerminationDate).AddDate(0, 0, 1)
+	}
+	return from, to, !from.Equal(start) || !to.Equal(end)
+}
+
+// ProrateBaseSalary 按折算方式计算入职、离职当月的基本工资
+// 按工作日折算时不超过整月工资；整月在职或不折算时返回 (整月基本工资, nil)
+// config: 薪资配置
+// period: 薪资期
+// e: 员工档案，使用入职和离职日期
+// method: 折算方式
+// calendar: 节假日日历，为空时只扣除周末
+func ProrateBaseSalary(config PayrollConfig, period time.Time, e Employee, method ProrationMethod, calendar *Calendar) (Money, *Proration) {
+	from, to, partial := employedRange(period, e)
+	if !method.prorates() || !partial {
+		return config.BaseSalary, nil
+	}
+	monthly := moneyToDec(config.BaseSalary)
+	proration := &Proration{Method: method, EmployedDays: decimal.Zero, BasisDays: MonthlyWorkDays}
+	if method == ProrateCalendarDays {
+		start := monthStart(period)
+		proration.BasisDays = decimal.NewFromInt(int64(daysBetween(start, start.AddDate(0, 1, 0))))
+		if from.Before(to) {
+			proration.EmployedDays = decimal.NewFromInt(int64(daysBetween(from, to)))
+		}
+		return toMoney(monthly.Mul(proration.EmployedDays).Div(proration.BasisDays).Round(2)), proration
+	}
+
+	workDays := 0
+	for d := from; d.Before(to); d = d.AddDate(0, 0, 1) {
+		if !isRestDay(d, calendar) {
+			workDays++
+		}
+	}
+	proration.EmployedDays = decimal.NewFromInt(int64(workDays))
+	pay := decimal.Min(monthly.Div(MonthlyWorkDays).Mul(proration.EmployedDays), monthly)
+	return toMoney(pay.Round(2)), proration
+}
+
+// proratedBaseSalary 计算员工输入的基础工资
+// 开启折算时按月薪计发，不满整月的薪资期按在职天数折算，再按小时工资扣除缺勤；未开启时按考勤工时计算
+func proratedBaseSalary(period time.Time, input EmployeeInput) (Money, *Proration) {
+	if !input.Proration.prorates() {
+		return CalculateBaseSalary(input.Config, input.Attendance), nil
+	}
+	pay, proration := ProrateBaseSalary(input.Config, period, input.Employee, input.Proration, input.Calendar)
+	absence := attendanceHourlyRate(input.Config, input.Attendance).Mul(hoursToDec(input.Attendance.AbsenceHours))
+	return toMoney(decimal.Max(moneyToDec(pay).Sub(absence), decimal.Zero)), proration
+}
@@ -0,0 +1,98 @@
+package salary
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestProrateBaseSalaryCalendarDays(t *testing.T) {
+	e := Employee{ID: "E1", HireDate: day("2024-01-15")}
+	pay, proration := ProrateBaseSalary(testConfig(), day("2024-01-01"), e, ProrateCalendarDays, nil)
+	assertMoney(t, "base", pay, "438709.68")
+	if !proration.EmployedDays.Equal(decimal.NewFromInt(17)) || !proration.BasisDays.Equal(decimal.NewFromInt(31)) {
+		t.Errorf("proration = %+v", proration)
+	}
+}
+
+func TestProrateBaseSalaryWorkDaysSkipsHolidays(t *testing.T) {
+	calendar := NewCalendar()
+	calendar.AddHoliday(day("2024-01-22"))
+	e := Employee{ID: "E1", HireDate: day("2024-01-15")}
+	pay, proration := ProrateBaseSalary(testConfig(), day("2024-01-01"), e, ProrateWorkDays, calendar)
+	assertMoney(t, "base", pay, "441379.31")
+	if !proration.EmployedDays.Equal(decimal.NewFromInt(12)) {
+		t.Errorf("employed days = %s, want 12", proration.EmployedDays)
+	}
+}
+
+func TestCalculateEmployeeProratesTermination(t *testing.T) {
+	input := EmployeeInput{
+		Employee:  Employee{ID: "E1", TerminationDate: day("2024-03-10")},
+		Config:    testConfig(),
+		Proration: ProrateCalendarDays,
+	}
+	result := CalculateEmployee(day("2024-03-01"), input)
+	assertMoney(t, "base", result.BaseSalary, "258064.52")
+	if result.Proration == nil {
+		t.Fatal("expected proration details")
+	}
+
+	input.Employee.TerminationDate = day("2024-04-10")
+	result = CalculateEmployee(day("2024-03-01"), input)
+	assertMoney(t, "full month", result.BaseSalary, "800000")
+	if result.Proration != nil {
+		t.Errorf("full month should not be prorated: %+v", result.Proration)
+	}
+}
+
+func TestEmployeeProrationOverridesRun(t *testing.T) {
+	hire := Employee{ID: "E1", HireDate: day("2024-01-15")}
+	run := PayrollRun{
+		Period:    day("2024-01-01"),
+		Proration: ProrateCalendarDays,
+		Inputs: []EmployeeInput{
+			{Employee: hire, Config: testConfig(), Attendance: AttendanceRecord{WorkHours: hours("174")}},
+			{Employee: Employee{ID: "E2", HireDate: hire.HireDate}, Config: testConfig(), Attendance: AttendanceRecord{WorkHours: hours("174")}, Proration: ProrateNone},
+		},
+	}
+	result := run.Calculate()
+	if len(result.Errors) > 0 {
+		t.Fatal(result.Errors)
+	}
+	// 未指定的员工沿用批次按自然日折算：8000 × 17 ÷ 31
+	assertMoney(t, "inherited", result.Employees[0].BaseSalary, "438709.68")
+	// 指定不折算的员工按考勤工时计算整月工资
+	assertMoney(t, "opted out", toMoney(moneyToDec(result.Employees[1].BaseSalary).Round(2)), "800000")
+	if result.Employees[1].Proration != nil {
+		t.Errorf("opted-out employee was prorated: %+v", result.Employees[1].Proration)
+	}
+}
+
+func TestStatutoryJurisdictionProration(t *testing.T) {
+	config := testConfig()
+	config.BaseSalary = toMoney(cenToDec(3100000))
+	input := EmployeeInput{
+		Employee:  Employee{ID: "HK1", Jurisdiction: JurisdictionHK, HireDate: day("2024-01-15")},
+		Config:    config,
+		Proration: ProrateCalendarDays,
+	}
+	result := CalculateEmployee(day("2024-01-01"), input)
+	// 31,000 × 17 ÷ 31 = 17,000
+	assertMoney(t, "base", result.BaseSalary, "1700000")
+	assertMoney(t, "gross", result.GrossSalary, "1700000")
+	if result.Proration == nil || !result.Proration.EmployedDays.Equal(decimal.NewFromInt(17)) {
+		t.Errorf("proration = %+v", result.Proration)
+	}
+	// 强积金按折算后的有关入息计算：17,000 × 5% = 850
+	if len(result.Statutory) == 0 {
+		t.Fatal("no statutory lines")
+	}
+	assertMoney(t, "MPF", result.Statutory[0].Employee, "85000")
+
+	// 中国内地员工的个税在职比例与折算区间一致
+	employed := employedFraction(day("2024-01-01"), input.Employee)
+	if !employed.Mul(decimal.NewFromInt(31)).Round(0).Equal(decimal.NewFromInt(17)) {
+		t.Errorf("employed fraction = %s, want 17/31", employed)
+	}
+}
@@ -0,0 +1,110 @@
+package salary
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ExternalDeduction 外部平台（如弹性福利平台）推送的员工月度扣款
+type ExternalDeduction struct {
+	EmployeeID string `json:"employee_id"` // 工号
+	Code       string `json:"code"`        // 扣款项目代码
+	Name       string `json:"name"`        // 扣款项目名称，如 健身房会员
+	Amount     Money  `json:"amount"`      // 金额（分）
+	PreTax     bool   `json:"pre_tax"`     // 是否税前扣除
+}
+
+// DeductionProvider 外部扣款提供方
+type DeductionProvider interface {
+	// Deductions 返回指定薪资期需要扣除的全部员工扣款
+	Deductions(ctx context.Context, period time.Time) ([]ExternalDeduction, error)
+}
+
+// HTTPDeductionProvider 通过HTTP接口获取扣款的参考实现
+// 请求：GET {Endpoint}?period=2024-05，携带 Authorization: Bearer {Token}
+// 响应：{"deductions": [{"employee_id": "E001", "code": "GYM", "name": "健身房会员", "amount": "9900", "pre_tax": false}]}
+type HTTPDeductionProvider struct {
+	Endpoint string       // 接口地址
+	Token    string       // 访问令牌，为空表示不鉴权
+	Client   *http.Client // HTTP客户端，为空时使用默认客户端
+}
+
+// Deductions 调用外部接口获取扣款
+func (p HTTPDeductionProvider) Deductions(ctx context.Context, period time.Time) ([]ExternalDeduction, error) {
+	u, err := url.Parse(p.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	query := u.Query()
+	query.Set("period", period.Format("2006-01"))
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if p.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.Token)
+	}
+
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("扣款接口返回HTTP状态 %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Deductions []ExternalDeduction `json:"deductions"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("扣款接口响应格式错误: %w", err)
+	}
+	return body.Deductions, nil
+}
+
+// CollectDeductions 从外部提供方获取本期扣款并计入对应员工
+// 每笔扣款作为仅在本期生效的扣款项目加入员工输入；找不到员工的扣款返回错误，不影响其他扣款
+func (r *PayrollRun) CollectDeductions(ctx context.Context, providers ...DeductionProvider) error {
+	index := make(map[string]int, len(r.Inputs))
+	for i, input := range r.Inputs {
+		index[input.Employee.ID] = i
+	}
+
+	var errs []error
+	for _, provider := range providers {
+		deductions, err := provider.Deductions(ctx, r.Period)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, d := range deductions {
+			i, ok := index[d.EmployeeID]
+			if !ok {
+				errs = append(errs, fmt.Errorf("外部扣款 %s 对应的员工 %s 不在本批次中", d.Code, d.EmployeeID))
+				continue
+			}
+			r.Inputs[i].Elements = append(r.Inputs[i].Elements, PayElement{
+				Code:        d.Code,
+				Name:        d.Name,
+				Kind:        KindDeduction,
+				Amount:      d.Amount,
+				Taxable:     d.PreTax,
+				Start:       r.Period,
+				Occurrences: 1,
+			})
+		}
+	}
+	return errors.Join(errs...)
+}
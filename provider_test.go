@@ -0,0 +1,64 @@
+package salary
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTPDeductionProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Query().Get("period") != "2024-05" {
+			http.Error(w, "bad period", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"deductions": [
+			{"employee_id": "E1", "code": "GYM", "name": "健身房会员", "amount": "9900", "pre_tax": false},
+			{"employee_id": "E9", "code": "GYM", "name": "健身房会员", "amount": "9900", "pre_tax": false}
+		]}`))
+	}))
+	defer srv.Close()
+
+	provider := HTTPDeductionProvider{Endpoint: srv.URL + "/deductions", Token: "secret"}
+	deductions, err := provider.Deductions(context.Background(), day("2024-05-01"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deductions) != 2 || deductions[0].Code != "GYM" {
+		t.Fatalf("deductions = %+v", deductions)
+	}
+	assertMoney(t, "amount", deductions[0].Amount, "9900")
+
+	unauthorized := HTTPDeductionProvider{Endpoint: srv.URL + "/deductions"}
+	if _, err := unauthorized.Deductions(context.Background(), day("2024-05-01")); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("err = %v, want HTTP 401", err)
+	}
+
+	// 找不到员工的扣款报错，其余扣款照常计入
+	run := PayrollRun{
+		Period: day("2024-05-01"),
+		Inputs: []EmployeeInput{{
+			Employee: Employee{ID: "E1"}, Config: testConfig(),
+			Attendance: AttendanceRecord{WorkHours: hours("174")},
+		}},
+	}
+	err = run.CollectDeductions(context.Background(), provider)
+	if err == nil || !strings.Contains(err.Error(), "E9") {
+		t.Errorf("err = %v, want unknown employee reported", err)
+	}
+	employee := run.Calculate().Employees[0]
+	if len(employee.Lines) != 1 || employee.Lines[0].Code != "GYM" || employee.Lines[0].Kind != KindDeduction {
+		t.Fatalf("lines = %+v", employee.Lines)
+	}
+	// 下一期不再扣
+	run.Period = day("2024-06-01")
+	if lines := run.Calculate().Employees[0].Lines; len(lines) != 0 {
+		t.Errorf("June lines = %+v", lines)
+	}
+}
@@ -0,0 +1,286 @@
+package salary
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ScheduledShift 排班班次，时间均为当天0点起的分钟数
+type ScheduledShift struct {
+	Code             string          // 班次代码，如 D（白班）、N（夜班）
+	Name             string          // 班次名称，差额津贴以此名称列示在工资条上
+	StartMinute      int             // 上班时间，如9:00为540
+	EndMinute        int             // 下班时间，小于等于上班时间表示跨午夜
+	BreakStartMinute int             // 班次内休息开始时间，如12:00为720
+	BreakMinutes     int             // 休息时长，不计工时，0表示无休息
+	Differential     decimal.Decimal // 班次差额津贴比例，如夜班0.3表示按小时工资的30%补贴班次内的正常工时，0表示无津贴
+}
+
+// window 返回班次在考勤日期的上下班时间
+func (s ScheduledShift) window(date time.Time) (time.Time, time.Time) {
+	start := date.Add(time.Duration(s.StartMinute) * time.Minute)
+	end := date.Add(time.Duration(s.EndMinute) * time.Minute)
+	if s.EndMinute <= s.StartMinute {
+		end = end.AddDate(0, 0, 1)
+	}
+	return start, end
+}
+
+// breakWindow 返回班次在考勤日期的休息时段，休息开始早于上班时间时视为次日
+func (s ScheduledShift) breakWindow(date time.Time) (time.Time, time.Time) {
+	start := date.Add(time.Duration(s.BreakStartMinute) * time.Minute)
+	if s.BreakStartMinute < s.StartMinute {
+		start = start.AddDate(0, 0, 1)
+	}
+	return start, start.Add(time.Duration(s.BreakMinutes) * time.Minute)
+}
+
+// Minutes 返回班次的应出勤分钟数（扣除休息）
+func (s ScheduledShift) Minutes() int {
+	start, end := s.window(time.Time{})
+	return max(int(end.Sub(start)/time.Minute)-s.BreakMinutes, 0)
+}
+
+// PunchPair 一次上班打卡和下班打卡
+type PunchPair struct {
+	EmployeeID string    // 工号
+	Date       time.Time // 考勤日期，跨午夜班次为上班当天；零值按上班打卡日期
+	ClockIn    time.Time // 上班打卡时间
+	ClockOut   time.Time // 下班打卡时间
+}
+
+// date 返回打卡所属的考勤日期
+func (p PunchPair) date() time.Time {
+	d := p.Date
+	if d.IsZero() {
+		d = p.ClockIn
+	}
+	return time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, d.Location())
+}
+
+// overlapMinutes 返回打卡区间与时段重叠的分钟数
+func overlapMinutes(pairs []PunchPair, from, to time.Time) int {
+	total := 0
+	for _, p := range pairs {
+		start, end := p.ClockIn, p.ClockOut
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if end.After(start) {
+			total += int(end.Sub(start) / time.Minute)
+		}
+	}
+	return total
+}
+
+// punchDay 按排班将一天的打卡整理为单日考勤
+// 工作日：班次内的出勤（扣除休息时段）计为正常工时，下班时间之后的出勤计为加班，上班前提早到岗不计加班；
+// 休息日和法定节假日：全部出勤（扣除休息时段）记入 WorkMinutes
+// 返回值: (单日考勤, 宽限期外未出勤的分钟数)
+func punchDay(date time.Time, dayType DayType, pairs []PunchPair, shift ScheduledShift, policy AttendancePolicy) (DailyAttendance, int) {
+	day := DailyAttendance{Date: date, DayType: dayType, ClockIn: pairs[0].ClockIn, ClockOut: pairs[len(pairs)-1].ClockOut}
+	breakStart, breakEnd := shift.breakWindow(date)
+	if dayType != Workday {
+		day.WorkMinutes = max(overlapMinutes(pairs, day.ClockIn, day.ClockOut)-overlapMinutes(pairs, breakStart, breakEnd), 0)
+		return day, 0
+	}
+
+	start, end := shift.window(date)
+	scheduled := shift.Minutes()
+	day.WorkMinutes = min(max(overlapMinutes(pairs, start, end)-overlapMinutes(pairs, breakStart, breakEnd), 0), scheduled)
+	day.OvertimeMinutes = overlapMinutes(pairs, end, day.ClockOut)
+	day.LateMinutes = min(max(int(day.ClockIn.Sub(start)/time.Minute), 0), scheduled)
+	day.EarlyLeaveMinutes = min(max(int(end.Sub(day.ClockOut)/time.Minute), 0), scheduled)
+
+	absence := scheduled - day.WorkMinutes
+	if day.LateMinutes > 0 && day.LateMinutes <= policy.LateGraceMinutes {
+		absence -= day.LateMinutes
+	}
+	if day.EarlyLeaveMinutes > 0 && day.EarlyLeaveMinutes <= policy.EarlyLeaveGraceMinutes {
+		absence -= day.EarlyLeaveMinutes
+	}
+	return day, max(absence, 0)
+}
+
+// PunchAttendance 按排班班次和节假日日历将打卡记录汇总为各员工的月度考勤
+// 迟到、早退按班次计算，宽限期内视为准时；工作日下班后的出勤计为工作日加班，休息日和法定节假日的出勤计入对应加班；
+// 工时取整、带薪节假日和班次津贴规则与 AggregateDailyAttendance 相同（如加班按30分钟向下取整）。
+// 在职期间没有打卡的工作日和宽限期外的迟到、早退计为缺勤；正常工时为实际出勤加缺勤，
+// 基本工资按正常工时扣除缺勤计算，也可按月薪折算后扣除缺勤
+// period: 薪资期
+// employees: 员工档案，按入职、离职日期确定应出勤的日期
+// punches: 打卡记录，只统计本薪资期内的考勤日期
+// shift: 排班班次，日历上的每个工作日均按该班次出勤
+// calendar: 节假日日历，为空时只区分工作日和周末
+// policy: 考勤汇总规则
+// 返回值: 按工号索引的考勤记录；打卡时间无效、重叠或员工不在名单中时返回错误
+func PunchAttendance(period time.Time, employees []Employee, punches []PunchPair, shift ScheduledShift, calendar *Calendar, policy AttendancePolicy) (map[string]AttendanceRecord, error) {
+	shiftOn := func(_ Employee, date time.Time) (ScheduledShift, bool) {
+		return shift, calendar.DayType(date) == Workday
+	}
+	records, _, err := punchAttendance(period, employees, punches, calendar, policy, shiftOn)
+	return records, err
+}
+
+// punchAttendance 按每位员工每天的排班汇总打卡记录
+// 有排班的日期按工作日计算（法定节假日除外），没有排班的日期按休息日计算；有排班的班次设置了差额津贴时，
+// 班次内的正常工时按班次代码汇总到 Shifts
+// shiftOn: 返回员工某天的排班班次，没有排班时返回 false
+// 返回值: (按工号索引的考勤记录, 按工号索引的整月应出勤分钟数, 错误)
+func punchAttendance(period time.Time, employees []Employee, punches []PunchPair, calendar *Calendar, policy AttendancePolicy,
+	shiftOn func(Employee, time.Time) (ScheduledShift, bool)) (map[string]AttendanceRecord, map[string]int, error) {
+	known := make(map[string]bool, len(employees))
+	for _, e := range employees {
+		known[e.ID] = true
+	}
+	byDay := make(map[string]map[string][]PunchPair)
+	for _, p := range punches {
+		if !known[p.EmployeeID] {
+			return nil, nil, fmt.Errorf("%w: 打卡记录中的员工 %s", ErrEmployeeNotFound, p.EmployeeID)
+		}
+		if !p.ClockOut.After(p.ClockIn) {
+			return nil, nil, fmt.Errorf("员工 %s 在 %s 的下班打卡不晚于上班打卡", p.EmployeeID, p.ClockIn.Format("2006-01-02 15:04"))
+		}
+		if !sameMonth(p.date(), period) {
+			continue
+		}
+		if byDay[p.EmployeeID] == nil {
+			byDay[p.EmployeeID] = make(map[string][]PunchPair)
+		}
+		key := dateKey(p.date())
+		byDay[p.EmployeeID][key] = append(byDay[p.EmployeeID][key], p)
+	}
+
+	records := make(map[string]AttendanceRecord, len(employees))
+	scheduled := make(map[string]int, len(employees))
+	for _, e := range employees {
+		from, to, _ := employedRange(period, e)
+		var days []DailyAttendance
+		var differentials []ShiftHours
+		absence := 0
+		start := monthStart(period)
+		for date := start; date.Before(start.AddDate(0, 1, 0)); date = date.AddDate(0, 0, 1) {
+			shift, ok := shiftOn(e, date)
+			dayType := calendar.DayType(date)
+			switch {
+			case dayType == Holiday:
+			case ok:
+				dayType = Workday
+			default:
+				dayType = RestDay
+			}
+			if ok && dayType == Workday {
+				scheduled[e.ID] += shift.Minutes()
+			}
+			employed := !date.Before(from) && date.Before(to)
+
+			pairs := byDay[e.ID][dateKey(date)]
+			if len(pairs) == 0 {
+				if dayType == Workday && employed {
+					absence += shift.Minutes()
+				}
+				continue
+			}
+			sort.Slice(pairs, func(i, j int) bool { return pairs[i].ClockIn.Before(pairs[j].ClockIn) })
+			for i := 1; i < len(pairs); i++ {
+				if pairs[i].ClockIn.Before(pairs[i-1].ClockOut) {
+					return nil, nil, fmt.Errorf("员工 %s 在 %s 的打卡记录重叠", e.ID, dateKey(date))
+				}
+			}
+			day, missed := punchDay(date, dayType, pairs, shift, policy)
+			days = append(days, day)
+			absence += missed
+			if ok && shift.Differential.IsPositive() && day.WorkMinutes > 0 {
+				differentials = addShiftMinutes(differentials, shift, day.WorkMinutes)
+			}
+		}
+		record := AggregateDailyAttendance(days, policy)
+		record.AbsenceHours = minutesToHours(absence)
+		record.WorkHours = Hours(hoursToDec(record.WorkHours).Add(hoursToDec(record.AbsenceHours)))
+		record.Shifts = append(record.Shifts, differentials...)
+		records[e.ID] = record
+	}
+	return records, scheduled, nil
+}
+
+// addShiftMinutes 将班次差额津贴的出勤分钟数累加到同一班次代码下
+func addShiftMinutes(shifts []ShiftHours, shift ScheduledShift, minutes int) []ShiftHours {
+	for i := range shifts {
+		if shifts[i].Code == shift.Code {
+			shifts[i].Hours = Hours(hoursToDec(shifts[i].Hours).Add(hoursToDec(minutesToHours(minutes))))
+			return shifts
+		}
+	}
+	return append(shifts, ShiftHours{Code: shift.Code, Name: shift.Name, Rate: shift.Differential, Hours: minutesToHours(minutes)})
+}
+
+// ImportPunchCSV 导入打卡记录CSV，首行为表头
+// 列：工号, 考勤日期（2006-01-02）, 上班打卡（15:04）, 下班打卡（15:04）；下班时间早于上班时间表示次日下班
+// 同一员工同一天可有多行，如午休打卡；行级错误不中断导入，汇总为 AttendanceImportErrors 返回，校验通过的行照常返回
+func ImportPunchCSV(r io.Reader) ([]PunchPair, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var punches []PunchPair
+	var errs AttendanceImportErrors
+	for i, row := range rows {
+		if i == 0 {
+			continue
+		}
+		cell := func(c int) string {
+			if c < len(row) {
+				return strings.TrimSpace(row[c])
+			}
+			return ""
+		}
+		line, id := i+1, cell(0)
+		if id == "" {
+			errs = append(errs, AttendanceLineError{Line: line, Err: errors.New("缺少工号")})
+			continue
+		}
+		date, err := time.ParseInLocation("2006-01-02", cell(1), time.Local)
+		if err != nil {
+			errs = append(errs, AttendanceLineError{Line: line, EmployeeID: id, Column: "考勤日期", Err: fmt.Errorf("%q 应为 YYYY-MM-DD", cell(1))})
+			continue
+		}
+		clock := func(c int, column string) (time.Time, bool) {
+			if cell(c) == "" {
+				errs = append(errs, AttendanceLineError{Line: line, EmployeeID: id, Column: column, Err: errors.New("缺少打卡时间")})
+				return time.Time{}, false
+			}
+			t, err := time.ParseInLocation("15:04", cell(c), time.Local)
+			if err != nil {
+				errs = append(errs, AttendanceLineError{Line: line, EmployeeID: id, Column: column, Err: fmt.Errorf("%q 应为 HH:MM", cell(c))})
+				return time.Time{}, false
+			}
+			return date.Add(time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute), true
+		}
+		in, okIn := clock(2, "上班打卡")
+		out, okOut := clock(3, "下班打卡")
+		if !okIn || !okOut {
+			continue
+		}
+		if !out.After(in) {
+			out = out.AddDate(0, 0, 1)
+		}
+		punches = append(punches, PunchPair{EmployeeID: id, Date: date, ClockIn: in, ClockOut: out})
+	}
+	if len(errs) > 0 {
+		return punches, errs
+	}
+	return punches, nil
+}
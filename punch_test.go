@@ -0,0 +1,59 @@
+package salary
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPunchAttendance(t *testing.T) {
+	shift := ScheduledShift{StartMinute: 9 * 60, EndMinute: 18 * 60, BreakStartMinute: 12 * 60, BreakMinutes: 60}
+	policy := AttendancePolicy{LateGraceMinutes: 5, OvertimeIncrement: 30, OvertimeRounding: RoundDown}
+	employees := []Employee{{ID: "E1", HireDate: day("2024-03-01"), TerminationDate: day("2024-03-05")}}
+	punches := []PunchPair{
+		{EmployeeID: "E1", ClockIn: at("2024-03-01 09:03"), ClockOut: at("2024-03-01 18:00")}, // 宽限期内迟到
+		{EmployeeID: "E1", ClockIn: at("2024-03-04 09:30"), ClockOut: at("2024-03-04 20:45")}, // 迟到30分钟，加班2小时45分
+		{EmployeeID: "E1", ClockIn: at("2024-03-09 10:00"), ClockOut: at("2024-03-09 15:00")}, // 休息日，扣除午休
+	}
+	// 3月5日为工作日且在职，没有打卡计为缺勤
+	records, err := PunchAttendance(day("2024-03-01"), employees, punches, shift, nil, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := records["E1"]
+	for _, c := range []struct {
+		name string
+		got  Hours
+		want string
+	}{
+		{"work", r.WorkHours, "24"},
+		{"absence", r.AbsenceHours, "8.5"},
+		{"weekday", r.OvertimeWeekday, "2.5"},
+		{"weekend", r.OvertimeWeekend, "4"},
+	} {
+		if !hoursToDec(c.got).Equal(hoursToDec(hours(c.want))) {
+			t.Errorf("%s = %s, want %s", c.name, hoursToDec(c.got), c.want)
+		}
+	}
+}
+
+func TestPunchAttendanceRejectsUnknownEmployee(t *testing.T) {
+	punches := []PunchPair{{EmployeeID: "X", ClockIn: at("2024-03-01 09:00"), ClockOut: at("2024-03-01 18:00")}}
+	_, err := PunchAttendance(day("2024-03-01"), nil, punches, ScheduledShift{StartMinute: 540, EndMinute: 1080}, nil, AttendancePolicy{})
+	if !errors.Is(err, ErrEmployeeNotFound) {
+		t.Errorf("err = %v, want ErrEmployeeNotFound", err)
+	}
+}
+
+func TestImportPunchCSV(t *testing.T) {
+	data := "工号,考勤日期,上班打卡,下班打卡\nE1,2024-03-01,22:00,06:00\nE2,2024-03-01,09:00,\n"
+	punches, err := ImportPunchCSV(strings.NewReader(data))
+	var lineErrs AttendanceImportErrors
+	if !errors.As(err, &lineErrs) || len(lineErrs) != 1 || lineErrs[0].Line != 3 {
+		t.Fatalf("err = %v", err)
+	}
+	if len(punches) != 1 || punches[0].ClockOut.Sub(punches[0].ClockIn) != 8*time.Hour {
+		t.Errorf("punches = %+v", punches)
+	}
+}
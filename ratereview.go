@@ -0,0 +1,83 @@
+package salary
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// RateDeviation 配置费率与城市政策费率的偏差
+type RateDeviation struct {
+	Item       string          `json:"item"`       // 费率项目
+	Configured decimal.Decimal `json:"configured"` // 配置的费率
+	Preset     decimal.Decimal `json:"preset"`     // 城市政策的费率
+}
+
+// String 返回偏差的中文描述
+func (d RateDeviation) String() string {
+	return fmt.Sprintf("%s：配置 %s，政策 %s", d.Item, d.Configured, d.Preset)
+}
+
+// ConfigReviewWarning 配置复核预警：薪资配置的费率与已加载的城市政策不一致，通常是政策调整后配置未及时更新
+type ConfigReviewWarning struct {
+	City        string          `json:"city"`         // 城市代码
+	Deviations  []RateDeviation `json:"deviations"`   // 超出允许偏差的费率
+	EmployeeIDs []string        `json:"employee_ids"` // 配置与政策不一致的员工
+}
+
+// String 返回预警的中文描述
+func (w ConfigReviewWarning) String() string {
+	items := make([]string, len(w.Deviations))
+	for i, d := range w.Deviations {
+		items[i] = d.String()
+	}
+	return fmt.Sprintf("城市 %s 的薪资配置与社保公积金政策不一致（%d名员工），请复核：%s", w.City, len(w.EmployeeIDs), strings.Join(items, "；"))
+}
+
+// CompareRates 比较薪资配置与城市政策的个人费率，返回差额超过 tolerance 的项目
+// config: 薪资配置
+// policy: 城市政策
+// tolerance: 允许的偏差（绝对值），如0.001表示0.1个百分点
+func CompareRates(config PayrollConfig, policy RegionPolicy, tolerance decimal.Decimal) []RateDeviation {
+	rates := []RateDeviation{
+		{Item: "养老保险费率", Configured: config.PensionRate, Preset: policy.PensionRate},
+		{Item: "医疗保险费率", Configured: config.MedicalRate, Preset: policy.MedicalRate},
+		{Item: "失业保险费率", Configured: config.UnemploymentRate, Preset: policy.UnemploymentRate},
+		{Item: "公积金费率", Configured: config.HousingFundRate, Preset: policy.HousingFundRate},
+	}
+	var deviations []RateDeviation
+	for _, d := range rates {
+		if d.Configured.Sub(d.Preset).Abs().GreaterThan(tolerance) {
+			deviations = append(deviations, d)
+		}
+	}
+	return deviations
+}
+
+// addConfigWarning 将员工的费率偏差并入预警列表，相同城市、相同偏差的员工合并为一条预警
+func addConfigWarning(warnings []ConfigReviewWarning, employeeID, city string, deviations []RateDeviation) []ConfigReviewWarning {
+	if len(deviations) == 0 {
+		return warnings
+	}
+	for i := range warnings {
+		if warnings[i].City == city && sameDeviations(warnings[i].Deviations, deviations) {
+			warnings[i].EmployeeIDs = append(warnings[i].EmployeeIDs, employeeID)
+			return warnings
+		}
+	}
+	return append(warnings, ConfigReviewWarning{City: city, Deviations: deviations, EmployeeIDs: []string{employeeID}})
+}
+
+// sameDeviations 判断两组偏差的项目和费率是否完全相同
+func sameDeviations(a, b []RateDeviation) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Item != b[i].Item || !a[i].Configured.Equal(b[i].Configured) || !a[i].Preset.Equal(b[i].Preset) {
+			return false
+		}
+	}
+	return true
+}
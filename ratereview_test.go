@@ -0,0 +1,48 @@
+package salary
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestRateDeviationWarnings(t *testing.T) {
+	policy := RegionPolicy{
+		City:             "shanghai",
+		PensionRate:      decimal.RequireFromString("0.08"),
+		MedicalRate:      decimal.RequireFromString("0.02"),
+		UnemploymentRate: decimal.RequireFromString("0.005"),
+		HousingFundRate:  decimal.RequireFromString("0.05"),
+	}
+	stale := testConfig() // 公积金费率仍为7%
+	current := testConfig()
+	current.HousingFundRate = decimal.RequireFromString("0.0505")
+	tolerance := decimal.RequireFromString("0.001")
+	run := PayrollRun{
+		Period:  time.Date(2024, 7, 1, 0, 0, 0, 0, time.Local),
+		Regions: map[string]RegionPolicy{"shanghai": policy},
+		Inputs: []EmployeeInput{
+			{Employee: Employee{ID: "E1", City: "shanghai"}, Config: stale},
+			{Employee: Employee{ID: "E2", City: "shanghai"}, Config: current},
+			{Employee: Employee{ID: "E3", City: "shanghai"}, Config: stale},
+		},
+		RateTolerance: &tolerance,
+	}
+	result := run.Calculate()
+	if len(result.ConfigWarnings) != 1 {
+		t.Fatalf("warnings = %v, want 1", result.ConfigWarnings)
+	}
+	w := result.ConfigWarnings[0]
+	if len(w.Deviations) != 1 || w.Deviations[0].Item != "公积金费率" {
+		t.Errorf("deviations = %v", w.Deviations)
+	}
+	if len(w.EmployeeIDs) != 2 || w.EmployeeIDs[0] != "E1" || w.EmployeeIDs[1] != "E3" {
+		t.Errorf("employees = %v, want [E1 E3]", w.EmployeeIDs)
+	}
+
+	run.RateTolerance = nil
+	if result := run.Calculate(); len(result.ConfigWarnings) != 0 {
+		t.Errorf("unchecked run warnings = %v", result.ConfigWarnings)
+	}
+}
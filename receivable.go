@@ -0,0 +1,97 @@
+package salary
+
+import (
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ReceivableKind 员工欠款台账记录类型
+type ReceivableKind int
+
+const (
+	ReceivableAccrued   ReceivableKind = iota // 新增欠款：公司代缴的个人社保公积金
+	ReceivableRecovered                       // 收回欠款：从工资中抵扣
+)
+
+// ReceivableEntry 员工欠款台账中的一条记录
+type ReceivableEntry struct {
+	EmployeeID string         // 工号
+	Period     time.Time      // 发生的薪资期
+	Kind       ReceivableKind // 记录类型
+	Amount     Money          // 金额（分）
+}
+
+// ReceivableLedger 员工欠款台账
+// 长期停薪期间由公司代缴的个人社保公积金记为员工欠款，员工复岗后从实发工资中抵扣
+type ReceivableLedger struct {
+	mu       sync.Mutex
+	balances map[string]decimal.Decimal
+	entries  []ReceivableEntry
+}
+
+// NewReceivableLedger 创建空的员工欠款台账
+func NewReceivableLedger() *ReceivableLedger {
+	return &ReceivableLedger{balances: make(map[string]decimal.Decimal)}
+}
+
+// Balance 查询员工当前欠款余额
+func (l *ReceivableLedger) Balance(employeeID string) Money {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return toMoney(l.balances[employeeID])
+}
+
+// Entries 查询员工的全部台账记录
+func (l *ReceivableLedger) Entries(employeeID string) []ReceivableEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var entries []ReceivableEntry
+	for _, e := range l.entries {
+		if e.EmployeeID == employeeID {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// Accrue 记入公司代缴形成的员工欠款
+func (l *ReceivableLedger) Accrue(employeeID string, period time.Time, amount Money) {
+	if !moneyToDec(amount).IsPositive() {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.balances[employeeID] = l.balances[employeeID].Add(moneyToDec(amount))
+	l.entries = append(l.entries, ReceivableEntry{EmployeeID: employeeID, Period: monthStart(period), Kind: ReceivableAccrued, Amount: amount})
+}
+
+// Recover 从员工可发工资中抵扣欠款，抵扣额不超过欠款余额和可发工资
+// available: 本期可用于抵扣的实发工资
+// 返回值: 实际抵扣金额
+func (l *ReceivableLedger) Recover(employeeID string, period time.Time, available Money) Money {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	balance := l.balances[employeeID]
+	amount := decimal.Min(balance, moneyToDec(available))
+	if !amount.IsPositive() {
+		return toMoney(decimal.Zero)
+	}
+	l.balances[employeeID] = balance.Sub(amount)
+	l.entries = append(l.entries, ReceivableEntry{EmployeeID: employeeID, Period: monthStart(period), Kind: ReceivableRecovered, Amount: toMoney(amount)})
+	return toMoney(amount)
+}
+
+// applyReceivable 根据员工本期结果登记或抵扣欠款，并更新结果中的实发工资
+func (l *ReceivableLedger) applyReceivable(result *EmployeeResult) {
+	id := result.Employee.ID
+	if result.Status == PeriodInactive {
+		// 停薪期间公司代缴的个人部分全部记为欠款
+		result.ReceivableAccrued = toMoney(moneyToDec(result.SocialInsurance).Add(moneyToDec(result.HousingFund)))
+		l.Accrue(id, result.Period, result.ReceivableAccrued)
+		return
+	}
+	result.ReceivableRecovered = l.Recover(id, result.Period, result.NetSalary)
+	result.NetSalary = toMoney(moneyToDec(result.NetSalary).Sub(moneyToDec(result.ReceivableRecovered)))
+}
@@ -0,0 +1,45 @@
+package salary
+
+import "testing"
+
+func TestReceivableAccruedAndRecovered(t *testing.T) {
+	ledger := NewReceivableLedger()
+	input := EmployeeInput{
+		Employee: Employee{ID: "E1"}, Config: testConfig(),
+		Status: PeriodInactive, InactiveInsurance: InactiveInsuranceContinue,
+	}
+	for _, period := range []string{"2024-01-01", "2024-02-01"} {
+		run := PayrollRun{Period: day(period), Receivables: ledger, Inputs: []EmployeeInput{input}}
+		result := run.Calculate()
+		// 停薪期间代缴的个人社保公积金 840 + 560 = 1,400元记为欠款
+		assertMoney(t, period+" accrued", result.Employees[0].ReceivableAccrued, "140000")
+	}
+	assertMoney(t, "balance after leave", ledger.Balance("E1"), "280000")
+
+	// 复岗后从实发工资中抵扣
+	input.Status = PeriodActive
+	input.Attendance = AttendanceRecord{WorkHours: hours("174")}
+	run := PayrollRun{Period: day("2024-03-01"), Receivables: ledger, Inputs: []EmployeeInput{input}}
+	employee := run.Calculate().Employees[0]
+	raw := CalculateEmployee(day("2024-03-01"), input)
+	assertMoney(t, "recovered", employee.ReceivableRecovered, "280000")
+	assertMoney(t, "net after recovery", employee.NetSalary, moneyToDec(raw.NetSalary).Sub(cenToDec(280000)).String())
+	assertMoney(t, "balance after recovery", ledger.Balance("E1"), "0")
+	if entries := ledger.Entries("E1"); len(entries) != 3 || entries[2].Kind != ReceivableRecovered {
+		t.Errorf("entries = %+v", entries)
+	}
+}
+
+func TestReceivableRecoverLimitedByNet(t *testing.T) {
+	ledger := NewReceivableLedger()
+	ledger.Accrue("E1", day("2024-01-01"), toMoney(cenToDec(500000)))
+	// 抵扣额不超过可发工资，剩余欠款留待下期
+	assertMoney(t, "recovered", ledger.Recover("E1", day("2024-02-01"), toMoney(cenToDec(300000))), "300000")
+	assertMoney(t, "remaining", ledger.Balance("E1"), "200000")
+	assertMoney(t, "no net", ledger.Recover("E1", day("2024-03-01"), toMoney(cenToDec(0))), "0")
+	// 非正数金额不记入
+	ledger.Accrue("E1", day("2024-03-01"), toMoney(cenToDec(-100)))
+	if len(ledger.Entries("E1")) != 2 {
+		t.Errorf("entries = %+v", ledger.Entries("E1"))
+	}
+}
@@ -0,0 +1,89 @@
+package salary
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// RegionPolicy 城市社保公积金政策（个人缴费部分）
+type RegionPolicy struct {
+	City             string          `json:"city"`              // 城市代码，如 shanghai
+	EffectiveFrom    time.Time       `json:"effective_from"`    // 生效日期
+	PensionRate      decimal.Decimal `json:"pension_rate"`      // 养老保险个人费率
+	MedicalRate      decimal.Decimal `json:"medical_rate"`      // 医疗保险个人费率
+	UnemploymentRate decimal.Decimal `json:"unemployment_rate"` // 失业保险个人费率
+	HousingFundRate  decimal.Decimal `json:"housing_fund_rate"` // 公积金个人费率
+	Base             BaseLimits      `json:"base"`              // 缴费基数上下限，每年公布新基数后更新
+}
+
+// Apply 将城市政策的费率写入薪资配置，设置了缴费基数上下限时同时作为各项社保的基数上下限，其他配置保持不变
+func (p RegionPolicy) Apply(config PayrollConfig) PayrollConfig {
+	config.PensionRate = p.PensionRate
+	config.MedicalRate = p.MedicalRate
+	config.UnemploymentRate = p.UnemploymentRate
+	config.HousingFundRate = p.HousingFundRate
+	if moneyToDec(p.Base.Floor).IsPositive() || moneyToDec(p.Base.Ceiling).IsPositive() {
+		config.PensionBase, config.MedicalBase, config.UnemploymentBase = p.Base, p.Base, p.Base
+	}
+	return config
+}
+
+// Validate 校验费率均在[0,1]区间内
+func (p RegionPolicy) Validate() error {
+	rates := map[string]decimal.Decimal{
+		"养老保险费率": p.PensionRate,
+		"医疗保险费率": p.MedicalRate,
+		"失业保险费率": p.UnemploymentRate,
+		"公积金费率":  p.HousingFundRate,
+	}
+	for name, rate := range rates {
+		if rate.IsNegative() || rate.GreaterThan(decimal.NewFromInt(1)) {
+			return fmt.Errorf("城市 %s 的%s %s 超出[0,1]范围", p.City, name, rate)
+		}
+	}
+	floor, ceiling := moneyToDec(p.Base.Floor), moneyToDec(p.Base.Ceiling)
+	if floor.IsPositive() && ceiling.IsPositive() && floor.GreaterThan(ceiling) {
+		return fmt.Errorf("城市 %s 的缴费基数下限高于上限", p.City)
+	}
+	return nil
+}
+
+// PolicyMissStrategy 员工所在城市缺少政策时的处理方式
+type PolicyMissStrategy int
+
+const (
+	PolicyMissSkip       PolicyMissStrategy = iota // 跳过该员工并记录错误，批次继续计算
+	PolicyMissUseDefault                           // 使用默认政策计算
+)
+
+// PolicyMiss 缺少城市政策的员工记录
+type PolicyMiss struct {
+	EmployeeID string             // 工号
+	Name       string             // 姓名
+	City       string             // 缺少政策的城市
+	Strategy   PolicyMissStrategy // 实际采用的处理方式
+}
+
+// ErrRegionPolicyMissing 员工所在城市未加载政策
+var ErrRegionPolicyMissing = errors.New("员工所在城市未加载社保公积金政策")
+
+// resolveRegion 查找员工所在城市的政策，城市政策仅适用于中国内地员工
+// 返回值: (适用的政策，为空表示沿用员工薪资配置, 缺失记录, 跳过时的错误)
+func (r *PayrollRun) resolveRegion(e Employee) (*RegionPolicy, *PolicyMiss, error) {
+	if r.Regions == nil || e.City == "" || e.Jurisdiction != JurisdictionCN {
+		return nil, nil, nil
+	}
+	if policy, ok := r.Regions[e.City]; ok {
+		return &policy, nil, nil
+	}
+
+	miss := &PolicyMiss{EmployeeID: e.ID, Name: e.Name, City: e.City, Strategy: r.PolicyMiss}
+	if r.PolicyMiss == PolicyMissUseDefault {
+		policy := r.DefaultPolicy
+		return &policy, miss, nil
+	}
+	return nil, miss, fmt.Errorf("%w: %s", ErrRegionPolicyMissing, e.City)
+}
@@ -0,0 +1,76 @@
+package salary
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func regionInput(id, city string) EmployeeInput {
+	return EmployeeInput{
+		Employee:   Employee{ID: id, Name: id, City: city},
+		Config:     testConfig(),
+		Attendance: AttendanceRecord{WorkHours: hours("174")},
+	}
+}
+
+func TestRegionPolicyMissSkip(t *testing.T) {
+	run := PayrollRun{
+		Period: day("2024-03-01"),
+		Regions: map[string]RegionPolicy{"shanghai": {
+			City: "shanghai", PensionRate: decimal.RequireFromString("0.08"), MedicalRate: decimal.RequireFromString("0.02"),
+			UnemploymentRate: decimal.RequireFromString("0.005"), HousingFundRate: decimal.RequireFromString("0.07"),
+		}},
+		Inputs: []EmployeeInput{regionInput("E1", "shanghai"), regionInput("E2", "lhasa")},
+	}
+	result := run.Calculate()
+	if len(result.Employees) != 1 || result.Employees[0].Employee.ID != "E1" {
+		t.Fatalf("employees = %+v", result.Employees)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].EmployeeID != "E2" || !errors.Is(result.Errors[0], ErrRegionPolicyMissing) {
+		t.Errorf("errors = %+v", result.Errors)
+	}
+	if len(result.PolicyMisses) != 1 || result.PolicyMisses[0].City != "lhasa" || result.PolicyMisses[0].Strategy != PolicyMissSkip {
+		t.Errorf("misses = %+v", result.PolicyMisses)
+	}
+}
+
+func TestRegionPolicyMissUseDefault(t *testing.T) {
+	run := PayrollRun{
+		Period:     day("2024-03-01"),
+		Regions:    map[string]RegionPolicy{},
+		PolicyMiss: PolicyMissUseDefault,
+		DefaultPolicy: RegionPolicy{
+			PensionRate: decimal.RequireFromString("0.1"), MedicalRate: decimal.RequireFromString("0.02"),
+			UnemploymentRate: decimal.RequireFromString("0.005"), HousingFundRate: decimal.RequireFromString("0.07"),
+		},
+		Inputs: []EmployeeInput{regionInput("E1", "lhasa")},
+	}
+	result := run.Calculate()
+	if len(result.Errors) != 0 || len(result.Employees) != 1 {
+		t.Fatalf("result = %+v", result)
+	}
+	// 默认政策：8000 × (10% + 2% + 0.5%) = 1000
+	assertMoney(t, "social insurance", result.Employees[0].SocialInsurance, "100000")
+	if len(result.PolicyMisses) != 1 || result.PolicyMisses[0].Strategy != PolicyMissUseDefault {
+		t.Errorf("misses = %+v", result.PolicyMisses)
+	}
+
+	// 未设置城市的员工沿用自身配置，不记缺失
+	run.Inputs = []EmployeeInput{regionInput("E2", "")}
+	if result := run.Calculate(); len(result.PolicyMisses) != 0 {
+		t.Errorf("misses without city = %+v", result.PolicyMisses)
+	}
+}
+
+func TestRegionPolicyValidate(t *testing.T) {
+	policy := RegionPolicy{City: "x", PensionRate: decimal.RequireFromString("1.2")}
+	if err := policy.Validate(); err == nil {
+		t.Error("rate above 1 should fail")
+	}
+	policy = RegionPolicy{City: "x", Base: BaseLimits{Floor: toMoney(cenToDec(3000000)), Ceiling: toMoney(cenToDec(500000))}}
+	if err := policy.Validate(); err == nil {
+		t.Error("floor above ceiling should fail")
+	}
+}
@@ -0,0 +1,63 @@
+package salary
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Relocation 员工跨城市调动，社保公积金缴纳城市随之变更
+// 调动不更换扣缴义务人，个人所得税继续按本单位本年累计数据预扣，不重新起算
+type Relocation struct {
+	FromCity string    `json:"from_city"` // 调出城市代码
+	ToCity   string    `json:"to_city"`   // 调入城市代码
+	Date     time.Time `json:"date"`      // 到调入城市报到日期
+}
+
+// PolicyMonth 调入城市政策开始适用的薪资期
+// 社保公积金按月参保，1日报到的当月即在调入城市缴纳；月中报到的当月已在调出城市缴纳，次月起转入
+func (r Relocation) PolicyMonth() time.Time {
+	month := monthStart(r.Date)
+	if r.Date.Day() > 1 {
+		month = month.AddDate(0, 1, 0)
+	}
+	return month
+}
+
+// Note 工资条上的调动说明
+func (r Relocation) Note() string {
+	return fmt.Sprintf("自%s起社保公积金缴纳城市由%s变更为%s，个人所得税继续按本年累计预扣",
+		r.PolicyMonth().Format("2006年01月"), r.FromCity, r.ToCity)
+}
+
+// relocatedCity 按调动记录确定员工在薪资期的社保缴纳城市
+// 首次调动生效前使用调出城市，之后使用最近一次已生效调动的调入城市；没有调动记录时沿用档案中的城市
+// city: 档案中的社保缴纳城市
+// relocations: 调动记录，顺序不限
+// period: 薪资期
+// 返回值: (适用的城市, 本期生效的调动，非生效月份为空)
+func relocatedCity(city string, relocations []Relocation, period time.Time) (string, *Relocation) {
+	if len(relocations) == 0 {
+		return city, nil
+	}
+	sorted := append([]Relocation(nil), relocations...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Date.Before(sorted[j].Date) })
+
+	period = monthStart(period)
+	if first := sorted[0]; period.Before(first.PolicyMonth()) {
+		if first.FromCity != "" {
+			city = first.FromCity
+		}
+		return city, nil
+	}
+	var current *Relocation
+	for i := range sorted {
+		if !period.Before(sorted[i].PolicyMonth()) {
+			current = &sorted[i]
+		}
+	}
+	if current.PolicyMonth().Equal(period) {
+		return current.ToCity, current
+	}
+	return current.ToCity, nil
+}
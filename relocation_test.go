@@ -0,0 +1,67 @@
+package salary
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestRelocationPolicyMonth(t *testing.T) {
+	if got := (Relocation{Date: day("2024-07-01")}).PolicyMonth(); !got.Equal(day("2024-07-01")) {
+		t.Errorf("1st of month: PolicyMonth = %s, want 2024-07", got.Format("2006-01"))
+	}
+	if got := (Relocation{Date: day("2024-07-10")}).PolicyMonth(); !got.Equal(day("2024-08-01")) {
+		t.Errorf("mid-month: PolicyMonth = %s, want 2024-08", got.Format("2006-01"))
+	}
+}
+
+func TestRelocationSwitchesPolicyAndCarriesTaxState(t *testing.T) {
+	regions := map[string]RegionPolicy{
+		"beijing":  {City: "beijing", PensionRate: decimal.RequireFromString("0.08"), MedicalRate: decimal.RequireFromString("0.02"), UnemploymentRate: decimal.RequireFromString("0.005"), HousingFundRate: decimal.RequireFromString("0.12")},
+		"shanghai": {City: "shanghai", PensionRate: decimal.RequireFromString("0.08"), MedicalRate: decimal.RequireFromString("0.02"), UnemploymentRate: decimal.RequireFromString("0.005"), HousingFundRate: decimal.RequireFromString("0.07")},
+	}
+	input := EmployeeInput{
+		Employee:    Employee{ID: "E001", Name: "张三", City: "shanghai"},
+		Config:      testConfig(),
+		Attendance:  AttendanceRecord{WorkHours: hours("174")},
+		TaxState:    &TaxWithholdingState{},
+		Relocations: []Relocation{{FromCity: "beijing", ToCity: "shanghai", Date: day("2024-02-05")}},
+	}
+
+	var results []EmployeeResult
+	for _, period := range []string{"2024-01-01", "2024-02-01", "2024-03-01", "2024-04-01"} {
+		run := PayrollRun{Period: day(period), Regions: regions, Inputs: []EmployeeInput{input}}
+		result := run.Calculate()
+		if len(result.Errors) > 0 {
+			t.Fatalf("%s: %v", period, result.Errors)
+		}
+		employee := result.Employees[0]
+		results = append(results, employee)
+		input.TaxState = employee.TaxState
+	}
+
+	// 2月5日报到，2月仍在北京缴纳，3月起按上海政策
+	for i, want := range []string{"beijing", "beijing", "shanghai", "shanghai"} {
+		if got := results[i].Employee.City; got != want {
+			t.Errorf("month %d city = %s, want %s", i+1, got, want)
+		}
+	}
+	assertMoney(t, "Feb housing fund", results[1].HousingFund, "96000")
+	assertMoney(t, "Mar housing fund", results[2].HousingFund, "56000")
+	if results[1].Relocation != nil || results[2].Relocation == nil || results[3].Relocation != nil {
+		t.Errorf("relocation should be reported only in March")
+	}
+	if state := results[3].TaxState; state.StartMonth != 1 || state.LastMonth != 4 {
+		t.Errorf("tax state = %+v, want continued from January", state)
+	}
+
+	var buf bytes.Buffer
+	if err := RenderPayslipText(&buf, results[2], PayslipOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "由beijing变更为shanghai") {
+		t.Errorf("payslip missing relocation note:\n%s", buf.String())
+	}
+}
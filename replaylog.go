@@ -0,0 +1,193 @@
+package salary
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ReplayEntry 重放日志中的一条变更请求
+type ReplayEntry struct {
+	Seq         int64     `json:"seq"`          // 顺序号，从1开始递增
+	Time        time.Time `json:"time"`         // 收到请求的时间
+	Method      string    `json:"method"`       // 请求方法
+	URI         string    `json:"uri"`          // 请求路径及查询参数
+	ContentType string    `json:"content_type"` // 请求体类型
+	Body        []byte    `json:"body"`         // 请求体原文
+}
+
+// ReplayLog 预写式重放日志：服务模式下每个变更请求在执行前先追加到日志，审计时可按时间顺序查看全部变更
+// 数据通过接口写入（员工档案 PUT /employees/{id}、批次 POST /runs 等）时，从空存储按顺序重放日志即可重建数据：
+// 变更请求串行执行，请求时间取日志记录的时间，重放时生成相同的编号和时间戳
+type ReplayLog struct {
+	mu  sync.Mutex
+	w   io.Writer
+	seq int64
+}
+
+// NewReplayLog 创建写入 w 的空重放日志，每条记录占一行JSON，顺序号从1开始
+// w 实现 Sync 方法（如 *os.File）时，每次追加后立即落盘；续写已有日志时使用 OpenReplayLog
+func NewReplayLog(w io.Writer) *ReplayLog {
+	return &ReplayLog{w: w}
+}
+
+// OpenReplayLog 打开已有的重放日志：读取全部记录，之后追加的记录从最后一条的顺序号继续编号，
+// 服务重启后顺序号不重复。文件应以追加方式打开（os.O_RDWR|os.O_APPEND）
+// 返回值: (重放日志, 已有记录，可交给 Replay 重建数据, 错误)
+func OpenReplayLog(rw io.ReadWriter) (*ReplayLog, []ReplayEntry, error) {
+	entries, err := ReadReplayLog(rw)
+	if err != nil {
+		return nil, entries, err
+	}
+	log := NewReplayLog(rw)
+	for _, entry := range entries {
+		log.seq = max(log.seq, entry.Seq)
+	}
+	return log, entries, nil
+}
+
+// Append 追加一条记录并返回分配了顺序号的记录，写入失败时返回错误，调用方不应继续执行该变更
+func (l *ReplayLog) Append(entry ReplayEntry) (ReplayEntry, error) {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	entry.Seq = l.seq + 1
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return entry, err
+	}
+	if _, err := l.w.Write(append(line, '\n')); err != nil {
+		return entry, fmt.Errorf("写入重放日志失败: %w", err)
+	}
+	if syncer, ok := l.w.(interface{ Sync() error }); ok {
+		if err := syncer.Sync(); err != nil {
+			return entry, fmt.Errorf("重放日志落盘失败: %w", err)
+		}
+	}
+	l.seq = entry.Seq
+	return entry, nil
+}
+
+// ReadReplayLog 读取重放日志的全部记录，按追加顺序排列
+func ReadReplayLog(r io.Reader) ([]ReplayEntry, error) {
+	var entries []ReplayEntry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), 2*maxAttachmentSize)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var entry ReplayEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return entries, fmt.Errorf("重放日志第%d行格式错误: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// replayResponse 重放时丢弃响应内容，只记录状态码
+type replayResponse struct {
+	header http.Header
+	status int
+}
+
+func (r *replayResponse) Header() http.Header         { return r.header }
+func (r *replayResponse) Write(b []byte) (int, error) { return len(b), nil }
+func (r *replayResponse) WriteHeader(status int)      { r.status = status }
+
+// ReplayResult 单条记录的重放结果
+type ReplayResult struct {
+	Seq    int64 // 顺序号
+	Status int   // 响应状态码，原请求失败的记录重放时同样失败
+}
+
+// Failed 判断记录重放是否失败
+func (r ReplayResult) Failed() bool {
+	return r.Status >= http.StatusBadRequest
+}
+
+// Replay 按顺序把记录重新提交给 h，用于从空存储重建数据；请求时间取记录的时间，编号和时间戳与原请求一致
+// h 应为未启用重放日志的服务，否则重放的请求会再次写入日志
+// 返回值: 各记录的重放结果，调用方应检查失败的记录
+func Replay(entries []ReplayEntry, h http.Handler) []ReplayResult {
+	results := make([]ReplayResult, 0, len(entries))
+	for _, entry := range entries {
+		req, err := http.NewRequest(entry.Method, entry.URI, bytes.NewReader(entry.Body))
+		if err != nil {
+			results = append(results, ReplayResult{Seq: entry.Seq, Status: http.StatusBadRequest})
+			continue
+		}
+		if entry.ContentType != "" {
+			req.Header.Set("Content-Type", entry.ContentType)
+		}
+		req = withRequestTime(req, entry.Time)
+		resp := &replayResponse{header: make(http.Header), status: http.StatusOK}
+		h.ServeHTTP(resp, req)
+		results = append(results, ReplayResult{Seq: entry.Seq, Status: resp.status})
+	}
+	return results
+}
+
+// SetReplayLog 启用重放日志，之后的变更请求在执行前先写入日志，并串行执行以保证执行顺序与日志顺序一致
+// NewServer 默认不写重放日志，嵌入方须自行调用；命令行服务模式通过 -replay-log 启用
+// 直接调用 MemoryStore 方法（如 SaveRun、PutEmployee）的修改不经过接口，不写入日志，重放时无法重建
+func (s *Server) SetReplayLog(log *ReplayLog) {
+	s.replay = log
+}
+
+// isMutation 判断请求是否会修改数据
+// 重算预览不保存结果，不视为变更
+func isMutation(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	return !strings.HasSuffix(r.URL.Path, "/preview")
+}
+
+// logMutation 将变更请求写入重放日志，并恢复请求体供后续处理
+// 返回值: (带有日志记录时间的请求, 错误)，写入失败时请求不得继续执行
+func (s *Server) logMutation(w http.ResponseWriter, r *http.Request) (*http.Request, error) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxAttachmentSize))
+	if err != nil {
+		return r, fmt.Errorf("读取请求体失败: %w", err)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	entry, err := s.replay.Append(ReplayEntry{
+		Method:      r.Method,
+		URI:         r.URL.RequestURI(),
+		ContentType: r.Header.Get("Content-Type"),
+		Body:        body,
+	})
+	if err != nil {
+		return r, err
+	}
+	return withRequestTime(r, entry.Time), nil
+}
+
+// requestTimeKey 请求上下文中记录请求时间的键
+type requestTimeKey struct{}
+
+// withRequestTime 在请求上下文中记录请求时间
+func withRequestTime(r *http.Request, t time.Time) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), requestTimeKey{}, t))
+}
+
+// requestTime 返回请求时间：写入重放日志的变更请求和重放的请求为日志记录的时间，其他为当前时间
+// 修改数据的接口以此作为创建、修改时间，重放时得到与原请求相同的时间戳
+func requestTime(ctx context.Context) time.Time {
+	if t, ok := ctx.Value(requestTimeKey{}).(time.Time); ok {
+		return t
+	}
+	return time.Now()
+}
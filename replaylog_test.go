@@ -0,0 +1,127 @@
+package salary
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+)
+
+func TestOpenReplayLogContinuesSequence(t *testing.T) {
+	var buf bytes.Buffer
+	first := NewReplayLog(&buf)
+	for i := 0; i < 2; i++ {
+		if _, err := first.Append(ReplayEntry{Method: "POST", URI: "/runs"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 服务重启：从已有日志继续编号
+	reopened, entries, err := OpenReplayLog(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[1].Seq != 2 {
+		t.Fatalf("entries = %+v", entries)
+	}
+	entry, err := reopened.Append(ReplayEntry{Method: "DELETE", URI: "/runs/1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Seq != 3 {
+		t.Errorf("Seq after reopen = %d, want 3", entry.Seq)
+	}
+	// bytes.Buffer 读取后只剩新追加的记录
+	if all, _ := ReadReplayLog(&buf); len(all) != 1 || all[0].Seq != 3 {
+		t.Errorf("appended entries = %+v", all)
+	}
+}
+
+func TestServerReplayLogRebuildsState(t *testing.T) {
+	var buf bytes.Buffer
+	original := NewMemoryStore()
+	server := NewServer(original)
+	server.SetReplayLog(NewReplayLog(&buf))
+
+	steps := []struct {
+		method, target, contentType, body string
+		status                            int
+	}{
+		{http.MethodPost, "/employees", "application/json", `{"employee":{"ID":"E1","HireDate":"2023-01-01T00:00:00Z"},"base_salary":800000,"bank_account":"6222021234567890128"}`, http.StatusCreated},
+		{http.MethodPost, "/runs", "application/json", `{"period":"2024-06","inputs":[{"Employee":{"ID":"E1","HireDate":"2023-01-01T00:00:00Z"},"Config":{"BaseSalary":800000,"FullMonthHours":174},"Attendance":{"WorkHours":174}}]}`, http.StatusCreated},
+		{http.MethodPost, "/runs/202406-000001/comments", "application/json", `{"author":"hr","body":"已核对"}`, http.StatusCreated},
+		{http.MethodPost, "/runs/202406-000001/attachments?name=approval.txt", "text/plain", "同意发放", http.StatusCreated},
+		{http.MethodPost, "/employees/E1/holds", "application/json", `{"amount":100000,"reason":"借款未还","placed_by":"hr"}`, http.StatusCreated},
+		{http.MethodPatch, "/employees", "application/json", `{"operator":"hr","updates":[{"employee_id":"E1","version":1,"base_salary":820000}]}`, http.StatusOK},
+		{http.MethodPost, "/holds/H-000001/release?released_by=finance", "", "", http.StatusOK},
+		// 重复创建失败，原请求已写入日志，重放时同样失败
+		{http.MethodPost, "/employees", "application/json", `{"employee":{"ID":"E1"},"bank_account":"6222021234567890128"}`, http.StatusConflict},
+	}
+	for _, step := range steps {
+		if rec := serve(server, step.method, step.target, step.contentType, step.body); rec.Code != step.status {
+			t.Fatalf("%s %s status = %d, want %d, body = %s", step.method, step.target, rec.Code, step.status, rec.Body)
+		}
+	}
+	// 查询和重算预览不修改数据，不写入日志
+	serve(server, http.MethodGet, "/runs/202406-000001/comments", "", "")
+	serve(server, http.MethodPost, "/runs/202406-000001/employees/E1/preview", "application/json", `{}`)
+
+	// 服务重启：读取已有日志，重放到空存储
+	reopened, entries, err := OpenReplayLog(bytes.NewBuffer(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(steps) {
+		t.Fatalf("logged %d entries, want %d", len(entries), len(steps))
+	}
+	rebuilt := NewMemoryStore()
+	restarted := NewServer(rebuilt)
+	results := Replay(entries, restarted)
+	for i, result := range results {
+		if result.Status != steps[i].status || result.Failed() != (steps[i].status >= 400) {
+			t.Errorf("replay seq %d status = %d, want %d", result.Seq, result.Status, steps[i].status)
+		}
+	}
+	restarted.SetReplayLog(reopened)
+
+	// 编号和时间与原请求一致
+	wantRun, _ := original.Run("202406-000001")
+	gotRun, err := rebuilt.Run("202406-000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gotRun.Employees) != 1 || !moneyToDec(gotRun.Employees[0].NetSalary).Equal(moneyToDec(wantRun.Employees[0].NetSalary)) {
+		t.Errorf("rebuilt run = %+v", gotRun)
+	}
+	wantComments, _ := original.Comments("202406-000001")
+	gotComments, _ := rebuilt.Comments("202406-000001")
+	if len(gotComments) != 1 || gotComments[0].ID != wantComments[0].ID || !gotComments[0].CreatedAt.Equal(wantComments[0].CreatedAt) {
+		t.Errorf("comments = %+v, want %+v", gotComments, wantComments)
+	}
+	wantAttachments, _ := original.Attachments("202406-000001")
+	gotAttachments, _ := rebuilt.Attachments("202406-000001")
+	if len(gotAttachments) != 1 || gotAttachments[0].ID != wantAttachments[0].ID || !gotAttachments[0].CreatedAt.Equal(wantAttachments[0].CreatedAt) {
+		t.Errorf("attachments = %+v, want %+v", gotAttachments, wantAttachments)
+	}
+	wantRecord, _ := original.Employee("E1")
+	gotRecord, err := rebuilt.Employee("E1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotRecord.Version != 2 || !gotRecord.UpdatedAt.Equal(wantRecord.UpdatedAt) {
+		t.Errorf("employee version = %d, updated at %v, want %v", gotRecord.Version, gotRecord.UpdatedAt, wantRecord.UpdatedAt)
+	}
+	assertMoney(t, "rebuilt base salary", gotRecord.BaseSalary, "820000")
+	wantHolds := original.Holds().Holds("E1")
+	gotHolds := rebuilt.Holds().Holds("E1")
+	if len(gotHolds) != 1 || gotHolds[0].ID != "H-000001" || !gotHolds[0].PlacedAt.Equal(wantHolds[0].PlacedAt) || !gotHolds[0].ReleasedAt.Equal(wantHolds[0].ReleasedAt) || gotHolds[0].Active() {
+		t.Errorf("holds = %+v, want %+v", gotHolds, wantHolds)
+	}
+
+	// 重启后的新请求继续编号
+	if rec := serve(restarted, http.MethodPost, "/runs/202406-000001/comments", "application/json", `{"author":"hr","body":"重启后追加"}`); rec.Code != http.StatusCreated {
+		t.Fatalf("comment after restart status = %d", rec.Code)
+	}
+	if comments, _ := rebuilt.Comments("202406-000001"); len(comments) != 2 || comments[1].ID == comments[0].ID {
+		t.Errorf("comments after restart = %+v", comments)
+	}
+}
@@ -0,0 +1,261 @@
+package salary
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// reportFields 报表可选的结果字段
+var reportFields = map[string]func(r EmployeeResult) Money{
+	"base_salary":            func(r EmployeeResult) Money { return r.BaseSalary },
+	"overtime_pay":           func(r EmployeeResult) Money { return r.OvertimePay },
+	"gross":                  func(r EmployeeResult) Money { return r.GrossSalary },
+	"social_insurance":       func(r EmployeeResult) Money { return r.SocialInsurance },
+	"housing_fund":           func(r EmployeeResult) Money { return r.HousingFund },
+	"taxable_income":         func(r EmployeeResult) Money { return r.TaxableIncome },
+	"special_deductions":     func(r EmployeeResult) Money { return r.SpecialDeductionTotal },
+	"income_tax":             func(r EmployeeResult) Money { return r.IncomeTax },
+	"net":                    func(r EmployeeResult) Money { return r.NetSalary },
+	"employer_contributions": func(r EmployeeResult) Money { return r.EmployerContributions },
+	"tax_provision":          func(r EmployeeResult) Money { return r.TaxProvision },
+	"hypothetical_tax":       func(r EmployeeResult) Money { return r.HypotheticalTax },
+}
+
+// reportGroupKeys 报表可选的分组维度
+var reportGroupKeys = map[string]func(r EmployeeResult) string{
+	"employee":     func(r EmployeeResult) string { return r.Employee.ID },
+	"name":         func(r EmployeeResult) string { return r.Employee.Name },
+	"department":   func(r EmployeeResult) string { return r.Employee.Department },
+	"grade":        func(r EmployeeResult) string { return r.Employee.Grade },
+	"city":         func(r EmployeeResult) string { return r.Employee.City },
+	"jurisdiction": func(r EmployeeResult) string { return string(r.Employee.Jurisdiction) },
+	"period":       func(r EmployeeResult) string { return r.Period.Format("2006-01") },
+	"year":         func(r EmployeeResult) string { return strconv.Itoa(r.Period.Year()) },
+}
+
+// resolveReportField 解析报表字段，返回从员工结果中取值的函数
+// 支持的写法：
+//   - 结果字段，如 gross、income_tax、employer_contributions
+//   - line:代码，收入或扣款明细金额，如 line:ADJ-BONUS
+//   - statutory:代码、statutory_employer:代码，法定扣缴的个人或单位部分，如 statutory:MPF
+//   - headcount，人数
+func resolveReportField(field string) (func(r EmployeeResult) decimal.Decimal, error) {
+	if field == "headcount" {
+		return func(r EmployeeResult) decimal.Decimal { return decimal.NewFromInt(1) }, nil
+	}
+	if f, ok := reportFields[field]; ok {
+		return func(r EmployeeResult) decimal.Decimal { return moneyToDec(f(r)) }, nil
+	}
+	kind, code, ok := strings.Cut(field, ":")
+	if !ok || code == "" {
+		return nil, fmt.Errorf("不支持的报表字段 %q", field)
+	}
+	switch kind {
+	case "line":
+		return func(r EmployeeResult) decimal.Decimal {
+			total := decimal.Zero
+			for _, line := range r.Lines {
+				if line.Code == code {
+					total = total.Add(moneyToDec(line.Amount))
+				}
+			}
+			return total
+		}, nil
+	case "statutory", "statutory_employer":
+		return func(r EmployeeResult) decimal.Decimal {
+			total := decimal.Zero
+			for _, s := range r.Statutory {
+				if s.Code != code {
+					continue
+				}
+				if kind == "statutory" {
+					total = total.Add(moneyToDec(s.Employee))
+				} else {
+					total = total.Add(moneyToDec(s.Employer))
+				}
+			}
+			return total
+		}, nil
+	default:
+		return nil, fmt.Errorf("不支持的报表字段 %q", field)
+	}
+}
+
+// ReportQuery 自定义报表查询
+type ReportQuery struct {
+	Fields  []string  // 统计字段；加 ytd: 前缀表示本年累计值，如 ytd:gross
+	GroupBy []string  // 分组维度，如 department、period；为空表示汇总为一行
+	From    time.Time // 起始薪资期（含），零值表示不限
+	To      time.Time // 截止薪资期（含），零值表示不限
+}
+
+// ReportTable 报表数据集
+type ReportTable struct {
+	Columns []string   `json:"columns"` // 列名：分组维度在前，统计字段在后
+	Rows    [][]string `json:"rows"`    // 数据行，金额单位为元，按分组维度排序
+}
+
+// BuildReport 按选定的字段和分组维度生成报表数据集，避免为每种报表单独编写导出函数
+// 同一分组内的值相加；ytd 字段取员工在该薪资期的本年累计值，通常与 period 分组配合使用
+// 人数（headcount）统计为员工薪资期记录数，按 period 分组时即为当期人数
+// 未在岗月份同样计入
+// history: 历史发薪批次
+// query: 报表查询
+func BuildReport(history []PayrollResult, query ReportQuery) (ReportTable, error) {
+	groupFuncs := make([]func(r EmployeeResult) string, len(query.GroupBy))
+	for i, key := range query.GroupBy {
+		f, ok := reportGroupKeys[key]
+		if !ok {
+			return ReportTable{}, fmt.Errorf("不支持的分组维度 %q", key)
+		}
+		groupFuncs[i] = f
+	}
+	type column struct {
+		value func(r EmployeeResult) decimal.Decimal
+		ytd   bool
+		count bool
+	}
+	columns := make([]column, len(query.Fields))
+	for i, field := range query.Fields {
+		name, ytd := strings.CutPrefix(field, "ytd:")
+		value, err := resolveReportField(name)
+		if err != nil {
+			return ReportTable{}, err
+		}
+		columns[i] = column{value: value, ytd: ytd, count: name == "headcount"}
+	}
+
+	// 按薪资期排序后逐条累计本年数据
+	var results []EmployeeResult
+	for _, run := range history {
+		results = append(results, run.Employees...)
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Period.Before(results[j].Period) })
+
+	type ytdKey struct {
+		employee string
+		year     int
+		column   int
+	}
+	ytd := make(map[ytdKey]decimal.Decimal)
+	groups := make(map[string][]string)
+	sums := make(map[string][]decimal.Decimal)
+	for _, r := range results {
+		values := make([]decimal.Decimal, len(columns))
+		for i, c := range columns {
+			values[i] = c.value(r)
+			if c.ytd && !c.count {
+				k := ytdKey{r.Employee.ID, r.Period.Year(), i}
+				ytd[k] = ytd[k].Add(values[i])
+				values[i] = ytd[k]
+			}
+		}
+		if (!query.From.IsZero() && r.Period.Before(monthStart(query.From))) ||
+			(!query.To.IsZero() && r.Period.After(monthStart(query.To))) {
+			continue
+		}
+
+		keys := make([]string, len(groupFuncs))
+		for i, f := range groupFuncs {
+			keys[i] = f(r)
+		}
+		id := strings.Join(keys, "\x00")
+		if _, ok := groups[id]; !ok {
+			groups[id] = keys
+			sums[id] = make([]decimal.Decimal, len(columns))
+		}
+		for i := range values {
+			sums[id][i] = sums[id][i].Add(values[i])
+		}
+	}
+
+	ids := make([]string, 0, len(groups))
+	for id := range groups {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	table := ReportTable{Columns: append(append([]string(nil), query.GroupBy...), query.Fields...)}
+	for _, id := range ids {
+		row := append([]string(nil), groups[id]...)
+		for i, c := range columns {
+			if c.count {
+				row = append(row, sums[id][i].String())
+				continue
+			}
+			row = append(row, sums[id][i].Div(decimal.NewFromInt(100)).StringFixed(2))
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table, nil
+}
+
+// WriteCSV 将报表数据集导出为CSV
+func (t ReportTable) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(t.Columns); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(t.Rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// handleCustomReport 自定义报表接口：
+// GET /reports/custom?fields=gross,net,ytd:income_tax&group_by=department,period&from=2024-01&to=2024-06&format=csv
+func (s *Server) handleCustomReport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	q := ReportQuery{Fields: splitList(query.Get("fields")), GroupBy: splitList(query.Get("group_by"))}
+	if len(q.Fields) == 0 {
+		writeError(w, errors.New("请选择统计字段"))
+		return
+	}
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{{"from", &q.From}, {"to", &q.To}} {
+		value := query.Get(p.name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse("2006-01", value)
+		if err != nil {
+			writeError(w, fmt.Errorf("%s 格式应为 YYYY-MM: %w", p.name, err))
+			return
+		}
+		*p.dst = t
+	}
+
+	table, err := BuildReport(s.store.Runs(), q)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if query.Get("format") == "csv" {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		table.WriteCSV(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, table)
+}
+
+// splitList 拆分逗号分隔的查询参数，忽略空项
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,91 @@
+package salary
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func reportHistory() []PayrollResult {
+	var history []PayrollResult
+	for _, period := range []string{"2024-01-01", "2024-02-01", "2024-03-01"} {
+		e1 := EmployeeResult{Employee: Employee{ID: "E1", Department: "研发部"}, Period: day(period), GrossSalary: toMoney(cenToDec(1000000))}
+		if period == "2024-02-01" {
+			e1.Lines = []PayLine{{Code: "ADJ-BONUS", Kind: KindEarning, Amount: toMoney(cenToDec(100000))}}
+		}
+		e2 := EmployeeResult{Employee: Employee{ID: "E2", Department: "销售部"}, Period: day(period), GrossSalary: toMoney(cenToDec(500000))}
+		history = append(history, PayrollResult{Period: day(period), Employees: []EmployeeResult{e1, e2}})
+	}
+	return history
+}
+
+func TestBuildReport(t *testing.T) {
+	table, err := BuildReport(reportHistory(), ReportQuery{
+		Fields:  []string{"gross", "ytd:gross", "headcount", "line:ADJ-BONUS"},
+		GroupBy: []string{"department", "period"},
+		From:    day("2024-02-01"),
+		To:      day("2024-03-01"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(table.Columns, []string{"department", "period", "gross", "ytd:gross", "headcount", "line:ADJ-BONUS"}) {
+		t.Errorf("columns = %v", table.Columns)
+	}
+	// 本年累计包含起始薪资期之前的1月
+	want := [][]string{
+		{"研发部", "2024-02", "10000.00", "20000.00", "1", "1000.00"},
+		{"研发部", "2024-03", "10000.00", "30000.00", "1", "0.00"},
+		{"销售部", "2024-02", "5000.00", "10000.00", "1", "0.00"},
+		{"销售部", "2024-03", "5000.00", "15000.00", "1", "0.00"},
+	}
+	if !reflect.DeepEqual(table.Rows, want) {
+		t.Errorf("rows = %v", table.Rows)
+	}
+
+	// 不分组时汇总为一行
+	table, err = BuildReport(reportHistory(), ReportQuery{Fields: []string{"gross", "headcount"}})
+	if err != nil || !reflect.DeepEqual(table.Rows, [][]string{{"45000.00", "6"}}) {
+		t.Errorf("total rows = %v, err = %v", table.Rows, err)
+	}
+
+	if _, err := BuildReport(nil, ReportQuery{Fields: []string{"salary"}}); err == nil {
+		t.Error("unknown field should fail")
+	}
+	if _, err := BuildReport(nil, ReportQuery{Fields: []string{"gross"}, GroupBy: []string{"team"}}); err == nil {
+		t.Error("unknown group key should fail")
+	}
+}
+
+func TestHandleCustomReport(t *testing.T) {
+	store := NewMemoryStore()
+	for _, run := range reportHistory() {
+		store.SaveRun(&run)
+	}
+	server := NewServer(store)
+
+	rec := serve(server, http.MethodGet, "/reports/custom?fields=gross,+net&group_by=department&from=2024-03", "", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+	var table ReportTable
+	if err := json.NewDecoder(rec.Body).Decode(&table); err != nil {
+		t.Fatal(err)
+	}
+	if len(table.Rows) != 2 || table.Rows[0][1] != "10000.00" {
+		t.Errorf("table = %+v", table)
+	}
+
+	rec = serve(server, http.MethodGet, "/reports/custom?fields=gross&format=csv", "", "")
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/csv") || rec.Body.String() != "gross\n45000.00\n" {
+		t.Errorf("csv = %q", rec.Body)
+	}
+
+	for _, target := range []string{"/reports/custom", "/reports/custom?fields=gross&from=2024/01", "/reports/custom?fields=bogus"} {
+		if rec := serve(server, http.MethodGet, target, "", ""); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s status = %d", target, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package salary
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ReprintRequest 批量补打工资条的范围
+type ReprintRequest struct {
+	EmployeeIDs []string  // 员工工号，为空表示全部员工
+	From        time.Time // 起始薪资期（含）
+	To          time.Time // 截止薪资期（含）
+	RequestedBy string    // 申请人，记入审计日志
+}
+
+// ReprintSummary 批量补打结果
+type ReprintSummary struct {
+	Payslips int      `json:"payslips"` // 生成的工资条份数
+	Missing  []string `json:"missing"`  // 范围内没有存档结果的员工
+}
+
+// reprintResults 按员工、薪资期查找范围内的存档结果，同一薪资期有多个批次时取最后保存的批次
+func (s *MemoryStore) reprintResults(req ReprintRequest) []EmployeeResult {
+	from, to := monthStart(req.From), monthStart(req.To)
+	wanted := make(map[string]bool, len(req.EmployeeIDs))
+	for _, id := range req.EmployeeIDs {
+		wanted[id] = true
+	}
+
+	latest := make(map[string]int)
+	var results []EmployeeResult
+	for _, run := range s.Runs() {
+		if run.Period.Before(from) || run.Period.After(to) {
+			continue
+		}
+		for _, r := range run.Employees {
+			if len(wanted) > 0 && !wanted[r.Employee.ID] {
+				continue
+			}
+			key := r.Employee.ID + "|" + r.Period.Format("200601")
+			if i, ok := latest[key]; ok {
+				results[i] = r
+				continue
+			}
+			latest[key] = len(results)
+			results = append(results, r)
+		}
+	}
+	return results
+}
+
+// ReprintPayslips 从存档的批次结果重新生成指定员工、薪资期范围内的PDF工资条，不重新计算
+// 用于审计和员工办理贷款等需要多个月收入证明的场景；输出为zip压缩包，每份工资条为“工号/薪资期.pdf”
+// req: 补打范围
+// opts: 工资条渲染选项
+// w: zip压缩包输出目标
+func (s *MemoryStore) ReprintPayslips(req ReprintRequest, opts PayslipOptions, w io.Writer) (ReprintSummary, error) {
+	results := s.reprintResults(req)
+	archive := zip.NewWriter(w)
+	var summary ReprintSummary
+	found := make(map[string]bool)
+	for _, r := range results {
+		f, err := archive.Create(fmt.Sprintf("%s/%s.pdf", r.Employee.ID, r.Period.Format("2006-01")))
+		if err != nil {
+			return summary, err
+		}
+		if err := writePDF(f, []pdfPage{payslipPage(r, opts)}); err != nil {
+			return summary, err
+		}
+		summary.Payslips++
+		found[r.Employee.ID] = true
+	}
+	for _, id := range req.EmployeeIDs {
+		if !found[id] {
+			summary.Missing = append(summary.Missing, id)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return summary, err
+	}
+
+	s.mu.RLock()
+	audit := s.audit
+	s.mu.RUnlock()
+	if audit != nil {
+		audit.Record(AuditEntry{
+			Actor:  req.RequestedBy,
+			Action: "payslip.reprint",
+			Period: monthStart(req.From),
+			Detail: fmt.Sprintf("补打%s至%s工资条%d份，员工：%s", req.From.Format("2006-01"), req.To.Format("2006-01"), summary.Payslips, reprintScope(req.EmployeeIDs)),
+		})
+	}
+	return summary, nil
+}
+
+// reprintScope 补打范围内员工的描述
+func reprintScope(ids []string) string {
+	if len(ids) == 0 {
+		return "全部"
+	}
+	return strings.Join(ids, ",")
+}
+
+// handleReprintPayslips 批量补打工资条，返回zip压缩包
+// 参数：employees 逗号分隔的工号（可省略），from、to 薪资期（YYYY-MM），company 公司名称，requested_by 申请人
+func (s *Server) handleReprintPayslips(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	from, err := time.ParseInLocation("2006-01", q.Get("from"), time.Local)
+	if err != nil {
+		writeError(w, fmt.Errorf("起始薪资期格式错误: %w", err))
+		return
+	}
+	to, err := time.ParseInLocation("2006-01", q.Get("to"), time.Local)
+	if err != nil {
+		writeError(w, fmt.Errorf("截止薪资期格式错误: %w", err))
+		return
+	}
+	req := ReprintRequest{From: from, To: to, RequestedBy: q.Get("requested_by")}
+	if ids := q.Get("employees"); ids != "" {
+		req.EmployeeIDs = strings.Split(ids, ",")
+	}
+	var buf bytes.Buffer
+	if _, err := s.store.ReprintPayslips(req, PayslipOptions{Company: q.Get("company")}, &buf); err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorBody(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="payslips-%s-%s.zip"`, from.Format("200601"), to.Format("200601")))
+	w.Write(buf.Bytes())
+}
@@ -0,0 +1,47 @@
+package salary
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReprintPayslips(t *testing.T) {
+	through := time.Date(2024, 6, 1, 0, 0, 0, 0, time.Local)
+	store := NewSandboxStore(SyntheticOptions{Headcount: 3, Seed: 5}, through, 3)
+	id := store.Employees()[0].Employee.ID
+
+	var buf bytes.Buffer
+	req := ReprintRequest{EmployeeIDs: []string{id, "NOBODY"}, From: through.AddDate(0, -1, 0), To: through}
+	summary, err := store.ReprintPayslips(req, PayslipOptions{Company: "示例公司"}, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Payslips != 2 || len(summary.Missing) != 1 || summary.Missing[0] != "NOBODY" {
+		t.Errorf("summary = %+v", summary)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range archive.File {
+		names = append(names, f.Name)
+		rc, _ := f.Open()
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		if !bytes.HasPrefix(data, []byte("%PDF-")) || !strings.HasSuffix(string(data), "%%EOF\n") {
+			t.Errorf("%s is not a PDF document", f.Name)
+		}
+	}
+	sort.Strings(names)
+	want := []string{id + "/2024-05.pdf", id + "/2024-06.pdf"}
+	if strings.Join(names, " ") != strings.Join(want, " ") {
+		t.Errorf("files = %v, want %v", names, want)
+	}
+}
@@ -0,0 +1,203 @@
+package salary
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ResidencyDays 一个纳税年度内在中国境内居住累计满183天的个人为居民个人
+const ResidencyDays = 183
+
+// DefaultResidencyWarningDays 距居民个人判定天数不足该天数时提示
+const DefaultResidencyWarningDays = 30
+
+// TaxResidency 个人所得税纳税人身份
+type TaxResidency int
+
+const (
+	ResidencyUntracked TaxResidency = iota // 未跟踪境内居住天数，按员工输入计算
+	Resident                               // 居民个人：按累计预扣法预扣
+	NonResident                            // 非居民个人：按月计税，不适用累计预扣法
+)
+
+// String 返回纳税人身份的中文名称
+func (r TaxResidency) String() string {
+	switch r {
+	case Resident:
+		return "居民个人"
+	case NonResident:
+		return "非居民个人"
+	default:
+		return "未跟踪"
+	}
+}
+
+// TaxMethod 本期个人所得税的计算方法
+type TaxMethod int
+
+const (
+	TaxMethodMonthly     TaxMethod = iota // 居民个人按单月计算
+	TaxMethodCumulative                   // 居民个人按累计预扣法计算
+	TaxMethodNonResident                  // 非居民个人按月计税：每月收入额减除费用后适用按月换算的税率表，不扣除专项附加扣除
+)
+
+// String 返回计算方法的中文名称
+func (m TaxMethod) String() string {
+	switch m {
+	case TaxMethodCumulative:
+		return "累计预扣法"
+	case TaxMethodNonResident:
+		return "非居民个人按月计税"
+	default:
+		return "按月计税"
+	}
+}
+
+// TravelRecord 外籍员工的一次在华停留，来自出入境记录
+type TravelRecord struct {
+	EmployeeID string    // 工号
+	Arrival    time.Time // 入境日期
+	Departure  time.Time // 出境日期，零值表示仍在境内
+}
+
+// ResidencyWarning 境内居住天数接近183天的提示
+type ResidencyWarning struct {
+	EmployeeID string // 工号
+	Year       int    // 纳税年度
+	Days       int    // 截至薪资期末的境内居住天数
+	Remaining  int    // 距183天还差的天数
+}
+
+// String 返回便于展示的说明
+func (w ResidencyWarning) String() string {
+	return fmt.Sprintf("员工 %s %d年境内居住已满%d天，再居住%d天将成为居民个人", w.EmployeeID, w.Year, w.Days, w.Remaining)
+}
+
+// ResidencyTracker 外籍员工境内居住天数台账
+// 当天在境内停留满24小时的计入居住天数，入境和出境当天不计入
+type ResidencyTracker struct {
+	mu          sync.Mutex
+	records     map[string][]TravelRecord
+	WarningDays int // 距183天不足该天数时提示，0时使用 DefaultResidencyWarningDays
+}
+
+// NewResidencyTracker 创建空的境内居住天数台账
+func NewResidencyTracker() *ResidencyTracker {
+	return &ResidencyTracker{records: make(map[string][]TravelRecord)}
+}
+
+// Add 登记出入境记录
+func (t *ResidencyTracker) Add(records ...TravelRecord) error {
+	for _, r := range records {
+		if !r.Departure.IsZero() && r.Departure.Before(r.Arrival) {
+			return fmt.Errorf("员工 %s 出境日期 %s 早于入境日期 %s", r.EmployeeID, r.Departure.Format("2006-01-02"), r.Arrival.Format("2006-01-02"))
+		}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, r := range records {
+		t.records[r.EmployeeID] = append(t.records[r.EmployeeID], r)
+	}
+	return nil
+}
+
+// Tracks 判断是否登记了员工的出入境记录
+func (t *ResidencyTracker) Tracks(employeeID string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.records[employeeID]) > 0
+}
+
+// dateOnly 返回日期所在日的零点
+func dateOnly(d time.Time) time.Time {
+	return time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, d.Location())
+}
+
+// DaysPresent 统计员工纳税年度内截至 asOf（含当天）的境内居住天数，重叠的停留记录不重复计算
+func (t *ResidencyTracker) DaysPresent(employeeID string, year int, asOf time.Time) int {
+	t.mu.Lock()
+	records := append([]TravelRecord(nil), t.records[employeeID]...)
+	t.mu.Unlock()
+
+	yearStart := time.Date(year, 1, 1, 0, 0, 0, 0, asOf.Location())
+	yearEnd := yearStart.AddDate(1, 0, 0)
+	last := dateOnly(asOf).AddDate(0, 0, 1)
+	if last.After(yearEnd) {
+		last = yearEnd
+	}
+
+	days := make(map[string]bool)
+	for _, r := range records {
+		// 入境次日起算，出境当天不计入；仍在境内时计至 asOf
+		from := dateOnly(r.Arrival).AddDate(0, 0, 1)
+		to := last
+		if !r.Departure.IsZero() && dateOnly(r.Departure).Before(to) {
+			to = dateOnly(r.Departure)
+		}
+		if from.Before(yearStart) {
+			from = yearStart
+		}
+		for d := from; d.Before(to); d = d.AddDate(0, 0, 1) {
+			days[d.Format("20060102")] = true
+		}
+	}
+	return len(days)
+}
+
+// Status 判断员工截至 asOf 的纳税人身份和是否需要提示
+// 返回值: (纳税人身份, 接近183天时的提示，否则为空)
+func (t *ResidencyTracker) Status(employeeID string, asOf time.Time) (TaxResidency, *ResidencyWarning) {
+	if !t.Tracks(employeeID) {
+		return ResidencyUntracked, nil
+	}
+	days := t.DaysPresent(employeeID, asOf.Year(), asOf)
+	if days >= ResidencyDays {
+		return Resident, nil
+	}
+	margin := t.WarningDays
+	if margin == 0 {
+		margin = DefaultResidencyWarningDays
+	}
+	if ResidencyDays-days <= margin {
+		return NonResident, &ResidencyWarning{EmployeeID: employeeID, Year: asOf.Year(), Days: days, Remaining: ResidencyDays - days}
+	}
+	return NonResident, nil
+}
+
+// ImportTravelRecordsCSV 导入出入境记录，首行为表头
+// 列：工号, 入境日期(YYYY-MM-DD), 出境日期(YYYY-MM-DD，仍在境内时留空)
+func ImportTravelRecordsCSV(r io.Reader) ([]TravelRecord, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	var records []TravelRecord
+	for i, row := range rows {
+		if i == 0 {
+			continue
+		}
+		line := i + 1
+		if len(row) < 2 {
+			return nil, fmt.Errorf("第%d行列数不足", line)
+		}
+		arrival, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(row[1]), time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("第%d行入境日期格式错误: %w", line, err)
+		}
+		record := TravelRecord{EmployeeID: strings.TrimSpace(row[0]), Arrival: arrival}
+		if len(row) > 2 && strings.TrimSpace(row[2]) != "" {
+			record.Departure, err = time.ParseInLocation("2006-01-02", strings.TrimSpace(row[2]), time.Local)
+			if err != nil {
+				return nil, fmt.Errorf("第%d行出境日期格式错误: %w", line, err)
+			}
+		}
+		records = append(records, record)
+	}
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Arrival.Before(records[j].Arrival) })
+	return records, nil
+}
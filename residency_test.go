@@ -0,0 +1,105 @@
+package salary
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func day(s string) time.Time {
+	d, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestResidencyTrackerDaysPresent(t *testing.T) {
+	tracker := NewResidencyTracker()
+	tracker.Add(
+		// 1月1日入境、1月11日出境：入境和出境当天不计，计9天
+		TravelRecord{EmployeeID: "F1", Arrival: day("2024-01-01"), Departure: day("2024-01-11")},
+		// 与上一条重叠的记录不重复计算
+		TravelRecord{EmployeeID: "F1", Arrival: day("2024-01-05"), Departure: day("2024-01-08")},
+		// 上年入境，跨年停留：从1月1日起计
+		TravelRecord{EmployeeID: "F2", Arrival: day("2023-12-20"), Departure: day("2024-01-03")},
+		// 当天往返不计
+		TravelRecord{EmployeeID: "F3", Arrival: day("2024-03-01"), Departure: day("2024-03-01")},
+	)
+	asOf := day("2024-12-31")
+	for id, want := range map[string]int{"F1": 9, "F2": 2, "F3": 0} {
+		if got := tracker.DaysPresent(id, 2024, asOf); got != want {
+			t.Errorf("DaysPresent(%s) = %d, want %d", id, got, want)
+		}
+	}
+}
+
+func TestResidencyTrackerStatus(t *testing.T) {
+	tracker := NewResidencyTracker()
+	tracker.Add(TravelRecord{EmployeeID: "F1", Arrival: day("2023-12-31")})
+
+	// 截至5月25日居住146天，距183天37天，不提示
+	if status, warning := tracker.Status("F1", day("2024-05-25")); status != NonResident || warning != nil {
+		t.Errorf("May 25: status = %s, warning = %v", status, warning)
+	}
+	// 截至6月15日居住167天，提示还差16天
+	status, warning := tracker.Status("F1", day("2024-06-15"))
+	if status != NonResident || warning == nil || warning.Remaining != 16 {
+		t.Errorf("June 15: status = %s, warning = %+v", status, warning)
+	}
+	if status, _ := tracker.Status("F1", day("2024-07-01")); status != Resident {
+		t.Errorf("July 1: status = %s, want 居民个人", status)
+	}
+	if status, _ := tracker.Status("OTHER", day("2024-07-01")); status != ResidencyUntracked {
+		t.Errorf("untracked employee status = %s", status)
+	}
+}
+
+func TestImportTravelRecordsCSV(t *testing.T) {
+	input := "工号,入境,出境\nF1,2024-01-01,2024-01-11\nF2,2024-02-01,\n"
+	records, err := ImportTravelRecordsCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || !records[1].Departure.IsZero() {
+		t.Errorf("records = %+v", records)
+	}
+	if _, err := ImportTravelRecordsCSV(strings.NewReader("h\nF1,2024/01/01\n")); err == nil {
+		t.Error("invalid date accepted")
+	}
+}
+
+func TestNonResidentIgnoresSpecialDeductions(t *testing.T) {
+	tracker := NewResidencyTracker()
+	tracker.Add(TravelRecord{EmployeeID: "F1", Arrival: day("2024-03-01")})
+	input := EmployeeInput{
+		Employee:   Employee{ID: "F1", HireDate: day("2024-03-01")},
+		Config:     testConfig(),
+		Attendance: AttendanceRecord{WorkHours: hours("174")},
+		Deductions: SpecialDeductions{ChildrenEducation: toMoney(cenToDec(200000))},
+		TaxState:   &TaxWithholdingState{},
+	}
+	run := PayrollRun{Period: day("2024-03-01"), Residency: tracker, Inputs: []EmployeeInput{input}}
+	result := run.Calculate()
+	if len(result.Errors) > 0 {
+		t.Fatal(result.Errors)
+	}
+	employee := result.Employees[0]
+	if employee.TaxResidency != NonResident || employee.TaxMethod != TaxMethodNonResident {
+		t.Errorf("residency = %s, method = %s", employee.TaxResidency, employee.TaxMethod)
+	}
+	if employee.TaxState != nil {
+		t.Error("non-resident used cumulative withholding")
+	}
+	// 子女教育扣除不生效：8000 - 1400 - 5000 = 1600元 × 3% = 48元
+	assertMoney(t, "special deductions", employee.SpecialDeductionTotal, "0")
+	assertMoney(t, "tax", employee.IncomeTax, "4800")
+
+	// 未跟踪的员工照常扣除：1600 - 2000 < 0，不纳税
+	run.Residency = nil
+	resident := run.Calculate().Employees[0]
+	if resident.TaxMethod != TaxMethodCumulative {
+		t.Errorf("untracked method = %s, want 累计预扣法", resident.TaxMethod)
+	}
+	assertMoney(t, "resident tax", resident.IncomeTax, "0")
+}
@@ -0,0 +1,187 @@
+package salary
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// resultDiffFields 比对的金额字段，顺序即报告中的列顺序
+var resultDiffFields = []struct {
+	key   string // 与导出记录JSON字段名一致，供应商文件列映射使用
+	name  string
+	value func(ExportRecord) Money
+	set   func(*ExportRecord, Money)
+}{
+	{"gross_salary", "税前工资", func(r ExportRecord) Money { return r.GrossSalary }, func(r *ExportRecord, m Money) { r.GrossSalary = m }},
+	{"social_insurance", "社保个人", func(r ExportRecord) Money { return r.SocialInsurance }, func(r *ExportRecord, m Money) { r.SocialInsurance = m }},
+	{"housing_fund", "公积金个人", func(r ExportRecord) Money { return r.HousingFund }, func(r *ExportRecord, m Money) { r.HousingFund = m }},
+	{"income_tax", "个人所得税", func(r ExportRecord) Money { return r.IncomeTax }, func(r *ExportRecord, m Money) { r.IncomeTax = m }},
+	{"net_salary", "实发工资", func(r ExportRecord) Money { return r.NetSalary }, func(r *ExportRecord, m Money) { r.NetSalary = m }},
+}
+
+// FieldDiff 单个金额字段的差异
+type FieldDiff struct {
+	Field string `json:"field"` // 字段名称
+	A     Money  `json:"a"`     // 第一份导出中的金额（分）
+	B     Money  `json:"b"`     // 第二份导出中的金额（分）
+	Delta Money  `json:"delta"` // B - A（分）
+}
+
+// EmployeeDiff 单个员工单个薪资期的差异
+type EmployeeDiff struct {
+	EmployeeID string      `json:"employee_id"` // 工号
+	Name       string      `json:"name"`        // 姓名
+	Period     string      `json:"period"`      // 薪资期，如 2024-05
+	Fields     []FieldDiff `json:"fields"`      // 超出容差的字段
+}
+
+// ResultDiff 两份工资结果导出的比对结果，用于从原薪资供应商迁移时的并行核对
+type ResultDiff struct {
+	Matched   int            `json:"matched"`   // 两份导出中都存在的员工薪资期数
+	Identical int            `json:"identical"` // 全部字段在容差内一致的数量
+	Changed   []EmployeeDiff `json:"changed"`   // 存在差异的员工，按薪资期和工号排序
+	OnlyInA   []string       `json:"only_in_a"` // 仅在第一份导出中的“工号 薪资期”
+	OnlyInB   []string       `json:"only_in_b"` // 仅在第二份导出中的“工号 薪资期”
+	Totals    []FieldDiff    `json:"totals"`    // 各字段合计，A、B 为两份导出各自的合计（含仅在一方的记录）
+}
+
+// ReadResultExport 读取工资结果导出JSON，支持 /exports/results 的响应体和记录数组两种格式
+func ReadResultExport(r io.Reader) ([]ExportRecord, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimSpace(data)
+	var records []ExportRecord
+	if len(data) > 0 && data[0] == '[' {
+		err = json.Unmarshal(data, &records)
+	} else {
+		var body struct {
+			Records []ExportRecord `json:"records"`
+		}
+		err = json.Unmarshal(data, &body)
+		records = body.Records
+	}
+	if err != nil {
+		return nil, fmt.Errorf("解析工资结果导出: %w", err)
+	}
+	return records, nil
+}
+
+// diffKey 按工号和薪资期匹配记录
+func diffKey(r ExportRecord) string {
+	return r.EmployeeID + " " + r.Period.Format("2006-01")
+}
+
+// DiffResults 按工号和薪资期比对两份工资结果导出
+// 同一员工同一薪资期出现多次时以最后一条为准（增量导出中后出现的记录为更正后的结果）
+// a、b: 两份导出的记录
+// tolerance: 允许的差额（分），绝对值不超过该值视为一致
+func DiffResults(a, b []ExportRecord, tolerance Money) ResultDiff {
+	index := func(records []ExportRecord) map[string]ExportRecord {
+		m := make(map[string]ExportRecord, len(records))
+		for _, r := range records {
+			m[diffKey(r)] = r
+		}
+		return m
+	}
+	left, right := index(a), index(b)
+	limit := moneyToDec(tolerance).Abs()
+
+	diff := ResultDiff{Totals: make([]FieldDiff, len(resultDiffFields))}
+	sums := make([][2]decimal.Decimal, len(resultDiffFields))
+	for key, ra := range left {
+		for i, f := range resultDiffFields {
+			sums[i][0] = sums[i][0].Add(moneyToDec(f.value(ra)))
+		}
+		rb, ok := right[key]
+		if !ok {
+			diff.OnlyInA = append(diff.OnlyInA, key)
+			continue
+		}
+		diff.Matched++
+		changed := EmployeeDiff{EmployeeID: ra.EmployeeID, Name: ra.Name, Period: ra.Period.Format("2006-01")}
+		for _, f := range resultDiffFields {
+			va, vb := moneyToDec(f.value(ra)), moneyToDec(f.value(rb))
+			if delta := vb.Sub(va); delta.Abs().GreaterThan(limit) {
+				changed.Fields = append(changed.Fields, FieldDiff{Field: f.name, A: toMoney(va), B: toMoney(vb), Delta: toMoney(delta)})
+			}
+		}
+		if len(changed.Fields) == 0 {
+			diff.Identical++
+			continue
+		}
+		diff.Changed = append(diff.Changed, changed)
+	}
+	for key, rb := range right {
+		for i, f := range resultDiffFields {
+			sums[i][1] = sums[i][1].Add(moneyToDec(f.value(rb)))
+		}
+		if _, ok := left[key]; !ok {
+			diff.OnlyInB = append(diff.OnlyInB, key)
+		}
+	}
+	for i, f := range resultDiffFields {
+		diff.Totals[i] = FieldDiff{Field: f.name, A: toMoney(sums[i][0]), B: toMoney(sums[i][1]), Delta: toMoney(sums[i][1].Sub(sums[i][0]))}
+	}
+
+	sort.Slice(diff.Changed, func(i, j int) bool {
+		if diff.Changed[i].Period != diff.Changed[j].Period {
+			return diff.Changed[i].Period < diff.Changed[j].Period
+		}
+		return diff.Changed[i].EmployeeID < diff.Changed[j].EmployeeID
+	})
+	sort.Strings(diff.OnlyInA)
+	sort.Strings(diff.OnlyInB)
+	return diff
+}
+
+// Empty 两份导出是否完全一致
+func (d ResultDiff) Empty() bool {
+	return len(d.Changed) == 0 && len(d.OnlyInA) == 0 && len(d.OnlyInB) == 0
+}
+
+// WriteReport 输出可读的比对报告，金额单位为元
+func (d ResultDiff) WriteReport(out io.Writer) error {
+	var w bytes.Buffer
+	fmt.Fprintf(&w, "匹配 %d 条，一致 %d 条，存在差异 %d 条，仅在A %d 条，仅在B %d 条\n",
+		d.Matched, d.Identical, len(d.Changed), len(d.OnlyInA), len(d.OnlyInB))
+	d.writeDetails(&w, "员工差异", "仅在A中", "仅在B中")
+	_, err := out.Write(w.Bytes())
+	return err
+}
+
+// writeDetails 输出差异员工、仅在一方的记录和合计
+func (d ResultDiff) writeDetails(w *bytes.Buffer, changedTitle, onlyATitle, onlyBTitle string) {
+	if len(d.Changed) > 0 {
+		fmt.Fprintf(w, "\n---------------- %s ----------------\n", changedTitle)
+		for _, e := range d.Changed {
+			fmt.Fprintf(w, "%s %s %s\n", e.Period, e.EmployeeID, e.Name)
+			for _, f := range e.Fields {
+				fmt.Fprintf(w, "  %-10s %12s → %12s  差额 %12s\n", f.Field, FormatMoneyCenToYuan(f.A), FormatMoneyCenToYuan(f.B), FormatMoneyCenToYuan(f.Delta))
+			}
+		}
+	}
+	for _, side := range []struct {
+		title string
+		keys  []string
+	}{{onlyATitle, d.OnlyInA}, {onlyBTitle, d.OnlyInB}} {
+		if len(side.keys) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n---------------- %s ----------------\n", side.title)
+		for _, key := range side.keys {
+			fmt.Fprintln(w, key)
+		}
+	}
+
+	fmt.Fprintln(w, "\n---------------- 合计 ----------------")
+	for _, f := range d.Totals {
+		fmt.Fprintf(w, "%-10s %14s → %14s  差额 %14s\n", f.Field, FormatMoneyCenToYuan(f.A), FormatMoneyCenToYuan(f.B), FormatMoneyCenToYuan(f.Delta))
+	}
+}
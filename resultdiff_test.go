@@ -0,0 +1,49 @@
+package salary
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDiffResults(t *testing.T) {
+	record := func(id string, net int64) ExportRecord {
+		return ExportRecord{EmployeeID: id, Name: "员工" + id, Period: day("2024-05-01"), NetSalary: toMoney(cenToDec(net))}
+	}
+	a := []ExportRecord{record("E001", 700000), record("E002", 650000), record("E003", 500000)}
+	b := []ExportRecord{record("E001", 700001), record("E002", 640000), record("E004", 300000)}
+
+	diff := DiffResults(a, b, toMoney(cenToDec(1)))
+	if diff.Matched != 2 || diff.Identical != 1 || len(diff.Changed) != 1 {
+		t.Fatalf("matched %d identical %d changed %d", diff.Matched, diff.Identical, len(diff.Changed))
+	}
+	assertMoney(t, "E002 delta", diff.Changed[0].Fields[0].Delta, "-10000")
+	if len(diff.OnlyInA) != 1 || diff.OnlyInA[0] != "E003 2024-05" || len(diff.OnlyInB) != 1 {
+		t.Errorf("only in A %v, only in B %v", diff.OnlyInA, diff.OnlyInB)
+	}
+	net := diff.Totals[len(diff.Totals)-1]
+	assertMoney(t, "net total delta", net.Delta, "-209999")
+
+	var buf bytes.Buffer
+	if err := diff.WriteReport(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "E002") || strings.Contains(buf.String(), "E001 员工E001") {
+		t.Errorf("report:\n%s", buf.String())
+	}
+}
+
+func TestReadResultExport(t *testing.T) {
+	for _, body := range []string{
+		`{"cursor": 2, "records": [{"employee_id": "E001", "period": "2024-05-01T00:00:00Z", "net_salary": "700000"}]}`,
+		`[{"employee_id": "E001", "period": "2024-05-01T00:00:00Z", "net_salary": "700000"}]`,
+	} {
+		records, err := ReadResultExport(strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != 1 || records[0].EmployeeID != "E001" {
+			t.Errorf("records = %+v", records)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package salary
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// RetentionAction 超过保留期限的员工明细的处理方式
+type RetentionAction int
+
+const (
+	RetentionAnonymize RetentionAction = iota // 匿名化：保留金额明细，清除可识别个人身份的信息
+	RetentionPurge                            // 删除：删除员工明细，仅保留批次汇总
+)
+
+// RetentionPolicy 薪资数据保留策略
+type RetentionPolicy struct {
+	Months int             // 保留月数，薪资期早于 当前月份 - Months 的批次将被处理
+	Action RetentionAction // 处理方式
+}
+
+// RunTotals 批次汇总，删除员工明细后保留用于历史统计
+type RunTotals struct {
+	Headcount       int   // 发薪人数
+	GrossSalary     Money // 税前工资合计（分）
+	SocialInsurance Money // 社保个人部分合计
+	HousingFund     Money // 公积金个人部分合计
+	IncomeTax       Money // 个人所得税合计
+	NetSalary       Money // 实发合计
+}
+
+// Totals 计算批次汇总；员工明细已删除时返回保留的汇总
+func (r PayrollResult) Totals() RunTotals {
+	if r.Retained != nil {
+		return *r.Retained
+	}
+	var gross, si, fund, tax, net decimal.Decimal
+	for _, e := range r.Employees {
+		gross = gross.Add(moneyToDec(e.GrossSalary))
+		si = si.Add(moneyToDec(e.SocialInsurance))
+		fund = fund.Add(moneyToDec(e.HousingFund))
+		tax = tax.Add(moneyToDec(e.IncomeTax))
+		net = net.Add(moneyToDec(e.NetSalary))
+	}
+	return RunTotals{
+		Headcount:       len(r.Employees),
+		GrossSalary:     toMoney(gross),
+		SocialInsurance: toMoney(si),
+		HousingFund:     toMoney(fund),
+		IncomeTax:       toMoney(tax),
+		NetSalary:       toMoney(net),
+	}
+}
+
+// RetentionItem 保留策略处理的一个批次
+type RetentionItem struct {
+	RunID     string    // 批次编号
+	Period    time.Time // 薪资期
+	Employees int       // 处理的员工明细条数
+}
+
+// RetentionReport 保留策略执行报告
+type RetentionReport struct {
+	DryRun bool            // 是否为试运行，试运行不修改数据
+	Action RetentionAction // 处理方式
+	Cutoff time.Time       // 早于该薪资期的批次被处理
+	Items  []RetentionItem // 处理的批次
+}
+
+// pseudonym 生成不可逆的员工化名，同一工号得到相同化名，匿名化后仍可按员工统计
+func pseudonym(employeeID string) string {
+	sum := sha256.Sum256([]byte(employeeID))
+	return "ANON-" + hex.EncodeToString(sum[:6])
+}
+
+// anonymizeEmployee 清除员工档案中可识别个人身份的信息，保留部门、职级等统计维度
+func anonymizeEmployee(e Employee) Employee {
+	return Employee{
+		ID:           pseudonym(e.ID),
+		Department:   e.Department,
+		Grade:        e.Grade,
+		Gender:       e.Gender,
+		City:         e.City,
+		Jurisdiction: e.Jurisdiction,
+	}
+}
+
+// ApplyRetention 按保留策略匿名化或删除超过保留期限的员工明细，批次汇总始终保留
+// now: 当前时间，用于计算保留期限
+// dryRun: 为真时只生成报告，不修改数据
+// audit: 审计日志，为空表示不记录；试运行不记录
+// actor: 操作人
+func (s *MemoryStore) ApplyRetention(policy RetentionPolicy, now time.Time, dryRun bool, audit *AuditLog, actor string) RetentionReport {
+	cutoff := monthStart(now).AddDate(0, -policy.Months, 0)
+	report := RetentionReport{DryRun: dryRun, Action: policy.Action, Cutoff: cutoff}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range s.runOrder {
+		run := s.runs[id]
+		if !run.Period.Before(cutoff) || len(run.Employees) == 0 {
+			continue
+		}
+		// 已匿名化的批次不重复处理
+		if policy.Action == RetentionAnonymize && run.Anonymized {
+			continue
+		}
+		report.Items = append(report.Items, RetentionItem{RunID: id, Period: run.Period, Employees: len(run.Employees)})
+		if dryRun {
+			continue
+		}
+
+		if !run.Anonymized {
+			errs := make([]EmployeeError, len(run.Errors))
+			for i, e := range run.Errors {
+				errs[i] = EmployeeError{EmployeeID: pseudonym(e.EmployeeID), Err: e.Err}
+			}
+			run.Errors = errs
+			run.PolicyMisses = nil
+			run.WageFloorViolations = nil
+			run.Inputs = nil
+			run.Warnings = nil
+		}
+		switch policy.Action {
+		case RetentionPurge:
+			totals := run.Totals()
+			run.Retained = &totals
+			run.Employees = nil
+		default:
+			employees := make([]EmployeeResult, len(run.Employees))
+			for i, e := range run.Employees {
+				e.Employee = anonymizeEmployee(e.Employee)
+				employees[i] = e
+			}
+			run.Employees = employees
+			run.Anonymized = true
+		}
+		s.runs[id] = run
+	}
+
+	if !dryRun && audit != nil {
+		action, verb := "retention.anonymized", "匿名化"
+		if policy.Action == RetentionPurge {
+			action, verb = "retention.purged", "删除"
+		}
+		for _, item := range report.Items {
+			audit.Record(AuditEntry{
+				Actor:  actor,
+				Action: action,
+				Period: item.Period,
+				Detail: fmt.Sprintf("批次%s超过保留期限，%s%d条员工明细", item.RunID, verb, item.Employees),
+			})
+		}
+	}
+	return report
+}
@@ -0,0 +1,75 @@
+package salary
+
+import (
+	"strings"
+	"testing"
+)
+
+func retentionStore() (*MemoryStore, *PayrollResult, *PayrollResult) {
+	store := NewMemoryStore()
+	employee := func(id string, net int64) EmployeeResult {
+		return EmployeeResult{
+			Employee:    Employee{ID: id, Name: "张" + id, Department: "研发部", Grade: "P5"},
+			GrossSalary: toMoney(cenToDec(net + 200000)),
+			NetSalary:   toMoney(cenToDec(net)),
+		}
+	}
+	old := &PayrollResult{Period: day("2017-03-01"), Employees: []EmployeeResult{employee("E1", 600000), employee("E2", 400000)}}
+	recent := &PayrollResult{Period: day("2024-03-01"), Employees: []EmployeeResult{employee("E1", 700000)}}
+	store.SaveRun(old)
+	store.SaveRun(recent)
+	return store, old, recent
+}
+
+func TestApplyRetentionAnonymize(t *testing.T) {
+	store, old, recent := retentionStore()
+	policy := RetentionPolicy{Months: 84, Action: RetentionAnonymize}
+	now := day("2024-06-15")
+
+	report := store.ApplyRetention(policy, now, true, nil, "dpo")
+	if len(report.Items) != 1 || report.Items[0].RunID != old.ID || report.Items[0].Employees != 2 || !report.Cutoff.Equal(day("2017-06-01")) {
+		t.Fatalf("dry run report = %+v", report)
+	}
+	if run, _ := store.Run(old.ID); run.Employees[0].Employee.Name == "" {
+		t.Fatal("dry run must not modify data")
+	}
+
+	audit := NewAuditLog()
+	store.ApplyRetention(policy, now, false, audit, "dpo")
+	run, _ := store.Run(old.ID)
+	e := run.Employees[0].Employee
+	if e.Name != "" || !strings.HasPrefix(e.ID, "ANON-") || e.ID != pseudonym("E1") || e.Department != "研发部" || !run.Anonymized {
+		t.Errorf("anonymized employee = %+v", e)
+	}
+	assertMoney(t, "net kept", run.Employees[0].NetSalary, "600000")
+	if run, _ := store.Run(recent.ID); run.Employees[0].Employee.ID != "E1" {
+		t.Error("recent run should be untouched")
+	}
+	entries := audit.Entries()
+	if len(entries) != 1 || entries[0].Action != "retention.anonymized" || entries[0].Actor != "dpo" {
+		t.Errorf("audit = %+v", entries)
+	}
+
+	// 已匿名化的批次不重复处理
+	if report := store.ApplyRetention(policy, now, false, nil, "dpo"); len(report.Items) != 0 {
+		t.Errorf("second run items = %+v", report.Items)
+	}
+}
+
+func TestApplyRetentionPurgeKeepsTotals(t *testing.T) {
+	store, old, _ := retentionStore()
+	before, _ := store.Run(old.ID)
+	want := before.Totals()
+
+	store.ApplyRetention(RetentionPolicy{Months: 84, Action: RetentionPurge}, day("2024-06-15"), false, nil, "dpo")
+	run, _ := store.Run(old.ID)
+	if len(run.Employees) != 0 || run.Retained == nil {
+		t.Fatalf("purged run = %+v", run)
+	}
+	totals := run.Totals()
+	if totals.Headcount != 2 {
+		t.Errorf("headcount = %d", totals.Headcount)
+	}
+	assertMoney(t, "net total", totals.NetSalary, "1000000")
+	assertMoney(t, "gross total", totals.GrossSalary, moneyToDec(want.GrossSalary).String())
+}
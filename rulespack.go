@@ -0,0 +1,130 @@
+package salary
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// RulesPack 法规规则包：税率表、城市政策、各类限额及生效日期
+// 法规调整时由发布方签名分发，用户放入本地或从URL获取即可更新规则，无需等待新版本程序
+type RulesPack struct {
+	Version       string                  `json:"version"`        // 规则包版本
+	EffectiveFrom time.Time               `json:"effective_from"` // 生效日期
+	TaxBrackets   []TaxBracket            `json:"tax_brackets"`   // 个人所得税税率表
+	Regions       map[string]RegionPolicy `json:"regions"`        // 城市社保公积金政策，按城市代码索引
+	Limits        map[string]Money        `json:"limits"`         // 各类限额（分），如专项附加扣除上限
+}
+
+// signedRulesPack 规则包分发文件格式：规则包JSON的base64编码及其Ed25519签名
+type signedRulesPack struct {
+	Payload   string `json:"payload"`   // 规则包JSON（base64编码）
+	Signature string `json:"signature"` // 对规则包JSON原文的Ed25519签名（base64编码）
+}
+
+// ErrRulesPackSignature 规则包签名校验失败
+var ErrRulesPackSignature = errors.New("规则包签名校验失败")
+
+// validateTaxBrackets 校验税率表：起征点严格递增，税率在(0,1]区间内
+func validateTaxBrackets(brackets []TaxBracket) error {
+	if len(brackets) == 0 {
+		return errors.New("税率表为空")
+	}
+	for i, b := range brackets {
+		if !b.Rate.IsPositive() || b.Rate.GreaterThan(decimal.NewFromInt(1)) {
+			return fmt.Errorf("第%d档税率 %s 不在(0,1]区间内", i+1, b.Rate)
+		}
+		if i > 0 && !moneyToDec(b.Threshold).GreaterThan(moneyToDec(brackets[i-1].Threshold)) {
+			return fmt.Errorf("第%d档起征点未大于上一档", i+1)
+		}
+	}
+	return nil
+}
+
+// Validate 校验规则包内容
+func (p *RulesPack) Validate() error {
+	if p.Version == "" {
+		return errors.New("规则包缺少版本号")
+	}
+	if len(p.TaxBrackets) > 0 {
+		if err := validateTaxBrackets(p.TaxBrackets); err != nil {
+			return fmt.Errorf("规则包 %s: %w", p.Version, err)
+		}
+	}
+	for _, region := range p.Regions {
+		if err := region.Validate(); err != nil {
+			return fmt.Errorf("规则包 %s: %w", p.Version, err)
+		}
+	}
+	return nil
+}
+
+// SignRulesPack 使用发布方私钥签名规则包，生成可分发的规则包文件内容
+func SignRulesPack(pack *RulesPack, key ed25519.PrivateKey) ([]byte, error) {
+	if err := pack.Validate(); err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(pack)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(signedRulesPack{
+		Payload:   base64.StdEncoding.EncodeToString(payload),
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(key, payload)),
+	}, "", "  ")
+}
+
+// LoadRulesPack 读取规则包文件，校验签名和内容后返回规则包
+// r: 规则包文件内容
+// publicKey: 发布方公钥
+func LoadRulesPack(r io.Reader, publicKey ed25519.PublicKey) (*RulesPack, error) {
+	var signed signedRulesPack
+	if err := json.NewDecoder(r).Decode(&signed); err != nil {
+		return nil, fmt.Errorf("规则包文件格式错误: %w", err)
+	}
+	payload, err := base64.StdEncoding.DecodeString(signed.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("规则包内容编码错误: %w", err)
+	}
+	signature, err := base64.StdEncoding.DecodeString(signed.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("规则包签名编码错误: %w", err)
+	}
+	if !ed25519.Verify(publicKey, payload, signature) {
+		return nil, ErrRulesPackSignature
+	}
+
+	var pack RulesPack
+	if err := json.Unmarshal(payload, &pack); err != nil {
+		return nil, fmt.Errorf("规则包内容格式错误: %w", err)
+	}
+	if err := pack.Validate(); err != nil {
+		return nil, err
+	}
+	return &pack, nil
+}
+
+// FetchRulesPack 从URL下载规则包并校验
+func FetchRulesPack(ctx context.Context, url string, publicKey ed25519.PublicKey) (*RulesPack, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("下载规则包失败: HTTP状态 %d", resp.StatusCode)
+	}
+	return LoadRulesPack(resp.Body, publicKey)
+}
@@ -0,0 +1,105 @@
+package salary
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func testRulesPack() *RulesPack {
+	return &RulesPack{
+		Version:       "2024.01",
+		EffectiveFrom: day("2024-01-01"),
+		TaxBrackets:   DefaultTaxBrackets(),
+		Limits:        map[string]Money{"child_education": toMoney(cenToDec(200000))},
+	}
+}
+
+func TestRulesPackSignAndLoad(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := SignRulesPack(testRulesPack(), private)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pack, err := LoadRulesPack(bytes.NewReader(data), public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pack.Version != "2024.01" || len(pack.TaxBrackets) != len(DefaultTaxBrackets()) || !pack.EffectiveFrom.Equal(day("2024-01-01")) {
+		t.Errorf("pack = %+v", pack)
+	}
+	assertMoney(t, "child_education", pack.Limits["child_education"], "200000")
+
+	otherPublic, _, _ := ed25519.GenerateKey(nil)
+	if _, err := LoadRulesPack(bytes.NewReader(data), otherPublic); !errors.Is(err, ErrRulesPackSignature) {
+		t.Errorf("wrong key err = %v", err)
+	}
+
+	// 篡改内容后签名失效
+	var signed signedRulesPack
+	if err := json.Unmarshal(data, &signed); err != nil {
+		t.Fatal(err)
+	}
+	payload, _ := base64.StdEncoding.DecodeString(signed.Payload)
+	signed.Payload = base64.StdEncoding.EncodeToString(bytes.Replace(payload, []byte("2024.01"), []byte("2024.02"), 1))
+	tampered, _ := json.Marshal(signed)
+	if _, err := LoadRulesPack(bytes.NewReader(tampered), public); !errors.Is(err, ErrRulesPackSignature) {
+		t.Errorf("tampered err = %v", err)
+	}
+}
+
+func TestRulesPackValidate(t *testing.T) {
+	_, private, _ := ed25519.GenerateKey(nil)
+	pack := testRulesPack()
+	pack.Version = ""
+	if _, err := SignRulesPack(pack, private); err == nil {
+		t.Error("pack without version should not be signed")
+	}
+
+	pack = testRulesPack()
+	pack.TaxBrackets[1].Threshold = pack.TaxBrackets[0].Threshold
+	if err := pack.Validate(); err == nil {
+		t.Error("non-increasing thresholds should fail")
+	}
+	pack = testRulesPack()
+	pack.TaxBrackets[0].Rate = decimal.RequireFromString("1.5")
+	if err := pack.Validate(); err == nil {
+		t.Error("rate above 1 should fail")
+	}
+}
+
+func TestFetchRulesPack(t *testing.T) {
+	public, private, _ := ed25519.GenerateKey(nil)
+	data, err := SignRulesPack(testRulesPack(), private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rules.json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	}))
+	defer srv.Close()
+
+	pack, err := FetchRulesPack(context.Background(), srv.URL+"/rules.json", public)
+	if err != nil || pack.Version != "2024.01" {
+		t.Fatalf("pack = %+v, err = %v", pack, err)
+	}
+	if _, err := FetchRulesPack(context.Background(), srv.URL+"/missing", public); err == nil {
+		t.Error("404 should fail")
+	}
+}
@@ -1,7 +1,6 @@
-package main
+package salary
 
 import (
-	"fmt"
 	"github.com/shopspring/decimal" // 导入高精度十进制计算库
 )
 
@@ -22,6 +21,16 @@ type PayrollConfig struct {
 	OvertimeWeekdayRate decimal.Decimal // 工作日加班费率倍数（如1.5表示1.5倍）
 	OvertimeWeekendRate decimal.Decimal // 周末加班费率倍数
 	OvertimeHolidayRate decimal.Decimal // 节假日加班费率倍数
+	HousingFundBaseCap  Money           // 公积金免税基数上限（分），通常为当地上年职工月平均工资的3倍，0表示不限
+	Tax                 *TaxConfig      // 个税配置，为空时使用 DefaultTaxConfig()
+
+	// 各险种缴费基数上下限，工资低于下限按下限、高于上限按上限缴纳（如当地社平工资的60%至300%），零值表示不限
+	PensionBase      BaseLimits // 养老保险缴费基数上下限
+	MedicalBase      BaseLimits // 医疗保险缴费基数上下限
+	UnemploymentBase BaseLimits // 失业保险缴费基数上下限
+	HousingFundBase  BaseLimits // 公积金缴存基数上下限
+
+	Employer EmployerRates // 单位缴费费率，用于计算人工成本，不影响员工实发
 }
 
 // AttendanceRecord 员工考勤记录，包含工作时长和加班信息
@@ -31,6 +40,9 @@ type AttendanceRecord struct {
 	OvertimeWeekend Hours // 周末加班时间（小时）
 	OvertimeHoliday Hours // 节假日加班时间（小时）
 	AbsenceHours    Hours // 缺勤时间（小时）
+	ScheduledHours  Hours // 按排班计算的本期应出勤工时，非零时替代 FullMonthHours 计算小时工资
+
+	Shifts []ShiftHours // 按班次津贴规则汇总的出勤小时
 }
 
 // SpecialDeductions 个人所得税专项附加扣除项
@@ -44,31 +56,9 @@ type SpecialDeductions struct {
 
 // TaxBracket 税率档次结构，用于累进税率计算
 type TaxBracket struct {
-	Threshold Money           // 该税率档次的起征点（分）
-	Rate      decimal.Decimal // 税率（如0.1表示10%）
-	Deduction Money           // 速算扣除数（分）
-}
-
-// GetTaxBrackets 初始化个人所得税税率表
-func GetTaxBrackets() []TaxBracket {
-	return []TaxBracket{
-		{
-			Threshold: toMoney(decimal.Zero),
-			Rate:      decimal.RequireFromString("0.03"),
-			Deduction: toMoney(decimal.Zero),
-		},
-		{
-			Threshold: toMoney(decimal.NewFromInt(6000)),
-			Rate:      decimal.RequireFromString("1.45"),
-			Deduction: toMoney(decimal.NewFromInt(2520)),
-		},
-		{
-			Threshold: toMoney(decimal.NewFromInt(1440000)),
-			Rate:      decimal.RequireFromString("0.80"),
-			Deduction: toMoney(decimal.NewFromInt(16920)),
-		},
-		// todo可根据实际情况添加更多税率档次
-	}
+	Threshold Money           `json:"threshold"` // 该税率档次的下限（分），应纳税所得额超过该值时适用本档
+	Rate      decimal.Decimal `json:"rate"`      // 税率（如0.1表示10%）
+	Deduction Money           `json:"deduction"` // 速算扣除数（分）
 }
 
 // toDec 辅助函数：将Money类型转换为decimal.Decimal
@@ -91,13 +81,58 @@ func cenToDec(cen int64) decimal.Decimal {
 	return decimal.NewFromInt(cen)
 }
 
+// MarshalJSON 金额序列化为十进制字符串（单位:分），避免浮点误差
+func (m Money) MarshalJSON() ([]byte, error) {
+	return decimal.Decimal(m).MarshalJSON()
+}
+
+// UnmarshalJSON 从十进制字符串或数字解析金额（单位:分）
+func (m *Money) UnmarshalJSON(data []byte) error {
+	return (*decimal.Decimal)(m).UnmarshalJSON(data)
+}
+
+// MarshalJSON 工时序列化为十进制字符串（单位:小时）
+func (h Hours) MarshalJSON() ([]byte, error) {
+	return decimal.Decimal(h).MarshalJSON()
+}
+
+// UnmarshalJSON 从十进制字符串或数字解析工时（单位:小时）
+func (h *Hours) UnmarshalJSON(data []byte) error {
+	return (*decimal.Decimal)(h).UnmarshalJSON(data)
+}
+
+// HourlyRate 计算小时工资 = 基本工资 / 全月标准工作小时
+// 全月标准工作小时为0（如整月停薪留职）时返回0，避免除零
+func HourlyRate(config PayrollConfig) decimal.Decimal {
+	if moneyToDec(config.FullMonthHours).IsZero() {
+		return decimal.Zero
+	}
+	return moneyToDec(config.BaseSalary).Div(moneyToDec(config.FullMonthHours))
+}
+
+// attendanceHourlyRate 计算考勤适用的小时工资：按排班计算了应出勤工时时以其为标准工时，否则按 HourlyRate 计算
+func attendanceHourlyRate(config PayrollConfig, attendance AttendanceRecord) decimal.Decimal {
+	if scheduled := hoursToDec(attendance.ScheduledHours); scheduled.IsPositive() {
+		return moneyToDec(config.BaseSalary).Div(scheduled)
+	}
+	return HourlyRate(config)
+}
+
+// standardHours 返回本期标准工时：按排班计算的应出勤工时，未排班时为配置的每月标准工时
+func standardHours(config PayrollConfig, attendance AttendanceRecord) Hours {
+	if hoursToDec(attendance.ScheduledHours).IsPositive() {
+		return attendance.ScheduledHours
+	}
+	return Hours(moneyToDec(config.FullMonthHours))
+}
+
 // CalculateBaseSalary 计算基础工资（考虑缺勤扣款）
 // config: 薪资配置
 // attendance: 考勤记录
 // 返回值: 计算后的基础工资
 func CalculateBaseSalary(config PayrollConfig, attendance AttendanceRecord) Money {
-	// 计算小时工资 = 基本工资 / 全月标准工作小时
-	hourlyRate := moneyToDec(config.BaseSalary).Div(moneyToDec(config.FullMonthHours))
+	// 计算小时工资 = 基本工资 / 全月标准工作小时（按排班时为本期应出勤工时）
+	hourlyRate := attendanceHourlyRate(config, attendance)
 
 	// 计算缺勤扣款 = 小时工资 × 缺勤小时
 	absenceDeduction := hourlyRate.Mul(hoursToDec(attendance.AbsenceHours))
@@ -115,7 +150,7 @@ func CalculateBaseSalary(config PayrollConfig, attendance AttendanceRecord) Mone
 // 返回值: 加班工资总额
 func CalculateOvertimePay(config PayrollConfig, attendance AttendanceRecord) Money {
 	// 计算小时工资
-	hourlyRate := moneyToDec(config.BaseSalary).Div(moneyToDec(config.FullMonthHours))
+	hourlyRate := attendanceHourlyRate(config, attendance)
 
 	// 初始化加班工资总额
 	total := decimal.Zero
@@ -148,66 +183,109 @@ func CalculateOvertimePay(config PayrollConfig, attendance AttendanceRecord) Mon
 	return toMoney(total.Round(2))
 }
 
-// CalculateSocialInsurance 计算社保和公积金
+// CalculateSocialInsurance 计算社保和公积金，各险种的缴费基数按配置的上下限封顶保底
 // config: 薪资配置
 // baseSalary: 计算社保的工资基数
 // 返回值: (社保总额, 公积金)
 func CalculateSocialInsurance(config PayrollConfig, baseSalary Money) (socialInsurance, housingFund Money) {
-	base := moneyToDec(baseSalary)
+	socialInsurance, housingFund, _ = calculateSocialInsurance(config, baseSalary)
+	return socialInsurance, housingFund
+}
+
+// InsuranceBaseClamp 某一险种的缴费基数封顶保底情况
+type InsuranceBaseClamp struct {
+	Insurance string    // 险种：pension、medical、unemployment、housing_fund
+	Wage      Money     // 工资基数（分）
+	Base      Money     // 实际缴费基数（分）
+	Clamp     BaseClamp // 按下限保底或按上限封顶
+}
+
+// calculateSocialInsurance 计算社保和公积金，并返回发生封顶保底的险种
+func calculateSocialInsurance(config PayrollConfig, baseSalary Money) (socialInsurance, housingFund Money, clamps []InsuranceBaseClamp) {
+	base := func(insurance string, limits BaseLimits) decimal.Decimal {
+		clamped, clamp := limits.Clamp(baseSalary)
+		if clamp != ClampNone {
+			clamps = append(clamps, InsuranceBaseClamp{Insurance: insurance, Wage: baseSalary, Base: clamped, Clamp: clamp})
+		}
+		return moneyToDec(clamped)
+	}
 
 	// 计算养老保险 = 基数 × 费率
-	pension := base.Mul(config.PensionRate)
+	pension := base("pension", config.PensionBase).Mul(config.PensionRate)
 
 	// 计算医疗保险
-	medical := base.Mul(config.MedicalRate)
+	medical := base("medical", config.MedicalBase).Mul(config.MedicalRate)
 
 	// 计算失业保险
-	unemployment := base.Mul(config.UnemploymentRate)
+	unemployment := base("unemployment", config.UnemploymentBase).Mul(config.UnemploymentRate)
 
 	// 计算公积金 = 基数 × 公积金费率
-	housingFund = toMoney(base.Mul(config.HousingFundRate).Round(2))
+	housingFund = toMoney(base("housing_fund", config.HousingFundBase).Mul(config.HousingFundRate).Round(2))
 
-	// 计算社保总额 = 养老 + 医疗 + 失业
+	// 计算社保总额 = 养老 + 医疗 + 失业，四舍五入到分
 	socialInsurance = toMoney(pension.Add(medical).Add(unemployment).Round(2))
+	return socialInsurance, housingFund, clamps
+}
 
-	// 四舍五入到分
-	return socialInsurance, housingFund
+// HousingFundExemptRate 公积金个人缴存部分免税比例上限
+var HousingFundExemptRate = decimal.RequireFromString("0.12")
+
+// HousingFundExcess 计算个人公积金缴存额中超过免税限额、需并入应纳税所得额的部分
+// 免税限额 = min(缴存基数, 免税基数上限) × 12%
+// config: 薪资配置
+// baseSalary: 缴存基数
+// housingFund: 个人实际缴存额
+// 返回值: 超额部分
+func HousingFundExcess(config PayrollConfig, baseSalary, housingFund Money) Money {
+	base := moneyToDec(baseSalary)
+	if limit := moneyToDec(config.HousingFundBaseCap); limit.IsPositive() {
+		base = decimal.Min(base, limit)
+	}
+	exempt := base.Mul(HousingFundExemptRate).Round(2)
+	return toMoney(decimal.Max(moneyToDec(housingFund).Sub(exempt), decimal.Zero))
 }
 
-// CalculateIncomeTax 计算个人所得税
+// CalculateIncomeTax 计算个人所得税，先减除默认个税配置的每月减除费用，再按当前税率表计税
 // taxableIncome: 应纳税所得额
 // deductions: 专项附加扣除项
 // 返回值: 个人所得税额
 func CalculateIncomeTax(taxableIncome Money, deductions SpecialDeductions) Money {
-	// 计算扣除总额 = 各专项扣除项之和
-	totalDeductions := moneyToDec(deductions.ChildrenEducation).
-		Add(moneyToDec(deductions.ContinuingEducation)).
-		Add(moneyToDec(deductions.HousingLoanInterest)).
-		Add(moneyToDec(deductions.HousingRent)).
-		Add(moneyToDec(deductions.SupportElderly))
+	return DefaultTaxConfig().IncomeTax(taxableIncome, deductions, decimal.NewFromInt(1))
+}
+
+// Total 计算专项附加扣除总额 = 各专项扣除项之和
+func (d SpecialDeductions) Total() Money {
+	return toMoney(moneyToDec(d.ChildrenEducation).
+		Add(moneyToDec(d.ContinuingEducation)).
+		Add(moneyToDec(d.HousingLoanInterest)).
+		Add(moneyToDec(d.HousingRent)).
+		Add(moneyToDec(d.SupportElderly)))
+}
 
+// calculateIncomeTaxWith 按指定税率表计算个人所得税
+// 税额 = 应纳税所得额 × 适用税率 - 速算扣除数，适用税率为应纳税所得额超过的最高一档
+// taxableIncome: 应纳税所得额
+// totalDeductions: 扣除总额
+// brackets: 税率表
+func calculateIncomeTaxWith(taxableIncome, totalDeductions Money, brackets []TaxBracket) Money {
 	// 计算应纳税所得额 = 税前收入 - 扣除总额
-	taxable := moneyToDec(taxableIncome).Sub(totalDeductions)
+	taxable := moneyToDec(taxableIncome).Sub(moneyToDec(totalDeductions))
 
 	// 如果应纳税所得额 <= 0，则无需缴税
 	if taxable.LessThanOrEqual(decimal.Zero) {
 		return toMoney(decimal.Zero)
 	}
 
-	// 获取税率表
-	brackets := GetTaxBrackets()
-
 	// 初始化税额
 	var tax decimal.Decimal
 
 	// 从最高税率档次开始查找适用的税率
 	for i := len(brackets) - 1; i >= 0; i-- {
 		bracket := brackets[i]
-		// 如果应纳税所得额超过当前档次起征点
+		// 如果应纳税所得额超过当前档次下限
 		if taxable.GreaterThan(moneyToDec(bracket.Threshold)) {
-			// 计算该档次的应纳税额 = (应纳税所得额 - 起征点) × 税率 - 速算扣除数
-			taxableAmount := taxable.Sub(moneyToDec(bracket.Threshold))
-			tax = taxableAmount.Mul(bracket.Rate).Sub(moneyToDec(bracket.Deduction))
+			// 应纳税额 = 应纳税所得额 × 税率 - 速算扣除数
+			tax = taxable.Mul(bracket.Rate).Sub(moneyToDec(bracket.Deduction))
 			break
 		}
 	}
@@ -256,64 +334,8 @@ func CalculateNetSalary(config PayrollConfig, attendance AttendanceRecord, deduc
 	return grossSalary, netSalary, insuranceTax, incomeTax
 }
 
-// FormatMoney 格式化货币显示，保留两位小数
+// FormatMoneyCenToYuan 格式化货币显示，保留两位小数
 func FormatMoneyCenToYuan(m Money) string {
-	// 使用银行家舍入法并格式化为两位小数
-	return "¥" + decimal.Decimal(m).Div(decimal.NewFromInt(100)).StringFixedBank(2)
-}
-func main() {
-	// 初始化薪资配置（金额单位为分）
-	config := PayrollConfig{
-		BaseSalary:          toMoney(decimal.NewFromInt(800000)), // 8000元 = 8,00,000分
-		FullMonthHours:      toMoney(decimal.NewFromInt(174)),    // 每月174工作小时
-		PensionRate:         decimal.RequireFromString("0.08"),   // 养老保险8%
-		MedicalRate:         decimal.RequireFromString("0.20"),   // 医疗保险20%
-		UnemploymentRate:    decimal.RequireFromString("0.05"),   // 失业保险5%
-		HousingFundRate:     decimal.RequireFromString("0.07"),   // 公积金7%
-		OvertimeWeekdayRate: decimal.RequireFromString("1.0"),    // 工作日加班1.5倍
-		OvertimeWeekendRate: decimal.RequireFromString("1.2"),    // 周末加班2倍
-		OvertimeHolidayRate: decimal.RequireFromString("3.0"),    // 节假日加班3倍
-	}
-	// 考勤记录
-	attendance := AttendanceRecord{
-		WorkHours:       Hours(decimal.RequireFromString("174")), // 全勤(小时)
-		OvertimeWeekday: Hours(decimal.RequireFromString("1")),   // 1小时工作日加班
-		OvertimeWeekend: Hours(decimal.RequireFromString("1")),   // 1小时周末加班
-		AbsenceHours:    Hours(decimal.Zero),                     // 无缺勤
-	}
-
-	// 专项附加扣除（单位为分）
-	deductions := SpecialDeductions{
-		ChildrenEducation:   toMoney(decimal.Zero),              //子女教育(分)
-		ContinuingEducation: toMoney(decimal.Zero),              //继续教育扣除金额(分)
-		HousingLoanInterest: toMoney(decimal.NewFromInt(10000)), // 房贷利息扣除(分)
-		HousingRent:         toMoney(decimal.Zero),              //住房租金扣除(分)
-		SupportElderly:      toMoney(decimal.NewFromInt(20000)), // 赡养老人扣除(分)
-	}
-	// 计算薪资各项
-	grossSalary, netSalary, insuranceTax, incomeTax := CalculateNetSalary(config, attendance, deductions)
-	// 计算加班工资单独显示
-	overtimePay := CalculateOvertimePay(config, attendance)
-	// 打印薪资明细报表
-	fmt.Println("\n================ 梓博薪资明细报表 ================")
-	fmt.Printf("%-15s %15s\n", "项目", "金额")
-	fmt.Println("----------------------------------------")
-	fmt.Printf("%-15s %15s\n", "梓博基本工资", FormatMoneyCenToYuan(config.BaseSalary))
-	fmt.Printf("%-15s %15s\n", "梓博加班工资", FormatMoneyCenToYuan(overtimePay))
-	fmt.Printf("%-15s %15s\n", "梓博税前工资", FormatMoneyCenToYuan(grossSalary))
-	fmt.Printf("%-15s %15s\n", "梓博社保公积金", FormatMoneyCenToYuan(insuranceTax))
-	fmt.Printf("%-15s %15s\n", "梓博个人所得税", FormatMoneyCenToYuan(incomeTax))
-	fmt.Println("----------------------------------------")
-	fmt.Printf("%-15s %15s\n", "梓博实发工资", FormatMoneyCenToYuan(netSalary))
-	/*fmt.Println("========================================")
-
-	// 打印详细说明
-	fmt.Println("\n计算说明：")
-	fmt.Println("1. 基本工资 = 合同约定月薪")
-	fmt.Println("2. 加班工资 = ∑(加班小时 × 小时工资 × 加班倍数)")
-	fmt.Println("3. 税前工资 = 基本工资 + 加班工资")
-	fmt.Println("4. 社保公积金 = 养老保险 + 医疗保险 + 失业保险 + 住房公积金")
-	fmt.Println("5. 应纳税所得额 = 税前工资 - 社保公积金 - 5000(起征点) - 专项扣除")
-	fmt.Println("6. 个人所得税按累进税率计算")
-	fmt.Println("7. 实发工资 = 税前工资 - 社保公积金 - 个人所得税")*/
+	// 使用工资条格式：元，银行家舍入法保留两位小数
+	return PayslipFormatter.Format(m)
 }
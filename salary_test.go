@@ -0,0 +1,153 @@
+package salary
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+// testConfig 测试用薪资配置：月薪8000元，每月174小时
+func testConfig() PayrollConfig {
+	return PayrollConfig{
+		BaseSalary:          toMoney(cenToDec(800000)),
+		FullMonthHours:      toMoney(decimal.NewFromInt(174)),
+		PensionRate:         decimal.RequireFromString("0.08"),
+		MedicalRate:         decimal.RequireFromString("0.02"),
+		UnemploymentRate:    decimal.RequireFromString("0.005"),
+		HousingFundRate:     decimal.RequireFromString("0.07"),
+		OvertimeWeekdayRate: decimal.RequireFromString("1.5"),
+		OvertimeWeekendRate: decimal.RequireFromString("2"),
+		OvertimeHolidayRate: decimal.RequireFromString("3"),
+	}
+}
+
+func hours(s string) Hours {
+	return Hours(decimal.RequireFromString(s))
+}
+
+func assertMoney(t *testing.T, name string, got Money, wantCents string) {
+	t.Helper()
+	if want := decimal.RequireFromString(wantCents); !moneyToDec(got).Equal(want) {
+		t.Errorf("%s = %s, want %s", name, moneyToDec(got), want)
+	}
+}
+
+func TestHourlyRate(t *testing.T) {
+	config := testConfig()
+	if got, want := HourlyRate(config).Round(4), decimal.RequireFromString("4597.7011"); !got.Equal(want) {
+		t.Errorf("HourlyRate = %s, want %s", got, want)
+	}
+	config.FullMonthHours = toMoney(decimal.Zero)
+	if got := HourlyRate(config); !got.IsZero() {
+		t.Errorf("HourlyRate with zero hours = %s, want 0", got)
+	}
+}
+
+func TestCalculateBaseSalary(t *testing.T) {
+	tests := []struct {
+		name       string
+		attendance AttendanceRecord
+		want       string
+	}{
+		{"全勤", AttendanceRecord{WorkHours: hours("174")}, "800000"},
+		{"缺勤87小时", AttendanceRecord{WorkHours: hours("174"), AbsenceHours: hours("87")}, "400000"},
+		{"无出勤", AttendanceRecord{}, "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CalculateBaseSalary(testConfig(), tt.attendance)
+			assertMoney(t, "CalculateBaseSalary", toMoney(moneyToDec(got).Round(2)), tt.want)
+		})
+	}
+}
+
+func TestCalculateOvertimePay(t *testing.T) {
+	attendance := AttendanceRecord{
+		WorkHours:       hours("174"),
+		OvertimeWeekday: hours("2"),
+		OvertimeWeekend: hours("1"),
+		OvertimeHoliday: hours("1"),
+	}
+	// 4597.7011分/小时 × (2×1.5 + 1×2 + 1×3) = 36781.61分
+	assertMoney(t, "CalculateOvertimePay", CalculateOvertimePay(testConfig(), attendance), "36781.61")
+	assertMoney(t, "CalculateOvertimePay without overtime", CalculateOvertimePay(testConfig(), AttendanceRecord{}), "0")
+}
+
+func TestCalculateSocialInsurance(t *testing.T) {
+	si, fund := CalculateSocialInsurance(testConfig(), toMoney(cenToDec(800000)))
+	// 8000元 × (8% + 2% + 0.5%) = 840元；8000元 × 7% = 560元
+	assertMoney(t, "socialInsurance", si, "84000")
+	assertMoney(t, "housingFund", fund, "56000")
+}
+
+func TestSocialInsuranceBaseLimits(t *testing.T) {
+	config := testConfig()
+	limits := BaseLimits{Floor: toMoney(cenToDec(500000)), Ceiling: toMoney(cenToDec(3000000))}
+	config.PensionBase, config.MedicalBase, config.UnemploymentBase = limits, limits, limits
+
+	// 工资3000元低于下限，社保按5000元保底：5000元 × 10.5% = 525元；公积金未设上下限，按3000元缴存
+	si, fund, clamps := calculateSocialInsurance(config, toMoney(cenToDec(300000)))
+	assertMoney(t, "floored socialInsurance", si, "52500")
+	assertMoney(t, "housingFund", fund, "21000")
+	if len(clamps) != 3 || clamps[0].Insurance != "pension" || clamps[0].Clamp != ClampFloor {
+		t.Errorf("clamps = %+v", clamps)
+	}
+
+	// 工资40000元高于上限，按30000元封顶：30000元 × 10.5% = 3150元
+	result := CalculateEmployee(day("2024-03-01"), EmployeeInput{
+		Config:     func() PayrollConfig { c := config; c.BaseSalary = toMoney(cenToDec(4000000)); return c }(),
+		Attendance: AttendanceRecord{WorkHours: hours("174")},
+	})
+	assertMoney(t, "capped socialInsurance", result.SocialInsurance, "315000")
+	if len(result.BaseClamps) != 3 || result.BaseClamps[0].Clamp != ClampCeiling {
+		t.Errorf("BaseClamps = %+v", result.BaseClamps)
+	}
+}
+
+func TestHousingFundExcess(t *testing.T) {
+	config := testConfig()
+	config.HousingFundBaseCap = toMoney(cenToDec(3000000))
+	tests := []struct {
+		name       string
+		base, fund int64
+		want       string
+	}{
+		{"未超过12%", 800000, 96000, "0"},
+		{"超过12%", 800000, 100000, "4000"},
+		{"基数超过免税上限", 4000000, 480000, "120000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := HousingFundExcess(config, toMoney(cenToDec(tt.base)), toMoney(cenToDec(tt.fund)))
+			assertMoney(t, "HousingFundExcess", got, tt.want)
+		})
+	}
+}
+
+func TestSpecialDeductionsTotal(t *testing.T) {
+	d := SpecialDeductions{
+		ChildrenEducation:   toMoney(cenToDec(200000)),
+		HousingLoanInterest: toMoney(cenToDec(100000)),
+		SupportElderly:      toMoney(cenToDec(300000)),
+	}
+	assertMoney(t, "Total", d.Total(), "600000")
+}
+
+func TestFormatMoneyCenToYuan(t *testing.T) {
+	tests := []struct {
+		cents string
+		want  string
+	}{
+		{"800000", "¥8000.00"},
+		{"12345", "¥123.45"},
+		{"-165832", "¥-1658.32"},
+	}
+	for _, tt := range tests {
+		if got := FormatMoneyCenToYuan(toMoney(decimal.RequireFromString(tt.cents))); got != tt.want {
+			t.Errorf("FormatMoneyCenToYuan(%s) = %q, want %q", tt.cents, got, tt.want)
+		}
+	}
+	if got := SummaryFormatter.Format(toMoney(cenToDec(123456789))); got != "123.46万元" {
+		t.Errorf("SummaryFormatter.Format = %q, want %q", got, "123.46万元")
+	}
+}
@@ -0,0 +1,192 @@
+package salary
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// SeasonalTaxMethod 季节工、临时工的个税预扣方式
+type SeasonalTaxMethod int
+
+const (
+	SeasonalTaxLabor SeasonalTaxMethod = iota // 按劳务报酬预扣
+	SeasonalTaxWage                           // 按工资薪金单月计税，减除费用5000元，不适用累计预扣
+	SeasonalTaxFlat                           // 按固定比例预扣，适用于当地核定的简易计税
+)
+
+// SeasonalWorker 季节工、临时工的本期出勤
+type SeasonalWorker struct {
+	ID          string          // 工号或身份证号
+	Name        string          // 姓名
+	DailyRate   Money           // 日工资（分）
+	Days        decimal.Decimal // 出勤天数，可为半天
+	BankAccount string          // 工资卡号，为空表示现金发放
+}
+
+// SeasonalBatch 季节工批次，与正式员工发薪批次并行计算
+// 只按日工资 × 出勤天数计发，不缴纳社保公积金，不计加班、工资项目和专项附加扣除
+type SeasonalBatch struct {
+	Period    time.Time         // 薪资期
+	TaxMethod SeasonalTaxMethod // 个税预扣方式
+	FlatRate  decimal.Decimal   // 按固定比例预扣时的比例
+	Workers   []SeasonalWorker  // 本期出勤的季节工
+}
+
+// seasonalTax 按批次的预扣方式计算个税
+func (b SeasonalBatch) seasonalTax(gross Money) Money {
+	switch b.TaxMethod {
+	case SeasonalTaxWage:
+		return DefaultTaxConfig().IncomeTax(gross, SpecialDeductions{}, decimal.NewFromInt(1))
+	case SeasonalTaxFlat:
+		return toMoney(moneyToDec(gross).Mul(b.FlatRate).Round(2))
+	default:
+		return CalculateLaborRemunerationTax(gross)
+	}
+}
+
+// Calculate 计算批次内全部季节工的工资，结果可与正式员工批次一起生成银行代发文件和导出
+// 日工资或出勤天数为负数、重复工号的人员记为错误，不影响其他人员
+func (b SeasonalBatch) Calculate() PayrollResult {
+	result := PayrollResult{
+		Period:        monthStart(b.Period),
+		EngineVersion: EngineVersion,
+		RulesVersion:  BuiltinRulesVersion,
+		Employees:     make([]EmployeeResult, 0, len(b.Workers)),
+	}
+	if b.TaxMethod == SeasonalTaxFlat && (b.FlatRate.IsNegative() || b.FlatRate.GreaterThan(decimal.NewFromInt(1))) {
+		for _, w := range b.Workers {
+			result.Errors = append(result.Errors, EmployeeError{EmployeeID: w.ID, Err: fmt.Errorf("%w: 预扣比例 %s", ErrInvalidRate, b.FlatRate)})
+		}
+		return result
+	}
+
+	zero := toMoney(decimal.Zero)
+	seen := make(map[string]bool, len(b.Workers))
+	for _, w := range b.Workers {
+		switch {
+		case seen[w.ID]:
+			result.Errors = append(result.Errors, EmployeeError{EmployeeID: w.ID, Err: errors.New("同一批次中工号重复")})
+			continue
+		case moneyToDec(w.DailyRate).IsNegative() || w.Days.IsNegative():
+			result.Errors = append(result.Errors, EmployeeError{EmployeeID: w.ID, Err: errors.New("日工资和出勤天数不能为负数")})
+			continue
+		}
+		seen[w.ID] = true
+
+		gross := toMoney(moneyToDec(w.DailyRate).Mul(w.Days).Round(2))
+		tax := b.seasonalTax(gross)
+		result.Employees = append(result.Employees, EmployeeResult{
+			Employee:        Employee{ID: w.ID, Name: w.Name},
+			Period:          monthStart(b.Period),
+			Category:        CategorySeasonal,
+			BaseSalary:      gross,
+			OvertimePay:     zero,
+			GrossSalary:     gross,
+			SocialInsurance: zero,
+			HousingFund:     zero,
+			TaxableIncome:   gross,
+			IncomeTax:       tax,
+			NetSalary:       toMoney(moneyToDec(gross).Sub(moneyToDec(tax))),
+			Lines: []PayLine{
+				{Code: "DAILY", Name: fmt.Sprintf("日工资 %s × %s天", FormatMoneyCenToYuan(w.DailyRate), w.Days), Kind: KindEarning, Amount: gross, Taxable: true},
+			},
+		})
+	}
+	return result
+}
+
+// Records 返回季节工的收款信息，可与正式员工档案合并后传给 BankPayments 生成代发文件
+func (b SeasonalBatch) Records() map[string]EmployeeRecord {
+	records := make(map[string]EmployeeRecord, len(b.Workers))
+	for _, w := range b.Workers {
+		bank, _ := LookupBank(w.BankAccount)
+		records[w.ID] = EmployeeRecord{
+			Employee:    Employee{ID: w.ID, Name: w.Name},
+			BankAccount: w.BankAccount,
+			BankName:    bank.Name,
+		}
+	}
+	return records
+}
+
+// SeasonalImportErrors 季节工导入的全部行级错误
+type SeasonalImportErrors []AttendanceLineError
+
+// Error 实现 error 接口，列出全部出错行
+func (e SeasonalImportErrors) Error() string {
+	lines := make([]string, len(e))
+	for i, le := range e {
+		lines[i] = le.Error()
+	}
+	return fmt.Sprintf("季节工导入有%d行错误: %s", len(e), strings.Join(lines, "；"))
+}
+
+// ImportSeasonalCSV 批量导入季节工出勤，首行为表头
+// 列：工号, 姓名, 日工资（元）, 出勤天数, 工资卡号（可省略）
+// 行级错误不中断导入，汇总为 SeasonalImportErrors 返回，校验通过的行照常返回
+func ImportSeasonalCSV(r io.Reader) ([]SeasonalWorker, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var workers []SeasonalWorker
+	var errs SeasonalImportErrors
+	seen := make(map[string]bool)
+	for i, row := range rows {
+		if i == 0 {
+			continue
+		}
+		cell := func(c int) string {
+			if c < len(row) {
+				return strings.TrimSpace(row[c])
+			}
+			return ""
+		}
+		line, id := i+1, cell(0)
+		switch {
+		case id == "":
+			errs = append(errs, AttendanceLineError{Line: line, Err: errors.New("缺少工号")})
+			continue
+		case seen[id]:
+			errs = append(errs, AttendanceLineError{Line: line, EmployeeID: id, Err: errors.New("工号重复")})
+			continue
+		}
+		seen[id] = true
+
+		worker := SeasonalWorker{ID: id, Name: cell(1), BankAccount: cell(4)}
+		valid := true
+		if worker.DailyRate, err = ParseMoney(cell(2)); err != nil {
+			errs = append(errs, AttendanceLineError{Line: line, EmployeeID: id, Column: "日工资", Err: err})
+			valid = false
+		}
+		days, err := parseHours(cell(3))
+		if err != nil {
+			errs = append(errs, AttendanceLineError{Line: line, EmployeeID: id, Column: "出勤天数", Err: fmt.Errorf("%q 应为非负数", cell(3))})
+			valid = false
+		}
+		worker.Days = hoursToDec(days)
+		if worker.BankAccount != "" {
+			if _, err := ValidateBankAccount(worker.BankAccount); err != nil {
+				errs = append(errs, AttendanceLineError{Line: line, EmployeeID: id, Column: "工资卡号", Err: err})
+				valid = false
+			}
+			worker.BankAccount = NormalizeBankAccount(worker.BankAccount)
+		}
+		if valid {
+			workers = append(workers, worker)
+		}
+	}
+	if len(errs) > 0 {
+		return workers, errs
+	}
+	return workers, nil
+}
@@ -0,0 +1,64 @@
+package salary
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestImportSeasonalCSV(t *testing.T) {
+	workers, err := ImportSeasonalCSV(strings.NewReader("工号,姓名,日工资,出勤天数,工资卡号\n" +
+		"S001,张三,200,22,6222021234567890128\n" +
+		"S002,李四,180,10.5,\n" +
+		"S003,王五,abc,-1,\n" +
+		"S001,重复,200,1,\n"))
+	var lineErrs SeasonalImportErrors
+	if !errors.As(err, &lineErrs) || len(lineErrs) != 3 {
+		t.Fatalf("err = %v", err)
+	}
+	if len(workers) != 2 || workers[1].Days.String() != "10.5" {
+		t.Fatalf("workers = %+v", workers)
+	}
+	assertMoney(t, "daily rate", workers[0].DailyRate, "20000")
+}
+
+func TestSeasonalBatchCalculate(t *testing.T) {
+	workers := []SeasonalWorker{
+		{ID: "S001", Name: "张三", DailyRate: toMoney(cenToDec(20000)), Days: decimal.NewFromInt(22), BankAccount: "6222021234567890128"},
+		{ID: "S002", Name: "李四", DailyRate: toMoney(cenToDec(18000)), Days: decimal.RequireFromString("10.5")},
+	}
+	cases := []struct {
+		method SeasonalTaxMethod
+		rate   string
+		tax    string
+	}{
+		// 4400元劳务报酬：4400 × 80% × 20% = 704元
+		{SeasonalTaxLabor, "0", "70400"},
+		// 按工资薪金：(4400 - 5000) 不纳税
+		{SeasonalTaxWage, "0", "0"},
+		// 按1.5%简易计税：66元
+		{SeasonalTaxFlat, "0.015", "6600"},
+	}
+	for _, tc := range cases {
+		batch := SeasonalBatch{Period: day("2024-08-01"), TaxMethod: tc.method, FlatRate: decimal.RequireFromString(tc.rate), Workers: workers}
+		result := batch.Calculate()
+		if len(result.Errors) > 0 || len(result.Employees) != 2 {
+			t.Fatalf("method %d: %v", tc.method, result.Errors)
+		}
+		r := result.Employees[0]
+		assertMoney(t, "gross", r.GrossSalary, "440000")
+		assertMoney(t, "tax", r.IncomeTax, tc.tax)
+		assertMoney(t, "social insurance", r.SocialInsurance, "0")
+		if r.Category != CategorySeasonal {
+			t.Errorf("category = %d", r.Category)
+		}
+	}
+
+	batch := SeasonalBatch{Period: day("2024-08-01"), Workers: workers}
+	payments, errs := BankPayments(batch.Calculate(), batch.Records(), nil)
+	if len(payments) != 1 || len(errs) != 1 || !errors.Is(errs[0], ErrMissingBankAccount) {
+		t.Errorf("payments %d, errors %v", len(payments), errs)
+	}
+}
@@ -0,0 +1,312 @@
+package salary
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Probe 就绪检查使用的依赖探针
+type Probe interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+// funcProbe 以函数实现的探针
+type funcProbe struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+func (p funcProbe) Name() string                    { return p.name }
+func (p funcProbe) Check(ctx context.Context) error { return p.check(ctx) }
+
+// NewProbe 以函数创建探针
+func NewProbe(name string, check func(ctx context.Context) error) Probe {
+	return funcProbe{name: name, check: check}
+}
+
+// Pinger 可检查连通性的数据库连接，*sql.DB 满足该接口
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// DatabaseProbe 数据库连通性探针
+func DatabaseProbe(db Pinger) Probe {
+	return NewProbe("database", db.PingContext)
+}
+
+// RulesPackProbe 规则包有效性探针
+// current: 返回当前生效的规则包，规则包可能被热更新
+func RulesPackProbe(current func() *RulesPack) Probe {
+	return NewProbe("rules_pack", func(ctx context.Context) error {
+		pack := current()
+		if pack == nil {
+			return errors.New("未加载规则包")
+		}
+		return pack.Validate()
+	})
+}
+
+// CalendarProbe 节假日日历可用性探针，要求已载入当前年度的节假日数据
+func CalendarProbe(calendar *Calendar) Probe {
+	return NewProbe("calendar", func(ctx context.Context) error {
+		year := time.Now().Year()
+		if calendar == nil || !calendar.HasYear(year) {
+			return fmt.Errorf("未载入%d年节假日数据", year)
+		}
+		return nil
+	})
+}
+
+// probeTimeout 单个探针的超时时间
+const probeTimeout = 2 * time.Second
+
+// Server 服务模式的HTTP服务
+type Server struct {
+	mux         *http.ServeMux
+	store       *MemoryStore
+	probes      []Probe
+	closer      *PeriodCloser
+	replay      *ReplayLog
+	mutations   sync.Mutex // 启用重放日志时串行执行变更请求
+	pension     *PensionFormula
+	housingFund *HousingFundPolicy
+}
+
+// NewServer 创建HTTP服务
+// store: 薪资数据存储
+// probes: 就绪检查需要验证的依赖
+func NewServer(store *MemoryStore, probes ...Probe) *Server {
+	s := &Server{mux: http.NewServeMux(), store: store, probes: probes}
+	s.mux.HandleFunc("GET /healthz", s.handleHealthz)
+	s.mux.HandleFunc("GET /readyz", s.handleReadyz)
+	s.mux.HandleFunc("POST /runs", s.handleCreateRun)
+	s.mux.HandleFunc("GET /runs/{id}/comments", s.handleListComments)
+	s.mux.HandleFunc("POST /runs/{id}/comments", s.handleAddComment)
+	s.mux.HandleFunc("GET /runs/{id}/attachments", s.handleListAttachments)
+	s.mux.HandleFunc("POST /runs/{id}/attachments", s.handleAddAttachment)
+	s.mux.HandleFunc("GET /runs/{id}/attachments/{attachment}", s.handleGetAttachment)
+	s.mux.HandleFunc("GET /runs/{id}/bank-file", s.handleBankFile)
+	s.mux.HandleFunc("POST /runs/{id}/payments/commit", s.handleCommitPayments)
+	s.mux.HandleFunc("POST /runs/{id}/bank-return", s.handleReconcileBankReturn)
+	s.mux.HandleFunc("POST /runs/{id}/employees/{employee}/preview", s.handlePreviewEmployee)
+	s.mux.HandleFunc("POST /employees/{id}/holds", s.handlePlaceHold)
+	s.mux.HandleFunc("POST /holds/{id}/release", s.handleReleaseHold)
+	s.mux.HandleFunc("GET /employees/{id}/payslips/{period}", s.handlePortalBundle)
+	s.mux.HandleFunc("GET /employees/{id}/payslips/{period}/standard", s.handleStandardPayslip)
+	s.mux.HandleFunc("GET /schemas/payslip.v1.json", s.handlePayslipSchema)
+	s.mux.HandleFunc("GET /employees/{id}", s.handleGetEmployee)
+	s.mux.HandleFunc("GET /employees/{id}/income-certificate", s.handleIncomeCertificate)
+	s.mux.HandleFunc("GET /employees/{id}/housing-fund", s.handleHousingFund)
+	s.mux.HandleFunc("POST /employees", s.handleCreateEmployee)
+	s.mux.HandleFunc("PATCH /employees", s.handleBulkUpdateEmployees)
+	s.mux.HandleFunc("GET /reports/base-declaration", s.handleBaseDeclaration)
+	s.mux.HandleFunc("GET /analytics/cost-per-hour", s.handleCostPerHour)
+	s.mux.HandleFunc("GET /analytics/kpi", s.handleKPI)
+	s.mux.HandleFunc("POST /periods/{period}/close", s.handleClosePeriod)
+	s.mux.HandleFunc("GET /reports/custom", s.handleCustomReport)
+	s.mux.HandleFunc("GET /exports/results", s.handleExportResults)
+	s.mux.HandleFunc("GET /payslips/reprint", s.handleReprintPayslips)
+	return s
+}
+
+// ServeHTTP 实现 http.Handler，启用重放日志时变更请求先写日志再执行
+// 变更请求串行执行，日志顺序即执行顺序，重放时按同样顺序生成编号
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.replay != nil && isMutation(r) {
+		s.mutations.Lock()
+		defer s.mutations.Unlock()
+		logged, err := s.logMutation(w, r)
+		if err != nil {
+			writeJSON(w, http.StatusServiceUnavailable, errorBody(err))
+			return
+		}
+		r = logged
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// writeJSON 输出JSON响应
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// handleHealthz 存活检查：进程能处理请求即返回成功
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handleReadyz 就绪检查：逐一执行依赖探针，任一失败返回503
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	checks := make(map[string]string, len(s.probes))
+	ready := true
+	for _, p := range s.probes {
+		ctx, cancel := context.WithTimeout(r.Context(), probeTimeout)
+		err := p.Check(ctx)
+		cancel()
+		if err != nil {
+			ready = false
+			checks[p.Name()] = err.Error()
+		} else {
+			checks[p.Name()] = "ok"
+		}
+	}
+
+	status, code := "ready", http.StatusOK
+	if !ready {
+		status, code = "unavailable", http.StatusServiceUnavailable
+	}
+	writeJSON(w, code, map[string]any{"status": status, "checks": checks})
+}
+
+// maxAttachmentSize 单个附件大小上限
+const maxAttachmentSize = 20 << 20
+
+// writeError 输出包含错误码的错误响应，批次、员工、附件、发薪记录或暂扣不存在时返回404，员工档案已存在时返回409
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusBadRequest
+	if errors.Is(err, ErrRunNotFound) || errors.Is(err, ErrEmployeeNotFound) || errors.Is(err, ErrAttachmentNotFound) ||
+		errors.Is(err, ErrNoIncomeRecords) || errors.Is(err, ErrHoldNotFound) {
+		status = http.StatusNotFound
+	}
+	if errors.Is(err, ErrEmployeeExists) {
+		status = http.StatusConflict
+	}
+	writeJSON(w, status, errorBody(err))
+}
+
+// handleCreateRun 提交发薪批次：POST /runs，请求体为 {"period": "2024-06", "inputs": [员工计算输入...]}
+// 按员工输入计算并保存批次，启用四眼原则时一次性调整同样需要第二审批；返回批次编号和未能计算的员工
+func (s *Server) handleCreateRun(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Period string          `json:"period"`
+		Inputs []EmployeeInput `json:"inputs"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, fmt.Errorf("请求格式错误: %w", err))
+		return
+	}
+	period, err := time.Parse("2006-01", req.Period)
+	if err != nil {
+		writeError(w, fmt.Errorf("薪资期格式应为 YYYY-MM: %w", err))
+		return
+	}
+	if len(req.Inputs) == 0 {
+		writeError(w, errors.New("批次没有员工计算输入"))
+		return
+	}
+
+	at := requestTime(r.Context())
+	fourEyes, audit := s.store.fourEyesPolicy()
+	run := PayrollRun{Period: period, Inputs: req.Inputs, FourEyes: fourEyes}
+	if audit != nil {
+		run.Audit = NewAuditLog()
+	}
+	result := run.Calculate()
+	s.store.SaveRun(&result)
+	if audit != nil {
+		// 审计时间取请求时间，重放时与原请求一致
+		for _, entry := range run.Audit.Entries() {
+			entry.Time = at
+			audit.Record(entry)
+		}
+	}
+	writeJSON(w, http.StatusCreated, map[string]any{
+		"id":        result.ID,
+		"period":    result.Period.Format("2006-01"),
+		"employees": len(result.Employees),
+		"errors":    result.Errors,
+	})
+}
+
+// handleListComments 查询批次评论
+func (s *Server) handleListComments(w http.ResponseWriter, r *http.Request) {
+	comments, err := s.store.Comments(r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, comments)
+}
+
+// handleAddComment 添加批次评论，请求体为 {"author": "...", "body": "..."}
+func (s *Server) handleAddComment(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Author string `json:"author"`
+		Body   string `json:"body"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, fmt.Errorf("请求格式错误: %w", err))
+		return
+	}
+	if req.Author == "" || req.Body == "" {
+		writeError(w, errors.New("评论人和内容不能为空"))
+		return
+	}
+	comment, err := s.store.addComment(r.PathValue("id"), req.Author, req.Body, requestTime(r.Context()))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, comment)
+}
+
+// handleListAttachments 查询批次附件列表（不含文件内容）
+func (s *Server) handleListAttachments(w http.ResponseWriter, r *http.Request) {
+	attachments, err := s.store.Attachments(r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, attachments)
+}
+
+// handleAddAttachment 上传批次附件，请求体为文件内容，文件名和上传人通过查询参数 name、uploaded_by 传入
+func (s *Server) handleAddAttachment(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		writeError(w, errors.New("缺少文件名参数 name"))
+		return
+	}
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxAttachmentSize))
+	if err != nil {
+		writeError(w, fmt.Errorf("读取附件失败: %w", err))
+		return
+	}
+	attachment, err := s.store.AddAttachment(r.PathValue("id"), RunAttachment{
+		Name:        name,
+		ContentType: r.Header.Get("Content-Type"),
+		UploadedBy:  r.URL.Query().Get("uploaded_by"),
+		Data:        data,
+		CreatedAt:   requestTime(r.Context()),
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, attachment)
+}
+
+// handleGetAttachment 下载批次附件
+func (s *Server) handleGetAttachment(w http.ResponseWriter, r *http.Request) {
+	attachment, err := s.store.Attachment(r.PathValue("id"), r.PathValue("attachment"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	contentType := attachment.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", attachment.Name))
+	w.Write(attachment.Data)
+}
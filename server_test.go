@@ -0,0 +1,178 @@
+package salary
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// serve 向服务发送请求并返回响应
+func serve(h http.Handler, method, target, contentType, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+// stubPinger 可控结果的数据库连接
+type stubPinger struct{ err error }
+
+func (p stubPinger) PingContext(ctx context.Context) error { return p.err }
+
+func TestHealthAndReadiness(t *testing.T) {
+	calendar := NewCalendar()
+	pinger := &stubPinger{}
+	server := NewServer(NewMemoryStore(), DatabaseProbe(pinger), CalendarProbe(calendar))
+
+	if rec := serve(server, http.MethodGet, "/healthz", "", ""); rec.Code != http.StatusOK {
+		t.Fatalf("healthz status = %d", rec.Code)
+	}
+
+	var body struct {
+		Status string            `json:"status"`
+		Checks map[string]string `json:"checks"`
+	}
+	rec := serve(server, http.MethodGet, "/readyz", "", "")
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusServiceUnavailable || body.Status != "unavailable" || body.Checks["database"] != "ok" ||
+		!strings.Contains(body.Checks["calendar"], "节假日数据") {
+		t.Errorf("readyz without calendar = %d %+v", rec.Code, body)
+	}
+
+	calendar.AddHoliday(time.Date(time.Now().Year(), 1, 1, 0, 0, 0, 0, time.Local))
+	if rec := serve(server, http.MethodGet, "/readyz", "", ""); rec.Code != http.StatusOK {
+		t.Errorf("readyz status = %d, body = %s", rec.Code, rec.Body)
+	}
+
+	pinger.err = errors.New("connection refused")
+	rec = serve(server, http.MethodGet, "/readyz", "", "")
+	body.Checks = nil
+	json.NewDecoder(rec.Body).Decode(&body)
+	if rec.Code != http.StatusServiceUnavailable || body.Checks["database"] != "connection refused" {
+		t.Errorf("readyz with database down = %d %+v", rec.Code, body)
+	}
+}
+
+func TestRulesPackProbe(t *testing.T) {
+	var pack *RulesPack
+	probe := RulesPackProbe(func() *RulesPack { return pack })
+	if err := probe.Check(context.Background()); err == nil {
+		t.Error("missing pack should fail")
+	}
+	pack = testRulesPack()
+	if err := probe.Check(context.Background()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRunComments(t *testing.T) {
+	store := NewMemoryStore()
+	store.SaveRun(&PayrollResult{ID: "202406-000001", Period: day("2024-06-01")})
+	server := NewServer(store)
+
+	if rec := serve(server, http.MethodPost, "/runs/missing/comments", "application/json", `{"author":"hr","body":"ok"}`); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown run status = %d", rec.Code)
+	}
+	if rec := serve(server, http.MethodPost, "/runs/202406-000001/comments", "application/json", `{"author":"hr"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("empty body status = %d", rec.Code)
+	}
+	rec := serve(server, http.MethodPost, "/runs/202406-000001/comments", "application/json", `{"author":"hr","body":"审批邮件已附"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+
+	rec = serve(server, http.MethodGet, "/runs/202406-000001/comments", "", "")
+	var comments []RunComment
+	if err := json.NewDecoder(rec.Body).Decode(&comments); err != nil {
+		t.Fatal(err)
+	}
+	if len(comments) != 1 || comments[0].Author != "hr" || comments[0].Body != "审批邮件已附" || comments[0].RunID != "202406-000001" {
+		t.Errorf("comments = %+v", comments)
+	}
+}
+
+func TestRunAttachments(t *testing.T) {
+	store := NewMemoryStore()
+	store.SaveRun(&PayrollResult{ID: "202406-000001", Period: day("2024-06-01")})
+	server := NewServer(store)
+
+	if rec := serve(server, http.MethodPost, "/runs/202406-000001/attachments", "text/plain", "x"); rec.Code != http.StatusBadRequest {
+		t.Errorf("missing name status = %d", rec.Code)
+	}
+	rec := serve(server, http.MethodPost, "/runs/202406-000001/attachments?name=approval.txt&uploaded_by=hr", "text/plain", "同意发放")
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+	var created RunAttachment
+	if err := json.NewDecoder(rec.Body).Decode(&created); err != nil {
+		t.Fatal(err)
+	}
+	if created.Size != len("同意发放") || created.UploadedBy != "hr" {
+		t.Errorf("attachment = %+v", created)
+	}
+
+	rec = serve(server, http.MethodGet, "/runs/202406-000001/attachments/"+created.ID, "", "")
+	if rec.Code != http.StatusOK || rec.Body.String() != "同意发放" {
+		t.Fatalf("download status = %d, body = %q", rec.Code, rec.Body)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "text/plain" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if rec := serve(server, http.MethodGet, "/runs/202406-000001/attachments/A999", "", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown attachment status = %d", rec.Code)
+	}
+}
+
+func TestRunAttachmentListOmitsContent(t *testing.T) {
+	store := NewMemoryStore()
+	store.SaveRun(&PayrollResult{ID: "202406-000001", Period: day("2024-06-01")})
+	server := NewServer(store)
+	serve(server, http.MethodPost, "/runs/202406-000001/attachments?name=a.bin", "", "\x00\x01")
+
+	rec := serve(server, http.MethodGet, "/runs/202406-000001/attachments", "", "")
+	if strings.Contains(rec.Body.String(), "data") {
+		t.Errorf("attachment list exposes content: %s", rec.Body)
+	}
+	var attachments []RunAttachment
+	if err := json.NewDecoder(rec.Body).Decode(&attachments); err != nil {
+		t.Fatal(err)
+	}
+	rec = serve(server, http.MethodGet, "/runs/202406-000001/attachments/"+attachments[0].ID, "", "")
+	// 未提供文件类型时按二进制下载
+	if ct := rec.Header().Get("Content-Type"); ct != "application/octet-stream" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if cd := rec.Header().Get("Content-Disposition"); !strings.Contains(cd, `filename="a.bin"`) {
+		t.Errorf("Content-Disposition = %q", cd)
+	}
+	if rec := serve(server, http.MethodGet, "/runs/missing/attachments", "", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown run status = %d", rec.Code)
+	}
+}
+
+func TestBaseDeclarationLimitsInYuan(t *testing.T) {
+	server := NewServer(NewMemoryStore())
+	rec := serve(server, http.MethodGet, "/reports/base-declaration?year=2024&floor_yuan=7,310.00&ceiling_yuan=3.6万", "", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+	// 原以分为单位的参数直接拒绝，不按元误读
+	for _, target := range []string{"/reports/base-declaration?year=2024&floor=731000", "/reports/base-declaration?year=2024&ceiling=3600000"} {
+		if rec := serve(server, http.MethodGet, target, "", ""); rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "_yuan") {
+			t.Errorf("%s: status = %d, body = %s", target, rec.Code, rec.Body)
+		}
+	}
+	if rec := serve(server, http.MethodGet, "/reports/base-declaration?year=2024&floor_yuan=7310.001", "", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("sub-cent floor status = %d", rec.Code)
+	}
+}
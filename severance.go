@@ -0,0 +1,113 @@
+package salary
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// SeveranceInput 经济补偿金计算输入（金额单位为分）
+type SeveranceInput struct {
+	HireDate         time.Time  // 入职日期
+	TerminationDate  time.Time  // 劳动合同解除或终止日期
+	AverageWage      Money      // 解除前12个月的平均月工资，不满12个月的按实际月数平均
+	LocalAverageWage Money      // 当地上年度职工月平均工资
+	MinimumWage      Money      // 当地月最低工资标准，平均工资低于该标准时按该标准计算；0表示不检查
+	PayInLieu        bool       // 未提前30日书面通知，额外支付一个月工资（代通知金，即“N+1”）
+	NoticeWage       Money      // 代通知金按上一个月工资计算，0表示按平均月工资
+	Tax              *TaxConfig // 解除日期适用的个税配置，可由 ConfigStore.TaxConfigFor 取得；为空时使用当前生效的税率表
+}
+
+// SeveranceResult 经济补偿金计算结果（金额单位为分）
+type SeveranceResult struct {
+	Months      decimal.Decimal // 按工作年限计算的补偿月数
+	MonthlyBase Money           // 每月补偿基数
+	Capped      bool            // 平均工资超过当地平均工资3倍，按3倍封顶且年限不超过12年
+	Severance   Money           // 经济补偿金
+	NoticePay   Money           // 代通知金
+	Total       Money           // 一次性补偿收入合计
+	TaxExempt   Money           // 免税部分，不超过当地上年职工平均工资3倍
+	Tax         Money           // 超过免税部分单独适用综合所得税率表计算的个税
+	Net         Money           // 税后补偿金额
+	Basis       string          // 计算说明，可写入离职结算资料包
+}
+
+// severanceMonths 按工作年限计算补偿月数：每满一年支付一个月；六个月以上不满一年的按一年计算，不满六个月的支付半个月
+func severanceMonths(hire, termination time.Time) decimal.Decimal {
+	months := monthsBetween(hire, termination)
+	if months < 0 {
+		return decimal.Zero
+	}
+	years := months / 12
+	rest := months % 12
+	full := decimal.NewFromInt(int64(years))
+	switch {
+	case rest >= 6:
+		return full.Add(decimal.NewFromInt(1))
+	case rest > 0 || hire.AddDate(years, 0, 0).Before(termination):
+		return full.Add(decimal.RequireFromString("0.5"))
+	default:
+		return full
+	}
+}
+
+// CalculateSeverance 计算经济补偿金及其个人所得税
+// 补偿金 = 补偿月数 × 月平均工资；月平均工资高于当地职工月平均工资3倍的，按3倍计算且年限最高不超过12年；
+// 一次性补偿收入在当地上年职工平均工资3倍以内的部分免征个税，超过部分不并入当年综合所得，单独适用综合所得税率表
+func CalculateSeverance(input SeveranceInput) SeveranceResult {
+	months := severanceMonths(input.HireDate, input.TerminationDate)
+	base := moneyToDec(input.AverageWage)
+	if floor := moneyToDec(input.MinimumWage); floor.IsPositive() && base.LessThan(floor) {
+		base = floor
+	}
+	threeTimes := moneyToDec(input.LocalAverageWage).Mul(decimal.NewFromInt(3))
+	capped := threeTimes.IsPositive() && base.GreaterThan(threeTimes)
+	if capped {
+		base = threeTimes
+		months = decimal.Min(months, decimal.NewFromInt(12))
+	}
+	severance := base.Mul(months).Round(2)
+
+	notice := decimal.Zero
+	if input.PayInLieu {
+		notice = moneyToDec(input.NoticeWage)
+		if notice.IsZero() {
+			notice = moneyToDec(input.AverageWage)
+		}
+	}
+	total := severance.Add(notice)
+
+	// 免税额 = 当地上年职工月平均工资 × 12 × 3
+	exempt := total
+	if annual := threeTimes.Mul(decimal.NewFromInt(12)); annual.IsPositive() {
+		exempt = decimal.Min(total, annual)
+	}
+	taxConfig := DefaultTaxConfig()
+	if input.Tax != nil {
+		taxConfig = *input.Tax
+	}
+	tax := calculateIncomeTaxWith(toMoney(total.Sub(exempt)), toMoney(decimal.Zero), taxConfig.AnnualBrackets())
+
+	basis := fmt.Sprintf("工作年限%s至%s，补偿%s个月，月基数%s", input.HireDate.Format("2006-01-02"),
+		input.TerminationDate.Format("2006-01-02"), months, FormatMoneyCenToYuan(toMoney(base)))
+	if capped {
+		basis += "（按当地职工月平均工资3倍封顶，年限不超过12年）"
+	}
+	if input.PayInLieu {
+		basis += fmt.Sprintf("；另付代通知金%s", FormatMoneyCenToYuan(toMoney(notice)))
+	}
+
+	return SeveranceResult{
+		Months:      months,
+		MonthlyBase: toMoney(base),
+		Capped:      capped,
+		Severance:   toMoney(severance),
+		NoticePay:   toMoney(notice),
+		Total:       toMoney(total),
+		TaxExempt:   toMoney(exempt),
+		Tax:         tax,
+		Net:         toMoney(total.Sub(moneyToDec(tax))),
+		Basis:       basis,
+	}
+}
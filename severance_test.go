@@ -0,0 +1,45 @@
+package salary
+
+import "testing"
+
+func TestSeveranceMonths(t *testing.T) {
+	cases := []struct {
+		hire, end, want string
+	}{
+		{"2020-03-01", "2024-03-01", "4"},
+		{"2020-03-01", "2024-03-20", "4.5"},
+		{"2020-03-01", "2024-09-01", "5"},
+		{"2024-01-01", "2024-04-01", "0.5"},
+	}
+	for _, tc := range cases {
+		if got := severanceMonths(day(tc.hire), day(tc.end)); got.String() != tc.want {
+			t.Errorf("%s ~ %s: months = %s, want %s", tc.hire, tc.end, got, tc.want)
+		}
+	}
+}
+
+func TestCalculateSeverance(t *testing.T) {
+	yuan := func(v int64) Money { return toMoney(cenToDec(v * 100)) }
+
+	// 5.5年，月均1.2万元，当地月平均1万元：N+1 = 6 × 12000 + 12000 = 84000，在免税额36万元内
+	r := CalculateSeverance(SeveranceInput{
+		HireDate: day("2019-01-01"), TerminationDate: day("2024-08-15"),
+		AverageWage: yuan(12000), LocalAverageWage: yuan(10000), PayInLieu: true,
+	})
+	assertMoney(t, "severance", r.Severance, "7200000")
+	assertMoney(t, "total", r.Total, "8400000")
+	assertMoney(t, "tax", r.Tax, "0")
+
+	// 高收入：月均5万元按3万元封顶，20年按12年：360000元；免税额360000元
+	high := CalculateSeverance(SeveranceInput{
+		HireDate: day("2004-01-01"), TerminationDate: day("2024-01-01"),
+		AverageWage: yuan(50000), LocalAverageWage: yuan(10000), PayInLieu: true, NoticeWage: yuan(50000),
+	})
+	if !high.Capped || high.Months.String() != "12" {
+		t.Errorf("capped %v months %s", high.Capped, high.Months)
+	}
+	assertMoney(t, "capped severance", high.Severance, "36000000")
+	// 代通知金5万元超过免税额部分：50000 × 10% - 2520 = 2480元
+	assertMoney(t, "tax on excess", high.Tax, "248000")
+	assertMoney(t, "net", high.Net, "40752000")
+}
@@ -0,0 +1,108 @@
+package salary
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ShiftPremiumRule 班次津贴规则：在指定时段或指定日期类型出勤的小时按小时工资的比例另计津贴，与加班倍数相互独立
+// 例如：22:00至次日6:00出勤加发30%；休息日出勤加发20%
+type ShiftPremiumRule struct {
+	Code        string          // 项目代码，如 NIGHT
+	Name        string          // 项目名称，如 夜班津贴
+	StartMinute int             // 时段开始（当天0点起的分钟数，如22:00为1320）
+	EndMinute   int             // 时段结束，小于开始时表示跨午夜；开始与结束相同表示全天
+	DayTypes    []DayType       // 适用的日期类型，为空表示全部
+	Rate        decimal.Decimal // 津贴比例，如0.3表示小时工资的30%
+}
+
+// ShiftHours 按班次津贴规则汇总的出勤小时
+type ShiftHours struct {
+	Code  string          // 项目代码
+	Name  string          // 项目名称
+	Rate  decimal.Decimal // 津贴比例
+	Hours Hours           // 符合规则的出勤小时
+}
+
+// appliesTo 判断规则是否适用于该日期类型
+func (r ShiftPremiumRule) appliesTo(dayType DayType) bool {
+	if len(r.DayTypes) == 0 {
+		return true
+	}
+	for _, t := range r.DayTypes {
+		if t == dayType {
+			return true
+		}
+	}
+	return false
+}
+
+// overlapMinutes 计算出勤时间与规则时段重叠的分钟数，时段按出勤开始当天及次日展开以处理跨午夜
+func (r ShiftPremiumRule) overlapMinutes(start, end time.Time) int {
+	if !end.After(start) {
+		return 0
+	}
+	if r.StartMinute == r.EndMinute {
+		return int(end.Sub(start).Minutes())
+	}
+	total := 0
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	for offset := -1; offset <= 1; offset++ {
+		windowStart := day.AddDate(0, 0, offset).Add(time.Duration(r.StartMinute) * time.Minute)
+		windowEnd := day.AddDate(0, 0, offset).Add(time.Duration(r.EndMinute) * time.Minute)
+		if r.EndMinute < r.StartMinute {
+			windowEnd = windowEnd.AddDate(0, 0, 1)
+		}
+		from, to := start, end
+		if windowStart.After(from) {
+			from = windowStart
+		}
+		if windowEnd.Before(to) {
+			to = windowEnd
+		}
+		if to.After(from) {
+			total += int(to.Sub(from).Minutes())
+		}
+	}
+	return total
+}
+
+// aggregateShiftHours 根据每日打卡时间按班次津贴规则汇总出勤小时
+// 未记录打卡时间的日期不计入；符合规则的分钟数不超过当天实际出勤和加班分钟数之和
+func aggregateShiftHours(days []DailyAttendance, rules []ShiftPremiumRule) []ShiftHours {
+	if len(rules) == 0 {
+		return nil
+	}
+	minutes := make([]int, len(rules))
+	for _, day := range days {
+		if day.ClockIn.IsZero() || day.ClockOut.IsZero() {
+			continue
+		}
+		worked := day.WorkMinutes + day.OvertimeMinutes
+		for i, rule := range rules {
+			if rule.appliesTo(day.DayType) {
+				minutes[i] += min(rule.overlapMinutes(day.ClockIn, day.ClockOut), worked)
+			}
+		}
+	}
+	shifts := make([]ShiftHours, len(rules))
+	for i, rule := range rules {
+		shifts[i] = ShiftHours{Code: rule.Code, Name: rule.Name, Rate: rule.Rate, Hours: minutesToHours(minutes[i])}
+	}
+	return shifts
+}
+
+// shiftLines 生成班次津贴明细，津贴 = 小时工资 × 符合规则的小时 × 津贴比例，计入应税收入
+func shiftLines(config PayrollConfig, attendance AttendanceRecord) []PayLine {
+	var lines []PayLine
+	hourlyRate := attendanceHourlyRate(config, attendance)
+	for _, s := range attendance.Shifts {
+		amount := hourlyRate.Mul(hoursToDec(s.Hours)).Mul(s.Rate).Round(2)
+		if !amount.IsPositive() {
+			continue
+		}
+		lines = append(lines, PayLine{Code: "SHIFT-" + s.Code, Name: s.Name, Kind: KindEarning, Amount: toMoney(amount), Taxable: true})
+	}
+	return lines
+}
@@ -0,0 +1,54 @@
+package salary
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func clock(date string, hour, minute int) time.Time {
+	return day(date).Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+}
+
+func TestShiftPremiums(t *testing.T) {
+	rules := []ShiftPremiumRule{
+		{Code: "NIGHT", Name: "夜班津贴", StartMinute: 22 * 60, EndMinute: 6 * 60, Rate: decimal.RequireFromString("0.3")},
+		{Code: "WEEKEND", Name: "休息日津贴", DayTypes: []DayType{RestDay}, Rate: decimal.RequireFromString("0.2")},
+	}
+	days := []DailyAttendance{
+		// 跨午夜：22:00至次日2:00计4小时夜班
+		{Date: day("2024-03-04"), DayType: Workday, WorkMinutes: 480, ClockIn: clock("2024-03-04", 18, 0), ClockOut: clock("2024-03-05", 2, 0)},
+		{Date: day("2024-03-05"), DayType: Workday, WorkMinutes: 480, ClockIn: clock("2024-03-05", 9, 0), ClockOut: clock("2024-03-05", 18, 0)},
+		// 5:00至6:00在夜班时段内，但当天只出勤30分钟，按出勤计
+		{Date: day("2024-03-06"), DayType: Workday, WorkMinutes: 30, ClockIn: clock("2024-03-06", 5, 0), ClockOut: clock("2024-03-06", 13, 0)},
+		// 休息日20:00至23:00：夜班1小时，休息日全天3小时
+		{Date: day("2024-03-09"), DayType: RestDay, OvertimeMinutes: 180, ClockIn: clock("2024-03-09", 20, 0), ClockOut: clock("2024-03-09", 23, 0)},
+		// 未记录打卡时间不计入
+		{Date: day("2024-03-10"), DayType: RestDay, OvertimeMinutes: 240},
+	}
+	attendance := AggregateDailyAttendance(days, AttendancePolicy{ShiftPremiums: rules})
+	if len(attendance.Shifts) != 2 {
+		t.Fatalf("shifts = %+v", attendance.Shifts)
+	}
+	if !hoursToDec(attendance.Shifts[0].Hours).Equal(hoursToDec(hours("5.5"))) {
+		t.Errorf("night hours = %s", hoursToDec(attendance.Shifts[0].Hours))
+	}
+	if !hoursToDec(attendance.Shifts[1].Hours).Equal(hoursToDec(hours("3"))) {
+		t.Errorf("weekend hours = %s", hoursToDec(attendance.Shifts[1].Hours))
+	}
+
+	// 按排班160小时，小时工资50元
+	attendance.ScheduledHours = hours("160")
+	lines := shiftLines(testConfig(), attendance)
+	if len(lines) != 2 || lines[0].Code != "SHIFT-NIGHT" || !lines[0].Taxable {
+		t.Fatalf("lines = %+v", lines)
+	}
+	assertMoney(t, "night premium", lines[0].Amount, "8250")
+	assertMoney(t, "weekend premium", lines[1].Amount, "3000")
+
+	result := CalculateEmployee(day("2024-03-01"), EmployeeInput{Employee: Employee{ID: "E1"}, Config: testConfig(), Attendance: attendance})
+	if len(result.Lines) != 2 || result.Lines[1].Code != "SHIFT-WEEKEND" {
+		t.Errorf("result lines = %+v", result.Lines)
+	}
+}
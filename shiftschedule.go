@@ -0,0 +1,131 @@
+package salary
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ShiftRotation 轮班规则：从起始日起按循环顺序逐日排班
+type ShiftRotation struct {
+	Cycle []string  // 循环中每天的班次代码，空代码表示休息，如 D,D,N,N,"",""（两白两夜两休）
+	Start time.Time // 循环的第一天
+}
+
+// shiftCode 返回日期在循环中的班次代码
+func (r ShiftRotation) shiftCode(date time.Time) string {
+	n := len(r.Cycle)
+	return r.Cycle[((daysBetween(r.Start, date)%n)+n)%n]
+}
+
+// ShiftAssignment 员工的排班：固定班次或轮班，可按日期调班
+type ShiftAssignment struct {
+	Shift    string            // 固定班次代码，日历上的工作日按该班次出勤；设置轮班规则时忽略
+	Rotation *ShiftRotation    // 轮班规则，为空表示固定班次；轮班员工在法定节假日排班时按节假日加班计算
+	Swaps    map[string]string // 调班：日期（2006-01-02）对应的班次代码，空代码表示当天休息
+}
+
+// ShiftSchedule 排班表：班次定义和各员工的排班，用于按排班计算应出勤工时、加班和班次津贴
+type ShiftSchedule struct {
+	Shifts      map[string]ScheduledShift  // 班次定义，按班次代码索引
+	Assignments map[string]ShiftAssignment // 员工排班，按工号索引
+}
+
+// Validate 校验排班表：排班引用的班次必须已定义，轮班规则必须有循环和起始日
+func (s ShiftSchedule) Validate() error {
+	var errs []error
+	check := func(id, code string) {
+		if _, ok := s.Shifts[code]; code != "" && !ok {
+			errs = append(errs, fmt.Errorf("员工 %s 的排班引用了未定义的班次 %s", id, code))
+		}
+	}
+	for code, shift := range s.Shifts {
+		if shift.Minutes() == 0 {
+			errs = append(errs, fmt.Errorf("班次 %s 的工时为0", code))
+		}
+	}
+	for id, a := range s.Assignments {
+		switch {
+		case a.Rotation != nil && (len(a.Rotation.Cycle) == 0 || a.Rotation.Start.IsZero()):
+			errs = append(errs, fmt.Errorf("员工 %s 的轮班规则缺少循环或起始日", id))
+		case a.Rotation != nil:
+			for _, code := range a.Rotation.Cycle {
+				check(id, code)
+			}
+		case a.Shift == "":
+			errs = append(errs, fmt.Errorf("员工 %s 没有指定班次", id))
+		default:
+			check(id, a.Shift)
+		}
+		for _, code := range a.Swaps {
+			check(id, code)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ShiftOn 查询员工某天的排班班次
+// 调班优先；轮班员工按循环排班；固定班次员工在日历上的工作日（含调休上班日）排班
+// 返回值: (班次, 当天是否排班)
+func (s ShiftSchedule) ShiftOn(employeeID string, date time.Time, calendar *Calendar) (ScheduledShift, bool) {
+	a, ok := s.Assignments[employeeID]
+	if !ok {
+		return ScheduledShift{}, false
+	}
+	code, swapped := a.Swaps[dateKey(date)]
+	switch {
+	case swapped:
+	case a.Rotation != nil:
+		code = a.Rotation.shiftCode(date)
+	case calendar.DayType(date) == Workday:
+		code = a.Shift
+	}
+	shift, ok := s.Shifts[code]
+	return shift, ok
+}
+
+// ScheduledHours 计算员工本期按排班的应出勤工时（不含法定节假日），替代固定的每月标准工时计算小时工资
+func (s ShiftSchedule) ScheduledHours(employeeID string, period time.Time, calendar *Calendar) Hours {
+	minutes := 0
+	start := monthStart(period)
+	for date := start; date.Before(start.AddDate(0, 1, 0)); date = date.AddDate(0, 0, 1) {
+		if shift, ok := s.ShiftOn(employeeID, date, calendar); ok && calendar.DayType(date) != Holiday {
+			minutes += shift.Minutes()
+		}
+	}
+	return minutesToHours(minutes)
+}
+
+// ScheduleAttendance 按排班表将打卡记录汇总为各员工的月度考勤
+// 排班日按班次计算迟到、早退、缺勤和下班后的加班，跨午夜班次的打卡归属上班当天；未排班日的出勤计为休息日加班，
+// 法定节假日的出勤计为节假日加班；设置了差额津贴的班次（如夜班）按班次内的正常工时计入班次津贴。
+// 考勤记录的 ScheduledHours 为整月应出勤工时，计算小时工资时替代配置的每月标准工时；法定节假日不计入应出勤工时，
+// 照常计发工资，因此考勤汇总规则无需再设置带薪节假日工时
+// period: 薪资期
+// employees: 员工档案，每名员工都必须有排班
+// punches: 打卡记录
+// schedule: 排班表
+// calendar: 节假日日历，为空时只区分工作日和周末
+// policy: 考勤汇总规则
+func ScheduleAttendance(period time.Time, employees []Employee, punches []PunchPair, schedule ShiftSchedule, calendar *Calendar, policy AttendancePolicy) (map[string]AttendanceRecord, error) {
+	if err := schedule.Validate(); err != nil {
+		return nil, err
+	}
+	for _, e := range employees {
+		if _, ok := schedule.Assignments[e.ID]; !ok {
+			return nil, fmt.Errorf("员工 %s 没有排班", e.ID)
+		}
+	}
+	shiftOn := func(e Employee, date time.Time) (ScheduledShift, bool) {
+		return schedule.ShiftOn(e.ID, date, calendar)
+	}
+	records, scheduled, err := punchAttendance(period, employees, punches, calendar, policy, shiftOn)
+	if err != nil {
+		return nil, err
+	}
+	for id, record := range records {
+		record.ScheduledHours = minutesToHours(scheduled[id])
+		records[id] = record
+	}
+	return records, nil
+}
@@ -0,0 +1,92 @@
+package salary
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func rotatingSchedule() ShiftSchedule {
+	return ShiftSchedule{
+		Shifts: map[string]ScheduledShift{
+			"D": {Code: "D", Name: "白班", StartMinute: 8 * 60, EndMinute: 20 * 60, BreakStartMinute: 12 * 60, BreakMinutes: 60},
+			"N": {Code: "N", Name: "夜班津贴", StartMinute: 20 * 60, EndMinute: 8 * 60, BreakStartMinute: 0, BreakMinutes: 60, Differential: decimal.RequireFromString("0.3")},
+		},
+		Assignments: map[string]ShiftAssignment{
+			"E1": {Rotation: &ShiftRotation{Cycle: []string{"D", "D", "N", "N", "", ""}, Start: day("2024-03-01")}},
+		},
+	}
+}
+
+func TestShiftScheduleShiftOn(t *testing.T) {
+	schedule := rotatingSchedule()
+	a := schedule.Assignments["E1"]
+	a.Swaps = map[string]string{"2024-03-05": "D"}
+	schedule.Assignments["E1"] = a
+	for date, want := range map[string]string{"2024-03-01": "D", "2024-03-03": "N", "2024-03-05": "D", "2024-03-06": "", "2024-03-07": "D", "2024-02-29": ""} {
+		shift, _ := schedule.ShiftOn("E1", day(date), nil)
+		if shift.Code != want {
+			t.Errorf("ShiftOn(%s) = %q, want %q", date, shift.Code, want)
+		}
+	}
+	if got := hoursToDec(schedule.ScheduledHours("E1", day("2024-03-01"), nil)); !got.Equal(decimal.NewFromInt(242)) {
+		t.Errorf("scheduled hours = %s, want 242", got)
+	}
+}
+
+func TestScheduleAttendance(t *testing.T) {
+	schedule := rotatingSchedule()
+	var punches []PunchPair
+	for d := day("2024-03-01"); d.Before(day("2024-04-01")); d = d.AddDate(0, 0, 1) {
+		shift, ok := schedule.ShiftOn("E1", d, nil)
+		if !ok {
+			continue
+		}
+		in, out := shift.window(d)
+		punches = append(punches, PunchPair{EmployeeID: "E1", Date: d, ClockIn: in, ClockOut: out})
+	}
+	punches[2].ClockOut = punches[2].ClockOut.Add(time.Hour)                                                                  // 3月3日夜班延长1小时
+	punches = append(punches, PunchPair{EmployeeID: "E1", ClockIn: at("2024-03-05 10:00"), ClockOut: at("2024-03-05 14:00")}) // 休息日出勤
+
+	records, err := ScheduleAttendance(day("2024-03-01"), []Employee{{ID: "E1"}}, punches, schedule, nil, AttendancePolicy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := records["E1"]
+	for _, c := range []struct {
+		name string
+		got  Hours
+		want int64
+	}{
+		{"scheduled", r.ScheduledHours, 231},
+		{"work", r.WorkHours, 231},
+		{"weekday", r.OvertimeWeekday, 1},
+		{"weekend", r.OvertimeWeekend, 4},
+	} {
+		if !hoursToDec(c.got).Equal(decimal.NewFromInt(c.want)) {
+			t.Errorf("%s = %s, want %d", c.name, hoursToDec(c.got), c.want)
+		}
+	}
+	if len(r.Shifts) != 1 || r.Shifts[0].Code != "N" || !hoursToDec(r.Shifts[0].Hours).Equal(decimal.NewFromInt(110)) {
+		t.Fatalf("shifts = %+v", r.Shifts)
+	}
+
+	result := CalculateEmployee(day("2024-03-01"), EmployeeInput{Employee: Employee{ID: "E1"}, Config: testConfig(), Attendance: r})
+	if !hoursToDec(result.StandardHours).Equal(decimal.NewFromInt(231)) {
+		t.Errorf("standard hours = %s, want 231", hoursToDec(result.StandardHours))
+	}
+	for _, line := range result.Lines {
+		if line.Code == "SHIFT-N" {
+			assertMoney(t, "night differential", line.Amount, "114285.71")
+		}
+	}
+}
+
+func TestShiftScheduleValidate(t *testing.T) {
+	schedule := rotatingSchedule()
+	schedule.Assignments["E2"] = ShiftAssignment{Shift: "X"}
+	if err := schedule.Validate(); err == nil {
+		t.Error("expected undefined shift error")
+	}
+}
@@ -0,0 +1,97 @@
+package salary
+
+import (
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// TaxScenario 个税测算口径：税率表和每月减除费用
+type TaxScenario struct {
+	Brackets          []TaxBracket // 税率表
+	StandardDeduction Money        // 每月减除费用（起征点，分）
+}
+
+// CurrentTaxScenario 返回当前生效的个税口径
+func CurrentTaxScenario() TaxScenario {
+	return TaxScenario{Brackets: GetTaxBrackets(), StandardDeduction: DefaultTaxConfig().StandardDeduction}
+}
+
+// monthlyTax 按测算口径重新计算某员工某月的个税
+func (s TaxScenario) monthlyTax(r EmployeeResult) Money {
+	deductions := moneyToDec(r.SpecialDeductionTotal).Add(moneyToDec(s.StandardDeduction))
+	return calculateIncomeTaxWith(r.TaxableIncome, toMoney(deductions), s.Brackets)
+}
+
+// TaxImpact 单个员工的税法调整影响
+type TaxImpact struct {
+	EmployeeID     string // 工号
+	Name           string // 姓名
+	Months         int    // 参与测算的月数
+	BaselineTax    Money  // 现行口径下的个税合计
+	ProposedTax    Money  // 调整后口径下的个税合计
+	TaxDelta       Money  // 个税变化 = 调整后 - 现行
+	NetSalaryDelta Money  // 实发工资变化 = -个税变化
+}
+
+// TaxImpactReport 税法调整影响测算报告
+type TaxImpactReport struct {
+	From, To         time.Time   // 测算覆盖的薪资期范围
+	Employees        []TaxImpact // 各员工影响，按个税变化从大到小排列
+	TotalBaselineTax Money       // 现行口径个税总额
+	TotalProposedTax Money       // 调整后口径个税总额
+	TotalTaxDelta    Money       // 个税总变化
+	TotalNetDelta    Money       // 实发工资总变化
+}
+
+// SimulateTaxChange 基于历史发薪结果测算税率表或起征点调整对现有员工的影响
+// 两种口径都基于历史结果中的应纳税所得额和专项附加扣除重新计算，差异只反映税法调整本身
+// history: 历史发薪批次，通常为最近一个纳税年度
+// baseline: 现行口径
+// proposed: 调整后口径
+func SimulateTaxChange(history []PayrollResult, baseline, proposed TaxScenario) TaxImpactReport {
+	var report TaxImpactReport
+	impacts := make(map[string]*TaxImpact)
+	var order []string
+
+	for _, run := range history {
+		if report.From.IsZero() || run.Period.Before(report.From) {
+			report.From = run.Period
+		}
+		if run.Period.After(report.To) {
+			report.To = run.Period
+		}
+		for _, r := range run.Employees {
+			impact, ok := impacts[r.Employee.ID]
+			if !ok {
+				impact = &TaxImpact{EmployeeID: r.Employee.ID, Name: r.Employee.Name}
+				impacts[r.Employee.ID] = impact
+				order = append(order, r.Employee.ID)
+			}
+			impact.Months++
+			impact.BaselineTax = toMoney(moneyToDec(impact.BaselineTax).Add(moneyToDec(baseline.monthlyTax(r))))
+			impact.ProposedTax = toMoney(moneyToDec(impact.ProposedTax).Add(moneyToDec(proposed.monthlyTax(r))))
+		}
+	}
+
+	var totalBaseline, totalProposed decimal.Decimal
+	for _, id := range order {
+		impact := impacts[id]
+		delta := moneyToDec(impact.ProposedTax).Sub(moneyToDec(impact.BaselineTax))
+		impact.TaxDelta = toMoney(delta)
+		impact.NetSalaryDelta = toMoney(delta.Neg())
+		totalBaseline = totalBaseline.Add(moneyToDec(impact.BaselineTax))
+		totalProposed = totalProposed.Add(moneyToDec(impact.ProposedTax))
+		report.Employees = append(report.Employees, *impact)
+	}
+	sort.SliceStable(report.Employees, func(i, j int) bool {
+		return moneyToDec(report.Employees[i].TaxDelta).GreaterThan(moneyToDec(report.Employees[j].TaxDelta))
+	})
+
+	report.TotalBaselineTax = toMoney(totalBaseline)
+	report.TotalProposedTax = toMoney(totalProposed)
+	report.TotalTaxDelta = toMoney(totalProposed.Sub(totalBaseline))
+	report.TotalNetDelta = toMoney(totalBaseline.Sub(totalProposed))
+	return report
+}
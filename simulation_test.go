@@ -0,0 +1,38 @@
+package salary
+
+import "testing"
+
+func TestSimulateTaxChange(t *testing.T) {
+	month := func(period string, taxable ...int64) PayrollResult {
+		run := PayrollResult{Period: day(period)}
+		for i, cents := range taxable {
+			id := []string{"E1", "E2"}[i]
+			run.Employees = append(run.Employees, EmployeeResult{Employee: Employee{ID: id, Name: id}, TaxableIncome: toMoney(cenToDec(cents))})
+		}
+		return run
+	}
+	history := []PayrollResult{month("2024-02-01", 1000000, 400000), month("2024-01-01", 1000000, 400000)}
+
+	baseline := TaxScenario{Brackets: DefaultTaxBrackets(), StandardDeduction: toMoney(cenToDec(500000))}
+	proposed := TaxScenario{Brackets: DefaultTaxBrackets(), StandardDeduction: toMoney(cenToDec(600000))}
+	report := SimulateTaxChange(history, baseline, proposed)
+
+	if !report.From.Equal(day("2024-01-01")) || !report.To.Equal(day("2024-02-01")) {
+		t.Errorf("range = %v ~ %v", report.From, report.To)
+	}
+	// E1：(10000-5000)×10%-210 = 290 降至 (10000-6000)×10%-210 = 190，两个月合计减少200
+	// E2 低于起征点，无变化，按个税变化从大到小排在前面
+	if len(report.Employees) != 2 || report.Employees[0].EmployeeID != "E2" || report.Employees[1].EmployeeID != "E1" {
+		t.Fatalf("employees = %+v", report.Employees)
+	}
+	e1 := report.Employees[1]
+	if e1.Months != 2 {
+		t.Errorf("months = %d", e1.Months)
+	}
+	assertMoney(t, "baseline", e1.BaselineTax, "58000")
+	assertMoney(t, "proposed", e1.ProposedTax, "38000")
+	assertMoney(t, "delta", e1.TaxDelta, "-20000")
+	assertMoney(t, "net delta", e1.NetSalaryDelta, "20000")
+	assertMoney(t, "total delta", report.TotalTaxDelta, "-20000")
+	assertMoney(t, "total net delta", report.TotalNetDelta, "20000")
+}
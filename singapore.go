@@ -0,0 +1,148 @@
+package salary
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// JurisdictionSG 新加坡
+const JurisdictionSG Jurisdiction = "SG"
+
+// 新加坡中央公积金（CPF）工资上限，金额单位为新元分
+var (
+	CPFOrdinaryWageCeiling = cenToDec(740000)   // 普通工资每月上限7,400新元
+	CPFAnnualWageCeiling   = cenToDec(10200000) // 全年工资上限102,000新元，额外工资上限 = 该额 - 全年普通工资
+	CPFMinTotalWages       = cenToDec(5000)     // 月工资不超过50新元无须缴纳
+	CPFEmployeeThreshold   = cenToDec(50000)    // 月工资不超过500新元时雇员无须缴纳
+	CPFPhaseInCeiling      = cenToDec(75000)    // 月工资500至750新元之间雇员缴费按比例递增
+)
+
+// CPFRateBand 按年龄划分的公积金缴费率
+type CPFRateBand struct {
+	MaxAge   int             // 适用的最大年龄（含），0表示不限
+	Employee decimal.Decimal // 雇员缴费率
+	Employer decimal.Decimal // 雇主缴费率
+}
+
+// CPFRateBands 新加坡公民及第三年起永久居民的公积金缴费率，按年龄升序排列
+var CPFRateBands = []CPFRateBand{
+	{MaxAge: 55, Employee: decimal.RequireFromString("0.20"), Employer: decimal.RequireFromString("0.17")},
+	{MaxAge: 60, Employee: decimal.RequireFromString("0.17"), Employer: decimal.RequireFromString("0.155")},
+	{MaxAge: 65, Employee: decimal.RequireFromString("0.115"), Employer: decimal.RequireFromString("0.12")},
+	{MaxAge: 70, Employee: decimal.RequireFromString("0.075"), Employer: decimal.RequireFromString("0.09")},
+	{Employee: decimal.RequireFromString("0.05"), Employer: decimal.RequireFromString("0.075")},
+}
+
+// cpfRateBand 查找指定年龄适用的缴费率
+func cpfRateBand(age int) CPFRateBand {
+	for _, band := range CPFRateBands {
+		if band.MaxAge == 0 || age <= band.MaxAge {
+			return band
+		}
+	}
+	return CPFRateBands[len(CPFRateBands)-1]
+}
+
+// ageAt 计算员工在薪资期所在月份1日的周岁年龄
+func ageAt(birthDate, period time.Time) int {
+	p := monthStart(period)
+	age := p.Year() - birthDate.Year()
+	if p.Month() < birthDate.Month() || (p.Month() == birthDate.Month() && p.Day() < birthDate.Day()) {
+		age--
+	}
+	return age
+}
+
+// CPFYearToDate 员工本年度截至上期已计缴公积金的工资
+type CPFYearToDate struct {
+	Year            int   // 年度，0表示与薪资期同一年度；与薪资期年度不同时从零开始累计
+	OrdinaryWages   Money // 累计普通工资（已按月上限封顶，分）
+	AdditionalWages Money // 累计额外工资（分）
+}
+
+// forYear 返回薪资期年度的累计数据，跨年时从零开始
+func (y CPFYearToDate) forYear(year int) CPFYearToDate {
+	if y.Year != 0 && y.Year != year {
+		return CPFYearToDate{Year: year, OrdinaryWages: toMoney(decimal.Zero), AdditionalWages: toMoney(decimal.Zero)}
+	}
+	y.Year = year
+	return y
+}
+
+// CPFContribution 公积金缴费明细
+type CPFContribution struct {
+	OrdinaryWages   Money         // 计缴的普通工资（封顶后）
+	AdditionalWages Money         // 计缴的额外工资（封顶后）
+	Employee        Money         // 雇员缴费
+	Employer        Money         // 雇主缴费
+	YearToDate      CPFYearToDate // 计入本月后的本年度累计工资，保存后作为下期输入
+}
+
+// CalculateCPF 计算新加坡公积金月度缴费
+// 普通工资按月上限封顶，额外工资（奖金等）按全年上限扣除全年普通工资和已计缴额外工资后的余额封顶；
+// 月工资500至750新元之间雇员缴费 = (工资 - 500) × 雇员缴费率 × 3
+// ordinaryWages: 本月普通工资
+// additionalWages: 本月额外工资
+// age: 员工年龄
+// ytd: 本年度截至上期已计缴的工资
+// 返回值: 缴费明细，YearToDate 为计入本月计缴工资后的累计数据
+func CalculateCPF(ordinaryWages, additionalWages Money, age int, ytd CPFYearToDate) CPFContribution {
+	zero := toMoney(decimal.Zero)
+	total := moneyToDec(ordinaryWages).Add(moneyToDec(additionalWages))
+	if total.LessThanOrEqual(CPFMinTotalWages) {
+		return CPFContribution{OrdinaryWages: zero, AdditionalWages: zero, Employee: zero, Employer: zero, YearToDate: ytd}
+	}
+
+	ow := decimal.Max(decimal.Min(moneyToDec(ordinaryWages), CPFOrdinaryWageCeiling), decimal.Zero)
+	// 额外工资上限按截至本月的普通工资计算，年末需按全年实际普通工资复核
+	awCeiling := CPFAnnualWageCeiling.Sub(moneyToDec(ytd.OrdinaryWages)).Sub(ow).Sub(moneyToDec(ytd.AdditionalWages))
+	aw := decimal.Max(decimal.Min(moneyToDec(additionalWages), awCeiling), decimal.Zero)
+
+	band := cpfRateBand(age)
+	wages := ow.Add(aw)
+	employer := wages.Mul(band.Employer).Round(2)
+	employee := wages.Mul(band.Employee)
+	switch {
+	case total.LessThanOrEqual(CPFEmployeeThreshold):
+		employee = decimal.Zero
+	case total.LessThanOrEqual(CPFPhaseInCeiling):
+		employee = total.Sub(CPFEmployeeThreshold).Mul(band.Employee).Mul(decimal.NewFromInt(3))
+	}
+	return CPFContribution{
+		OrdinaryWages:   toMoney(ow),
+		AdditionalWages: toMoney(aw),
+		Employee:        toMoney(employee.Round(2)),
+		Employer:        toMoney(employer),
+		YearToDate: CPFYearToDate{
+			Year:            ytd.Year,
+			OrdinaryWages:   toMoney(moneyToDec(ytd.OrdinaryWages).Add(ow)),
+			AdditionalWages: toMoney(moneyToDec(ytd.AdditionalWages).Add(aw)),
+		},
+	}
+}
+
+// isAdditionalWage 判断明细是否为额外工资：奖金类一次性调整
+func isAdditionalWage(line PayLine) bool {
+	return line.Kind == KindEarning && line.Code == "ADJ-"+string(ReasonBonus)
+}
+
+// init 注册新加坡法定扣缴项目：中央公积金
+func init() {
+	RegisterStatutoryDeduction(JurisdictionSG, StatutoryDeduction{
+		Code:  "CPF",
+		Label: "中央公积金",
+		Compute: func(ctx StatutoryContext) StatutoryAmount {
+			additional := decimal.Zero
+			for _, line := range ctx.Lines {
+				if isAdditionalWage(line) {
+					additional = additional.Add(moneyToDec(line.Amount))
+				}
+			}
+			age := ageAt(ctx.Input.Employee.BirthDate, ctx.Period)
+			ytd := ctx.Input.CPFYearToDate.forYear(ctx.Period.Year())
+			cpf := CalculateCPF(toMoney(ctx.Gross.Sub(additional)), toMoney(additional), age, ytd)
+			return StatutoryAmount{Employee: cpf.Employee, Employer: cpf.Employer, Provision: toMoney(decimal.Zero), CPFYearToDate: &cpf.YearToDate}
+		},
+	})
+}
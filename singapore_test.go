@@ -0,0 +1,130 @@
+package salary
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCPFYearToDateCarriedAcrossMonths(t *testing.T) {
+	config := testConfig()
+	config.BaseSalary = toMoney(cenToDec(1000000))
+	input := EmployeeInput{
+		Employee:   Employee{ID: "SG1", Jurisdiction: JurisdictionSG, BirthDate: day("1990-05-01")},
+		Config:     config,
+		Attendance: AttendanceRecord{WorkHours: hours("174")},
+	}
+
+	var december EmployeeResult
+	for m := 1; m <= 12; m++ {
+		period := time.Date(2024, time.Month(m), 1, 0, 0, 0, 0, time.Local)
+		if m == 12 {
+			input.Adjustments = []Adjustment{{ID: "B1", Kind: KindEarning, Amount: toMoney(cenToDec(2000000)), Taxable: true, ReasonCode: ReasonBonus}}
+		}
+		result := CalculateEmployee(period, input)
+		if result.CPFYearToDate == nil {
+			t.Fatalf("month %d: CPFYearToDate not returned", m)
+		}
+		input.CPFYearToDate = *result.CPFYearToDate
+		december = result
+	}
+	// 普通工资每月按7,400新元封顶，全年88,800新元；额外工资上限 = 102,000 - 88,800 = 13,200新元
+	assertMoney(t, "YTD ordinary", input.CPFYearToDate.OrdinaryWages, "8880000")
+	assertMoney(t, "YTD additional", input.CPFYearToDate.AdditionalWages, "1320000")
+	// 12月雇员缴费 = (7,400 + 13,200) × 20% = 4,120新元
+	assertMoney(t, "December employee CPF", december.Statutory[0].Employee, "412000")
+
+	// 新年度从零开始累计
+	january := CalculateEmployee(day("2025-01-01"), EmployeeInput{
+		Employee: input.Employee, Config: config, Attendance: input.Attendance, CPFYearToDate: input.CPFYearToDate,
+	})
+	if ytd := january.CPFYearToDate; ytd.Year != 2025 {
+		t.Errorf("January year = %d, want 2025", ytd.Year)
+	}
+	assertMoney(t, "January YTD ordinary", january.CPFYearToDate.OrdinaryWages, "740000")
+}
+
+func TestCalculateCPFReturnsYearToDate(t *testing.T) {
+	ytd := CPFYearToDate{Year: 2024, OrdinaryWages: toMoney(cenToDec(8140000))}
+	cpf := CalculateCPF(toMoney(cenToDec(740000)), toMoney(cenToDec(3000000)), 30, ytd)
+	assertMoney(t, "capped additional", cpf.AdditionalWages, "1320000")
+	assertMoney(t, "next ordinary", cpf.YearToDate.OrdinaryWages, "8880000")
+	assertMoney(t, "next additional", cpf.YearToDate.AdditionalWages, "1320000")
+
+	// 额外工资额度用完后不再计缴
+	again := CalculateCPF(toMoney(cenToDec(0)), toMoney(cenToDec(100000)), 30, cpf.YearToDate)
+	assertMoney(t, "exhausted additional", again.AdditionalWages, "0")
+}
+
+func TestCalculateCPFThresholdsAndAgeBands(t *testing.T) {
+	cases := []struct {
+		name               string
+		wages              int64
+		age                int
+		employee, employer string
+	}{
+		{"50新元以下无须缴纳", 4000, 30, "0", "0"},
+		{"500新元以下仅雇主缴纳", 40000, 30, "0", "6800"},
+		{"500至750新元雇员按比例递增", 60000, 30, "6000", "10200"},
+		{"55岁以下", 500000, 30, "100000", "85000"},
+		{"55至60岁", 500000, 58, "85000", "77500"},
+		{"60至65岁", 500000, 62, "57500", "60000"},
+		{"70岁以上", 500000, 72, "25000", "37500"},
+		{"普通工资封顶", 1000000, 30, "148000", "125800"},
+	}
+	for _, c := range cases {
+		cpf := CalculateCPF(toMoney(cenToDec(c.wages)), toMoney(cenToDec(0)), c.age, CPFYearToDate{})
+		assertMoney(t, c.name+" employee", cpf.Employee, c.employee)
+		assertMoney(t, c.name+" employer", cpf.Employer, c.employer)
+	}
+}
+
+func TestAgeAt(t *testing.T) {
+	birth := day("1966-06-15")
+	if age := ageAt(birth, day("2024-06-01")); age != 57 {
+		t.Errorf("age before birthday = %d, want 57", age)
+	}
+	if age := ageAt(birth, day("2024-07-01")); age != 58 {
+		t.Errorf("age after birthday = %d, want 58", age)
+	}
+}
+
+func TestCPFYearToDateFromSavedRunFeedsNextCeiling(t *testing.T) {
+	config := testConfig()
+	config.BaseSalary = toMoney(cenToDec(1000000))
+	bonus := func(amount int64) []Adjustment {
+		return []Adjustment{{ID: "B1", Kind: KindEarning, Amount: toMoney(cenToDec(amount)), Taxable: true, ReasonCode: ReasonBonus}}
+	}
+	input := EmployeeInput{
+		Employee:   Employee{ID: "SG1", Jurisdiction: JurisdictionSG, BirthDate: day("1990-05-01")},
+		Config:     config,
+		Attendance: AttendanceRecord{WorkHours: hours("174")},
+		// 1至10月普通工资按上限累计 74,000新元
+		CPFYearToDate: CPFYearToDate{Year: 2024, OrdinaryWages: toMoney(cenToDec(7400000)), AdditionalWages: toMoney(cenToDec(0))},
+	}
+
+	november := input
+	november.Adjustments = bonus(1500000)
+	run := PayrollRun{Period: day("2024-11-01"), Inputs: []EmployeeInput{november}}
+	saved := run.Calculate().Employees[0]
+	// 额外工资上限 102,000 - 74,000 - 7,400 = 20,600新元，15,000新元奖金全额计缴
+	assertMoney(t, "November additional", saved.Statutory[0].Employee, "448000")
+	if saved.CPFYearToDate == nil {
+		t.Fatal("saved result has no CPFYearToDate")
+	}
+
+	// 12月输入取上期保存的累计数据：额外工资上限 102,000 - 81,400 - 7,400 - 15,000 < 0，奖金不再计缴
+	december := input
+	december.Adjustments = bonus(1000000)
+	december.CPFYearToDate = *saved.CPFYearToDate
+	run = PayrollRun{Period: day("2024-12-01"), Inputs: []EmployeeInput{december}}
+	result := run.Calculate().Employees[0]
+	assertMoney(t, "December employee CPF", result.Statutory[0].Employee, "148000")
+	assertMoney(t, "December YTD additional", result.CPFYearToDate.AdditionalWages, "1500000")
+
+	// 沿用10月的累计数据会漏算11月已计缴的额外工资，奖金被错误计缴
+	stale := CalculateEmployee(day("2024-12-01"), EmployeeInput{
+		Employee: input.Employee, Config: config, Attendance: input.Attendance,
+		Adjustments: bonus(1000000), CPFYearToDate: input.CPFYearToDate,
+	})
+	assertMoney(t, "stale December employee CPF", stale.Statutory[0].Employee, "348000")
+}
@@ -0,0 +1,138 @@
+package salary
+
+import (
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// StatutoryContext 计算法定扣缴项目所需的员工本期数据
+type StatutoryContext struct {
+	Period  time.Time       // 薪资期
+	Input   EmployeeInput   // 员工计算输入
+	Gross   decimal.Decimal // 税前工资
+	Taxable decimal.Decimal // 计税收入（税前工资 - 免税收入 + 非现金福利）
+	Lines   []PayLine       // 工资项目和一次性调整明细，含补充养老计划个人缴费扣款项
+	Pension []PensionLine   // 企业补充养老计划缴费明细，如需按自愿性供款抵减计税收入时使用
+}
+
+// StatutoryAmount 法定扣缴项目的计算结果
+type StatutoryAmount struct {
+	Employee  Money // 从工资中扣缴的个人部分
+	Employer  Money // 单位承担部分
+	Provision Money // 不扣缴、仅供员工参考的预估金额，如自行申报的税款
+
+	CPFYearToDate *CPFYearToDate // 新加坡公积金计入本期后的本年度累计工资，其他项目为空
+}
+
+// StatutoryDeduction 辖区模块注册的法定扣缴项目
+type StatutoryDeduction struct {
+	Code    string                                     // 项目代码，如 MPF
+	Label   string                                     // 工资条和报表显示名称
+	Compute func(ctx StatutoryContext) StatutoryAmount // 计算函数
+}
+
+// StatutoryLine 员工结果中的一项法定扣缴
+type StatutoryLine struct {
+	Code      string // 项目代码
+	Label     string // 显示名称
+	Employee  Money  // 个人扣缴（分）
+	Employer  Money  // 单位承担（分）
+	Provision Money  // 预估金额（分）
+}
+
+// statutoryRegistry 各辖区注册的法定扣缴项目
+var statutoryRegistry = struct {
+	sync.RWMutex
+	items map[Jurisdiction][]StatutoryDeduction
+}{items: make(map[Jurisdiction][]StatutoryDeduction)}
+
+// RegisterStatutoryDeduction 为辖区注册法定扣缴项目，按注册顺序计算；新增国家或地区只需注册各自的项目
+func RegisterStatutoryDeduction(jurisdiction Jurisdiction, deduction StatutoryDeduction) {
+	statutoryRegistry.Lock()
+	defer statutoryRegistry.Unlock()
+	statutoryRegistry.items[jurisdiction] = append(statutoryRegistry.items[jurisdiction], deduction)
+}
+
+// StatutoryDeductions 查询辖区注册的法定扣缴项目
+func StatutoryDeductions(jurisdiction Jurisdiction) []StatutoryDeduction {
+	statutoryRegistry.RLock()
+	defer statutoryRegistry.RUnlock()
+	return append([]StatutoryDeduction(nil), statutoryRegistry.items[jurisdiction]...)
+}
+
+// calculateStatutory 按辖区注册的法定扣缴项目计算员工工资
+// 个人扣缴合计计入 SocialInsurance 并从实发工资中扣除，单位承担合计计入 EmployerContributions，
+// 预估金额合计计入 TaxProvision；这些辖区雇主不代扣个人所得税
+// 企业补充养老计划与中国内地员工相同：个人缴费作为扣款项，单位缴费计入 EmployerContributions；不缴纳内地基本养老保险，PensionBase 为0
+func calculateStatutory(period time.Time, input EmployeeInput, deductions []StatutoryDeduction) EmployeeResult {
+	config := input.Config
+	baseSalary, proration := proratedBaseSalary(period, input)
+	payable, unpaidOvertime, compTime := splitOvertime(input.OvertimeTreatment, input.Attendance)
+	overtimePay := CalculateOvertimePay(config, payable)
+
+	pension, pensionLines, pensionEmployer := pensionContributions(period, input, baseSalary)
+
+	lines := append(elementLines(input.Elements, period), shiftLines(config, input.Attendance)...)
+	lines = append(lines, adjustmentLines(input.Adjustments)...)
+	lines = append(lines, pensionLines...)
+	taxableEarnings, exemptEarnings, preTaxDeductions, postTaxDeductions := lineTotals(lines)
+	gross := moneyToDec(baseSalary).Add(moneyToDec(overtimePay)).Add(taxableEarnings).Add(exemptEarnings)
+
+	lines, penaltyTruncated := capPenalties(lines, gross, input.PenaltyCapRate)
+	if penaltyTruncated.IsPositive() {
+		_, _, preTaxDeductions, postTaxDeductions = lineTotals(lines)
+	}
+
+	benefits := benefitTotal(lines)
+	ctx := StatutoryContext{Period: monthStart(period), Input: input, Gross: gross, Taxable: gross.Sub(exemptEarnings).Add(benefits), Lines: lines, Pension: pension}
+	var statutory []StatutoryLine
+	employee, employer, provision := decimal.Zero, pensionEmployer, decimal.Zero
+	var cpfYearToDate *CPFYearToDate
+	for _, d := range deductions {
+		amount := d.Compute(ctx)
+		if amount.CPFYearToDate != nil {
+			cpfYearToDate = amount.CPFYearToDate
+		}
+		statutory = append(statutory, StatutoryLine{
+			Code:      d.Code,
+			Label:     d.Label,
+			Employee:  amount.Employee,
+			Employer:  amount.Employer,
+			Provision: amount.Provision,
+		})
+		employee = employee.Add(moneyToDec(amount.Employee))
+		employer = employer.Add(moneyToDec(amount.Employer))
+		provision = provision.Add(moneyToDec(amount.Provision))
+	}
+
+	net := gross.Sub(employee).Sub(preTaxDeductions).Sub(postTaxDeductions)
+	zero := toMoney(decimal.Zero)
+	return EmployeeResult{
+		Employee:              input.Employee,
+		Period:                monthStart(period),
+		BaseSalary:            baseSalary,
+		OvertimePay:           overtimePay,
+		GrossSalary:           toMoney(gross),
+		SocialInsurance:       toMoney(employee),
+		HousingFund:           zero,
+		TaxableIncome:         toMoney(ctx.Taxable),
+		SpecialDeductionTotal: zero,
+		IncomeTax:             zero,
+		NetSalary:             toMoney(net),
+		Lines:                 lines,
+		PenaltyTruncated:      toMoney(penaltyTruncated),
+		Attendance:            input.Attendance,
+		StandardHours:         standardHours(config, input.Attendance),
+		TaxProvision:          toMoney(provision),
+		EmployerContributions: toMoney(employer),
+		Statutory:             statutory,
+		UnpaidOvertimeHours:   unpaidOvertime,
+		Proration:             proration,
+		CompTimeHours:         compTime,
+		BenefitsInKind:        toMoney(benefits),
+		CPFYearToDate:         cpfYearToDate,
+		Pension:               pension,
+	}
+}
@@ -0,0 +1,72 @@
+package salary
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PayCategory 工资计算类别
+type PayCategory int
+
+const (
+	CategoryRegular  PayCategory = iota // 正式员工工资薪金
+	CategoryStipend                     // 入职前实习津贴（劳务报酬）
+	CategorySeasonal                    // 季节工、临时工按日计发
+)
+
+// CalculateLaborRemunerationTax 计算劳务报酬个人所得税预扣税额（金额单位为分）
+// 每次收入不超过4000元的，减除费用800元；超过4000元的，减除20%费用
+// 预扣率：不超过2万元20%；2万至5万元30%，速算扣除数2000元；超过5万元40%，速算扣除数7000元
+func CalculateLaborRemunerationTax(income Money) Money {
+	amount := moneyToDec(income)
+	if !amount.IsPositive() {
+		return toMoney(decimal.Zero)
+	}
+
+	// 计算应纳税所得额
+	var taxable decimal.Decimal
+	if amount.LessThanOrEqual(cenToDec(400000)) {
+		taxable = amount.Sub(cenToDec(80000))
+	} else {
+		taxable = amount.Mul(decimal.RequireFromString("0.8"))
+	}
+	if !taxable.IsPositive() {
+		return toMoney(decimal.Zero)
+	}
+
+	// 按劳务报酬预扣率表计算
+	var tax decimal.Decimal
+	switch {
+	case taxable.LessThanOrEqual(cenToDec(2000000)):
+		tax = taxable.Mul(decimal.RequireFromString("0.2"))
+	case taxable.LessThanOrEqual(cenToDec(5000000)):
+		tax = taxable.Mul(decimal.RequireFromString("0.3")).Sub(cenToDec(200000))
+	default:
+		tax = taxable.Mul(decimal.RequireFromString("0.4")).Sub(cenToDec(700000))
+	}
+	return toMoney(tax.Round(2))
+}
+
+// isPreBoarding 判断薪资期是否早于员工合同开始（入职）月份
+func isPreBoarding(period time.Time, e Employee) bool {
+	return !e.HireDate.IsZero() && monthStart(period).Before(monthStart(e.HireDate))
+}
+
+// calculateStipend 计算入职前实习津贴：不缴社保公积金，按劳务报酬预扣个税
+func calculateStipend(period time.Time, input EmployeeInput) EmployeeResult {
+	tax := CalculateLaborRemunerationTax(input.Stipend)
+	return EmployeeResult{
+		Employee:      input.Employee,
+		Period:        monthStart(period),
+		Category:      CategoryStipend,
+		GrossSalary:   input.Stipend,
+		TaxableIncome: input.Stipend,
+		IncomeTax:     tax,
+		NetSalary:     toMoney(moneyToDec(input.Stipend).Sub(moneyToDec(tax))),
+		Attendance:    input.Attendance,
+		Lines: []PayLine{
+			{Code: "STIPEND", Name: "实习津贴", Kind: KindEarning, Amount: input.Stipend, Taxable: true},
+		},
+	}
+}
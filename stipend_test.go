@@ -0,0 +1,44 @@
+package salary
+
+import "testing"
+
+func TestCalculateLaborRemunerationTax(t *testing.T) {
+	cases := []struct {
+		income, want int64
+	}{
+		{50000, 0},          // 不超过800元不纳税
+		{300000, 44000},     // (3000-800)×20%
+		{1000000, 160000},   // 10000×80%×20%
+		{3000000, 520000},   // 30000×80%×30%-2000
+		{10000000, 2500000}, // 100000×80%×40%-7000
+	}
+	for _, c := range cases {
+		got := CalculateLaborRemunerationTax(toMoney(cenToDec(c.income)))
+		if !moneyToDec(got).Equal(cenToDec(c.want)) {
+			t.Errorf("income %d: tax = %s, want %d", c.income, moneyToDec(got), c.want)
+		}
+	}
+}
+
+func TestStipendBeforeHireDate(t *testing.T) {
+	input := EmployeeInput{
+		Employee:   Employee{ID: "E1", HireDate: day("2024-07-01")},
+		Config:     testConfig(),
+		Attendance: AttendanceRecord{WorkHours: hours("174")},
+		Stipend:    toMoney(cenToDec(300000)),
+	}
+
+	result := CalculateEmployee(day("2024-06-01"), input)
+	if result.Category != CategoryStipend || len(result.Lines) != 1 || result.Lines[0].Code != "STIPEND" {
+		t.Fatalf("result = %+v", result)
+	}
+	assertMoney(t, "gross", result.GrossSalary, "300000")
+	assertMoney(t, "social insurance", result.SocialInsurance, "0")
+	assertMoney(t, "tax", result.IncomeTax, "44000")
+	assertMoney(t, "net", result.NetSalary, "256000")
+
+	// 入职当月起按正式工资计算
+	if result := CalculateEmployee(day("2024-07-01"), input); result.Category != CategoryRegular || !moneyToDec(result.SocialInsurance).IsPositive() {
+		t.Errorf("hire month result = %+v", result)
+	}
+}
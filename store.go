@@ -0,0 +1,209 @@
+package salary
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrRunNotFound 发薪批次不存在
+var ErrRunNotFound = errors.New("发薪批次不存在")
+
+// RunComment 批次评论，如审核意见、调整说明
+type RunComment struct {
+	ID        string    `json:"id"`         // 评论编号
+	RunID     string    `json:"run_id"`     // 批次编号
+	Author    string    `json:"author"`     // 评论人
+	Body      string    `json:"body"`       // 内容
+	CreatedAt time.Time `json:"created_at"` // 创建时间
+}
+
+// RunAttachment 批次附件，如审批邮件、调整依据
+type RunAttachment struct {
+	ID          string    `json:"id"`           // 附件编号
+	RunID       string    `json:"run_id"`       // 批次编号
+	Name        string    `json:"name"`         // 文件名
+	ContentType string    `json:"content_type"` // 文件类型
+	Size        int       `json:"size"`         // 文件大小（字节）
+	UploadedBy  string    `json:"uploaded_by"`  // 上传人
+	CreatedAt   time.Time `json:"created_at"`   // 上传时间
+	Data        []byte    `json:"-"`            // 文件内容
+}
+
+// MemoryStore 内存存储，保存发薪批次及其评论和附件、员工档案，供审计时通过接口查询
+type MemoryStore struct {
+	mu          sync.RWMutex
+	runs        map[string]PayrollResult
+	runOrder    []string
+	comments    map[string][]RunComment
+	attachments map[string][]RunAttachment
+	employees   map[string]EmployeeRecord
+	fourEyes    *FourEyesPolicy
+	audit       *AuditLog
+	exports     map[string]ExportRecord
+	holds       *HoldLedger
+	exportSeq   int64
+	seq         int
+}
+
+// NewMemoryStore 创建空的内存存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		runs:        make(map[string]PayrollResult),
+		comments:    make(map[string][]RunComment),
+		attachments: make(map[string][]RunAttachment),
+		employees:   make(map[string]EmployeeRecord),
+		exports:     make(map[string]ExportRecord),
+		holds:       NewHoldLedger(),
+	}
+}
+
+// nextID 生成带前缀的递增编号，调用方需持有写锁
+func (s *MemoryStore) nextID(prefix string) string {
+	s.seq++
+	return fmt.Sprintf("%s-%06d", prefix, s.seq)
+}
+
+// SaveRun 保存批次结果，未设置编号时自动生成并写回结果
+func (s *MemoryStore) SaveRun(result *PayrollResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if result.ID == "" {
+		result.ID = s.nextID(result.Period.Format("200601"))
+	}
+	if _, exists := s.runs[result.ID]; !exists {
+		s.runOrder = append(s.runOrder, result.ID)
+	}
+	s.runs[result.ID] = *result
+	s.trackExports(*result)
+}
+
+// Run 查询批次结果
+func (s *MemoryStore) Run(id string) (PayrollResult, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	run, ok := s.runs[id]
+	if !ok {
+		return PayrollResult{}, fmt.Errorf("%w: %s", ErrRunNotFound, id)
+	}
+	return run, nil
+}
+
+// Runs 返回全部批次结果，按薪资期排序
+func (s *MemoryStore) Runs() []PayrollResult {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	runs := make([]PayrollResult, 0, len(s.runOrder))
+	for _, id := range s.runOrder {
+		runs = append(runs, s.runs[id])
+	}
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].Period.Before(runs[j].Period) })
+	return runs
+}
+
+// AddComment 为批次添加评论
+func (s *MemoryStore) AddComment(runID, author, body string) (RunComment, error) {
+	return s.addComment(runID, author, body, time.Now())
+}
+
+// addComment 为批次添加评论，at 为评论时间
+func (s *MemoryStore) addComment(runID, author, body string, at time.Time) (RunComment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.runs[runID]; !ok {
+		return RunComment{}, fmt.Errorf("%w: %s", ErrRunNotFound, runID)
+	}
+	comment := RunComment{ID: s.nextID("C"), RunID: runID, Author: author, Body: body, CreatedAt: at}
+	s.comments[runID] = append(s.comments[runID], comment)
+	return comment, nil
+}
+
+// Comments 查询批次的全部评论，按添加顺序排列
+func (s *MemoryStore) Comments(runID string) ([]RunComment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if _, ok := s.runs[runID]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrRunNotFound, runID)
+	}
+	return append([]RunComment(nil), s.comments[runID]...), nil
+}
+
+// AddAttachment 为批次上传附件，未设置上传时间时使用当前时间
+func (s *MemoryStore) AddAttachment(runID string, attachment RunAttachment) (RunAttachment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.runs[runID]; !ok {
+		return RunAttachment{}, fmt.Errorf("%w: %s", ErrRunNotFound, runID)
+	}
+	attachment.ID = s.nextID("A")
+	attachment.RunID = runID
+	attachment.Size = len(attachment.Data)
+	if attachment.CreatedAt.IsZero() {
+		attachment.CreatedAt = time.Now()
+	}
+	s.attachments[runID] = append(s.attachments[runID], attachment)
+	return attachment, nil
+}
+
+// Attachments 查询批次的全部附件，按上传顺序排列
+func (s *MemoryStore) Attachments(runID string) ([]RunAttachment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if _, ok := s.runs[runID]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrRunNotFound, runID)
+	}
+	return append([]RunAttachment(nil), s.attachments[runID]...), nil
+}
+
+// Attachment 查询单个附件（含文件内容）
+func (s *MemoryStore) Attachment(runID, attachmentID string) (RunAttachment, error) {
+	attachments, err := s.Attachments(runID)
+	if err != nil {
+		return RunAttachment{}, err
+	}
+	for _, a := range attachments {
+		if a.ID == attachmentID {
+			return a, nil
+		}
+	}
+	return RunAttachment{}, fmt.Errorf("%w: %s", ErrAttachmentNotFound, attachmentID)
+}
+
+// EmployeeResults 查询员工在全部批次中的计算结果，按薪资期排序
+func (s *MemoryStore) EmployeeResults(employeeID string) []EmployeeResult {
+	var results []EmployeeResult
+	for _, run := range s.Runs() {
+		for _, r := range run.Employees {
+			if r.Employee.ID == employeeID {
+				results = append(results, r)
+			}
+		}
+	}
+	return results
+}
+
+// payslipContext 查询员工某薪资期的结果、上一期结果和本期公告
+func (s *MemoryStore) payslipContext(employeeID string, period time.Time) (current EmployeeResult, previous *EmployeeResult, announcements []Announcement, ok bool) {
+	for _, run := range s.Runs() {
+		for _, r := range run.Employees {
+			if r.Employee.ID != employeeID {
+				continue
+			}
+			switch {
+			case sameMonth(r.Period, period):
+				current, announcements, ok = r, run.Announcements, true
+			case r.Period.Before(monthStart(period)):
+				last := r
+				previous = &last
+			}
+		}
+	}
+	return current, previous, announcements, ok
+}
+
+// Holds 返回实发工资暂扣台账
+func (s *MemoryStore) Holds() *HoldLedger {
+	return s.holds
+}
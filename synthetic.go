@@ -0,0 +1,203 @@
+package salary
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// SyntheticOptions 合成数据生成参数
+type SyntheticOptions struct {
+	Headcount       int       // 员工人数
+	Seed            uint64    // 随机种子，相同种子生成相同数据
+	MedianSalary    Money     // 月薪中位数（分），0时为10000元
+	SalarySpread    float64   // 月薪对数正态分布的离散度，0时为0.4
+	AbsenceRate     float64   // 每名员工每月出现缺勤的概率，0时为0.1
+	OvertimeRate    float64   // 每名员工每月出现加班的概率，0时为0.3
+	Departments     []string  // 部门，为空时使用内置部门
+	Cities          []string  // 社保缴纳城市，为空时不设置城市
+	HireBefore      time.Time // 入职日期上限，零值时为当前时间
+	StandardHours   int64     // 每月标准工时，0时为174小时
+	MaxAbsenceHours int       // 单月最多缺勤小时，0时为16
+	MaxOvertime     int       // 单月单类加班最多小时，0时为20
+}
+
+// withDefaults 补齐未设置的参数
+func (o SyntheticOptions) withDefaults() SyntheticOptions {
+	if moneyToDec(o.MedianSalary).IsZero() {
+		o.MedianSalary = toMoney(cenToDec(1000000))
+	}
+	if o.SalarySpread == 0 {
+		o.SalarySpread = 0.4
+	}
+	if o.AbsenceRate == 0 {
+		o.AbsenceRate = 0.1
+	}
+	if o.OvertimeRate == 0 {
+		o.OvertimeRate = 0.3
+	}
+	if len(o.Departments) == 0 {
+		o.Departments = []string{"研发部", "销售部", "财务部", "人力资源部", "运营部"}
+	}
+	if o.HireBefore.IsZero() {
+		o.HireBefore = time.Now()
+	}
+	if o.StandardHours == 0 {
+		o.StandardHours = 174
+	}
+	if o.MaxAbsenceHours == 0 {
+		o.MaxAbsenceHours = 16
+	}
+	if o.MaxOvertime == 0 {
+		o.MaxOvertime = 20
+	}
+	return o
+}
+
+// syntheticSurnames、syntheticGivenNames 合成姓名用字，组合结果不对应真实人员
+var (
+	syntheticSurnames   = []string{"赵", "钱", "孙", "李", "周", "吴", "郑", "王", "冯", "陈"}
+	syntheticGivenNames = []string{"一", "二", "三", "子", "文", "明", "华", "宁", "安", "平", "晨", "雨"}
+)
+
+// SyntheticGenerator 合成数据生成器：生成虚构员工档案和考勤，不包含任何真实个人信息
+type SyntheticGenerator struct {
+	opts SyntheticOptions
+	rng  *rand.Rand
+}
+
+// NewSyntheticGenerator 创建合成数据生成器
+func NewSyntheticGenerator(opts SyntheticOptions) *SyntheticGenerator {
+	opts = opts.withDefaults()
+	return &SyntheticGenerator{opts: opts, rng: rand.New(rand.NewPCG(opts.Seed, opts.Seed^0x5a5a5a5a))}
+}
+
+// syntheticBankAccount 生成格式合法的虚构卡号：固定前缀 + 随机数字 + Luhn 校验位
+func (g *SyntheticGenerator) syntheticBankAccount() string {
+	digits := []byte("622202")
+	for len(digits) < 18 {
+		digits = append(digits, byte('0'+g.rng.IntN(10)))
+	}
+	for check := byte('0'); check <= '9'; check++ {
+		if luhnValid(string(append(digits, check))) {
+			return string(append(digits, check))
+		}
+	}
+	return string(digits)
+}
+
+// Config 合成员工使用的薪资配置，费率为常见的个人缴费比例
+func (g *SyntheticGenerator) Config(base Money) PayrollConfig {
+	return PayrollConfig{
+		BaseSalary:          base,
+		FullMonthHours:      toMoney(decimal.NewFromInt(g.opts.StandardHours)),
+		PensionRate:         decimal.RequireFromString("0.08"),
+		MedicalRate:         decimal.RequireFromString("0.02"),
+		UnemploymentRate:    decimal.RequireFromString("0.005"),
+		HousingFundRate:     decimal.RequireFromString("0.07"),
+		OvertimeWeekdayRate: decimal.RequireFromString("1.5"),
+		OvertimeWeekendRate: decimal.RequireFromString("2"),
+		OvertimeHolidayRate: decimal.RequireFromString("3"),
+	}
+}
+
+// Employees 生成虚构员工档案，月薪服从以中位数为中心的对数正态分布，取整到百元
+func (g *SyntheticGenerator) Employees() []EmployeeRecord {
+	o := g.opts
+	median := moneyToDec(o.MedianSalary).InexactFloat64()
+	records := make([]EmployeeRecord, 0, o.Headcount)
+	for i := 0; i < o.Headcount; i++ {
+		salary := median * math.Exp(g.rng.NormFloat64()*o.SalarySpread)
+		base := decimal.NewFromFloat(salary / 10000).Round(0).Mul(decimal.NewFromInt(10000))
+		gender := GenderMale
+		if g.rng.IntN(2) == 1 {
+			gender = GenderFemale
+		}
+		e := Employee{
+			ID:         fmt.Sprintf("SBX%05d", i+1),
+			Name:       syntheticSurnames[g.rng.IntN(len(syntheticSurnames))] + syntheticGivenNames[g.rng.IntN(len(syntheticGivenNames))] + syntheticGivenNames[g.rng.IntN(len(syntheticGivenNames))],
+			Department: o.Departments[g.rng.IntN(len(o.Departments))],
+			Grade:      fmt.Sprintf("P%d", 1+g.rng.IntN(8)),
+			Gender:     gender,
+			HireDate:   o.HireBefore.AddDate(0, 0, -30-g.rng.IntN(3650)),
+			BirthDate:  o.HireBefore.AddDate(-22-g.rng.IntN(38), 0, -g.rng.IntN(365)),
+		}
+		if len(o.Cities) > 0 {
+			e.City = o.Cities[g.rng.IntN(len(o.Cities))]
+		}
+		records = append(records, EmployeeRecord{
+			Employee:    e,
+			BaseSalary:  toMoney(decimal.Max(base, cenToDec(10000))),
+			BankAccount: g.syntheticBankAccount(),
+			UpdatedBy:   "sandbox",
+		})
+	}
+	return records
+}
+
+// randomHours 返回0到max之间的整数小时
+func (g *SyntheticGenerator) randomHours(limit int) Hours {
+	return Hours(decimal.NewFromInt(int64(g.rng.IntN(limit + 1))))
+}
+
+// Attendance 生成一个月的考勤：按概率出现缺勤和各类加班
+func (g *SyntheticGenerator) Attendance() AttendanceRecord {
+	o := g.opts
+	zero := Hours(decimal.Zero)
+	a := AttendanceRecord{
+		WorkHours:       Hours(decimal.NewFromInt(o.StandardHours)),
+		OvertimeWeekday: zero,
+		OvertimeWeekend: zero,
+		OvertimeHoliday: zero,
+		AbsenceHours:    zero,
+	}
+	if g.rng.Float64() < o.AbsenceRate {
+		a.AbsenceHours = g.randomHours(o.MaxAbsenceHours)
+	}
+	if g.rng.Float64() < o.OvertimeRate {
+		a.OvertimeWeekday = g.randomHours(o.MaxOvertime)
+		a.OvertimeWeekend = g.randomHours(o.MaxOvertime / 2)
+	}
+	return a
+}
+
+// Inputs 为员工生成某一薪资期的计算输入，入职前的员工不计入
+func (g *SyntheticGenerator) Inputs(records []EmployeeRecord, period time.Time) []EmployeeInput {
+	end := monthStart(period).AddDate(0, 1, 0)
+	inputs := make([]EmployeeInput, 0, len(records))
+	for _, r := range records {
+		if !r.Employee.HireDate.Before(end) {
+			continue
+		}
+		inputs = append(inputs, EmployeeInput{
+			Employee:   r.Employee,
+			Config:     g.Config(r.BaseSalary),
+			Attendance: g.Attendance(),
+		})
+	}
+	return inputs
+}
+
+// NewSandboxStore 创建沙箱租户：用合成数据填充员工档案，并计算截至 through 的最近 months 个月的发薪批次
+// 供集成方和测试人员在不接触真实个人数据的情况下走通完整流程
+func NewSandboxStore(opts SyntheticOptions, through time.Time, months int) *MemoryStore {
+	if opts.HireBefore.IsZero() {
+		opts.HireBefore = monthStart(through).AddDate(0, -months, 0)
+	}
+	g := NewSyntheticGenerator(opts)
+	store := NewMemoryStore()
+	records := g.Employees()
+	for _, r := range records {
+		store.PutEmployee(r)
+	}
+	for i := months - 1; i >= 0; i-- {
+		period := monthStart(through).AddDate(0, -i, 0)
+		run := PayrollRun{Period: period, Inputs: g.Inputs(records, period)}
+		result := run.Calculate()
+		store.SaveRun(&result)
+	}
+	return store
+}
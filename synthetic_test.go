@@ -0,0 +1,40 @@
+package salary
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSyntheticGeneratorDeterministic(t *testing.T) {
+	opts := SyntheticOptions{Headcount: 20, Seed: 42}
+	a := NewSyntheticGenerator(opts).Employees()
+	b := NewSyntheticGenerator(opts).Employees()
+	if len(a) != 20 {
+		t.Fatalf("len = %d, want 20", len(a))
+	}
+	for i := range a {
+		if a[i].Employee.Name != b[i].Employee.Name || !moneyToDec(a[i].BaseSalary).Equal(moneyToDec(b[i].BaseSalary)) {
+			t.Fatalf("employee %d differs between runs with the same seed", i)
+		}
+		if _, err := ValidateBankAccount(a[i].BankAccount); err != nil {
+			t.Errorf("employee %d bank account: %v", i, err)
+		}
+	}
+}
+
+func TestNewSandboxStore(t *testing.T) {
+	through := time.Date(2024, 6, 1, 0, 0, 0, 0, time.Local)
+	store := NewSandboxStore(SyntheticOptions{Headcount: 10, Seed: 7}, through, 3)
+	if got := len(store.Employees()); got != 10 {
+		t.Errorf("employees = %d, want 10", got)
+	}
+	runs := store.Runs()
+	if len(runs) != 3 {
+		t.Fatalf("runs = %d, want 3", len(runs))
+	}
+	for _, run := range runs {
+		if len(run.Employees) != 10 || len(run.Errors) != 0 {
+			t.Errorf("run %s: %d employees, %d errors", run.Period.Format("2006-01"), len(run.Employees), len(run.Errors))
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package salary
+
+import "github.com/shopspring/decimal"
+
+// grossUpTax 计算公司承担个税时的税上税：求税额 T 使 T = 税额(应纳税所得额 + T)
+// 从最高档开始逐档求解 T = (所得 × 税率 - 速算扣除数) / (1 - 税率)，含税所得落在该档时即为解；税率为1的档次无解，跳过
+// taxableIncome: 公司承担税款前的应纳税所得额
+// totalDeductions: 扣除总额
+// brackets: 税率表
+func grossUpTax(taxableIncome, totalDeductions Money, brackets []TaxBracket) Money {
+	base := moneyToDec(taxableIncome).Sub(moneyToDec(totalDeductions))
+	one := decimal.NewFromInt(1)
+	for i := len(brackets) - 1; i >= 0; i-- {
+		b := brackets[i]
+		if b.Rate.GreaterThanOrEqual(one) {
+			continue
+		}
+		tax := base.Mul(b.Rate).Sub(moneyToDec(b.Deduction)).Div(one.Sub(b.Rate))
+		if !tax.IsNegative() && base.Add(tax).GreaterThan(moneyToDec(b.Threshold)) {
+			return toMoney(tax.Round(2))
+		}
+	}
+	return toMoney(decimal.Zero)
+}
+
+// bearIncomeTax 由公司承担员工本期个人所得税
+// 按不含税收入换算含税所得：应纳税所得额 = (不含税所得 - 扣除 - 速算扣除数) ÷ (1 - 税率)，
+// 公司承担的税款计为应税收入项目 code，员工实发工资不再扣除个税
+// r: 员工本期结果，IncomeTax 为按不含税收入计算的税额
+// brackets: 适用的税率表
+// code、name: 公司承担个税的收入项目代码和名称
+// 返回值: 公司承担的税款
+func bearIncomeTax(r *EmployeeResult, brackets []TaxBracket, code, name string) Money {
+	deductions := toMoney(moneyToDec(r.SpecialDeductionTotal).Add(moneyToDec(r.StandardDeduction)))
+	tax := grossUpTax(r.TaxableIncome, deductions, brackets)
+	r.NetSalary = toMoney(moneyToDec(r.NetSalary).Add(moneyToDec(r.IncomeTax)))
+	r.GrossSalary = toMoney(moneyToDec(r.GrossSalary).Add(moneyToDec(tax)))
+	r.TaxableIncome = toMoney(moneyToDec(r.TaxableIncome).Add(moneyToDec(tax)))
+	r.IncomeTax = tax
+	r.Lines = append(r.Lines, PayLine{Code: code, Name: name, Kind: KindEarning, Amount: tax, Taxable: true})
+	return tax
+}
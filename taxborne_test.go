@@ -0,0 +1,24 @@
+package salary
+
+import "testing"
+
+func TestEmployerBearsTax(t *testing.T) {
+	input := EmployeeInput{Employee: Employee{ID: "E1"}, Config: testConfig(), Attendance: AttendanceRecord{WorkHours: hours("174")}}
+	input.Config.BaseSalary = toMoney(cenToDec(2000000))
+	without := CalculateEmployee(day("2024-03-01"), input)
+	input.EmployerBearsTax = true
+	with := CalculateEmployee(day("2024-03-01"), input)
+
+	// 员工实发不扣个税
+	assertMoney(t, "net", with.NetSalary, moneyToDec(without.NetSalary).Add(moneyToDec(without.IncomeTax)).String())
+	if len(with.Lines) != 1 || with.Lines[0].Code != "TAX-BORNE" {
+		t.Fatalf("lines = %+v", with.Lines)
+	}
+	// 公司代付的个税并入收入后，按含税所得重新计算的税额与代付金额一致
+	assertMoney(t, "gross", with.GrossSalary, moneyToDec(without.GrossSalary).Add(moneyToDec(with.IncomeTax)).String())
+	deductions := toMoney(moneyToDec(with.SpecialDeductionTotal).Add(moneyToDec(with.StandardDeduction)))
+	recomputed := calculateIncomeTaxWith(with.TaxableIncome, deductions, DefaultTaxBrackets())
+	if diff := moneyToDec(recomputed).Sub(moneyToDec(with.IncomeTax)).Abs(); diff.GreaterThan(cenToDec(1)) {
+		t.Errorf("borne tax %s, recomputed %s", moneyToDec(with.IncomeTax), moneyToDec(recomputed))
+	}
+}
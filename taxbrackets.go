@@ -0,0 +1,159 @@
+package salary
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// DefaultTaxBrackets 内置的综合所得七级超额累进税率表（按月换算，金额单位为分）
+// 月应纳税所得额：不超过3000元3%；3000至12000元10%；12000至25000元20%；25000至35000元25%；
+// 35000至55000元30%；55000至80000元35%；超过80000元45%
+func DefaultTaxBrackets() []TaxBracket {
+	rows := []struct {
+		threshold int64
+		rate      string
+		deduction int64
+	}{
+		{0, "0.03", 0},
+		{300000, "0.10", 21000},
+		{1200000, "0.20", 141000},
+		{2500000, "0.25", 266000},
+		{3500000, "0.30", 441000},
+		{5500000, "0.35", 716000},
+		{8000000, "0.45", 1516000},
+	}
+	brackets := make([]TaxBracket, len(rows))
+	for i, r := range rows {
+		brackets[i] = TaxBracket{
+			Threshold: toMoney(cenToDec(r.threshold)),
+			Rate:      decimal.RequireFromString(r.rate),
+			Deduction: toMoney(cenToDec(r.deduction)),
+		}
+	}
+	return brackets
+}
+
+// activeTaxBrackets 当前生效的税率表，默认为内置税率表
+var activeTaxBrackets = struct {
+	sync.RWMutex
+	brackets []TaxBracket
+}{brackets: DefaultTaxBrackets()}
+
+// GetTaxBrackets 返回当前生效的个人所得税税率表副本
+func GetTaxBrackets() []TaxBracket {
+	activeTaxBrackets.RLock()
+	defer activeTaxBrackets.RUnlock()
+	return append([]TaxBracket(nil), activeTaxBrackets.brackets...)
+}
+
+// SetTaxBrackets 替换当前生效的税率表，用于自定义或未来调整后的税率表
+// 税率表须按下限严格递增、税率在(0,1]区间内，校验失败时不生效
+func SetTaxBrackets(brackets []TaxBracket) error {
+	if err := validateTaxBrackets(brackets); err != nil {
+		return err
+	}
+	activeTaxBrackets.Lock()
+	defer activeTaxBrackets.Unlock()
+	activeTaxBrackets.brackets = append([]TaxBracket(nil), brackets...)
+	return nil
+}
+
+// LoadTaxBrackets 从JSON读取税率表并设为当前生效的税率表
+// 格式：[{"threshold": "0", "rate": "0.03", "deduction": "0"}, ...]，金额单位为分
+func LoadTaxBrackets(r io.Reader) error {
+	var brackets []TaxBracket
+	if err := json.NewDecoder(r).Decode(&brackets); err != nil {
+		return fmt.Errorf("税率表格式错误: %w", err)
+	}
+	return SetTaxBrackets(brackets)
+}
+
+// TaxConfig 个税计算配置
+type TaxConfig struct {
+	StandardDeduction Money // 每月减除费用（起征点，分）
+	ProRate           bool  // 入职、离职当月是否按在职天数折算减除费用
+
+	Brackets []TaxBracket // 适用的税率表，为空时使用当前生效的税率表；按生效日期管理配置时由 ConfigStore 填入
+}
+
+// brackets 返回适用的税率表
+func (c TaxConfig) brackets() []TaxBracket {
+	if len(c.Brackets) > 0 {
+		return c.Brackets
+	}
+	return GetTaxBrackets()
+}
+
+// AnnualBrackets 返回按年换算的税率表：适用税率表的下限和速算扣除数乘以12，
+// 用于累计预扣法、全年一次性奖金并入计税和一次性补偿收入计税；内置税率表换算后即为 AnnualTaxBrackets
+func (c TaxConfig) AnnualBrackets() []TaxBracket {
+	return annualizeBrackets(c.brackets())
+}
+
+// annualizeBrackets 将按月换算的税率表换算为年度税率表
+func annualizeBrackets(monthly []TaxBracket) []TaxBracket {
+	twelve := decimal.NewFromInt(12)
+	annual := make([]TaxBracket, len(monthly))
+	for i, b := range monthly {
+		annual[i] = TaxBracket{
+			Threshold: toMoney(moneyToDec(b.Threshold).Mul(twelve)),
+			Rate:      b.Rate,
+			Deduction: toMoney(moneyToDec(b.Deduction).Mul(twelve)),
+		}
+	}
+	return annual
+}
+
+// cumulativeDeduction 累计预扣法的累计减除费用 = 每月减除费用 × 任职月数，不按在职天数折算
+func (c TaxConfig) cumulativeDeduction(months int) decimal.Decimal {
+	return moneyToDec(c.StandardDeduction).Mul(decimal.NewFromInt(int64(months)))
+}
+
+// DefaultTaxConfig 默认个税配置：每月减除费用5000元，不折算
+func DefaultTaxConfig() TaxConfig {
+	return TaxConfig{StandardDeduction: MonthlyBasicExemption}
+}
+
+// taxConfig 返回薪资配置中的个税配置，未设置时使用默认配置
+func (c PayrollConfig) taxConfig() TaxConfig {
+	if c.Tax == nil {
+		return DefaultTaxConfig()
+	}
+	return *c.Tax
+}
+
+// standardDeduction 返回本月实际减除费用：开启折算时按在职比例折算
+// employed: 本月在职天数占当月天数的比例
+func (c TaxConfig) standardDeduction(employed decimal.Decimal) Money {
+	amount := moneyToDec(c.StandardDeduction)
+	if c.ProRate && employed.LessThan(decimal.NewFromInt(1)) {
+		amount = amount.Mul(employed).Round(2)
+	}
+	return toMoney(amount)
+}
+
+// IncomeTax 计算个人所得税：应纳税所得额减除每月减除费用和专项附加扣除后按适用的税率表计税
+// taxableIncome: 应纳税所得额（未减除费用）
+// deductions: 专项附加扣除项
+// employed: 本月在职天数占当月天数的比例，仅在开启折算时使用
+func (c TaxConfig) IncomeTax(taxableIncome Money, deductions SpecialDeductions, employed decimal.Decimal) Money {
+	total := moneyToDec(deductions.Total()).Add(moneyToDec(c.standardDeduction(employed)))
+	return calculateIncomeTaxWith(taxableIncome, toMoney(total), c.brackets())
+}
+
+// employedFraction 计算员工本月在职天数占当月天数的比例，入职、离职当天均计为在职
+func employedFraction(period time.Time, e Employee) decimal.Decimal {
+	start := monthStart(period)
+	end := start.AddDate(0, 1, 0)
+	from, to, _ := employedRange(period, e)
+	if !from.Before(to) {
+		return decimal.Zero
+	}
+	days := decimal.NewFromFloat(to.Sub(from).Hours() / 24).Round(0)
+	return days.Div(decimal.NewFromFloat(end.Sub(start).Hours() / 24).Round(0))
+}
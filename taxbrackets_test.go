@@ -0,0 +1,103 @@
+package salary
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestCalculateIncomeTaxDefaultBrackets(t *testing.T) {
+	tests := []struct {
+		taxable int64
+		want    string
+	}{
+		{0, "0"},
+		{300000, "9000"},      // 3000 × 3% = 90
+		{1000000, "79000"},    // 10000 × 10% - 210 = 790
+		{1500000, "159000"},   // 15000 × 20% - 1410 = 1590
+		{10000000, "2984000"}, // 100000 × 45% - 15160 = 29840
+	}
+	for _, tt := range tests {
+		got := calculateIncomeTaxWith(toMoney(cenToDec(tt.taxable)), toMoney(cenToDec(0)), GetTaxBrackets())
+		assertMoney(t, "calculateIncomeTaxWith", got, tt.want)
+	}
+}
+
+func TestDefaultTaxBracketsContinuous(t *testing.T) {
+	// 速算扣除数保证在各档下限处税额连续
+	brackets := DefaultTaxBrackets()
+	for i := 1; i < len(brackets); i++ {
+		at := moneyToDec(brackets[i].Threshold)
+		lower := at.Mul(brackets[i-1].Rate).Sub(moneyToDec(brackets[i-1].Deduction))
+		upper := at.Mul(brackets[i].Rate).Sub(moneyToDec(brackets[i].Deduction))
+		if !lower.Equal(upper) {
+			t.Errorf("bracket %d discontinuous at %s: %s vs %s", i+1, at, lower, upper)
+		}
+	}
+}
+
+func TestSetTaxBracketsValidation(t *testing.T) {
+	t.Cleanup(func() { SetTaxBrackets(DefaultTaxBrackets()) })
+
+	invalid := []string{
+		`[]`,
+		`[{"threshold": "0", "rate": "1.45", "deduction": "0"}]`,
+		`[{"threshold": "0", "rate": "0.03", "deduction": "0"}, {"threshold": "0", "rate": "0.1", "deduction": "0"}]`,
+		`not json`,
+	}
+	for _, input := range invalid {
+		if err := LoadTaxBrackets(strings.NewReader(input)); err == nil {
+			t.Errorf("LoadTaxBrackets(%s) succeeded, want error", input)
+		}
+	}
+	if len(GetTaxBrackets()) != 7 {
+		t.Fatalf("invalid tables must not replace the active table")
+	}
+
+	flat := `[{"threshold": "0", "rate": "0.1", "deduction": "0"}]`
+	if err := LoadTaxBrackets(strings.NewReader(flat)); err != nil {
+		t.Fatal(err)
+	}
+	// (6000 - 5000) × 10% = 100元
+	assertMoney(t, "flat tax", CalculateIncomeTax(toMoney(cenToDec(600000)), SpecialDeductions{}), "10000")
+}
+
+func TestGrossUpTax(t *testing.T) {
+	// 含税所得 = 不含税所得 + 税款，按含税所得计算的税额应等于税款
+	for _, net := range []int64{200000, 1100000, 4000000} {
+		tax := grossUpTax(toMoney(cenToDec(net)), toMoney(cenToDec(0)), DefaultTaxBrackets())
+		gross := moneyToDec(tax).Add(cenToDec(net))
+		check := calculateIncomeTaxWith(toMoney(gross), toMoney(cenToDec(0)), DefaultTaxBrackets())
+		if d := moneyToDec(check).Sub(moneyToDec(tax)).Abs(); d.GreaterThan(cenToDec(1)) {
+			t.Errorf("grossUpTax(%d) = %s, tax on grossed-up income = %s", net, moneyToDec(tax), moneyToDec(check))
+		}
+	}
+}
+
+func TestCalculateIncomeTaxStandardDeduction(t *testing.T) {
+	// 月工资20000元，减除费用5000元后15000元 × 20% - 1410 = 1590元
+	assertMoney(t, "20000", CalculateIncomeTax(toMoney(cenToDec(2000000)), SpecialDeductions{}), "159000")
+	assertMoney(t, "below 5000", CalculateIncomeTax(toMoney(cenToDec(480000)), SpecialDeductions{}), "0")
+
+	// 减除费用6000元：(10000 - 6000) × 10% - 210 = 190元
+	config := TaxConfig{StandardDeduction: toMoney(cenToDec(600000))}
+	assertMoney(t, "custom", config.IncomeTax(toMoney(cenToDec(1000000)), SpecialDeductions{}, decimal.NewFromInt(1)), "19000")
+}
+
+func TestTaxConfigProRate(t *testing.T) {
+	config := TaxConfig{StandardDeduction: toMoney(cenToDec(500000)), ProRate: true}
+	// 6月16日入职，在职15天，减除费用2500元
+	period := day("2024-06-01")
+	employed := employedFraction(period, Employee{HireDate: day("2024-06-16")})
+	assertMoney(t, "pro-rated deduction", config.standardDeduction(employed), "250000")
+
+	config.ProRate = false
+	assertMoney(t, "full deduction", config.standardDeduction(employed), "500000")
+
+	// 离职当天计为在职：6月10日离职，在职10天
+	employed = employedFraction(period, Employee{TerminationDate: day("2024-06-10")})
+	if want := decimal.RequireFromString("10").Div(decimal.NewFromInt(30)); !employed.Equal(want) {
+		t.Errorf("employedFraction = %s, want %s", employed, want)
+	}
+}
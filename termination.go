@@ -0,0 +1,100 @@
+package salary
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// MonthlyWorkDays 月计薪天数，日工资 = 月工资 / 21.75
+var MonthlyWorkDays = decimal.RequireFromString("21.75")
+
+// LeavePayoutMultiplier 离职时未休年假额外支付的日工资倍数
+// 未休年假工资报酬为日工资的300%，其中100%已随正常出勤发放，离职结算时额外支付200%
+var LeavePayoutMultiplier = decimal.NewFromInt(2)
+
+// OutstandingLoan 离职时尚未结清的员工借款
+type OutstandingLoan struct {
+	Description string `json:"description"` // 借款说明
+	Balance     Money  `json:"balance"`     // 未结清余额（分）
+}
+
+// TerminationInput 离职结算输入
+type TerminationInput struct {
+	FinalPay           EmployeeResult    // 最后一期工资计算结果
+	TerminationDate    time.Time         // 离职日期
+	Severance          Money             // 经济补偿金（分）
+	SeveranceBasis     string            // 经济补偿金计算说明
+	UnusedLeaveDays    decimal.Decimal   // 未休年假天数
+	MonthlySalary      Money             // 计算日工资的月工资（分）
+	OutstandingLoans   []OutstandingLoan // 未结清借款
+	LastInsuranceMonth time.Time         // 社保公积金最后缴纳月份，零值表示离职当月
+}
+
+// TerminationBundle 离职结算资料包，作为一份文件归入离职档案
+type TerminationBundle struct {
+	Employee           Employee          `json:"employee"`             // 员工档案
+	TerminationDate    time.Time         `json:"termination_date"`     // 离职日期
+	FinalPay           EmployeeResult    `json:"final_pay"`            // 最后一期工资明细
+	Severance          Money             `json:"severance"`            // 经济补偿金
+	SeveranceBasis     string            `json:"severance_basis"`      // 经济补偿金计算说明
+	UnusedLeaveDays    decimal.Decimal   `json:"unused_leave_days"`    // 折算的未休年假天数（整天）
+	LeavePayout        Money             `json:"leave_payout"`         // 未休年假工资报酬
+	OutstandingLoans   []OutstandingLoan `json:"outstanding_loans"`    // 未结清借款
+	LoanTotal          Money             `json:"loan_total"`           // 借款合计
+	LastInsuranceMonth string            `json:"last_insurance_month"` // 社保公积金最后缴纳月份
+	NetSettlement      Money             `json:"net_settlement"`       // 结算净额 = 实发工资 + 补偿金 + 年假报酬 - 借款
+}
+
+// CalculateLeavePayout 计算离职时未休年假工资报酬
+// 不足1整天的部分不支付，报酬 = 月工资 / 21.75 × 整天数 × 额外倍数
+func CalculateLeavePayout(monthlySalary Money, unusedDays decimal.Decimal) Money {
+	days := unusedDays.Floor()
+	if !days.IsPositive() {
+		return toMoney(decimal.Zero)
+	}
+	daily := moneyToDec(monthlySalary).Div(MonthlyWorkDays)
+	return toMoney(daily.Mul(days).Mul(LeavePayoutMultiplier).Round(2))
+}
+
+// BuildTerminationBundle 汇总离职结算所需的全部数据
+func BuildTerminationBundle(input TerminationInput) TerminationBundle {
+	lastInsurance := input.LastInsuranceMonth
+	if lastInsurance.IsZero() {
+		lastInsurance = input.TerminationDate
+	}
+
+	loans := decimal.Zero
+	for _, loan := range input.OutstandingLoans {
+		loans = loans.Add(moneyToDec(loan.Balance))
+	}
+
+	leavePayout := CalculateLeavePayout(input.MonthlySalary, input.UnusedLeaveDays)
+	net := moneyToDec(input.FinalPay.NetSalary).
+		Add(moneyToDec(input.Severance)).
+		Add(moneyToDec(leavePayout)).
+		Sub(loans)
+
+	return TerminationBundle{
+		Employee:           input.FinalPay.Employee,
+		TerminationDate:    input.TerminationDate,
+		FinalPay:           input.FinalPay,
+		Severance:          input.Severance,
+		SeveranceBasis:     input.SeveranceBasis,
+		UnusedLeaveDays:    input.UnusedLeaveDays.Floor(),
+		LeavePayout:        leavePayout,
+		OutstandingLoans:   input.OutstandingLoans,
+		LoanTotal:          toMoney(loans),
+		LastInsuranceMonth: lastInsurance.Format("2006-01"),
+		NetSettlement:      toMoney(net),
+	}
+}
+
+// ExportTerminationBundle 将离职结算资料包导出为JSON文档
+func ExportTerminationBundle(w io.Writer, bundle TerminationBundle) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(bundle)
+}
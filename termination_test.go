@@ -0,0 +1,50 @@
+package salary
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestCalculateLeavePayout(t *testing.T) {
+	// 日工资 8700 / 21.75 = 400，3.6天按3天，额外支付200%
+	assertMoney(t, "payout", CalculateLeavePayout(toMoney(cenToDec(870000)), decimal.RequireFromString("3.6")), "240000")
+	assertMoney(t, "under one day", CalculateLeavePayout(toMoney(cenToDec(870000)), decimal.RequireFromString("0.5")), "0")
+}
+
+func TestBuildTerminationBundle(t *testing.T) {
+	bundle := BuildTerminationBundle(TerminationInput{
+		FinalPay:        EmployeeResult{Employee: Employee{ID: "E1", Name: "张三"}, NetSalary: toMoney(cenToDec(600000))},
+		TerminationDate: day("2024-05-20"),
+		Severance:       toMoney(cenToDec(1600000)),
+		SeveranceBasis:  "工作2年，支付2个月工资",
+		UnusedLeaveDays: decimal.RequireFromString("3.6"),
+		MonthlySalary:   toMoney(cenToDec(870000)),
+		OutstandingLoans: []OutstandingLoan{
+			{Description: "备用金", Balance: toMoney(cenToDec(60000))},
+			{Description: "购房借款", Balance: toMoney(cenToDec(40000))},
+		},
+	})
+	assertMoney(t, "leave payout", bundle.LeavePayout, "240000")
+	assertMoney(t, "loans", bundle.LoanTotal, "100000")
+	// 6000 + 16000 + 2400 - 1000
+	assertMoney(t, "net settlement", bundle.NetSettlement, "2340000")
+	if bundle.LastInsuranceMonth != "2024-05" || !bundle.UnusedLeaveDays.Equal(decimal.NewFromInt(3)) || bundle.Employee.ID != "E1" {
+		t.Errorf("bundle = %+v", bundle)
+	}
+
+	var buf bytes.Buffer
+	if err := ExportTerminationBundle(&buf, bundle); err != nil {
+		t.Fatal(err)
+	}
+	var decoded TerminationBundle
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.SeveranceBasis != bundle.SeveranceBasis || len(decoded.OutstandingLoans) != 2 {
+		t.Errorf("decoded = %+v", decoded)
+	}
+	assertMoney(t, "decoded net", decoded.NetSettlement, "2340000")
+}
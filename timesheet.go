@@ -0,0 +1,176 @@
+package salary
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// UnallocatedProject 没有工时记录的人工成本归入的项目代码
+const UnallocatedProject = "UNALLOCATED"
+
+// TimesheetEntry 工时记录：员工在某薪资期投入某项目的小时数
+type TimesheetEntry struct {
+	EmployeeID string    // 工号
+	Period     time.Time // 薪资期
+	Project    string    // 项目代码
+	Hours      Hours     // 投入小时数
+}
+
+// ProjectAllocation 员工某一薪资期分摊到某项目的人工成本
+type ProjectAllocation struct {
+	Project    string    // 项目代码
+	EmployeeID string    // 工号
+	Department string    // 部门
+	Period     time.Time // 薪资期
+	Hours      Hours     // 投入小时数
+	Cost       Money     // 分摊的全口径人工成本（分）
+}
+
+// AllocateLaborCost 按工时记录将员工每月的全口径人工成本分摊到项目
+// 分摊比例 = 项目工时 / 员工当月工时合计，分摊金额四舍五入到分，尾差计入工时最多的项目，保证合计等于人工成本；
+// 当月没有工时记录的员工全部成本计入 UnallocatedProject
+// history: 历史发薪批次
+// timesheets: 工时记录
+// opts: 人工成本口径
+// 返回值: 分摊明细，按发薪批次、员工、项目代码顺序排列
+func AllocateLaborCost(history []PayrollResult, timesheets []TimesheetEntry, opts CostOptions) []ProjectAllocation {
+	// 按员工和月份汇总各项目工时
+	type key struct {
+		employee string
+		period   string
+	}
+	hours := make(map[key]map[string]decimal.Decimal)
+	for _, t := range timesheets {
+		k := key{t.EmployeeID, t.Period.Format("200601")}
+		if hours[k] == nil {
+			hours[k] = make(map[string]decimal.Decimal)
+		}
+		hours[k][t.Project] = hours[k][t.Project].Add(hoursToDec(t.Hours))
+	}
+
+	var allocations []ProjectAllocation
+	for _, run := range history {
+		for _, r := range run.Employees {
+			if r.Status != PeriodActive {
+				continue
+			}
+			cost := opts.loadedCost(r).Round(2)
+			projects := hours[key{r.Employee.ID, r.Period.Format("200601")}]
+
+			total := decimal.Zero
+			for _, h := range projects {
+				total = total.Add(h)
+			}
+			if !total.IsPositive() {
+				projects = map[string]decimal.Decimal{UnallocatedProject: decimal.Zero}
+			}
+
+			names := make([]string, 0, len(projects))
+			for name := range projects {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			rows := make([]ProjectAllocation, len(names))
+			largest := 0
+			allocated := decimal.Zero
+			for i, name := range names {
+				share := cost
+				if total.IsPositive() {
+					share = cost.Mul(projects[name]).Div(total).Round(2)
+				}
+				allocated = allocated.Add(share)
+				if projects[name].GreaterThan(projects[names[largest]]) {
+					largest = i
+				}
+				rows[i] = ProjectAllocation{
+					Project:    name,
+					EmployeeID: r.Employee.ID,
+					Department: r.Employee.Department,
+					Period:     r.Period,
+					Hours:      Hours(projects[name]),
+					Cost:       toMoney(share),
+				}
+			}
+			rows[largest].Cost = toMoney(moneyToDec(rows[largest].Cost).Add(cost.Sub(allocated)))
+			allocations = append(allocations, rows...)
+		}
+	}
+	return allocations
+}
+
+// ProjectCostRow 项目人工成本报表的一行
+type ProjectCostRow struct {
+	Project   string // 项目代码
+	Period    string // 薪资期，如 2024-05
+	Headcount int    // 投入人数
+	Hours     Hours  // 投入小时数合计
+	Cost      Money  // 人工成本合计（分）
+}
+
+// ProjectCostReport 按项目和薪资期汇总分摊明细
+// 返回值: 按项目代码、薪资期排序的报表
+func ProjectCostReport(allocations []ProjectAllocation) []ProjectCostRow {
+	type bucket struct {
+		employees   map[string]bool
+		hours, cost decimal.Decimal
+	}
+	buckets := make(map[[2]string]*bucket)
+	for _, a := range allocations {
+		k := [2]string{a.Project, a.Period.Format("2006-01")}
+		b, ok := buckets[k]
+		if !ok {
+			b = &bucket{employees: make(map[string]bool)}
+			buckets[k] = b
+		}
+		b.employees[a.EmployeeID] = true
+		b.hours = b.hours.Add(hoursToDec(a.Hours))
+		b.cost = b.cost.Add(moneyToDec(a.Cost))
+	}
+
+	rows := make([]ProjectCostRow, 0, len(buckets))
+	for k, b := range buckets {
+		rows = append(rows, ProjectCostRow{
+			Project:   k[0],
+			Period:    k[1],
+			Headcount: len(b.employees),
+			Hours:     Hours(b.hours),
+			Cost:      toMoney(b.cost),
+		})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Project != rows[j].Project {
+			return rows[i].Project < rows[j].Project
+		}
+		return rows[i].Period < rows[j].Period
+	})
+	return rows
+}
+
+// WriteProjectCostCSV 将项目人工成本报表导出为CSV
+func WriteProjectCostCSV(w io.Writer, rows []ProjectCostRow) error {
+	cw := csv.NewWriter(w)
+	header := []string{"项目", "薪资期", "投入人数", "投入工时", "人工成本"}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := []string{
+			row.Project,
+			row.Period,
+			strconv.Itoa(row.Headcount),
+			hoursToDec(row.Hours).String(),
+			FormatMoneyCenToYuan(row.Cost),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
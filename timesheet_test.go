@@ -0,0 +1,62 @@
+package salary
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestAllocateLaborCost(t *testing.T) {
+	loaded := func(id, dept string, cents int64) EmployeeResult {
+		return EmployeeResult{
+			Employee: Employee{ID: id, Department: dept}, Period: day("2024-05-01"), Status: PeriodActive,
+			EmployerCost: &EmployerCost{LoadedCost: toMoney(cenToDec(cents))},
+		}
+	}
+	history := []PayrollResult{{Period: day("2024-05-01"), Employees: []EmployeeResult{
+		loaded("E1", "研发部", 1000000),
+		loaded("E2", "行政部", 500000),
+		{Employee: Employee{ID: "E3"}, Period: day("2024-05-01"), Status: PeriodInactive},
+	}}}
+	timesheets := []TimesheetEntry{
+		{EmployeeID: "E1", Period: day("2024-05-01"), Project: "B", Hours: hours("10")},
+		{EmployeeID: "E1", Period: day("2024-05-01"), Project: "A", Hours: hours("6")},
+		{EmployeeID: "E1", Period: day("2024-05-01"), Project: "A", Hours: hours("4")},
+		{EmployeeID: "E1", Period: day("2024-05-01"), Project: "C", Hours: hours("10")},
+		{EmployeeID: "E1", Period: day("2024-04-01"), Project: "A", Hours: hours("100")},
+	}
+	allocations := AllocateLaborCost(history, timesheets, CostOptions{})
+
+	if len(allocations) != 4 {
+		t.Fatalf("allocations = %+v", allocations)
+	}
+	// 三个项目各10小时，每项 3333.3333 元，尾差计入第一个工时最多的项目
+	if allocations[0].Project != "A" || !hoursToDec(allocations[0].Hours).Equal(decimal.NewFromInt(10)) || allocations[0].Department != "研发部" {
+		t.Errorf("A = %+v", allocations[0])
+	}
+	assertMoney(t, "A", allocations[0].Cost, "333333.34")
+	assertMoney(t, "B", allocations[1].Cost, "333333.33")
+	assertMoney(t, "C", allocations[2].Cost, "333333.33")
+	// 没有工时记录的员工计入未分摊
+	if allocations[3].Project != UnallocatedProject || allocations[3].EmployeeID != "E2" {
+		t.Errorf("unallocated = %+v", allocations[3])
+	}
+	assertMoney(t, "unallocated", allocations[3].Cost, "500000")
+
+	rows := ProjectCostReport(append(allocations, ProjectAllocation{
+		Project: "A", EmployeeID: "E4", Period: day("2024-05-01"), Hours: hours("5"), Cost: toMoney(cenToDec(100000)),
+	}))
+	if len(rows) != 4 || rows[0].Project != "A" || rows[0].Period != "2024-05" || rows[0].Headcount != 2 {
+		t.Fatalf("rows = %+v", rows)
+	}
+	assertMoney(t, "A total", rows[0].Cost, "433333.34")
+
+	var b strings.Builder
+	if err := WriteProjectCostCSV(&b, rows); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(b.String()), "\n"); len(lines) != 5 || !strings.HasPrefix(lines[1], "A,2024-05,2,15,") {
+		t.Errorf("csv = %q", lines)
+	}
+}
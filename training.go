@@ -0,0 +1,131 @@
+package salary
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// DefaultClawBackWageShare 离职当月从工资中扣除培训费用的上限比例，参照扣除赔偿损失每月不超过当月工资20%的规定
+var DefaultClawBackWageShare = decimal.RequireFromString("0.2")
+
+// TrainingAgreement 专项培训服务期协议
+// 员工在服务期内离职的，按未履行的服务期比例返还培训费用，返还额不超过培训费用按服务期分摊后未履行部分
+type TrainingAgreement struct {
+	ID            string    `json:"id"`             // 协议编号
+	EmployeeID    string    `json:"employee_id"`    // 工号
+	Description   string    `json:"description"`    // 培训内容
+	Cost          Money     `json:"cost"`           // 公司支付的专项培训费用（分），不含培训期间的工资
+	ServiceStart  time.Time `json:"service_start"`  // 服务期起始日期
+	ServiceMonths int       `json:"service_months"` // 约定服务期月数
+}
+
+// Validate 校验培训费用为正数、服务期月数为正
+func (a TrainingAgreement) Validate() error {
+	if !moneyToDec(a.Cost).IsPositive() {
+		return errors.New("培训费用应大于0")
+	}
+	if a.ServiceMonths <= 0 || a.ServiceStart.IsZero() {
+		return errors.New("须指定服务期起始日期和月数")
+	}
+	return nil
+}
+
+// ServedMonths 截至某日已履行的服务期整月数，不超过约定月数
+func (a TrainingAgreement) ServedMonths(asOf time.Time) int {
+	served := monthsBetween(a.ServiceStart, asOf)
+	return max(0, min(served, a.ServiceMonths))
+}
+
+// Remaining 截至某日尚未摊销的培训费用 = 培训费用 × 未履行月数 ÷ 约定月数
+func (a TrainingAgreement) Remaining(asOf time.Time) Money {
+	if a.ServiceMonths <= 0 {
+		return toMoney(decimal.Zero)
+	}
+	left := decimal.NewFromInt(int64(a.ServiceMonths - a.ServedMonths(asOf)))
+	return toMoney(moneyToDec(a.Cost).Mul(left).Div(decimal.NewFromInt(int64(a.ServiceMonths))).Round(2))
+}
+
+// TrainingAmortization 培训费用摊销表的一行
+type TrainingAmortization struct {
+	Month     time.Time // 服务期满的月份
+	Amortized Money     // 本月摊销额（分）
+	Remaining Money     // 本月后尚未摊销的金额（分）
+}
+
+// Schedule 按月生成培训费用摊销表，尾差计入最后一个月
+func (a TrainingAgreement) Schedule() []TrainingAmortization {
+	schedule := make([]TrainingAmortization, 0, max(a.ServiceMonths, 0))
+	previous := a.Cost
+	for n := 1; n <= a.ServiceMonths; n++ {
+		asOf := a.ServiceStart.AddDate(0, n, 0)
+		remaining := a.Remaining(asOf)
+		schedule = append(schedule, TrainingAmortization{
+			Month:     monthStart(asOf),
+			Amortized: toMoney(moneyToDec(previous).Sub(moneyToDec(remaining))),
+			Remaining: remaining,
+		})
+		previous = remaining
+	}
+	return schedule
+}
+
+// ClawBackInput 离职时计算培训费用返还的输入
+type ClawBackInput struct {
+	TerminationDate   time.Time       // 离职日期
+	FinalWage         Money           // 离职当月应发工资（分），扣除上限的计算基数
+	FinalNet          Money           // 离职当月扣除培训费用前的实发工资（分）
+	MinimumWage       Money           // 当地月最低工资标准（分），扣除后实发不得低于该标准；0表示不检查
+	WageShare         decimal.Decimal // 当月工资中可扣除的上限比例，零值使用 DefaultClawBackWageShare
+	EmployerInitiated bool            // 公司解除或因公司过错由员工解除劳动合同，不要求返还
+}
+
+// TrainingClawBack 离职时的培训费用返还结果（分）
+type TrainingClawBack struct {
+	AgreementID  string // 协议编号
+	Owed         Money  // 应返还金额，即未摊销的培训费用
+	Deducted     Money  // 本次从离职工资中扣除的金额
+	Outstanding  Money  // 超出扣除上限、需另行追偿的金额
+	ServedMonths int    // 已履行服务期月数
+	Waived       bool   // 因公司解除等原因免于返还
+}
+
+// ClawBack 计算员工离职时应返还的培训费用和可从离职工资中扣除的金额
+// 扣除额不超过当月应发工资的上限比例，且扣除后实发工资不低于当地最低工资标准，其余部分作为待追偿金额
+func (a TrainingAgreement) ClawBack(input ClawBackInput) TrainingClawBack {
+	zero := toMoney(decimal.Zero)
+	result := TrainingClawBack{AgreementID: a.ID, ServedMonths: a.ServedMonths(input.TerminationDate), Owed: zero, Deducted: zero, Outstanding: zero}
+	if input.EmployerInitiated {
+		result.Waived = true
+		return result
+	}
+	owed := moneyToDec(a.Remaining(input.TerminationDate))
+	result.Owed = toMoney(owed)
+
+	share := input.WageShare
+	if share.IsZero() {
+		share = DefaultClawBackWageShare
+	}
+	limit := moneyToDec(input.FinalWage).Mul(share).Round(2)
+	if !moneyToDec(input.MinimumWage).IsZero() {
+		limit = decimal.Min(limit, moneyToDec(input.FinalNet).Sub(moneyToDec(input.MinimumWage)))
+	}
+	deducted := decimal.Max(decimal.Min(owed, limit), decimal.Zero)
+	result.Deducted = toMoney(deducted)
+	result.Outstanding = toMoney(owed.Sub(deducted))
+	return result
+}
+
+// Adjustment 生成从离职工资中扣除培训费用的税后扣款
+func (c TrainingClawBack) Adjustment(id, approver string) Adjustment {
+	return Adjustment{
+		ID:         id,
+		Kind:       KindDeduction,
+		Amount:     c.Deducted,
+		ReasonCode: ReasonTraining,
+		Reason:     fmt.Sprintf("培训服务期协议%s未满，已履行%d个月", c.AgreementID, c.ServedMonths),
+		Approver:   approver,
+	}
+}
@@ -0,0 +1,42 @@
+package salary
+
+import "testing"
+
+func TestTrainingClawBack(t *testing.T) {
+	agreement := TrainingAgreement{ID: "TR-01", EmployeeID: "E001", Cost: toMoney(cenToDec(2400000)), ServiceStart: day("2024-01-01"), ServiceMonths: 24}
+	if err := agreement.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	// 服务满9个月，未履行15个月：24000 × 15 / 24 = 15000元
+	assertMoney(t, "remaining", agreement.Remaining(day("2024-10-15")), "1500000")
+
+	schedule := agreement.Schedule()
+	if len(schedule) != 24 {
+		t.Fatalf("schedule len = %d", len(schedule))
+	}
+	assertMoney(t, "monthly amortization", schedule[0].Amortized, "100000")
+	assertMoney(t, "final remaining", schedule[23].Remaining, "0")
+
+	claw := agreement.ClawBack(ClawBackInput{
+		TerminationDate: day("2024-10-15"),
+		FinalWage:       toMoney(cenToDec(1000000)),
+		FinalNet:        toMoney(cenToDec(850000)),
+		MinimumWage:     toMoney(cenToDec(269000)),
+	})
+	// 当月应发10000元的20%为2000元，其余13000元另行追偿
+	assertMoney(t, "deducted", claw.Deducted, "200000")
+	assertMoney(t, "outstanding", claw.Outstanding, "1300000")
+	if adj := claw.Adjustment("ADJ-1", "hr"); adj.Kind != KindDeduction || adj.ReasonCode != ReasonTraining {
+		t.Errorf("adjustment = %+v", adj)
+	}
+
+	// 扣除后实发不得低于最低工资
+	low := agreement.ClawBack(ClawBackInput{TerminationDate: day("2024-10-15"), FinalWage: toMoney(cenToDec(300000)), FinalNet: toMoney(cenToDec(280000)), MinimumWage: toMoney(cenToDec(269000))})
+	assertMoney(t, "deducted at minimum wage", low.Deducted, "11000")
+
+	waived := agreement.ClawBack(ClawBackInput{TerminationDate: day("2024-10-15"), EmployerInitiated: true})
+	if !waived.Waived || !moneyToDec(waived.Owed).IsZero() {
+		t.Errorf("employer-initiated termination should waive claw-back: %+v", waived)
+	}
+	assertMoney(t, "after service period", agreement.Remaining(day("2026-01-01")), "0")
+}
@@ -0,0 +1,297 @@
+package salary
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ReconcileConfig 与原薪资供应商并行核对的配置：列映射、金额单位和容差
+type ReconcileConfig struct {
+	Columns           map[string]string // 字段到供应商列名的映射，字段为 employee_id、name、department、period 及各金额字段
+	PeriodFormat      string            // 薪资期列的日期格式，默认 2006-01
+	Period            time.Time         // 供应商文件没有薪资期列时使用的薪资期
+	AmountInFen       bool              // 供应商金额以分为单位，默认为元
+	IgnoreSubFen      bool              // 比对前双方金额四舍五入到分，忽略不足一分的差异
+	Tolerance         Money             // 允许的差额（分），不超过该值视为一致
+	MaterialThreshold Money             // 重大差异阈值（分），任一字段差额超过该值的员工列为重大差异
+}
+
+// reconcileColumns 供应商文件可映射的字段
+func reconcileColumns() []string {
+	columns := []string{"employee_id", "name", "department", "period"}
+	for _, f := range resultDiffFields {
+		columns = append(columns, f.key)
+	}
+	return columns
+}
+
+// rawReconcileConfig 核对配置文件的原始内容，金额以元填写
+type rawReconcileConfig struct {
+	Columns           map[string]string `json:"columns" yaml:"columns"`
+	PeriodFormat      string            `json:"period_format" yaml:"period_format"`
+	Period            string            `json:"period" yaml:"period"`
+	AmountUnit        string            `json:"amount_unit" yaml:"amount_unit"`
+	IgnoreSubFen      bool              `json:"ignore_sub_fen" yaml:"ignore_sub_fen"`
+	Tolerance         configValue       `json:"tolerance" yaml:"tolerance"`
+	MaterialThreshold configValue       `json:"material_threshold" yaml:"material_threshold"`
+}
+
+// LoadReconcileConfig 读取JSON或YAML格式的核对配置（按扩展名识别）
+// 示例：
+//
+//	columns: {employee_id: 员工编号, name: 姓名, net_salary: 实发合计, income_tax: 代扣个税}
+//	period: 2024-05
+//	amount_unit: yuan        # yuan 或 fen
+//	ignore_sub_fen: true
+//	tolerance: "0.01"        # 元
+//	material_threshold: "10" # 元
+func LoadReconcileConfig(path string) (ReconcileConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ReconcileConfig{}, err
+	}
+	var raw rawReconcileConfig
+	if err := decodeConfigData(data, strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), "."), &raw); err != nil {
+		return ReconcileConfig{}, fmt.Errorf("核对配置 %s: %w", path, err)
+	}
+	config, err := raw.build()
+	if err != nil {
+		return ReconcileConfig{}, fmt.Errorf("核对配置 %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// build 校验并转换核对配置
+func (raw rawReconcileConfig) build() (ReconcileConfig, error) {
+	var p configParser
+	config := ReconcileConfig{
+		Columns:           raw.Columns,
+		PeriodFormat:      raw.PeriodFormat,
+		IgnoreSubFen:      raw.IgnoreSubFen,
+		Tolerance:         p.money("tolerance", raw.Tolerance),
+		MaterialThreshold: p.money("material_threshold", raw.MaterialThreshold),
+	}
+	switch raw.AmountUnit {
+	case "", "yuan":
+	case "fen":
+		config.AmountInFen = true
+	default:
+		p.errs = append(p.errs, fmt.Errorf("amount_unit: 应为 yuan 或 fen，实际为 %q", raw.AmountUnit))
+	}
+	if raw.Period != "" {
+		period, err := time.ParseInLocation("2006-01", raw.Period, time.Local)
+		if err != nil {
+			p.errs = append(p.errs, fmt.Errorf("period: 格式应为 YYYY-MM: %w", err))
+		}
+		config.Period = period
+	}
+	if err := config.validate(); err != nil {
+		p.errs = append(p.errs, err)
+	}
+	return config, errors.Join(p.errs...)
+}
+
+// validate 检查列映射：工号必须映射，字段名须为可映射的字段，没有薪资期列时须指定薪资期
+func (c ReconcileConfig) validate() error {
+	var errs []error
+	known := reconcileColumns()
+	for field := range c.Columns {
+		if !slices.Contains(known, field) {
+			errs = append(errs, fmt.Errorf("columns.%s: 未知字段，可映射的字段为 %s", field, strings.Join(known, "、")))
+		}
+	}
+	if c.Columns["employee_id"] == "" {
+		errs = append(errs, errors.New("columns.employee_id: 缺少工号列"))
+	}
+	if c.Columns["period"] == "" && c.Period.IsZero() {
+		errs = append(errs, errors.New("供应商文件没有薪资期列时须指定 period"))
+	}
+	return errors.Join(errs...)
+}
+
+// parseVendorAmount 解析供应商文件中的金额，写法与 ParseMoney 相同，但允许负数和超过两位的小数，空值按0处理
+func (c ReconcileConfig) parseVendorAmount(s string) (Money, error) {
+	if strings.TrimSpace(s) == "" {
+		return toMoney(decimal.Zero), nil
+	}
+	d, err := parseAmount(s)
+	if err != nil {
+		return toMoney(decimal.Zero), err
+	}
+	if !c.AmountInFen {
+		d = d.Mul(decimal.NewFromInt(100))
+	}
+	return toMoney(d), nil
+}
+
+// ImportVendorCSV 按列映射读取原薪资供应商导出的CSV，转换为工资结果导出记录
+// 未映射的金额字段为0，比对时会显示为差异；解析错误会列出行号和列名
+func ImportVendorCSV(r io.Reader, config ReconcileConfig) ([]ExportRecord, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("读取表头: %w", err)
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+	columns := make(map[string]int, len(config.Columns))
+	for field, name := range config.Columns {
+		i, ok := index[name]
+		if !ok {
+			return nil, fmt.Errorf("供应商文件缺少列 %q（映射到 %s）", name, field)
+		}
+		columns[field] = i
+	}
+	format := config.PeriodFormat
+	if format == "" {
+		format = "2006-01"
+	}
+
+	var records []ExportRecord
+	var errs []error
+	for line := 2; ; line++ {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		cell := func(field string) string {
+			if i, ok := columns[field]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+		record := ExportRecord{EmployeeID: cell("employee_id"), Name: cell("name"), Department: cell("department"), Period: monthStart(config.Period)}
+		if record.EmployeeID == "" {
+			continue
+		}
+		if v := cell("period"); v != "" {
+			period, err := time.ParseInLocation(format, v, time.Local)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("第%d行 %s: 薪资期格式应为 %s", line, config.Columns["period"], format))
+				continue
+			}
+			record.Period = monthStart(period)
+		}
+		for _, f := range resultDiffFields {
+			if _, ok := columns[f.key]; !ok {
+				continue
+			}
+			amount, err := config.parseVendorAmount(cell(f.key))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("第%d行 %s: %w", line, config.Columns[f.key], err))
+				continue
+			}
+			f.set(&record, amount)
+		}
+		records = append(records, record)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return records, nil
+}
+
+// ReconciliationScorecard 并行核对记分卡，用于迁移签核
+type ReconciliationScorecard struct {
+	Compared        int             `json:"compared"`          // 双方都有的员工薪资期数
+	Matched         int             `json:"matched"`           // 全部字段在容差内一致的数量
+	MatchRate       decimal.Decimal `json:"match_rate"`        // 一致率（%），保留两位小数
+	Material        []EmployeeDiff  `json:"material"`          // 重大差异员工
+	Immaterial      int             `json:"immaterial"`        // 超出容差但未达重大差异阈值的数量
+	MissingInVendor []string        `json:"missing_in_vendor"` // 仅本系统有结果的“工号 薪资期”
+	MissingInEngine []string        `json:"missing_in_engine"` // 仅供应商有结果的“工号 薪资期”
+	Diff            ResultDiff      `json:"diff"`              // 逐员工比对明细
+}
+
+// roundToFen 金额四舍五入到分
+func roundToFen(records []ExportRecord) []ExportRecord {
+	rounded := make([]ExportRecord, len(records))
+	for i, r := range records {
+		for _, f := range resultDiffFields {
+			f.set(&r, toMoney(moneyToDec(f.value(r)).Round(0)))
+		}
+		rounded[i] = r
+	}
+	return rounded
+}
+
+// Reconcile 将本系统的计算结果与供应商结果逐员工核对并生成记分卡
+// engine: 本系统的工资结果导出
+// vendor: 按列映射读取的供应商结果
+// config: 核对配置
+func Reconcile(engine, vendor []ExportRecord, config ReconcileConfig) ReconciliationScorecard {
+	if config.IgnoreSubFen {
+		engine, vendor = roundToFen(engine), roundToFen(vendor)
+	}
+	diff := DiffResults(engine, vendor, config.Tolerance)
+	card := ReconciliationScorecard{
+		Compared:        diff.Matched,
+		Matched:         diff.Identical,
+		MatchRate:       decimal.Zero,
+		MissingInVendor: diff.OnlyInA,
+		MissingInEngine: diff.OnlyInB,
+		Diff:            diff,
+	}
+	if diff.Matched > 0 {
+		card.MatchRate = decimal.NewFromInt(int64(diff.Identical)).Mul(decimal.NewFromInt(100)).
+			Div(decimal.NewFromInt(int64(diff.Matched))).Round(2)
+	}
+	threshold := moneyToDec(config.MaterialThreshold).Abs()
+	for _, e := range diff.Changed {
+		material := false
+		for _, f := range e.Fields {
+			if moneyToDec(f.Delta).Abs().GreaterThan(threshold) {
+				material = true
+				break
+			}
+		}
+		if material {
+			card.Material = append(card.Material, e)
+		} else {
+			card.Immaterial++
+		}
+	}
+	return card
+}
+
+// SignOff 是否满足迁移签核条件：没有重大差异，双方员工一致
+func (c ReconciliationScorecard) SignOff() bool {
+	return len(c.Material) == 0 && len(c.MissingInVendor) == 0 && len(c.MissingInEngine) == 0
+}
+
+// WriteReport 输出记分卡和重大差异明细，金额单位为元
+func (c ReconciliationScorecard) WriteReport(out io.Writer) error {
+	status := "未通过"
+	if c.SignOff() {
+		status = "通过"
+	}
+	var w bytes.Buffer
+	fmt.Fprintf(&w, "核对 %d 条，一致 %d 条，一致率 %s%%\n", c.Compared, c.Matched, c.MatchRate.StringFixed(2))
+	fmt.Fprintf(&w, "重大差异 %d 条，轻微差异 %d 条，供应商缺失 %d 条，本系统缺失 %d 条\n",
+		len(c.Material), c.Immaterial, len(c.MissingInVendor), len(c.MissingInEngine))
+	fmt.Fprintf(&w, "签核：%s\n", status)
+	details := c.Diff
+	details.Changed = c.Material
+	details.writeDetails(&w, "重大差异", "供应商缺失", "本系统缺失")
+	_, err := out.Write(w.Bytes())
+	return err
+}
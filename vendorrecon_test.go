@@ -0,0 +1,88 @@
+package salary
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestImportVendorCSVAndReconcile(t *testing.T) {
+	raw := rawReconcileConfig{
+		Columns:           map[string]string{"employee_id": "员工编号", "name": "姓名", "net_salary": "实发合计", "income_tax": "代扣个税"},
+		Period:            "2024-05",
+		IgnoreSubFen:      true,
+		Tolerance:         "0.01",
+		MaterialThreshold: "10",
+	}
+	config, err := raw.build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vendor, err := ImportVendorCSV(strings.NewReader("员工编号,姓名,实发合计,代扣个税\n"+
+		"E001,张三,\"7,000.004\",48.00\n"+
+		"E002,李四,6500.02,30.00\n"+
+		"E003,王五,5000.00,0\n"+
+		"E005,赵六,4000.00,0\n"), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vendor) != 4 || !vendor[0].Period.Equal(day("2024-05-01")) {
+		t.Fatalf("vendor records = %+v", vendor)
+	}
+
+	record := func(id string, net, tax int64) ExportRecord {
+		return ExportRecord{EmployeeID: id, Period: day("2024-05-01"), NetSalary: toMoney(cenToDec(net)), IncomeTax: toMoney(cenToDec(tax))}
+	}
+	engine := []ExportRecord{
+		record("E001", 700000, 4800), // 相差不足一分
+		record("E002", 650000, 3000), // 相差2分，超出容差但不重大
+		record("E003", 480000, 0),    // 相差200元，重大差异
+		record("E004", 300000, 0),    // 供应商缺失
+	}
+	card := Reconcile(engine, vendor, config)
+	if card.Compared != 3 || card.Matched != 1 || card.Immaterial != 1 || len(card.Material) != 1 {
+		t.Fatalf("scorecard = %+v", card)
+	}
+	if card.MatchRate.String() != "33.33" || card.Material[0].EmployeeID != "E003" {
+		t.Errorf("match rate %s, material %s", card.MatchRate, card.Material[0].EmployeeID)
+	}
+	if card.SignOff() || len(card.MissingInVendor) != 1 || len(card.MissingInEngine) != 1 {
+		t.Errorf("missing in vendor %v, in engine %v", card.MissingInVendor, card.MissingInEngine)
+	}
+}
+
+func TestReconcileConfigValidation(t *testing.T) {
+	_, err := rawReconcileConfig{Columns: map[string]string{"bonus": "奖金"}, AmountUnit: "usd"}.build()
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, want := range []string{"columns.bonus", "columns.employee_id", "amount_unit", "period"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error missing %s: %v", want, err)
+		}
+	}
+
+	config := ReconcileConfig{Columns: map[string]string{"employee_id": "工号", "period": "期间", "net_salary": "实发"}}
+	_, err = ImportVendorCSV(strings.NewReader("工号,期间,实发\nE001,2024-05,abc\n"), config)
+	if !errors.Is(err, ErrInvalidMoney) || !strings.Contains(err.Error(), "第2行") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestParseVendorAmount(t *testing.T) {
+	yuan := ReconcileConfig{}
+	got, err := yuan.parseVendorAmount("-1,234.567元")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertMoney(t, "negative sub-cent vendor amount", got, "-123456.7")
+	if got, err := (ReconcileConfig{AmountInFen: true}).parseVendorAmount("￥800,000"); err != nil || !moneyToDec(got).Equal(cenToDec(800000)) {
+		t.Errorf("fen amount = %s, %v", moneyToDec(got), err)
+	}
+	if got, err := yuan.parseVendorAmount(" "); err != nil || !moneyToDec(got).IsZero() {
+		t.Errorf("blank amount = %s, %v", moneyToDec(got), err)
+	}
+	if _, err := yuan.parseVendorAmount("N/A"); !errors.Is(err, ErrInvalidMoney) {
+		t.Errorf("err = %v, want ErrInvalidMoney", err)
+	}
+}
@@ -0,0 +1,87 @@
+package salary
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// EngineVersion 当前计算引擎版本，计算口径发生变化时递增并在 engineChangelog 中登记
+const EngineVersion = "1.4.0"
+
+// BuiltinRulesVersion 未加载外部规则包时使用的内置规则版本
+const BuiltinRulesVersion = "builtin"
+
+// EngineChange 计算引擎的一条口径变更
+type EngineChange struct {
+	Version     string // 引入变更的引擎版本
+	Description string // 变更说明
+}
+
+// engineChangelog 计算引擎口径变更记录，按版本升序排列
+var engineChangelog = []EngineChange{
+	{Version: "1.0.0", Description: "初始版本：基础工资、加班工资、社保公积金及单月个税计算"},
+	{Version: "1.1.0", Description: "全月标准工时为0或本期未在岗时输出零收入结果，不再发生除零"},
+	{Version: "1.1.0", Description: "周期性工资项目和一次性调整计入税前工资，计税项目计入应纳税所得额"},
+	{Version: "1.1.0", Description: "停薪期间公司代缴的个人社保公积金记为员工欠款，复岗后从实发工资中抵扣"},
+	{Version: "1.2.0", Description: "个人住房公积金超过缴存基数12%（或当地封顶基数12%）的部分并入应纳税所得额"},
+	{Version: "1.3.0", Description: "内置个税税率表更正为七级超额累进税率表（按月换算），税额 = 应纳税所得额 × 税率 - 速算扣除数"},
+	{Version: "1.4.0", Description: "单月计税先减除每月减除费用（默认5000元，可配置，可按入职、离职当月在职天数折算）"},
+}
+
+// parseVersion 解析 主版本.次版本.修订号 格式的版本号
+func parseVersion(v string) ([3]int, error) {
+	var parsed [3]int
+	parts := strings.Split(v, ".")
+	if len(parts) != 3 {
+		return parsed, fmt.Errorf("无效的引擎版本号 %q", v)
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return parsed, fmt.Errorf("无效的引擎版本号 %q", v)
+		}
+		parsed[i] = n
+	}
+	return parsed, nil
+}
+
+// compareVersions 比较两个版本号，a<b 返回-1，相等返回0，a>b 返回1
+func compareVersions(a, b [3]int) int {
+	for i := range a {
+		switch {
+		case a[i] < b[i]:
+			return -1
+		case a[i] > b[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+// CompatibilityReport 列出两个引擎版本之间的口径变更，用于解释重算历史批次时出现的差异
+// from: 历史结果使用的引擎版本
+// to: 当前引擎版本
+// 返回值: 版本区间 (from, to] 内的全部变更
+func CompatibilityReport(from, to string) ([]EngineChange, error) {
+	fromVersion, err := parseVersion(from)
+	if err != nil {
+		return nil, err
+	}
+	toVersion, err := parseVersion(to)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []EngineChange
+	for _, change := range engineChangelog {
+		v, err := parseVersion(change.Version)
+		if err != nil {
+			return nil, err
+		}
+		if compareVersions(v, fromVersion) > 0 && compareVersions(v, toVersion) <= 0 {
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
@@ -0,0 +1,34 @@
+package salary
+
+import "testing"
+
+func TestPayrollResultVersionStamp(t *testing.T) {
+	run := PayrollRun{Period: day("2024-03-01")}
+	result := run.Calculate()
+	if result.EngineVersion != EngineVersion || result.RulesVersion != BuiltinRulesVersion {
+		t.Errorf("versions = %q/%q", result.EngineVersion, result.RulesVersion)
+	}
+
+	run.RulesVersion = "2024.03"
+	if result := run.Calculate(); result.RulesVersion != "2024.03" {
+		t.Errorf("rules version = %q", result.RulesVersion)
+	}
+}
+
+func TestCompatibilityReport(t *testing.T) {
+	changes, err := CompatibilityReport("1.1.0", "1.3.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 区间左开右闭：不含1.1.0，含1.3.0
+	if len(changes) != 2 || changes[0].Version != "1.2.0" || changes[1].Version != "1.3.0" {
+		t.Errorf("changes = %+v", changes)
+	}
+
+	if changes, err := CompatibilityReport(EngineVersion, EngineVersion); err != nil || len(changes) != 0 {
+		t.Errorf("same version: changes = %+v, err = %v", changes, err)
+	}
+	if _, err := CompatibilityReport("1.x", EngineVersion); err == nil {
+		t.Error("invalid version should fail")
+	}
+}
@@ -0,0 +1,129 @@
+package salary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WageFloor 集体合同约定的岗位类别最低工资（月标准，分）
+type WageFloor struct {
+	JobCategory string // 岗位类别，与员工档案的 JobCategory 对应
+	Monthly     Money  // 月最低工资标准（分）
+}
+
+// CollectiveAgreement 工会集体合同中的岗位工资标准
+// 未约定工资标准的岗位类别不检查
+type CollectiveAgreement struct {
+	Name          string      // 集体合同名称
+	EffectiveFrom time.Time   // 生效日期，零值表示不限
+	EffectiveTo   time.Time   // 失效日期（含当日），零值表示长期有效
+	Floors        []WageFloor // 各岗位类别的最低工资标准
+}
+
+// Validate 校验集体合同：岗位类别不能为空或重复，最低工资必须为正数
+func (a CollectiveAgreement) Validate() error {
+	seen := make(map[string]bool, len(a.Floors))
+	for _, f := range a.Floors {
+		if f.JobCategory == "" {
+			return errors.New("集体合同工资标准缺少岗位类别")
+		}
+		if seen[f.JobCategory] {
+			return fmt.Errorf("集体合同中岗位类别 %s 重复", f.JobCategory)
+		}
+		seen[f.JobCategory] = true
+		if !moneyToDec(f.Monthly).IsPositive() {
+			return fmt.Errorf("岗位类别 %s 的最低工资必须大于0", f.JobCategory)
+		}
+	}
+	if !a.EffectiveFrom.IsZero() && !a.EffectiveTo.IsZero() && a.EffectiveTo.Before(a.EffectiveFrom) {
+		return errors.New("集体合同失效日期早于生效日期")
+	}
+	return nil
+}
+
+// FloorFor 查询岗位类别在薪资期适用的最低工资标准
+// 返回值: (月最低工资, 是否约定)，合同在薪资期内未生效时视为未约定
+func (a CollectiveAgreement) FloorFor(category string, period time.Time) (Money, bool) {
+	start := monthStart(period)
+	end := start.AddDate(0, 1, 0)
+	if (!a.EffectiveFrom.IsZero() && !dateOnly(a.EffectiveFrom).Before(end)) ||
+		(!a.EffectiveTo.IsZero() && dateOnly(a.EffectiveTo).Before(start)) {
+		return Money{}, false
+	}
+	for _, f := range a.Floors {
+		if f.JobCategory == category {
+			return f.Monthly, true
+		}
+	}
+	return Money{}, false
+}
+
+// WageFloorViolation 员工本期工资低于集体合同约定的岗位最低工资
+type WageFloorViolation struct {
+	EmployeeID  string // 工号
+	Name        string // 姓名
+	JobCategory string // 岗位类别
+	Floor       Money  // 本期适用的最低工资，入职、离职当月按在职天数折算
+	Pay         Money  // 参与比较的工资：税前工资扣除加班工资
+	Shortfall   Money  // 差额
+}
+
+// String 返回便于展示的说明
+func (v WageFloorViolation) String() string {
+	return fmt.Sprintf("%s（%s）岗位类别 %s 本期工资%s，低于集体合同标准%s，差额%s",
+		v.Name, v.EmployeeID, v.JobCategory, FormatMoneyCenToYuan(v.Pay), FormatMoneyCenToYuan(v.Floor), FormatMoneyCenToYuan(v.Shortfall))
+}
+
+// Violations 检查员工计算结果是否达到集体合同约定的岗位最低工资
+// 比较口径为税前工资扣除加班工资；入职、离职当月的标准按在职天数折算；本期未在岗的员工不检查
+// a: 集体合同，为空时不检查
+// results: 员工计算结果
+// 返回值: 低于标准的员工，顺序与输入一致
+func (a *CollectiveAgreement) Violations(results []EmployeeResult) []WageFloorViolation {
+	if a == nil {
+		return nil
+	}
+	var violations []WageFloorViolation
+	for _, r := range results {
+		if r.Status == PeriodInactive || r.Employee.JobCategory == "" {
+			continue
+		}
+		floor, ok := a.FloorFor(r.Employee.JobCategory, r.Period)
+		if !ok {
+			continue
+		}
+		required := moneyToDec(floor).Mul(employedFraction(r.Period, r.Employee)).Round(2)
+		pay := moneyToDec(r.GrossSalary).Sub(moneyToDec(r.OvertimePay))
+		if !pay.LessThan(required) {
+			continue
+		}
+		violations = append(violations, WageFloorViolation{
+			EmployeeID:  r.Employee.ID,
+			Name:        r.Employee.Name,
+			JobCategory: r.Employee.JobCategory,
+			Floor:       toMoney(required),
+			Pay:         toMoney(pay),
+			Shortfall:   toMoney(required.Sub(pay)),
+		})
+	}
+	return violations
+}
+
+// WageFloorCheck 集体合同工资标准检查：本期批次中没有低于岗位最低工资的员工
+func WageFloorCheck(store *MemoryStore) CloseCheck {
+	return NewCloseCheck("wage_floors", func(ctx context.Context, period time.Time) error {
+		var ids []string
+		for _, run := range store.periodRuns(period) {
+			for _, v := range run.WageFloorViolations {
+				ids = append(ids, v.EmployeeID)
+			}
+		}
+		if len(ids) > 0 {
+			return fmt.Errorf("%d名员工工资低于集体合同标准: %s", len(ids), strings.Join(ids, ", "))
+		}
+		return nil
+	})
+}
@@ -0,0 +1,71 @@
+package salary
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestWageFloorViolations(t *testing.T) {
+	agreement := &CollectiveAgreement{
+		Name: "2024年度工资专项集体合同",
+		Floors: []WageFloor{
+			{JobCategory: "operator", Monthly: toMoney(decimal.NewFromInt(900000))},
+			{JobCategory: "clerk", Monthly: toMoney(decimal.NewFromInt(600000))},
+		},
+	}
+	if err := agreement.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	// 加班工资不计入比较口径
+	attendance := AttendanceRecord{WorkHours: hours("174"), OvertimeWeekday: hours("20")}
+	run := PayrollRun{
+		Period:     day("2024-03-01"),
+		WageFloors: agreement,
+		Inputs: []EmployeeInput{
+			{Employee: Employee{ID: "E1", Name: "张三", JobCategory: "operator"}, Config: testConfig(), Attendance: attendance},
+			{Employee: Employee{ID: "E2", Name: "李四", JobCategory: "clerk"}, Config: testConfig(), Attendance: attendance},
+			{Employee: Employee{ID: "E3", Name: "王五"}, Config: testConfig(), Attendance: attendance},
+		},
+	}
+	result := run.Calculate()
+	if len(result.WageFloorViolations) != 1 {
+		t.Fatalf("violations = %+v, want 1", result.WageFloorViolations)
+	}
+	v := result.WageFloorViolations[0]
+	if v.EmployeeID != "E1" || !moneyToDec(v.Shortfall).Round(0).Equal(decimal.NewFromInt(100000)) {
+		t.Errorf("violation = %s", v)
+	}
+
+	store := NewMemoryStore()
+	store.SaveRun(&result)
+	if err := WageFloorCheck(store).Check(context.Background(), run.Period); err == nil {
+		t.Error("expected wage floor check to fail")
+	}
+}
+
+func TestWageFloorProratedForNewHire(t *testing.T) {
+	agreement := &CollectiveAgreement{Floors: []WageFloor{{JobCategory: "operator", Monthly: toMoney(decimal.NewFromInt(900000))}}}
+	result := EmployeeResult{
+		Employee:    Employee{ID: "E1", JobCategory: "operator", HireDate: day("2024-04-16")},
+		Period:      day("2024-04-01"),
+		GrossSalary: toMoney(decimal.NewFromInt(460000)),
+		OvertimePay: toMoney(decimal.Zero),
+	}
+	if v := agreement.Violations([]EmployeeResult{result}); len(v) != 0 {
+		t.Errorf("prorated floor of 4500 should be met: %v", v)
+	}
+	result.GrossSalary = toMoney(decimal.NewFromInt(440000))
+	if v := agreement.Violations([]EmployeeResult{result}); len(v) != 1 {
+		t.Errorf("violations = %v, want 1", v)
+	}
+}
+
+func TestCollectiveAgreementRejectsDuplicateCategory(t *testing.T) {
+	floor := toMoney(decimal.NewFromInt(100))
+	agreement := CollectiveAgreement{Floors: []WageFloor{{JobCategory: "a", Monthly: floor}, {JobCategory: "a", Monthly: floor}}}
+	if err := agreement.Validate(); err == nil {
+		t.Error("duplicate category should be rejected")
+	}
+}
@@ -0,0 +1,108 @@
+package salary
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrWarningEscalated 按批次设置升级为错误的预警
+var ErrWarningEscalated = errors.New("预警已升级为错误")
+
+// MonthlyOvertimeCap 劳动法规定的每月加班上限36小时
+var MonthlyOvertimeCap = decimal.NewFromInt(36)
+
+// WarningCode 稳定的预警类别代码，供集成方按类别处理和配置升级
+type WarningCode string
+
+const (
+	WarnOvertimeCap     WarningCode = "WRN001" // 月加班时长超过法定上限
+	WarnPenaltyCapped   WarningCode = "WRN002" // 违纪扣款超过上限，超出部分未扣除
+	WarnNegativeNet     WarningCode = "WRN003" // 实发工资为负数
+	WarnPolicyFallback  WarningCode = "WRN004" // 缺少城市政策，已按默认政策计算
+	WarnResidency       WarningCode = "WRN005" // 外籍员工境内居住天数接近183天
+	WarnConfigDeviation WarningCode = "WRN006" // 薪资配置费率与城市政策不一致
+	WarnWageFloor       WarningCode = "WRN007" // 工资低于集体合同岗位最低工资
+)
+
+// Warning 不阻止批次计算的预警，如超出法定上限、数值异常、采用了默认处理
+type Warning struct {
+	Code       WarningCode // 预警类别
+	EmployeeID string      // 工号
+	Message    string      // 说明
+}
+
+// String 返回便于展示的说明
+func (w Warning) String() string {
+	return fmt.Sprintf("[%s] %s", w.Code, w.Message)
+}
+
+// warningError 升级为错误的预警
+type warningError struct {
+	warning Warning
+}
+
+// Error 实现 error 接口
+func (e *warningError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrWarningEscalated, e.warning)
+}
+
+// Unwrap 返回 ErrWarningEscalated，便于 errors.Is 判断
+func (e *warningError) Unwrap() error {
+	return ErrWarningEscalated
+}
+
+// employeeWarnings 由员工计算结果生成预警
+func employeeWarnings(r EmployeeResult) []Warning {
+	var warnings []Warning
+	overtime := hoursToDec(r.Attendance.OvertimeWeekday).Add(hoursToDec(r.Attendance.OvertimeWeekend)).Add(hoursToDec(r.Attendance.OvertimeHoliday))
+	if overtime.GreaterThan(MonthlyOvertimeCap) {
+		warnings = append(warnings, Warning{Code: WarnOvertimeCap, EmployeeID: r.Employee.ID,
+			Message: fmt.Sprintf("员工 %s 本月加班%s小时，超过法定上限%s小时", r.Employee.ID, overtime, MonthlyOvertimeCap)})
+	}
+	if moneyToDec(r.PenaltyTruncated).IsPositive() {
+		warnings = append(warnings, Warning{Code: WarnPenaltyCapped, EmployeeID: r.Employee.ID,
+			Message: fmt.Sprintf("员工 %s 违纪扣款超过上限，%s未扣除", r.Employee.ID, FormatMoneyCenToYuan(r.PenaltyTruncated))})
+	}
+	if moneyToDec(r.NetSalary).IsNegative() {
+		warnings = append(warnings, Warning{Code: WarnNegativeNet, EmployeeID: r.Employee.ID,
+			Message: fmt.Sprintf("员工 %s 实发工资为%s", r.Employee.ID, FormatMoneyCenToYuan(r.NetSalary))})
+	}
+	return warnings
+}
+
+// collectWarnings 汇总批次的全部预警，按批次设置将指定类别升级为错误
+// 升级的预警记入 Errors，员工计算结果保留供核对，批次在处理前不能关账
+func (r *PayrollRun) collectWarnings(result *PayrollResult) {
+	var warnings []Warning
+	for _, e := range result.Employees {
+		warnings = append(warnings, employeeWarnings(e)...)
+	}
+	for _, m := range result.PolicyMisses {
+		if m.Strategy == PolicyMissUseDefault {
+			warnings = append(warnings, Warning{Code: WarnPolicyFallback, EmployeeID: m.EmployeeID,
+				Message: fmt.Sprintf("员工 %s 所在城市 %s 缺少政策，已按默认政策计算", m.EmployeeID, m.City)})
+		}
+	}
+	for _, w := range result.ResidencyWarnings {
+		warnings = append(warnings, Warning{Code: WarnResidency, EmployeeID: w.EmployeeID, Message: w.String()})
+	}
+	for _, w := range result.ConfigWarnings {
+		for _, id := range w.EmployeeIDs {
+			warnings = append(warnings, Warning{Code: WarnConfigDeviation, EmployeeID: id, Message: w.String()})
+		}
+	}
+	for _, v := range result.WageFloorViolations {
+		warnings = append(warnings, Warning{Code: WarnWageFloor, EmployeeID: v.EmployeeID, Message: v.String()})
+	}
+
+	for _, w := range warnings {
+		if slices.Contains(r.EscalateWarnings, w.Code) {
+			result.Errors = append(result.Errors, EmployeeError{EmployeeID: w.EmployeeID, Err: &warningError{warning: w}})
+			continue
+		}
+		result.Warnings = append(result.Warnings, w)
+	}
+}
@@ -0,0 +1,33 @@
+package salary
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRunWarningsAndEscalation(t *testing.T) {
+	inputs := []EmployeeInput{
+		{Employee: Employee{ID: "E1"}, Config: testConfig(), Attendance: AttendanceRecord{WorkHours: hours("174"), OvertimeWeekday: hours("30"), OvertimeWeekend: hours("8")}},
+		{Employee: Employee{ID: "E2"}, Config: testConfig(), Attendance: AttendanceRecord{WorkHours: hours("174")}},
+	}
+	run := PayrollRun{Period: day("2024-03-01"), Inputs: inputs}
+	result := run.Calculate()
+	if len(result.Errors) != 0 || len(result.Warnings) != 1 {
+		t.Fatalf("errors = %v, warnings = %v", result.Errors, result.Warnings)
+	}
+	if w := result.Warnings[0]; w.Code != WarnOvertimeCap || w.EmployeeID != "E1" {
+		t.Errorf("warning = %+v", w)
+	}
+
+	run.EscalateWarnings = []WarningCode{WarnOvertimeCap}
+	result = run.Calculate()
+	if len(result.Warnings) != 0 || len(result.Errors) != 1 {
+		t.Fatalf("errors = %v, warnings = %v", result.Errors, result.Warnings)
+	}
+	if err := result.Errors[0]; err.EmployeeID != "E1" || !errors.Is(err, ErrWarningEscalated) || ErrorCodeOf(err) != CodeWarningEscalated {
+		t.Errorf("error = %v", err)
+	}
+	if len(result.Employees) != 2 {
+		t.Errorf("escalated employees should keep their results for review, got %d", len(result.Employees))
+	}
+}
@@ -0,0 +1,83 @@
+package salary
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DefaultDailyWorkMinutes 工作日标准工时8小时
+const DefaultDailyWorkMinutes = 8 * 60
+
+// WorkSegment 一段连续出勤，通常为一次上班打卡到下班打卡
+type WorkSegment struct {
+	Start time.Time // 开始时间
+	End   time.Time // 结束时间
+}
+
+// DailyAttendanceFromSegments 按节假日日历将出勤时段整理为每日考勤
+// 跨零点的时段按自然日拆分，各部分按所在日期的类型计算；工作日超过标准工时的部分计为加班，
+// 休息日和法定节假日的出勤全部记入 WorkMinutes，由 AggregateDailyAttendance 计入对应加班类别。
+// 每日的上下班打卡时间取当天最早的开始和最晚的结束，用于计算班次津贴
+// segments: 出勤时段，结束时间必须晚于开始时间且时段之间不能重叠
+// calendar: 节假日日历，为空时只区分工作日和周末
+// dailyMinutes: 工作日标准工时（分钟），0表示8小时
+// 返回值: 每日考勤，按日期排序
+func DailyAttendanceFromSegments(segments []WorkSegment, calendar *Calendar, dailyMinutes int) ([]DailyAttendance, error) {
+	if dailyMinutes <= 0 {
+		dailyMinutes = DefaultDailyWorkMinutes
+	}
+	sorted := append([]WorkSegment(nil), segments...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start.Before(sorted[j].Start) })
+	for i, s := range sorted {
+		if !s.End.After(s.Start) {
+			return nil, fmt.Errorf("出勤时段 %s 的结束时间不晚于开始时间", s.Start.Format("2006-01-02 15:04"))
+		}
+		if i > 0 && s.Start.Before(sorted[i-1].End) {
+			return nil, fmt.Errorf("出勤时段 %s 与 %s 重叠", sorted[i-1].Start.Format("2006-01-02 15:04"), s.Start.Format("2006-01-02 15:04"))
+		}
+	}
+
+	var days []DailyAttendance
+	index := make(map[string]int)
+	for _, s := range sorted {
+		for start := s.Start; start.Before(s.End); {
+			date := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+			end := date.AddDate(0, 0, 1)
+			if s.End.Before(end) {
+				end = s.End
+			}
+			i, ok := index[dateKey(date)]
+			if !ok {
+				i = len(days)
+				index[dateKey(date)] = i
+				days = append(days, DailyAttendance{Date: date, DayType: calendar.DayType(date), ClockIn: start})
+			}
+			days[i].WorkMinutes += int(end.Sub(start) / time.Minute)
+			days[i].ClockOut = end
+			start = end
+		}
+	}
+	for i := range days {
+		if d := &days[i]; d.DayType == Workday && d.WorkMinutes > dailyMinutes {
+			d.OvertimeMinutes = d.WorkMinutes - dailyMinutes
+			d.WorkMinutes = dailyMinutes
+		}
+	}
+	return days, nil
+}
+
+// ClassifyWorkSegments 按节假日日历将出勤时段自动划分为正常工时、工作日加班、休息日加班和节假日加班
+// 调休上班日按工作日计算，调休休息日按休息日计算，法定节假日按节假日计算；取整、带薪节假日和
+// 按周认定加班等规则与 AggregateDailyAttendance 相同
+// segments: 出勤时段
+// calendar: 节假日日历，为空时只区分工作日和周末
+// policy: 考勤汇总规则
+// 返回值: 月度考勤记录
+func ClassifyWorkSegments(segments []WorkSegment, calendar *Calendar, policy AttendancePolicy) (AttendanceRecord, error) {
+	days, err := DailyAttendanceFromSegments(segments, calendar, policy.StandardDailyMinutes)
+	if err != nil {
+		return AttendanceRecord{}, err
+	}
+	return AggregateDailyAttendance(days, policy), nil
+}
@@ -0,0 +1,71 @@
+package salary
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func at(s string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04", s, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestChinaCalendarDayType(t *testing.T) {
+	c := NewChinaCalendar()
+	tests := []struct {
+		date string
+		want DayType
+	}{
+		{"2024-02-04", Workday}, // 周日调休上班
+		{"2024-02-10", Holiday},
+		{"2024-02-14", RestDay}, // 春节调休休息日
+		{"2024-02-19", Workday},
+		{"2024-02-24", RestDay},
+		{"2025-01-28", Holiday}, // 除夕
+	}
+	for _, tt := range tests {
+		if got := c.DayType(day(tt.date)); got != tt.want {
+			t.Errorf("DayType(%s) = %d, want %d", tt.date, got, tt.want)
+		}
+	}
+	if err := c.LoadChinaHolidays(1999); err == nil {
+		t.Error("expected error for unsupported year")
+	}
+}
+
+func TestClassifyWorkSegments(t *testing.T) {
+	segments := []WorkSegment{
+		{Start: at("2024-02-04 09:00"), End: at("2024-02-04 19:00")}, // 调休上班日，加班2小时
+		{Start: at("2024-02-10 09:00"), End: at("2024-02-10 13:00")}, // 法定节假日
+		{Start: at("2024-02-14 10:00"), End: at("2024-02-14 12:00")}, // 调休休息日
+		{Start: at("2024-09-30 22:00"), End: at("2024-10-01 02:00")}, // 跨零点进入国庆节
+	}
+	record, err := ClassifyWorkSegments(segments, NewChinaCalendar(), AttendancePolicy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	check := func(name string, got Hours, want int64) {
+		if !hoursToDec(got).Equal(decimal.NewFromInt(want)) {
+			t.Errorf("%s = %s, want %d", name, hoursToDec(got), want)
+		}
+	}
+	check("work", record.WorkHours, 10)
+	check("weekday", record.OvertimeWeekday, 2)
+	check("weekend", record.OvertimeWeekend, 2)
+	check("holiday", record.OvertimeHoliday, 6)
+}
+
+func TestClassifyWorkSegmentsRejectsOverlap(t *testing.T) {
+	segments := []WorkSegment{
+		{Start: at("2024-03-04 09:00"), End: at("2024-03-04 12:00")},
+		{Start: at("2024-03-04 11:00"), End: at("2024-03-04 18:00")},
+	}
+	if _, err := ClassifyWorkSegments(segments, nil, AttendancePolicy{}); err == nil {
+		t.Error("expected overlap error")
+	}
+}
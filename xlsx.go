@@ -0,0 +1,220 @@
+package salary
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// XLSXOptions Excel导出选项
+type XLSXOptions struct {
+	EmployeeSheets bool // 是否为每名员工生成明细工作表
+}
+
+// xlsxCell 工作表单元格
+type xlsxCell struct {
+	Text   string // 文本内容，Number 为空时使用
+	Number string // 数值内容（如金额，单位元）
+	Bold   bool   // 是否加粗
+}
+
+// xlsxSheet 工作表
+type xlsxSheet struct {
+	Name string
+	Rows [][]xlsxCell
+}
+
+// xlsxText 文本单元格
+func xlsxText(s string) xlsxCell { return xlsxCell{Text: s} }
+
+// xlsxMoney 金额单元格，分转换为元
+func xlsxMoney(m Money) xlsxCell {
+	return xlsxCell{Number: moneyToDec(m).Div(decimal.NewFromInt(100)).StringFixed(2)}
+}
+
+// xlsxHeader 加粗的表头单元格
+func xlsxHeader(s string) xlsxCell { return xlsxCell{Text: s, Bold: true} }
+
+// ExportXLSX 将批次结果导出为Excel工作簿，仅包含汇总工作表
+// 汇总工作表每行一名员工，列出税前工资、社会保险、住房公积金、个人所得税和实发工资，末行为合计，金额单位为元
+func ExportXLSX(run PayrollResult, w io.Writer) error {
+	return ExportXLSXWithOptions(run, w, XLSXOptions{})
+}
+
+// ExportXLSXWithOptions 按选项将批次结果导出为Excel工作簿，可为每名员工追加明细工作表
+func ExportXLSXWithOptions(run PayrollResult, w io.Writer, opts XLSXOptions) error {
+	sheets := []xlsxSheet{xlsxSummarySheet(run)}
+	if opts.EmployeeSheets {
+		used := map[string]bool{sheets[0].Name: true}
+		for _, r := range run.Employees {
+			sheets = append(sheets, xlsxEmployeeSheet(r, xlsxSheetName(r.Employee.ID, used)))
+		}
+	}
+	return writeXLSX(w, sheets)
+}
+
+// xlsxSummarySheet 生成批次汇总工作表
+func xlsxSummarySheet(run PayrollResult) xlsxSheet {
+	sheet := xlsxSheet{Name: "汇总"}
+	sheet.Rows = append(sheet.Rows,
+		[]xlsxCell{xlsxHeader(fmt.Sprintf("%s 工资汇总", run.Period.Format("2006年01月")))},
+		[]xlsxCell{xlsxHeader("工号"), xlsxHeader("姓名"), xlsxHeader("部门"), xlsxHeader("税前工资"),
+			xlsxHeader("社会保险"), xlsxHeader("住房公积金"), xlsxHeader("个人所得税"), xlsxHeader("实发工资")})
+	for _, r := range run.Employees {
+		sheet.Rows = append(sheet.Rows, []xlsxCell{
+			xlsxText(r.Employee.ID), xlsxText(r.Employee.Name), xlsxText(r.Employee.Department),
+			xlsxMoney(r.GrossSalary), xlsxMoney(r.SocialInsurance), xlsxMoney(r.HousingFund),
+			xlsxMoney(r.IncomeTax), xlsxMoney(r.NetSalary),
+		})
+	}
+	totals := run.Totals()
+	total := []xlsxCell{xlsxHeader("合计"), xlsxText(fmt.Sprintf("%d人", totals.Headcount)), {},
+		xlsxMoney(totals.GrossSalary), xlsxMoney(totals.SocialInsurance), xlsxMoney(totals.HousingFund),
+		xlsxMoney(totals.IncomeTax), xlsxMoney(totals.NetSalary)}
+	for i := range total[3:] {
+		total[3+i].Bold = true
+	}
+	sheet.Rows = append(sheet.Rows, total)
+	return sheet
+}
+
+// xlsxEmployeeSheet 生成员工明细工作表，项目与工资条一致
+func xlsxEmployeeSheet(r EmployeeResult, name string) xlsxSheet {
+	sheet := xlsxSheet{Name: name}
+	sheet.Rows = append(sheet.Rows,
+		[]xlsxCell{xlsxHeader(fmt.Sprintf("%s %s", r.Employee.ID, r.Employee.Name)), xlsxText(r.Period.Format("2006-01"))},
+		[]xlsxCell{xlsxHeader("项目"), xlsxHeader("金额（元）")})
+	for _, item := range payslipItems(r) {
+		sheet.Rows = append(sheet.Rows, []xlsxCell{xlsxText(item.Name), xlsxMoney(item.Amount)})
+	}
+	return sheet
+}
+
+// xlsxSheetName 生成合法且不重复的工作表名称：去除 Excel 不允许的字符，最长31个字符
+func xlsxSheetName(name string, used map[string]bool) string {
+	clean := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	if clean == "" {
+		clean = "Sheet"
+	}
+	runes := []rune(clean)
+	if len(runes) > 31 {
+		runes = runes[:31]
+	}
+	candidate := string(runes)
+	for n := 2; used[candidate]; n++ {
+		suffix := fmt.Sprintf("(%d)", n)
+		base := runes
+		if len(base)+len(suffix) > 31 {
+			base = base[:31-len(suffix)]
+		}
+		candidate = string(base) + suffix
+	}
+	used[candidate] = true
+	return candidate
+}
+
+// xlsxColumn 返回从0开始的列序号对应的列名，如 0 为 A，26 为 AA
+func xlsxColumn(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+// xmlEscape 转义XML文本
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// 工作簿固定部件
+const (
+	xlsxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+	// 样式：0 默认，1 千分位两位小数，2 加粗，3 加粗千分位两位小数
+	xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts><fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills><borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders><cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs><cellXfs count="4"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="4" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/><xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/><xf numFmtId="4" fontId="1" fillId="0" borderId="0" xfId="0" applyNumberFormat="1" applyFont="1"/></cellXfs></styleSheet>`
+)
+
+// writeXLSX 输出包含指定工作表的Excel工作簿，文本使用内联字符串
+func writeXLSX(w io.Writer, sheets []xlsxSheet) error {
+	archive := zip.NewWriter(w)
+	part := func(name, content string) error {
+		f, err := archive.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(f, content)
+		return err
+	}
+
+	var types, workbook, rels strings.Builder
+	types.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	workbook.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	rels.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i, sheet := range sheets {
+		n := i + 1
+		fmt.Fprintf(&types, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, n)
+		fmt.Fprintf(&workbook, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, xmlEscape(sheet.Name), n, n)
+		fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, n, n)
+		if err := part(fmt.Sprintf("xl/worksheets/sheet%d.xml", n), sheet.xml()); err != nil {
+			return err
+		}
+	}
+	types.WriteString(`</Types>`)
+	workbook.WriteString(`</sheets></workbook>`)
+	fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/></Relationships>`, len(sheets)+1)
+
+	for _, p := range []struct{ name, content string }{
+		{"[Content_Types].xml", types.String()},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", workbook.String()},
+		{"xl/_rels/workbook.xml.rels", rels.String()},
+		{"xl/styles.xml", xlsxStyles},
+	} {
+		if err := part(p.name, p.content); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// xml 生成工作表XML
+func (s xlsxSheet) xml() string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for r, row := range s.Rows {
+		fmt.Fprintf(&b, `<row r="%d">`, r+1)
+		for c, cell := range row {
+			ref := fmt.Sprintf("%s%d", xlsxColumn(c), r+1)
+			style := 0
+			if cell.Bold {
+				style = 2
+			}
+			switch {
+			case cell.Number != "":
+				fmt.Fprintf(&b, `<c r="%s" s="%d"><v>%s</v></c>`, ref, style+1, cell.Number)
+			case cell.Text != "":
+				fmt.Fprintf(&b, `<c r="%s" s="%d" t="inlineStr"><is><t>%s</t></is></c>`, ref, style, xmlEscape(cell.Text))
+			}
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData></worksheet>`)
+	return b.String()
+}
@@ -0,0 +1,61 @@
+package salary
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportXLSX(t *testing.T) {
+	through := time.Date(2024, 6, 1, 0, 0, 0, 0, time.Local)
+	run := NewSandboxStore(SyntheticOptions{Headcount: 3, Seed: 9}, through, 1).Runs()[0]
+
+	var buf bytes.Buffer
+	if err := ExportXLSXWithOptions(run, &buf, XLSXOptions{EmployeeSheets: true}); err != nil {
+		t.Fatal(err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := make(map[string]string)
+	for _, f := range archive.File {
+		rc, _ := f.Open()
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		if err := xml.Unmarshal(data, new(any)); err != nil && !strings.HasSuffix(f.Name, ".rels") {
+			t.Errorf("%s: invalid XML: %v", f.Name, err)
+		}
+		parts[f.Name] = string(data)
+	}
+	for _, name := range []string{"[Content_Types].xml", "xl/workbook.xml", "xl/worksheets/sheet1.xml", "xl/worksheets/sheet4.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("missing part %s", name)
+		}
+	}
+	summary := parts["xl/worksheets/sheet1.xml"]
+	net := moneyToDec(run.Totals().NetSalary).Shift(-2).StringFixed(2)
+	if !strings.Contains(summary, "<v>"+net+"</v>") || !strings.Contains(summary, "合计") {
+		t.Errorf("summary sheet lacks net total %s", net)
+	}
+	if !strings.Contains(parts["xl/workbook.xml"], `name="`+run.Employees[0].Employee.ID+`"`) {
+		t.Error("employee detail sheet missing from workbook")
+	}
+}
+
+func TestXLSXSheetName(t *testing.T) {
+	used := map[string]bool{}
+	if got := xlsxSheetName("A/B", used); got != "A_B" {
+		t.Errorf("sanitized name = %q", got)
+	}
+	if got := xlsxSheetName("A/B", used); got != "A_B(2)" {
+		t.Errorf("duplicate name = %q", got)
+	}
+	if got := xlsxColumn(27); got != "AB" {
+		t.Errorf("xlsxColumn(27) = %q", got)
+	}
+}